@@ -5,10 +5,14 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/brensch/schniffer/internal/bot"
 	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/email"
 	"github.com/brensch/schniffer/internal/manager"
 	"github.com/brensch/schniffer/internal/providers"
 	"github.com/brensch/schniffer/internal/web"
@@ -36,6 +40,20 @@ func main() {
 	}
 	defer store.Close()
 
+	// Optional: serve web reads from a snapshot copy when the writer holds the lock.
+	// Snapshot reads can be up to this interval stale.
+	if v := os.Getenv("READ_SNAPSHOT_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil {
+			slog.Error("invalid READ_SNAPSHOT_INTERVAL", slog.Any("err", err))
+			os.Exit(1)
+		}
+		err = store.EnableSnapshotFallback(ctx, interval)
+		if err != nil {
+			slog.Warn("read snapshot fallback disabled", slog.Any("err", err))
+		}
+	}
+
 	provRegistry := providers.NewRegistry()
 	provRegistry.Register("recreation_gov", providers.NewRecreationGov())
 	provRegistry.Register("reservecalifornia", providers.NewReserveCalifornia())
@@ -46,6 +64,7 @@ func main() {
 		panic(err)
 	}
 	// must register intents before opening
+	// (guild members is needed for both join and leave events)
 	discordSession.Identify.Intents =
 		discordgo.IntentsGuilds |
 			discordgo.IntentsGuildMessages |
@@ -64,6 +83,17 @@ func main() {
 		slog.Error("failed to create bot", slog.Any("err", err))
 		panic(err)
 	}
+	if admins := os.Getenv("ADMIN_USER_IDS"); admins != "" {
+		b.SetAdmins(strings.Split(admins, ",")...)
+	}
+	if path := os.Getenv("WELCOME_MESSAGE_FILE"); path != "" {
+		welcome, err := os.ReadFile(path)
+		if err != nil {
+			slog.Error("failed to read welcome message", slog.Any("err", err))
+			os.Exit(1)
+		}
+		b.SetWelcomeMessage(string(welcome))
+	}
 	err = b.MountHandlers()
 	if err != nil {
 		slog.Error("bot mount handlers failed", slog.Any("err", err))
@@ -77,8 +107,48 @@ func main() {
 	defer discordSession.Close()
 
 	mgr := manager.NewManager(store, provRegistry, discordSession, broadcastChannel)
+	mgr.SetSummaryChannel(os.Getenv("SUMMARY_CHANNEL_ID"))
+	if os.Getenv("SUMMARY_DM_OWNER") == "true" {
+		guild, err := discordSession.Guild(guildID)
+		if err != nil {
+			slog.Error("failed to look up guild owner for summary fallback", slog.Any("err", err))
+		} else {
+			mgr.SetSummaryFallbackUser(guild.OwnerID)
+		}
+	}
+	b.SetPollStatus(mgr)
+	b.SetVerifier(mgr)
+	b.SetAvailableNow(mgr)
+	if path := os.Getenv("NOTIFICATION_TEMPLATE"); path != "" {
+		tmpl, err := manager.LoadNotificationTemplate(path)
+		if err != nil {
+			slog.Error("failed to load notification template", slog.Any("err", err))
+			os.Exit(1)
+		}
+		mgr.SetNotificationTemplate(tmpl)
+	}
+	mgr.SetAnnounceNewCampsites(os.Getenv("ANNOUNCE_NEW_CAMPSITES") == "true")
+	if os.Getenv("DETAILED_NOTIFICATIONS") == "true" {
+		mgr.SetEmbedOptions(manager.DetailedEmbedOptions)
+	}
+	if v := os.Getenv("POLL_CONCURRENCY"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			slog.Error("invalid POLL_CONCURRENCY", slog.Any("err", err))
+			os.Exit(1)
+		}
+		mgr.SetPollConcurrency(n)
+	}
+	// Email notifications are opt-in per user and only available when SMTP is configured
+	if cfg, ok := email.ConfigFromEnv(); ok {
+		mailer := email.NewMailer(cfg)
+		mgr.SetEmailSender(mailer)
+		b.SetEmailSender(mailer)
+	}
 	go mgr.Run(ctx)
 	go mgr.RunDailySummary(ctx)
+	mgr.SetHistoryRetention(retentionFromEnv("HISTORY_RETENTION_DAYS"), retentionFromEnv("NOTIFICATION_RETENTION_DAYS"))
+	mgr.RunHistoryPruning(ctx)
 
 	// // Background metadata sync
 	// go mgr.RunCampgroundSync(ctx, "recreation_gov")
@@ -89,8 +159,30 @@ func main() {
 	if webAddr == "" {
 		webAddr = ":8069"
 	}
-	webServer := web.NewServer(store, mgr, webAddr)
+	webServer := web.NewServer(ctx, store, mgr, webAddr)
+	if os.Getenv("IMAGE_PROXY") == "true" {
+		var hosts []string
+		if h := os.Getenv("IMAGE_PROXY_HOSTS"); h != "" {
+			hosts = strings.Split(h, ",")
+		}
+		webServer.EnableImageProxy(hosts...)
+		if dir := os.Getenv("IMAGE_CACHE_DIR"); dir != "" {
+			if err := webServer.SetImageCacheDir(dir); err != nil {
+				slog.Error("failed to set up image cache", slog.Any("err", err))
+			}
+		}
+	}
+	webServer.SetAdminToken(os.Getenv("ADMIN_TOKEN"))
+	tokenSecret := os.Getenv("WEB_TOKEN_SECRET")
+	if tokenSecret == "" {
+		slog.Warn("WEB_TOKEN_SECRET not set; user web links will be refused")
+	}
+	webServer.SetUserTokenSecret(tokenSecret)
+	b.SetLinkSigner(webServer)
+	webServer.SetGzip(os.Getenv("DISABLE_GZIP") != "true")
+	webDone := make(chan struct{})
 	go func() {
+		defer close(webDone)
 		err := webServer.Run(ctx)
 		if err != nil {
 			slog.Error("web server failed", slog.Any("err", err))
@@ -98,5 +190,27 @@ func main() {
 	}()
 
 	<-ctx.Done()
+	// let the web server finish in-flight requests and background scrapes
+	<-webDone
+	// flush database writes the pollers already queued before the store closes
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := mgr.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("manager shutdown incomplete", slog.Any("err", err))
+	}
 	slog.Info("night night")
 }
+
+// retentionFromEnv reads a retention window in days. Unset means 0, which keeps the manager default.
+func retentionFromEnv(key string) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0
+	}
+	days, err := strconv.Atoi(v)
+	if err != nil || days <= 0 {
+		slog.Error("invalid "+key+", expected a positive number of days", slog.String("value", v))
+		os.Exit(1)
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
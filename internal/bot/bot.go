@@ -1,9 +1,12 @@
 package bot
 
 import (
+	"context"
 	"log/slog"
+	"strings"
 
 	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/email"
 	"github.com/brensch/schniffer/internal/nonsense"
 	"github.com/brensch/schniffer/internal/providers"
 	"github.com/bwmarrin/discordgo"
@@ -17,7 +20,15 @@ type Bot struct {
 	store    *db.Store
 	registry *providers.Registry
 	logger   *slog.Logger
-	useGuild bool // use guild commands (default) vs global commands (production)
+	useGuild bool            // use guild commands (default) vs global commands (production)
+	admins   map[string]bool // user IDs allowed to run admin subcommands
+
+	pollStatus   PollStatus   // optional, for /schniff status
+	availableNow AvailableNow // optional, enables /schniff now
+	welcome      string       // optional welcome DM override
+	email        email.Sender // optional, enables /schniff email
+	verifier     Verifier     // optional, enables /schniff verify
+	linkSigner   LinkSigner   // optional, signs the user ID in web links
 }
 
 func New(store *db.Store, discordSession *discordgo.Session, registry *providers.Registry, guildID string, useGuild bool) (*Bot, error) {
@@ -41,6 +52,7 @@ func (b *Bot) MountHandlers() error {
 	b.session.AddHandler(b.onReady)
 	b.session.AddHandler(b.onInteraction)
 	b.session.AddHandler(b.onGuildMemberAdd)
+	b.session.AddHandler(b.onGuildMemberRemove)
 	return nil
 }
 
@@ -76,31 +88,7 @@ func (b *Bot) onGuildMemberAdd(s *discordgo.Session, m *discordgo.GuildMemberAdd
 		b.logger.Error("failed to create DM channel", slog.Any("err", err))
 		return
 	} else {
-		// Add detailed instructions on how to use the bot
-		instructions := `
-
-**Hello schniffist**
-
-Congratulations! Being a schniffist is an honour.
-
-**How to schniff**
-
-👃 Add a schniff
-
-⏰ Wait
-
-🔍 I find you a campsite
-
-📨 I send you a message, you click the link to the freed website, and then book it
-
-Send all your commands directly to me privately (ie not in the schniffer channel).
-Type /schniff to see the commands available. You can figure it out from there.
-
-**Why can you find campsites that are free when they're all booked right now?**
-People make plans, those plans change. They cancel their booking. They normally do it on sunday night for some reason. I don't know why, i'm not a human and don't do human stuff, i'm a schniffer.
-`
-
-		_, err = s.ChannelMessageSend(dmChannel.ID, instructions)
+		_, err = s.ChannelMessageSend(dmChannel.ID, b.welcomeMessage())
 		if err != nil {
 			b.logger.Error("failed to send DM to new user", slog.Any("err", err))
 		} else {
@@ -129,8 +117,26 @@ People make plans, those plans change. They cancel their booking. They normally
 
 }
 
-func (b *Bot) registerCommands() {
-	cmds := []*discordgo.ApplicationCommand{
+// onGuildMemberRemove stops polling for users who have left the guild.
+func (b *Bot) onGuildMemberRemove(s *discordgo.Session, m *discordgo.GuildMemberRemove) {
+	if m.User == nil {
+		return
+	}
+	count, err := b.store.DeactivateUserRequests(context.Background(), m.User.ID)
+	if err != nil {
+		b.logger.Error("failed to deactivate requests for departed member", slog.String("id", m.User.ID), slog.Any("err", err))
+		return
+	}
+	b.logger.Info("member left, deactivated their schniffs",
+		slog.String("user", m.User.Username),
+		slog.String("id", m.User.ID),
+		slog.Int64("count", count))
+}
+
+// schniffCommands is the single definition of the slash commands; it drives both registration and
+// the command list in the welcome DM.
+func schniffCommands() []*discordgo.ApplicationCommand {
+	return []*discordgo.ApplicationCommand{
 		{
 			Name:        "schniff",
 			Description: "Manage campground monitors",
@@ -139,6 +145,20 @@ func (b *Bot) registerCommands() {
 					{Name: "campground", Type: discordgo.ApplicationCommandOptionString, Required: true, Description: "Select campground", Autocomplete: true},
 					{Name: "checkin", Type: discordgo.ApplicationCommandOptionString, Required: true, Description: "Check-in (YYYY-MM-DD)"},
 					{Name: "checkout", Type: discordgo.ApplicationCommandOptionString, Required: true, Description: "Check-out (YYYY-MM-DD)"},
+					{Name: "min_rating", Type: discordgo.ApplicationCommandOptionNumber, Required: false, Description: "Only alert for sites rated at least this (0-5)", MinValue: &minRatingFloor, MaxValue: 5},
+					{Name: "rated_only", Type: discordgo.ApplicationCommandOptionBoolean, Required: false, Description: "Skip sites with no rating when min_rating is set"},
+					{Name: "include_day_use", Type: discordgo.ApplicationCommandOptionBoolean, Required: false, Description: "Also alert for day-use sites (skipped by default)"},
+					{Name: "specialty_only", Type: discordgo.ApplicationCommandOptionBoolean, Required: false, Description: "Only alert for cabins, yurts, lookouts and similar"},
+					{Name: "reservable_only", Type: discordgo.ApplicationCommandOptionBoolean, Required: false, Description: "Skip walk-up (first come, first served) sites"},
+					{Name: "min_nights", Type: discordgo.ApplicationCommandOptionInteger, Required: false, Description: "Only alert for stays of at least this many consecutive nights", MinValue: &minNightsFloor, MaxValue: 14},
+					{Name: "flex_days", Type: discordgo.ApplicationCommandOptionInteger, Required: false, Description: "Also watch this many days either side of your dates (default 0)", MinValue: &minFlexDays, MaxValue: 7},
+					{Name: "equipment", Type: discordgo.ApplicationCommandOptionString, Required: false, Description: "Only alert for sites that allow this equipment (pick a campground first)", Autocomplete: true},
+					{Name: "campsite_type", Type: discordgo.ApplicationCommandOptionString, Required: false, Description: "Only alert for sites of this type (pick a campground first)", Autocomplete: true},
+					{Name: "nights", Type: discordgo.ApplicationCommandOptionString, Required: false, Description: "Which nights count (default any)", Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "Any night", Value: db.NightsAny},
+						{Name: "Weekends (Fri/Sat nights)", Value: db.NightsWeekends},
+						{Name: "Weekdays (Sun-Thu nights)", Value: db.NightsWeekdays},
+					}},
 				}},
 				{Name: "add-bulk", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Add a schniff for all campgrounds in a group. Use `/schniff map` to make groups.", Options: []*discordgo.ApplicationCommandOption{
 					{Name: "group", Type: discordgo.ApplicationCommandOptionString, Required: true, Description: "Select group", Autocomplete: true},
@@ -146,15 +166,74 @@ func (b *Bot) registerCommands() {
 					{Name: "checkout", Type: discordgo.ApplicationCommandOptionString, Required: true, Description: "Check-out (YYYY-MM-DD)"},
 				}},
 				{Name: "map", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Open map to create groups or quickly see availability at a site."},
-				{Name: "remove", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Remove a schniff. Blank id removes all.", Options: []*discordgo.ApplicationCommandOption{
+				{Name: "remove", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Remove a schniff. Blank id removes all (after confirming).", Options: []*discordgo.ApplicationCommandOption{
 					{Name: "ids", Type: discordgo.ApplicationCommandOptionInteger, Required: false, Description: "Request ID to remove", Autocomplete: true},
 				}},
+				{Name: "remove-expired", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Remove your schniffs whose checkout has passed"},
+				{Name: "remove-before", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Remove your schniffs that check out before a date", Options: []*discordgo.ApplicationCommandOption{
+					{Name: "date", Type: discordgo.ApplicationCommandOptionString, Required: true, Description: "Remove schniffs checking out before this (YYYY-MM-DD)"},
+				}},
+				{Name: "pause", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Stop checking a schniff for now without removing it", Options: []*discordgo.ApplicationCommandOption{
+					{Name: "ids", Type: discordgo.ApplicationCommandOptionInteger, Required: true, Description: "Request ID to pause", Autocomplete: true},
+				}},
+				{Name: "resume", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Start checking a paused schniff again", Options: []*discordgo.ApplicationCommandOption{
+					{Name: "ids", Type: discordgo.ApplicationCommandOptionInteger, Required: true, Description: "Request ID to resume", Autocomplete: true},
+				}},
+				{Name: "restore", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Bring back a removed or expired schniff for new dates", Options: []*discordgo.ApplicationCommandOption{
+					{Name: "ids", Type: discordgo.ApplicationCommandOptionInteger, Required: true, Description: "Schniff to restore", Autocomplete: true},
+					{Name: "checkin", Type: discordgo.ApplicationCommandOptionString, Required: true, Description: "New check-in (YYYY-MM-DD)"},
+					{Name: "checkout", Type: discordgo.ApplicationCommandOptionString, Required: true, Description: "New check-out (YYYY-MM-DD)"},
+				}},
 				{Name: "list", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "List all your active schniffs"},
+				{Name: "groups", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "List and delete your campground groups"},
 				{Name: "summary", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Get summary of schniff activity for all users"},
+				{Name: "stats", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "See your own schniffing history"},
+				{Name: "status", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Check the schniffer is alive and polling"},
+				{Name: "now", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "See what you can book right now across all your schniffs"},
+				{Name: "missed", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "See campsites that opened and got booked again in the last week"},
+				{Name: "email", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Also get notifications by email", Options: []*discordgo.ApplicationCommandOption{
+					{Name: "address", Type: discordgo.ApplicationCommandOptionString, Required: false, Description: "Email to send a verification code to"},
+					{Name: "code", Type: discordgo.ApplicationCommandOptionString, Required: false, Description: "Verification code from the email"},
+					{Name: "remove", Type: discordgo.ApplicationCommandOptionBoolean, Required: false, Description: "Stop email notifications"},
+				}},
+				{Name: "prefs", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Show or set notification preferences", Options: []*discordgo.ApplicationCommandOption{
+					{Name: "quiet_start", Type: discordgo.ApplicationCommandOptionString, Required: false, Description: "Start of quiet hours, HH:MM (e.g. 22:00)"},
+					{Name: "quiet_end", Type: discordgo.ApplicationCommandOptionString, Required: false, Description: "End of quiet hours, HH:MM (e.g. 07:00)"},
+					{Name: "timezone", Type: discordgo.ApplicationCommandOptionString, Required: false, Description: "Your timezone, e.g. America/Los_Angeles (default UTC)"},
+					{Name: "quiet_hours", Type: discordgo.ApplicationCommandOptionBoolean, Required: false, Description: "Turn quiet hours on or off"},
+					{Name: "digest", Type: discordgo.ApplicationCommandOptionBoolean, Required: false, Description: "Combine openings into one message every 30 minutes"},
+					{Name: "webhook", Type: discordgo.ApplicationCommandOptionString, Required: false, Description: "Also POST notifications as JSON to this URL (off to remove)"},
+					{Name: "renotify_after", Type: discordgo.ApplicationCommandOptionInteger, Required: false, Description: "Minutes a site must stay booked before a reopening is notified again (0 = always)", MinValue: &minRenotifyAfter, MaxValue: 10080},
+					{Name: "max_per_day", Type: discordgo.ApplicationCommandOptionInteger, Required: false, Description: "Most times one schniff may notify you in 24 hours (default 20)", MinValue: &minMaxPerDay, MaxValue: 500},
+					{Name: "price_below", Type: discordgo.ApplicationCommandOptionNumber, Required: false, Description: "DM when a schniffed site's nightly price drops under this many dollars (0 = off)", MinValue: &minPriceBelow, MaxValue: 10000},
+				}},
+				{Name: "tag-add", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Admin: tag a campground", Options: []*discordgo.ApplicationCommandOption{
+					{Name: "campground", Type: discordgo.ApplicationCommandOptionString, Required: true, Description: "Select campground", Autocomplete: true},
+					{Name: "tag", Type: discordgo.ApplicationCommandOptionString, Required: true, Description: "Tag, e.g. lakeside"},
+				}},
+				{Name: "tag-remove", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Admin: remove a tag from a campground", Options: []*discordgo.ApplicationCommandOption{
+					{Name: "campground", Type: discordgo.ApplicationCommandOptionString, Required: true, Description: "Select campground", Autocomplete: true},
+					{Name: "tag", Type: discordgo.ApplicationCommandOptionString, Required: true, Description: "Tag to remove"},
+				}},
+				{Name: "block", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Admin: stop polling a campground", Options: []*discordgo.ApplicationCommandOption{
+					{Name: "campground", Type: discordgo.ApplicationCommandOptionString, Required: true, Description: "Select campground", Autocomplete: true},
+					{Name: "reason", Type: discordgo.ApplicationCommandOptionString, Required: false, Description: "Why, e.g. closed for the season"},
+				}},
+				{Name: "unblock", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Admin: resume polling a campground", Options: []*discordgo.ApplicationCommandOption{
+					{Name: "campground", Type: discordgo.ApplicationCommandOptionString, Required: true, Description: "Select campground", Autocomplete: true},
+				}},
+				{Name: "verify", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Admin: compare stored availability to a live fetch", Options: []*discordgo.ApplicationCommandOption{
+					{Name: "campground", Type: discordgo.ApplicationCommandOptionString, Required: true, Description: "Select campground", Autocomplete: true},
+					{Name: "days", Type: discordgo.ApplicationCommandOptionInteger, Required: false, Description: "Days ahead to check (default 30)", MinValue: &minVerifyDays, MaxValue: 180},
+				}},
 				// {Name: "nonsense", Type: discordgo.ApplicationCommandOptionSubCommand, Description: "Broadcast a silly greeting to the channel"},
 			},
 		},
 	}
+}
+
+func (b *Bot) registerCommands() {
+	cmds := schniffCommands()
 	appID := b.session.State.Application.ID
 	guildID := ""
 	if b.useGuild {
@@ -179,6 +258,9 @@ func (b *Bot) onInteraction(s *discordgo.Session, i *discordgo.InteractionCreate
 	case discordgo.InteractionApplicationCommand:
 		b.handleApplicationCommand(s, i)
 		return
+	case discordgo.InteractionMessageComponent:
+		b.handleMessageComponent(s, i)
+		return
 	default:
 		return
 	}
@@ -201,8 +283,14 @@ func (b *Bot) handleAutocomplete(s *discordgo.Session, i *discordgo.InteractionC
 		choices = b.autocompleteCampgrounds(i, focused.StringValue())
 	case "group":
 		choices = b.autocompleteGroups(i, focused.StringValue())
+	case "equipment", "campsite_type":
+		choices = b.autocompleteSiteKind(sub, focused)
 	case "ids":
-		choices = b.autocompleteRemoveIDs(i)
+		if sub.Name == "restore" {
+			choices = b.autocompleteRestoreIDs(i)
+		} else {
+			choices = b.autocompleteRemoveIDs(i)
+		}
 	}
 	if choices == nil {
 		return
@@ -239,13 +327,50 @@ func (b *Bot) handleApplicationCommand(s *discordgo.Session, i *discordgo.Intera
 		b.handleRemoveCommand(s, i, sub)
 	case "list":
 		b.handleListCommand(s, i, sub)
+	case "groups":
+		b.handleGroupsCommand(s, i, sub)
+	case "stats":
+		b.handleStatsCommand(s, i, sub)
 	case "summary":
 		b.handleSummaryCommand(s, i, sub)
+	case "status":
+		b.handleStatusCommand(s, i, sub)
+	case "now":
+		b.handleNowCommand(s, i, sub)
+	case "missed":
+		b.handleMissedCommand(s, i, sub)
+	case "email":
+		b.handleEmailCommand(s, i, sub)
+	case "remove-expired", "remove-before":
+		b.handleRemoveBeforeCommand(s, i, sub)
+	case "restore":
+		b.handleRestoreCommand(s, i, sub)
+	case "prefs":
+		b.handlePrefsCommand(s, i, sub)
+	case "tag-add", "tag-remove":
+		b.handleTagCommand(s, i, sub)
+	case "pause", "resume":
+		b.handlePauseCommand(s, i, sub)
+	case "verify":
+		b.handleVerifyCommand(s, i, sub)
+	case "block", "unblock":
+		b.handleBlockCommand(s, i, sub)
 	case "nonsense":
 		b.handleNonsenseCommand(s, i, sub)
 	}
 }
 
+// handleMessageComponent dispatches button clicks by custom ID prefix
+func (b *Bot) handleMessageComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	customID := i.MessageComponentData().CustomID
+	switch {
+	case strings.HasPrefix(customID, removeAllConfirmID), strings.HasPrefix(customID, removeAllCancelID):
+		b.handleRemoveAllComponent(s, i, customID)
+	case strings.HasPrefix(customID, groupDeleteID):
+		b.handleGroupDeleteComponent(s, i, customID)
+	}
+}
+
 // findFocusedOption returns the focused option (if any) from a list
 func findFocusedOption(opts []*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
 	for _, o := range opts {
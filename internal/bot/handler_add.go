@@ -9,6 +9,15 @@ import (
 	"github.com/bwmarrin/discordgo"
 )
 
+// minRatingFloor is the lower bound for the min_rating option; discordgo takes it by pointer.
+var minRatingFloor = 0.0
+
+// minNightsFloor is the lower bound for the min_nights option.
+var minNightsFloor = 1.0
+
+// minFlexDays is the lower bound for the flex_days option.
+var minFlexDays = 0.0
+
 func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCreate, sub *discordgo.ApplicationCommandInteractionDataOption) {
 	opts := optMap(sub.Options)
 	campgroundResponse, ok := opts["campground"]
@@ -49,8 +58,42 @@ func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCre
 		return
 	}
 
-	uid := getUserID(i)
-	_, err = b.store.AddRequest(context.Background(), db.SchniffRequest{UserID: uid, Provider: campgroundProvider, CampgroundID: campgroundID, Checkin: start, Checkout: end})
+	req := db.SchniffRequest{UserID: getUserID(i), Provider: campgroundProvider, CampgroundID: campgroundID, Checkin: start, Checkout: end}
+	if o, ok := opts["min_rating"]; ok && o != nil {
+		req.MinRating = o.FloatValue()
+	}
+	if o, ok := opts["rated_only"]; ok && o != nil {
+		req.RequireRating = o.BoolValue()
+	}
+	if o, ok := opts["include_day_use"]; ok && o != nil {
+		req.IncludeDayUse = o.BoolValue()
+	}
+	if o, ok := opts["specialty_only"]; ok && o != nil {
+		req.SpecialtyOnly = o.BoolValue()
+	}
+	if o, ok := opts["reservable_only"]; ok && o != nil {
+		req.ReservableOnly = o.BoolValue()
+	}
+	if o, ok := opts["min_nights"]; ok && o != nil {
+		req.MinNights = int(o.IntValue())
+	}
+	if o, ok := opts["flex_days"]; ok && o != nil {
+		req.FlexDays = int(o.IntValue())
+	}
+	if o, ok := opts["nights"]; ok && o != nil {
+		req.NightFilter, err = db.ParseNightFilter(o.StringValue())
+		if err != nil {
+			respond(s, i, err.Error())
+			return
+		}
+	}
+	if o, ok := opts["equipment"]; ok && o != nil {
+		req.Equipment = strings.TrimSpace(o.StringValue())
+	}
+	if o, ok := opts["campsite_type"]; ok && o != nil {
+		req.CampsiteType = strings.TrimSpace(o.StringValue())
+	}
+	_, err = b.store.AddRequest(context.Background(), req)
 	if err != nil {
 		respond(s, i, "error: "+err.Error())
 		return
@@ -59,12 +102,82 @@ func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCre
 	// get the length of the stay
 	stayDuration := end.Sub(start)
 	formattedName := b.formatCampgroundWithLink(context.Background(), campgroundProvider, campgroundID, campgroundName)
-	respond(s, i, fmt.Sprintf("Now schniffing: %s, dates %s to %s (%.0f nights)", formattedName, start.Format("2006-01-02"), end.Format("2006-01-02"), stayDuration.Hours()/24))
+	msg := fmt.Sprintf("Now schniffing: %s, dates %s to %s (%.0f nights)", formattedName, start.Format("2006-01-02"), end.Format("2006-01-02"), stayDuration.Hours()/24)
+	if req.FlexDays > 0 {
+		msg += fmt.Sprintf(" (±%d days)", req.FlexDays)
+	}
+	if req.SpecialtyOnly {
+		msg += ", cabins/yurts/lookouts only"
+	}
+	if req.ReservableOnly {
+		msg += ", reservable sites only"
+	}
+	if req.MinNights > 1 {
+		msg += fmt.Sprintf(", stays of %d+ nights", req.MinNights)
+	}
+	switch req.NightFilter {
+	case db.NightsWeekends:
+		msg += ", Fri/Sat nights only"
+	case db.NightsWeekdays:
+		msg += ", Sun-Thu nights only"
+	}
+	if req.CampsiteType != "" {
+		msg += ", " + req.CampsiteType + " sites only"
+	}
+	if req.Equipment != "" {
+		msg += ", sites allowing " + req.Equipment
+	}
+	if req.MinRating > 0 {
+		msg += fmt.Sprintf(", sites rated %.1f+", req.MinRating)
+		if !req.RequireRating {
+			msg += " (or unrated)"
+		}
+	}
+	respond(s, i, msg)
+}
+
+// autocompleteSiteKind suggests the equipment or campsite types known for the campground already
+// chosen in the command, filtered by what has been typed so far.
+func (b *Bot) autocompleteSiteKind(sub, focused *discordgo.ApplicationCommandInteractionDataOption) []*discordgo.ApplicationCommandOptionChoice {
+	cg, ok := optMap(sub.Options)["campground"]
+	if !ok || cg == nil {
+		return []*discordgo.ApplicationCommandOptionChoice{}
+	}
+	parts := strings.SplitN(cg.StringValue(), "||", 3)
+	if len(parts) != 3 {
+		return []*discordgo.ApplicationCommandOptionChoice{}
+	}
+
+	ctx := context.Background()
+	var kinds []string
+	var err error
+	if focused.Name == "equipment" {
+		kinds, err = b.store.GetCampsiteEquipmentTypes(ctx, parts[0], parts[1])
+	} else {
+		kinds, err = b.store.GetCampsiteTypes(ctx, parts[0], parts[1])
+	}
+	if err != nil {
+		b.logger.Warn("list campsite kinds failed", "option", focused.Name, "err", err)
+		return nil
+	}
+
+	query := strings.ToLower(focused.StringValue())
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, 25)
+	for _, k := range kinds {
+		if query != "" && !strings.Contains(strings.ToLower(k), query) {
+			continue
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: sanitizeGenericText(k), Value: sanitizeChoiceValue(k)})
+		if len(choices) >= 25 { // Discord limit
+			break
+		}
+	}
+	return choices
 }
 
 func (b *Bot) autocompleteCampgrounds(i *discordgo.InteractionCreate, query string) []*discordgo.ApplicationCommandOptionChoice {
 	ctx := context.Background()
-	cgs, err := b.store.ListCampgrounds(ctx, query)
+	cgs, err := b.store.SearchCampgrounds(ctx, query, 25)
 	if err != nil {
 		b.logger.Warn("list campgrounds failed", "err", err)
 		return nil
@@ -73,11 +73,26 @@ func (b *Bot) handleAddBulkCommand(s *discordgo.Session, i *discordgo.Interactio
 		return
 	}
 
+	// Check every campground in the group still exists before creating any requests
+	campgrounds, err := b.store.GetCampgroundsByIDs(context.Background(), group.Campgrounds)
+	if err != nil {
+		respond(s, i, "error looking up group campgrounds: "+err.Error())
+		return
+	}
+	exists := make(map[db.CampgroundRef]bool, len(campgrounds))
+	for _, c := range campgrounds {
+		exists[db.CampgroundRef{Provider: c.Provider, CampgroundID: c.ID}] = true
+	}
+
 	// Create schniff requests for all campgrounds in the group
 	var successCount int
 	var errors []string
 
 	for _, campgroundRef := range group.Campgrounds {
+		if !exists[campgroundRef] {
+			errors = append(errors, fmt.Sprintf("Skipped %s/%s: campground not found", campgroundRef.Provider, campgroundRef.CampgroundID))
+			continue
+		}
 		_, err := b.store.AddRequest(context.Background(), db.SchniffRequest{
 			UserID:       uid,
 			Provider:     campgroundRef.Provider,
@@ -0,0 +1,85 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleBlockCommand adds a campground to the polling blocklist, or removes it. Admin only.
+// Owners of active schniffs on a newly blocked campground get a DM explaining why it went quiet.
+func (b *Bot) handleBlockCommand(s *discordgo.Session, i *discordgo.InteractionCreate, sub *discordgo.ApplicationCommandInteractionDataOption) {
+	if !b.isAdmin(i) {
+		respond(s, i, "only admins can manage the blocklist")
+		return
+	}
+	opts := optMap(sub.Options)
+	campgroundResponse, ok := opts["campground"]
+	if !ok || campgroundResponse == nil {
+		respond(s, i, "campground selection is required")
+		return
+	}
+	parts := strings.SplitN(campgroundResponse.StringValue(), "||", 3)
+	if len(parts) != 3 {
+		respond(s, i, "invalid campground selection")
+		return
+	}
+	provider, campgroundID, name := parts[0], parts[1], parts[2]
+	link := b.linkCampground(provider, campgroundID, name)
+
+	ctx := context.Background()
+	if sub.Name == "unblock" {
+		if err := b.store.UnblockCampground(ctx, provider, campgroundID); err != nil {
+			respond(s, i, "error: "+err.Error())
+			return
+		}
+		respond(s, i, fmt.Sprintf("%s will be polled again", link))
+		return
+	}
+
+	reason := ""
+	if o, ok := opts["reason"]; ok && o != nil {
+		reason = strings.TrimSpace(o.StringValue())
+	}
+	affected, err := b.store.BlockCampground(ctx, provider, campgroundID, reason, getUserID(i))
+	if err != nil {
+		respond(s, i, "error: "+err.Error())
+		return
+	}
+	b.notifyBlocked(s, name, reason, affected)
+	respond(s, i, fmt.Sprintf("%s won't be polled anymore, %d schniffs paused", link, len(affected)))
+}
+
+// notifyBlocked DMs each owner once about their schniffs on a blocked campground.
+func (b *Bot) notifyBlocked(s *discordgo.Session, name, reason string, requests []db.SchniffRequest) {
+	byUser := make(map[string][]string)
+	var users []string
+	for _, r := range requests {
+		if _, ok := byUser[r.UserID]; !ok {
+			users = append(users, r.UserID)
+		}
+		byUser[r.UserID] = append(byUser[r.UserID], fmt.Sprintf("#%d", r.ID))
+	}
+
+	why := ""
+	if reason != "" {
+		why = fmt.Sprintf(" (%s)", sanitizeGenericText(reason))
+	}
+	for _, userID := range users {
+		channel, err := s.UserChannelCreate(userID)
+		if err != nil {
+			b.logger.Warn("failed to create DM channel", slog.String("userID", userID), slog.Any("err", err))
+			continue
+		}
+		msg := fmt.Sprintf("⏸️ An admin paused checking %s%s, so your schniffs on it (%s) won't find anything for now. "+
+			"They'll pick up again if it's unblocked; use `/schniff remove` if you want to drop them.",
+			sanitizeGenericText(name), why, strings.Join(byUser[userID], ", "))
+		if _, err := s.ChannelMessageSend(channel.ID, msg); err != nil {
+			b.logger.Warn("failed to send blocked notice", slog.String("userID", userID), slog.Any("err", err))
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package bot
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/brensch/schniffer/internal/email"
+	"github.com/bwmarrin/discordgo"
+)
+
+// SetEmailSender enables /schniff email. Without it the command explains email isn't set up.
+func (b *Bot) SetEmailSender(sender email.Sender) {
+	b.email = sender
+}
+
+// newVerificationCode returns a random 6 digit code.
+func newVerificationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// handleEmailCommand registers, verifies or removes the user's notification email.
+// Registering sends a code to the address, which the user confirms with the code option.
+func (b *Bot) handleEmailCommand(s *discordgo.Session, i *discordgo.InteractionCreate, sub *discordgo.ApplicationCommandInteractionDataOption) {
+	if b.email == nil {
+		respond(s, i, "email notifications aren't enabled on this schniffer")
+		return
+	}
+	ctx := context.Background()
+	uid := getUserID(i)
+	opts := optMap(sub.Options)
+
+	if o, ok := opts["remove"]; ok && o != nil && o.BoolValue() {
+		if err := b.store.RemoveUserEmail(ctx, uid); err != nil {
+			respond(s, i, "error: "+err.Error())
+			return
+		}
+		respond(s, i, "email removed, notifications will only come here")
+		return
+	}
+
+	if o, ok := opts["code"]; ok && o != nil {
+		addr, err := b.store.VerifyUserEmail(ctx, uid, o.StringValue())
+		if err != nil {
+			respond(s, i, "error: "+err.Error())
+			return
+		}
+		respond(s, i, fmt.Sprintf("verified! notifications will also go to %s", addr))
+		return
+	}
+
+	if o, ok := opts["address"]; ok && o != nil {
+		addr, err := email.ValidateAddress(o.StringValue())
+		if err != nil {
+			respond(s, i, err.Error())
+			return
+		}
+		code, err := newVerificationCode()
+		if err != nil {
+			respond(s, i, "error: "+err.Error())
+			return
+		}
+		if err := b.store.SetPendingUserEmail(ctx, uid, addr, code); err != nil {
+			respond(s, i, "error: "+err.Error())
+			return
+		}
+
+		// a slow SMTP server can take longer than Discord's 3s interaction window
+		_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+		})
+
+		body := fmt.Sprintf("<p>Your schniffer verification code is <strong>%s</strong>.</p>"+
+			"<p>Run <code>/schniff email code:%s</code> in Discord to start getting notifications here. It expires in 24 hours.</p>", code, code)
+		msg := fmt.Sprintf("sent a code to %s, confirm it with `/schniff email code:<code>`", addr)
+		if err := b.email.Send(addr, "Verify your schniffer email", body); err != nil {
+			b.logger.Warn("failed to send verification email", "error", err)
+			msg = "couldn't send the verification email, try again later"
+		}
+		_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{Content: msg, Flags: discordgo.MessageFlagsEphemeral})
+		if err != nil {
+			b.logger.Warn("email followup send failed", "err", err)
+		}
+		return
+	}
+
+	addr, ok, err := b.store.GetVerifiedUserEmail(ctx, uid)
+	switch {
+	case err != nil:
+		respond(s, i, "error: "+err.Error())
+	case ok:
+		respond(s, i, fmt.Sprintf("notifications also go to %s. Use `remove:true` to stop.", addr))
+	default:
+		respond(s, i, "no email set. Use `/schniff email address:<you@example.com>` to add one.")
+	}
+}
@@ -0,0 +1,122 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const groupDeleteID = "group_delete:"
+
+// handleGroupsCommand lists the user's groups with a delete button for each.
+func (b *Bot) handleGroupsCommand(s *discordgo.Session, i *discordgo.InteractionCreate, _ *discordgo.ApplicationCommandInteractionDataOption) {
+	data, err := b.groupsMessage(getUserID(i))
+	if err != nil {
+		respond(s, i, "error: "+err.Error())
+		return
+	}
+	data.Flags = discordgo.MessageFlagsEphemeral
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: data,
+	})
+	if err != nil {
+		b.logger.Warn("failed to respond to groups command", "error", err)
+	}
+}
+
+// groupsMessage renders the user's groups as an embed plus one delete button per group.
+func (b *Bot) groupsMessage(uid string) (*discordgo.InteractionResponseData, error) {
+	groups, err := b.store.GetUserGroups(context.Background(), uid)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return &discordgo.InteractionResponseData{
+			Content:    "you have no groups. Use `/schniff map` to make some.",
+			Embeds:     []*discordgo.MessageEmbed{},
+			Components: []discordgo.MessageComponent{},
+		}, nil
+	}
+
+	// Discord allows 5 rows of 5 buttons
+	const maxGroups = 25
+	var desc strings.Builder
+	var rows []discordgo.MessageComponent
+	var row discordgo.ActionsRow
+	for idx, g := range groups {
+		if idx == maxGroups {
+			desc.WriteString(fmt.Sprintf("…and %d more\n", len(groups)-maxGroups))
+			break
+		}
+		desc.WriteString(fmt.Sprintf("**%s** • %d campgrounds\n", sanitizeGenericText(g.Name), len(g.Campgrounds)))
+		row.Components = append(row.Components, discordgo.Button{
+			Label:    truncateLabel("Delete "+g.Name, 80),
+			Style:    discordgo.DangerButton,
+			CustomID: fmt.Sprintf("%s%s:%d", groupDeleteID, uid, g.ID),
+		})
+		if len(row.Components) == 5 {
+			rows = append(rows, row)
+			row = discordgo.ActionsRow{}
+		}
+	}
+	if len(row.Components) > 0 {
+		rows = append(rows, row)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("🗂️ Your %d groups", len(groups)),
+		Description: desc.String(),
+		Color:       0xc47331,
+	}
+	return &discordgo.InteractionResponseData{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: rows,
+	}, nil
+}
+
+// handleGroupDeleteComponent deletes a group from a groups list button and re-renders the list.
+// The custom ID is group_delete:<uid>:<groupID> so only the owner can use it.
+func (b *Bot) handleGroupDeleteComponent(s *discordgo.Session, i *discordgo.InteractionCreate, customID string) {
+	uid := getUserID(i)
+	owner, rawID, ok := strings.Cut(strings.TrimPrefix(customID, groupDeleteID), ":")
+	if !ok || owner != uid {
+		respond(s, i, "that button isn't for you")
+		return
+	}
+	groupID, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		respond(s, i, "invalid group")
+		return
+	}
+	if err := b.store.DeleteGroup(context.Background(), groupID, uid); err != nil {
+		respond(s, i, "error: "+err.Error())
+		return
+	}
+	b.logger.Info("deleted group", "user_id", uid, "group_id", groupID)
+
+	data, err := b.groupsMessage(uid)
+	if err != nil {
+		respond(s, i, "group deleted, but failed to refresh list: "+err.Error())
+		return
+	}
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: data,
+	})
+	if err != nil {
+		b.logger.Warn("failed to update groups list", "error", err)
+	}
+}
+
+// truncateLabel shortens a button label to Discord's limit.
+func truncateLabel(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-1]) + "…"
+}
@@ -3,44 +3,28 @@ package bot
 import (
 	"context"
 	"fmt"
-	"sort"
 	"strings"
 	"time"
 
+	"github.com/brensch/schniffer/internal/db"
 	"github.com/bwmarrin/discordgo"
 )
 
 // handleListCommand prints, for each active schniff owned by the user:
 // - number of checks in the last 24 hours (for that campground)
 // - number of notifications in the last 24 hours (for that request)
-// - latest per-date availability counts within the schniff date range
+// - a one-line summary of how many nights in the schniff date range have a free site
 func (b *Bot) handleListCommand(s *discordgo.Session, i *discordgo.InteractionCreate, _ *discordgo.ApplicationCommandInteractionDataOption) {
 	uid := getUserID(i)
-	reqs, err := b.store.ListUserActiveRequests(context.Background(), uid)
+	items, err := b.store.ListUserActiveRequestsDetailed(context.Background(), uid)
 	if err != nil {
 		respond(s, i, "error: "+err.Error())
 		return
 	}
-	// Filter to user and keep stable order by created_at via ID ascending
-	type item struct {
-		id                int64
-		provider          string
-		campgroundID      string
-		checkin, checkout time.Time
-		created           time.Time
-	}
-	items := make([]item, 0)
-	for _, r := range reqs {
-		if r.UserID != uid || !r.Active {
-			continue
-		}
-		items = append(items, item{id: r.ID, provider: r.Provider, campgroundID: r.CampgroundID, checkin: r.Checkin, checkout: r.Checkout, created: r.CreatedAt})
-	}
 	if len(items) == 0 {
 		respond(s, i, "no active schniffs")
 		return
 	}
-	sort.Slice(items, func(a, b int) bool { return items[a].id < items[b].id })
 
 	// We'll defer initial ack for longer responses (ephemeral)
 	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -53,11 +37,11 @@ func (b *Bot) handleListCommand(s *discordgo.Session, i *discordgo.InteractionCr
 	embeds := make([]*discordgo.MessageEmbed, 0, len(items))
 	for _, it := range items {
 		// display name with link
-		name := b.formatCampgroundWithLink(context.Background(), it.provider, it.campgroundID, it.campgroundID)
+		name := b.linkCampground(it.Provider, it.CampgroundID, it.CampgroundName)
 
-		nights := int(it.checkout.Sub(it.checkin).Hours() / 24)
+		nights := int(it.Checkout.Sub(it.Checkin).Hours() / 24)
 		// total checks for this campground since the request was created
-		totalChecks, err := b.store.CountLookupsSinceTime(context.Background(), it.provider, it.campgroundID, it.created)
+		totalChecks, err := b.store.CountLookupsSinceTime(context.Background(), it.Provider, it.CampgroundID, it.CreatedAt)
 		if err != nil {
 			b.logger.Warn("count request checks failed", "err", err)
 			totalChecks = 0
@@ -65,7 +49,28 @@ func (b *Bot) handleListCommand(s *discordgo.Session, i *discordgo.InteractionCr
 		// Build description in the required format but inside an embed
 		desc := strings.Builder{}
 		desc.WriteString(name + "\n")
-		desc.WriteString(fmt.Sprintf("%s (%s) -> %s (%s) (%d nights)\n", it.checkin.Format("2006-01-02"), weekday(it.checkin), it.checkout.Format("2006-01-02"), weekday(it.checkout), nights))
+		if it.Paused {
+			desc.WriteString("⏸️ paused, use `/schniff resume` to check it again\n")
+		}
+		desc.WriteString(fmt.Sprintf("%s (%s) -> %s (%s) (%d nights)\n", it.Checkin.Format("2006-01-02"), weekday(it.Checkin), it.Checkout.Format("2006-01-02"), weekday(it.Checkout), nights))
+		if it.NightFilter == db.NightsWeekends || it.NightFilter == db.NightsWeekdays {
+			desc.WriteString(fmt.Sprintf("%s only\n", it.NightFilter))
+		}
+		if it.FlexDays > 0 {
+			desc.WriteString(fmt.Sprintf("also watching %d days either side\n", it.FlexDays))
+		}
+		if it.MinNights > 1 {
+			desc.WriteString(fmt.Sprintf("stays of %d+ nights\n", it.MinNights))
+		}
+		if it.ReservableOnly {
+			desc.WriteString("reservable sites only\n")
+		}
+		byDate, err := b.store.LatestAvailabilityByDate(context.Background(), it.Provider, it.CampgroundID, it.Checkin, it.Checkout.AddDate(0, 0, -1))
+		if err != nil {
+			b.logger.Warn("availability summary failed", "err", err)
+		} else {
+			desc.WriteString(summarizeAvailability(it.Checkin, it.Checkout, it.NightFilter, byDate) + "\n")
+		}
 		desc.WriteString(fmt.Sprintf("total api calls: %d\n", totalChecks))
 
 		embeds = append(embeds, &discordgo.MessageEmbed{
@@ -88,3 +93,33 @@ func (b *Bot) handleListCommand(s *discordgo.Session, i *discordgo.InteractionCr
 		}
 	}
 }
+
+// summarizeAvailability condenses per-date availability into a single line, e.g.
+// "3/21 nights have at least one free site", so long windows stay within embed limits. Only the
+// nights in [checkin, checkout) that pass the schniff's night filter are counted.
+func summarizeAvailability(checkin, checkout time.Time, nightFilter string, byDate []db.AvailabilityByDate) string {
+	nights := 0
+	for d := checkin; d.Before(checkout); d = d.AddDate(0, 0, 1) {
+		if db.NightMatches(nightFilter, d) {
+			nights++
+		}
+	}
+	checked, open := 0, 0
+	for _, d := range byDate {
+		if !db.NightMatches(nightFilter, d.Date) {
+			continue
+		}
+		checked++
+		if d.Free > 0 {
+			open++
+		}
+	}
+	if checked == 0 {
+		return "no availability data yet"
+	}
+	summary := fmt.Sprintf("%d/%d nights have at least one free site", open, nights)
+	if unchecked := nights - checked; unchecked > 0 {
+		summary += fmt.Sprintf(" (%d not checked yet)", unchecked)
+	}
+	return summary
+}
@@ -0,0 +1,37 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+)
+
+func TestSummarizeAvailability(t *testing.T) {
+	date := func(d int) time.Time { return time.Date(2025, 7, d, 0, 0, 0, 0, time.UTC) } // Jul 4 2025 is a Friday
+	day := func(d, free int) db.AvailabilityByDate {
+		return db.AvailabilityByDate{Date: date(d), Total: 10, Free: free}
+	}
+
+	cases := []struct {
+		name        string
+		checkout    int
+		nightFilter string
+		byDate      []db.AvailabilityByDate
+		want        string
+	}{
+		{"no data", 6, db.NightsAny, nil, "no availability data yet"},
+		{"all checked", 4, db.NightsAny, []db.AvailabilityByDate{day(1, 2), day(2, 0), day(3, 1)}, "2/3 nights have at least one free site"},
+		{"partially checked", 22, db.NightsAny, []db.AvailabilityByDate{day(1, 0), day(2, 4)}, "1/21 nights have at least one free site (19 not checked yet)"},
+		// only the Friday and Saturday nights count, and the free weekday is ignored
+		{"weekends", 7, db.NightsWeekends, []db.AvailabilityByDate{day(3, 5), day(4, 1), day(5, 0)}, "1/2 nights have at least one free site"},
+		{"weekends unchecked", 7, db.NightsWeekends, []db.AvailabilityByDate{day(3, 5)}, "no availability data yet"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := summarizeAvailability(date(1), date(tc.checkout), tc.nightFilter, tc.byDate); got != tc.want {
+				t.Errorf("summarizeAvailability() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
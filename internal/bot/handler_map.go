@@ -2,16 +2,35 @@ package bot
 
 import (
 	"fmt"
+	"net/url"
 
 	"github.com/bwmarrin/discordgo"
 )
 
+// LinkSigner turns a Discord user ID into the token the web server accepts as proof of identity.
+type LinkSigner interface {
+	IssueUserToken(userID string) string
+}
+
+// SetLinkSigner makes web links carry a short-lived signed user token rather than the raw user ID.
+func (b *Bot) SetLinkSigner(s LinkSigner) {
+	b.linkSigner = s
+}
+
+// userToken returns the ?user= value for uid's web links.
+func (b *Bot) userToken(uid string) string {
+	if b.linkSigner == nil {
+		return uid
+	}
+	return b.linkSigner.IssueUserToken(uid)
+}
+
 func (b *Bot) handleLinkMapCommand(s *discordgo.Session, i *discordgo.InteractionCreate, sub *discordgo.ApplicationCommandInteractionDataOption) {
 	uid := getUserID(i)
 
 	// Create the URL with the user's token and welcome parameter
 	baseURL := "https://schniff.snek2.ddns.net"
-	groupCreationURL := fmt.Sprintf("%s/?user=%s&welcome=true", baseURL, uid)
+	groupCreationURL := fmt.Sprintf("%s/?user=%s&welcome=true", baseURL, url.QueryEscape(b.userToken(uid)))
 
 	// Create an embed with the link
 	embed := &discordgo.MessageEmbed{
@@ -50,6 +69,6 @@ func (b *Bot) handleLinkMapCommand(s *discordgo.Session, i *discordgo.Interactio
 	})
 
 	if err != nil {
-		b.logger.Warn("failed to respond to creategroup command", "error", err)
+		b.logger.Warn("failed to respond to map command", "error", err)
 	}
 }
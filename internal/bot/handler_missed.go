@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const missedOpeningsDays = 7
+
+// handleMissedCommand lists campsites that opened and were booked again within the user's
+// schniff windows over the last week.
+func (b *Bot) handleMissedCommand(s *discordgo.Session, i *discordgo.InteractionCreate, _ *discordgo.ApplicationCommandInteractionDataOption) {
+	uid := getUserID(i)
+	missed, err := b.store.GetMissedOpenings(context.Background(), uid, missedOpeningsDays)
+	if err != nil {
+		respond(s, i, "error: "+err.Error())
+		return
+	}
+	if len(missed) == 0 {
+		respond(s, i, fmt.Sprintf("no missed openings in the last %d days", missedOpeningsDays))
+		return
+	}
+
+	// Keep the embed within Discord's description limit
+	const maxLines = 25
+	var desc strings.Builder
+	for idx, m := range missed {
+		if idx == maxLines {
+			desc.WriteString(fmt.Sprintf("…and %d more\n", len(missed)-maxLines))
+			break
+		}
+		name := sanitizeGenericText(m.CampgroundName)
+		if url := b.campsiteURL(m.Provider, m.CampgroundID, m.CampsiteID); url != "" {
+			name = fmt.Sprintf("[%s](%s)", name, url)
+		}
+		desc.WriteString(fmt.Sprintf("%s • site %s • %s • open %s (missed it!)\n",
+			name, m.CampsiteID, m.Date.Format("Mon 2006-01-02"), formatOpenDuration(m.Open())))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("👻 %d missed openings in the last %d days", len(missed), missedOpeningsDays),
+		Description: desc.String(),
+		Color:       0xc47331,
+	}
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		b.logger.Warn("failed to respond to missed command", "error", err)
+	}
+}
@@ -0,0 +1,75 @@
+package bot
+
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// AvailableNow renders the campsite/nights open right now across a user's schniffs for
+// /schniff now, filtered the way their notifications are.
+type AvailableNow interface {
+	AvailableNow(ctx context.Context, userID string) ([]*discordgo.MessageEmbed, error)
+}
+
+// SetAvailableNow enables /schniff now.
+func (b *Bot) SetAvailableNow(a AvailableNow) {
+	b.availableNow = a
+}
+
+// handleNowCommand shows what can be booked right now across all of the caller's active schniffs,
+// without waiting for the next notification.
+func (b *Bot) handleNowCommand(s *discordgo.Session, i *discordgo.InteractionCreate, _ *discordgo.ApplicationCommandInteractionDataOption) {
+	if b.availableNow == nil {
+		respond(s, i, "now isn't available")
+		return
+	}
+	ctx := context.Background()
+	uid := getUserID(i)
+	reqs, err := b.store.ListUserActiveRequestsDetailed(ctx, uid)
+	if err != nil {
+		respond(s, i, "error: "+err.Error())
+		return
+	}
+	if len(reqs) == 0 {
+		respond(s, i, "no active schniffs")
+		return
+	}
+
+	// Looking up every schniff can take a moment, so defer the reply
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	})
+
+	embeds, err := b.availableNow.AvailableNow(ctx, uid)
+	if err != nil {
+		b.logger.Warn("available now failed", "userID", uid, "err", err)
+		_, _ = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: "error: " + err.Error(),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		})
+		return
+	}
+	if len(embeds) == 0 {
+		_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: "Nothing open right now across your schniffs.",
+			Flags:   discordgo.MessageFlagsEphemeral,
+		})
+		if err != nil {
+			b.logger.Warn("failed to respond to now command", "error", err)
+		}
+		return
+	}
+	// Each embed fits Discord's per-message size limit on its own, so send them one at a time
+	for _, embed := range embeds {
+		_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		})
+		if err != nil {
+			b.logger.Warn("failed to respond to now command", "error", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handlePauseCommand pauses or resumes one of the user's schniffs. Paused schniffs keep their
+// place in /schniff list but aren't checked until resumed.
+func (b *Bot) handlePauseCommand(s *discordgo.Session, i *discordgo.InteractionCreate, sub *discordgo.ApplicationCommandInteractionDataOption) {
+	opts := optMap(sub.Options)
+	opt, ok := opts["ids"]
+	if !ok || opt == nil {
+		respond(s, i, "schniff ID is required")
+		return
+	}
+	id := opt.IntValue()
+	resume := sub.Name == "resume"
+	req, ok := b.ownedRequest(s, i, id)
+	if !ok {
+		return
+	}
+	switch {
+	case !req.Active:
+		respond(s, i, fmt.Sprintf("schniff #%d has been removed, use `/schniff restore` to bring it back", id))
+		return
+	case resume && !req.Paused:
+		respond(s, i, fmt.Sprintf("schniff #%d isn't paused", id))
+		return
+	case !resume && req.Paused:
+		respond(s, i, fmt.Sprintf("schniff #%d is already paused", id))
+		return
+	}
+	if err := b.store.SetRequestActive(context.Background(), id, getUserID(i), resume); err != nil {
+		respond(s, i, "error: "+err.Error())
+		return
+	}
+	if resume {
+		respond(s, i, fmt.Sprintf("resumed schniff #%d", id))
+		return
+	}
+	respond(s, i, fmt.Sprintf("paused schniff #%d, use `/schniff resume` to bring it back", id))
+}
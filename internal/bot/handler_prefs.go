@@ -0,0 +1,136 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/httpx"
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	minRenotifyAfter = 0.0
+	minMaxPerDay     = 1.0
+	minPriceBelow    = 0.0
+)
+
+// handlePrefsCommand updates the user's notification preferences, then shows them. With no options
+// it only shows the current settings. Setting quiet hours turns them on unless quiet_hours says otherwise.
+func (b *Bot) handlePrefsCommand(s *discordgo.Session, i *discordgo.InteractionCreate, sub *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx := context.Background()
+	uid := getUserID(i)
+	opts := optMap(sub.Options)
+
+	prefs, err := b.store.GetNotificationPrefs(ctx, uid)
+	if err != nil {
+		respond(s, i, "error: "+err.Error())
+		return
+	}
+
+	if len(sub.Options) > 0 {
+		if o, ok := opts["quiet_start"]; ok && o != nil {
+			if _, err := db.ParseClock(o.StringValue()); err != nil {
+				respond(s, i, err.Error())
+				return
+			}
+			prefs.QuietStart = o.StringValue()
+			prefs.Enabled = true
+		}
+		if o, ok := opts["quiet_end"]; ok && o != nil {
+			if _, err := db.ParseClock(o.StringValue()); err != nil {
+				respond(s, i, err.Error())
+				return
+			}
+			prefs.QuietEnd = o.StringValue()
+			prefs.Enabled = true
+		}
+		if o, ok := opts["timezone"]; ok && o != nil {
+			if _, err := time.LoadLocation(o.StringValue()); err != nil {
+				respond(s, i, fmt.Sprintf("unknown timezone %q, use a name like America/Los_Angeles", o.StringValue()))
+				return
+			}
+			prefs.Timezone = o.StringValue()
+		}
+		if o, ok := opts["quiet_hours"]; ok && o != nil {
+			prefs.Enabled = o.BoolValue()
+		}
+		if o, ok := opts["digest"]; ok && o != nil {
+			prefs.Digest = o.BoolValue()
+		}
+		if o, ok := opts["webhook"]; ok && o != nil {
+			webhook, err := parseWebhookURL(o.StringValue())
+			if err != nil {
+				respond(s, i, err.Error())
+				return
+			}
+			prefs.WebhookURL = webhook
+		}
+		if o, ok := opts["renotify_after"]; ok && o != nil {
+			prefs.RenotifyAfter = time.Duration(o.IntValue()) * time.Minute
+		}
+		if o, ok := opts["max_per_day"]; ok && o != nil {
+			prefs.MaxPerDay = int(o.IntValue())
+		}
+		if o, ok := opts["price_below"]; ok && o != nil {
+			prefs.PriceAlertBelow = o.FloatValue()
+		}
+		if prefs.Enabled && (prefs.QuietStart == "" || prefs.QuietEnd == "") {
+			respond(s, i, "set both quiet_start and quiet_end to use quiet hours")
+			return
+		}
+		if err := b.store.UpsertNotificationPrefs(ctx, prefs); err != nil {
+			respond(s, i, "error: "+err.Error())
+			return
+		}
+	}
+
+	respond(s, i, describePrefs(prefs))
+}
+
+// describePrefs renders the preferences for a Discord reply.
+func describePrefs(p db.NotificationPrefs) string {
+	quiet := "quiet hours: off, notifications are sent straight away"
+	if p.Enabled {
+		quiet = fmt.Sprintf("quiet hours: %s-%s (%s), notifications in that window are held and sent when it ends", p.QuietStart, p.QuietEnd, p.Timezone)
+	}
+	delivery := "digest: off, one message per schniff"
+	if p.Digest {
+		delivery = "digest: on, openings are combined into one message every 30 minutes"
+	}
+	webhook := "webhook: none"
+	if p.WebhookURL != "" {
+		webhook = "webhook: notifications are also POSTed to " + p.WebhookURL
+	}
+	renotify := "renotify: every time a site reopens"
+	if p.RenotifyAfter > 0 {
+		renotify = fmt.Sprintf("renotify: only when a site reopens after being booked for at least %d minutes", int(p.RenotifyAfter/time.Minute))
+	}
+	limit := fmt.Sprintf("limit: each schniff notifies at most %d times a day", p.DailyLimit())
+	price := "price alerts: off"
+	if p.PriceAlertBelow > 0 {
+		price = fmt.Sprintf("price alerts: DM when a schniffed site drops under $%.2f a night", p.PriceAlertBelow)
+	}
+	return quiet + "\n" + delivery + "\n" + webhook + "\n" + renotify + "\n" + limit + "\n" + price
+}
+
+// parseWebhookURL validates a webhook option. "off" clears it.
+func parseWebhookURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if strings.EqualFold(raw, "off") {
+		return "", nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", fmt.Errorf("webhook must be an http(s) URL, or off to remove it")
+	}
+	// Hostnames are checked again when the webhook is posted, once they've been resolved
+	if ip, err := netip.ParseAddr(u.Hostname()); (err == nil && !httpx.IsPublicAddr(ip)) || strings.EqualFold(u.Hostname(), "localhost") {
+		return "", fmt.Errorf("webhook must be on the public internet")
+	}
+	return u.String(), nil
+}
@@ -2,8 +2,10 @@ package bot
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"strconv"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -14,53 +16,149 @@ func (b *Bot) handleRemoveCommand(s *discordgo.Session, i *discordgo.Interaction
 	opt, ok := opts["ids"]
 	if ok && opt != nil {
 		id := int64(opt.IntValue())
+		req, ok := b.ownedRequest(s, i, id)
+		if !ok {
+			return
+		}
+		if !req.Active {
+			respond(s, i, fmt.Sprintf("schniff #%d is already removed", id))
+			return
+		}
 		err := b.store.DeactivateRequest(context.Background(), id, uid)
 		if err != nil {
 			respond(s, i, "error: "+err.Error())
 			return
 		}
-		respond(s, i, "removed")
+		respond(s, i, fmt.Sprintf("removed schniff #%d for %s to %s", id, req.Checkin.Format("2006-01-02"), req.Checkout.Format("2006-01-02")))
 		return
 	}
 
-	// No ID provided, remove all schniffs
-	reqs, err := b.store.ListActiveRequests(context.Background())
+	// No ID provided, ask for confirmation before removing all of the user's schniffs
+	reqs, err := b.store.ListUserActiveRequests(context.Background(), uid)
 	if err != nil {
 		b.logger.Warn("list active reqs failed", "err", err)
 		respond(s, i, "failed to get schniffs to remove")
 		return
 	}
+	if len(reqs) == 0 {
+		respond(s, i, "no active schniffs")
+		return
+	}
 
-	for _, r := range reqs {
-		err = b.store.DeactivateRequest(context.Background(), r.ID, r.UserID)
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("This will remove all %d of your active schniffs. Are you sure?", len(reqs)),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Label:    fmt.Sprintf("Remove %d schniffs", len(reqs)),
+							Style:    discordgo.DangerButton,
+							CustomID: removeAllConfirmID + uid,
+						},
+						discordgo.Button{
+							Label:    "Cancel",
+							Style:    discordgo.SecondaryButton,
+							CustomID: removeAllCancelID + uid,
+						},
+					},
+				},
+			},
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		b.logger.Warn("failed to send remove-all confirmation", "error", err)
+	}
+}
+
+// handleRemoveBeforeCommand removes the caller's schniffs that check out before a date, today for
+// remove-expired.
+func (b *Bot) handleRemoveBeforeCommand(s *discordgo.Session, i *discordgo.InteractionCreate, sub *discordgo.ApplicationCommandInteractionDataOption) {
+	before := time.Now().UTC()
+	if sub.Name == "remove-before" {
+		opt, ok := optMap(sub.Options)["date"]
+		if !ok || opt == nil {
+			respond(s, i, "date is required")
+			return
+		}
+		parsed, err := time.Parse("2006-01-02", opt.StringValue())
 		if err != nil {
-			respond(s, i, "error: "+err.Error())
+			respond(s, i, "invalid date, use YYYY-MM-DD")
 			return
 		}
-		slog.Info("Removed schniff for user", "id", r.ID, "user_id", r.UserID)
+		before = parsed
+	}
+
+	count, err := b.store.DeactivateUserRequestsBefore(context.Background(), getUserID(i), before)
+	if err != nil {
+		respond(s, i, "error: "+err.Error())
+		return
+	}
+	switch {
+	case count == 0:
+		respond(s, i, fmt.Sprintf("no active schniffs check out before %s", before.Format("2006-01-02")))
+	case count == 1:
+		respond(s, i, fmt.Sprintf("removed 1 schniff checking out before %s", before.Format("2006-01-02")))
+	default:
+		respond(s, i, fmt.Sprintf("removed %d schniffs checking out before %s", count, before.Format("2006-01-02")))
+	}
+}
+
+const (
+	removeAllConfirmID = "remove_all_confirm:"
+	removeAllCancelID  = "remove_all_cancel:"
+)
+
+// handleRemoveAllComponent completes or cancels a remove-all confirmation.
+// The custom ID carries the requesting user so nobody else can confirm on their behalf.
+func (b *Bot) handleRemoveAllComponent(s *discordgo.Session, i *discordgo.InteractionCreate, customID string) {
+	uid := getUserID(i)
+	var content string
+	switch {
+	case customID == removeAllCancelID+uid:
+		content = "cancelled, your schniffs are safe"
+	case customID == removeAllConfirmID+uid:
+		count, err := b.store.DeactivateUserRequests(context.Background(), uid)
+		if err != nil {
+			content = "error: " + err.Error()
+			break
+		}
+		slog.Info("Removed all schniffs for user", "user_id", uid, "count", count)
+		content = fmt.Sprintf("removed all %d schniffs", count)
+	default:
+		respond(s, i, "that button isn't for you")
+		return
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+	if err != nil {
+		b.logger.Warn("failed to update remove-all confirmation", "error", err)
 	}
-	respond(s, i, "removed all schniffs")
 }
 
 // autocompleteRemoveIDs suggests the caller's active schniffs as choices.
 func (b *Bot) autocompleteRemoveIDs(i *discordgo.InteractionCreate) []*discordgo.ApplicationCommandOptionChoice {
 	uid := getUserID(i)
-	reqs, err := b.store.ListActiveRequests(context.Background())
+	reqs, err := b.store.ListUserActiveRequestsDetailed(context.Background(), uid)
 	if err != nil {
 		b.logger.Warn("list active reqs failed", "err", err)
 		return nil
 	}
 	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, 25)
 	for _, r := range reqs {
-		if r.UserID != uid {
-			continue
-		}
-		name := r.CampgroundID
-		if cg, ok, _ := b.store.GetCampgroundByID(context.Background(), r.Provider, r.CampgroundID); ok {
-			name = cg.Name
-		}
 		label := r.Checkin.Format("2006-01-02") + "→" + r.Checkout.Format("2006-01-02")
-		display := sanitizeGenericText(label + " • " + name)
+		if r.Paused {
+			label = "⏸️ " + label
+		}
+		display := sanitizeGenericText(label + " • " + r.CampgroundName)
 		value := sanitizeChoiceValue(strconv.FormatInt(r.ID, 10))
 		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: display, Value: value})
 		if len(choices) >= 25 {
@@ -0,0 +1,57 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleRestoreCommand brings back one of the user's removed or expired schniffs for new dates.
+func (b *Bot) handleRestoreCommand(s *discordgo.Session, i *discordgo.InteractionCreate, sub *discordgo.ApplicationCommandInteractionDataOption) {
+	opts := optMap(sub.Options)
+	opt, ok := opts["ids"]
+	if !ok || opt == nil || opt.IntValue() == 0 {
+		respond(s, i, "schniff ID is required")
+		return
+	}
+	checkin, checkinOK := opts["checkin"]
+	checkout, checkoutOK := opts["checkout"]
+	if !checkinOK || checkin == nil || !checkoutOK || checkout == nil {
+		respond(s, i, "check-in and check-out dates are required")
+		return
+	}
+	start, end, err := parseDates(checkin.StringValue(), checkout.StringValue())
+	if err != nil {
+		respond(s, i, "invalid dates: "+err.Error())
+		return
+	}
+
+	id := opt.IntValue()
+	if err := b.store.ReactivateRequest(context.Background(), id, getUserID(i), start, end); err != nil {
+		respond(s, i, "error: "+err.Error())
+		return
+	}
+	respond(s, i, fmt.Sprintf("restored schniff #%d for %s to %s", id, start.Format("2006-01-02"), end.Format("2006-01-02")))
+}
+
+// autocompleteRestoreIDs suggests the caller's recently removed or expired schniffs as choices.
+func (b *Bot) autocompleteRestoreIDs(i *discordgo.InteractionCreate) []*discordgo.ApplicationCommandOptionChoice {
+	reqs, err := b.store.ListUserInactiveRequests(context.Background(), getUserID(i), 25)
+	if err != nil {
+		b.logger.Warn("list inactive reqs failed", "err", err)
+		return nil
+	}
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(reqs))
+	for _, r := range reqs {
+		label := r.Checkin.Format("2006-01-02") + "→" + r.Checkout.Format("2006-01-02")
+		display := sanitizeGenericText(label + " • " + r.CampgroundName)
+		value := sanitizeChoiceValue(strconv.FormatInt(r.ID, 10))
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: display, Value: value})
+	}
+	if len(choices) == 0 {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: "No removed or expired schniffs", Value: "0"})
+	}
+	return choices
+}
@@ -0,0 +1,27 @@
+package bot
+
+import (
+	"context"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleStatsCommand shows the caller their own schniffing history.
+func (b *Bot) handleStatsCommand(s *discordgo.Session, i *discordgo.InteractionCreate, _ *discordgo.ApplicationCommandInteractionDataOption) {
+	stats, err := b.store.GetUserStats(context.Background(), getUserID(i))
+	if err != nil {
+		respond(s, i, "Failed to get stats: "+err.Error())
+		return
+	}
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{db.MakeUserStatsEmbed(stats)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		b.logger.Warn("failed to respond to stats command", "error", err)
+	}
+}
@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// PollStatus reports the manager's live polling state for /schniff status.
+type PollStatus interface {
+	PollIntervals() map[string]time.Duration
+}
+
+// SetPollStatus lets /schniff status report current polling intervals.
+func (b *Bot) SetPollStatus(p PollStatus) {
+	b.pollStatus = p
+}
+
+// handleStatusCommand shows whether the schniffer is alive: providers, last successful poll,
+// active schniffs and polling intervals.
+func (b *Bot) handleStatusCommand(s *discordgo.Session, i *discordgo.InteractionCreate, _ *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx := context.Background()
+	lastLookups, err := b.store.LastSuccessfulLookups(ctx)
+	if err != nil {
+		respond(s, i, "error: "+err.Error())
+		return
+	}
+	active, lookupsToday, notificationsToday, err := b.store.StatsToday(ctx)
+	if err != nil {
+		respond(s, i, "error: "+err.Error())
+		return
+	}
+	var intervals map[string]time.Duration
+	if b.pollStatus != nil {
+		intervals = b.pollStatus.PollIntervals()
+	}
+
+	names := b.registry.GetProviderNames()
+	sort.Strings(names)
+	var desc strings.Builder
+	for _, name := range names {
+		last := "never"
+		if t, ok := lastLookups[name]; ok {
+			last = formatOpenDuration(time.Since(t)) + " ago"
+		}
+		line := fmt.Sprintf("**%s** • last poll %s", name, last)
+		if interval, ok := intervals[name]; ok {
+			line += fmt.Sprintf(" • every %s", interval)
+		}
+		desc.WriteString(line + "\n")
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "🐽 Schniffer status",
+		Description: desc.String(),
+		Color:       0xc47331,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Active schniffs", Value: fmt.Sprintf("%d", active), Inline: true},
+			{Name: "Lookups today", Value: fmt.Sprintf("%d", lookupsToday), Inline: true},
+			{Name: "Notifications today", Value: fmt.Sprintf("%d", notificationsToday), Inline: true},
+		},
+	}
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		b.logger.Warn("failed to respond to status command", "error", err)
+	}
+}
@@ -0,0 +1,73 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// SetAdmins sets the Discord user IDs allowed to run admin subcommands such as tag-add.
+func (b *Bot) SetAdmins(userIDs ...string) {
+	b.admins = make(map[string]bool)
+	for _, id := range userIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			b.admins[id] = true
+		}
+	}
+}
+
+func (b *Bot) isAdmin(i *discordgo.InteractionCreate) bool {
+	return b.admins[getUserID(i)]
+}
+
+// handleTagCommand adds or removes a curated tag on a campground. Admin only.
+func (b *Bot) handleTagCommand(s *discordgo.Session, i *discordgo.InteractionCreate, sub *discordgo.ApplicationCommandInteractionDataOption) {
+	if !b.isAdmin(i) {
+		respond(s, i, "only admins can manage tags")
+		return
+	}
+	opts := optMap(sub.Options)
+	campgroundResponse, ok := opts["campground"]
+	if !ok || campgroundResponse == nil {
+		respond(s, i, "campground selection is required")
+		return
+	}
+	tagResponse, ok := opts["tag"]
+	if !ok || tagResponse == nil {
+		respond(s, i, "tag is required")
+		return
+	}
+
+	parts := strings.SplitN(campgroundResponse.StringValue(), "||", 3)
+	if len(parts) != 3 {
+		respond(s, i, "invalid campground selection")
+		return
+	}
+	provider, campgroundID, name := parts[0], parts[1], parts[2]
+	tag := tagResponse.StringValue()
+
+	ctx := context.Background()
+	var err error
+	if sub.Name == "tag-add" {
+		err = b.store.AddCampgroundTag(ctx, provider, campgroundID, tag, getUserID(i))
+	} else {
+		err = b.store.RemoveCampgroundTag(ctx, provider, campgroundID, tag)
+	}
+	if err != nil {
+		respond(s, i, "error: "+err.Error())
+		return
+	}
+
+	tags, err := b.store.GetCampgroundTags(ctx, provider, campgroundID)
+	if err != nil {
+		respond(s, i, "error: "+err.Error())
+		return
+	}
+	current := "none"
+	if len(tags) > 0 {
+		current = strings.Join(tags, ", ")
+	}
+	respond(s, i, fmt.Sprintf("%s tags: %s", b.linkCampground(provider, campgroundID, name), current))
+}
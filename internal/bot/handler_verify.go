@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/brensch/schniffer/internal/manager"
+	"github.com/bwmarrin/discordgo"
+)
+
+const defaultVerifyDays = 30
+
+// minVerifyDays is the lower bound for the days option; discordgo takes it by pointer.
+var minVerifyDays = 1.0
+
+// Verifier compares stored availability to a live fetch for /schniff verify.
+type Verifier interface {
+	VerifyCampground(ctx context.Context, provider, campgroundID string, start, end time.Time) (manager.VerifyReport, error)
+}
+
+// SetVerifier enables the admin /schniff verify diagnostic.
+func (b *Bot) SetVerifier(v Verifier) {
+	b.verifier = v
+}
+
+// handleVerifyCommand fetches live availability for a campground and reports where it disagrees
+// with what's stored. Admin only, and read-only.
+func (b *Bot) handleVerifyCommand(s *discordgo.Session, i *discordgo.InteractionCreate, sub *discordgo.ApplicationCommandInteractionDataOption) {
+	if !b.isAdmin(i) {
+		respond(s, i, "only admins can verify campgrounds")
+		return
+	}
+	if b.verifier == nil {
+		respond(s, i, "verify isn't available")
+		return
+	}
+	opts := optMap(sub.Options)
+	campgroundResponse, ok := opts["campground"]
+	if !ok || campgroundResponse == nil {
+		respond(s, i, "campground selection is required")
+		return
+	}
+	parts := strings.SplitN(campgroundResponse.StringValue(), "||", 3)
+	if len(parts) != 3 {
+		respond(s, i, "invalid campground selection")
+		return
+	}
+	provider, campgroundID, name := parts[0], parts[1], parts[2]
+	days := defaultVerifyDays
+	if o, ok := opts["days"]; ok && o != nil && o.IntValue() > 0 {
+		days = int(o.IntValue())
+	}
+
+	// the live fetch can take longer than Discord's 3s interaction window
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	})
+
+	start := time.Now().UTC()
+	report, err := b.verifier.VerifyCampground(context.Background(), provider, campgroundID, start, start.AddDate(0, 0, days-1))
+	if err != nil {
+		_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{Content: "error: " + err.Error(), Flags: discordgo.MessageFlagsEphemeral})
+		if err != nil {
+			b.logger.Warn("verify followup send failed", "err", err)
+		}
+		return
+	}
+
+	var desc strings.Builder
+	desc.WriteString(fmt.Sprintf("%s • %s → %s\n", b.linkCampground(provider, campgroundID, name),
+		report.Start.Format("2006-01-02"), report.End.Format("2006-01-02")))
+	desc.WriteString(fmt.Sprintf("compared %d site-dates: %d differ, %d live but not stored, %d stored but not live\n",
+		report.Compared, report.Differing, report.MissingLocal, report.MissingLive))
+	for _, mm := range report.Samples {
+		desc.WriteString(fmt.Sprintf("site %s • %s • stored %s, live %s\n",
+			mm.CampsiteID, mm.Date.Format("Mon 2006-01-02"), describeState(mm.Stored), describeState(mm.Live)))
+	}
+	if more := report.Mismatches() - len(report.Samples); more > 0 {
+		desc.WriteString(fmt.Sprintf("…and %d more\n", more))
+	}
+
+	title := "✅ Stored availability matches"
+	if report.Mismatches() > 0 {
+		title = fmt.Sprintf("⚠️ %d availability mismatches", report.Mismatches())
+	}
+	embed := &discordgo.MessageEmbed{Title: title, Description: desc.String(), Color: 0xc47331}
+	_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{Embeds: []*discordgo.MessageEmbed{embed}, Flags: discordgo.MessageFlagsEphemeral})
+	if err != nil {
+		b.logger.Warn("verify followup send failed", "err", err)
+	}
+}
+
+func describeState(available *bool) string {
+	switch {
+	case available == nil:
+		return "missing"
+	case *available:
+		return "open"
+	default:
+		return "booked"
+	}
+}
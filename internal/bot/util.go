@@ -7,6 +7,7 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"github.com/brensch/schniffer/internal/db"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -93,6 +94,21 @@ func sanitizeChoiceValue(s string) string {
 	return s[:outputMaxLength]
 }
 
+// ownedRequest loads one of the caller's schniffs. If it can't, it responds to the interaction
+// and returns false, answering the same for missing schniffs and other users' schniffs.
+func (b *Bot) ownedRequest(s *discordgo.Session, i *discordgo.InteractionCreate, id int64) (db.SchniffRequest, bool) {
+	req, found, err := b.store.GetRequestByID(context.Background(), id)
+	if err != nil {
+		respond(s, i, "error: "+err.Error())
+		return db.SchniffRequest{}, false
+	}
+	if !found || req.UserID != getUserID(i) {
+		respond(s, i, fmt.Sprintf("schniff #%d not found", id))
+		return db.SchniffRequest{}, false
+	}
+	return req, true
+}
+
 // formatCampgroundWithLink returns a formatted campground name with a link if available.
 // If a campground URL can be generated, it creates a markdown link format.
 func (b *Bot) formatCampgroundWithLink(ctx context.Context, provider, campgroundID, fallbackName string) string {
@@ -106,16 +122,44 @@ func (b *Bot) formatCampgroundWithLink(ctx context.Context, provider, campground
 		name = cg.Name
 	}
 
-	providerInterface, ok := b.registry.Get(cg.Provider)
+	return b.linkCampground(cg.Provider, cg.ID, name)
+}
+
+// linkCampground formats an already-known campground name as a markdown link when the provider can build one.
+func (b *Bot) linkCampground(provider, campgroundID, name string) string {
+	providerInterface, ok := b.registry.Get(provider)
 	if !ok {
 		return name
 	}
 
 	// Get campground URL
-	url := providerInterface.CampgroundURL(cg.ID)
+	url := providerInterface.CampgroundURL(campgroundID)
 	if url != "" {
 		return fmt.Sprintf("[%s](%s)", name, url)
 	}
 
 	return name
 }
+
+// campsiteURL returns the provider's booking link for a campsite, or "" if the provider is unknown.
+func (b *Bot) campsiteURL(provider, campgroundID, campsiteID string) string {
+	p, ok := b.registry.Get(provider)
+	if !ok || p == nil {
+		return ""
+	}
+	return p.CampsiteURL(campgroundID, campsiteID)
+}
+
+// formatOpenDuration renders how long something stayed open in a compact human form.
+func formatOpenDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
@@ -0,0 +1,59 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultWelcomeMessage is DMed to new guild members unless overridden with SetWelcomeMessage.
+// The list of available commands is appended automatically.
+const DefaultWelcomeMessage = `**Hello schniffist**
+
+Congratulations! Being a schniffist is an honour.
+
+**How to schniff**
+
+👃 Add a schniff
+
+⏰ Wait
+
+🔍 I find you a campsite
+
+📨 I send you a message, you click the link to the freed website, and then book it
+
+Send all your commands directly to me privately (ie not in the schniffer channel).
+
+**Why can you find campsites that are free when they're all booked right now?**
+People make plans, those plans change. They cancel their booking. They normally do it on sunday night for some reason. I don't know why, i'm not a human and don't do human stuff, i'm a schniffer.`
+
+// SetWelcomeMessage overrides the welcome DM text for this deployment. Empty restores the default.
+func (b *Bot) SetWelcomeMessage(msg string) {
+	b.welcome = strings.TrimSpace(msg)
+}
+
+// welcomeMessage returns the welcome DM followed by the registered commands, so the
+// text can't drift from what is actually available.
+func (b *Bot) welcomeMessage() string {
+	msg := b.welcome
+	if msg == "" {
+		msg = DefaultWelcomeMessage
+	}
+	msg += "\n\n" + commandHelp()
+	// Discord rejects messages over 2000 characters
+	if r := []rune(msg); len(r) > 2000 {
+		msg = string(r[:1999]) + "…"
+	}
+	return msg
+}
+
+// commandHelp lists every registered subcommand with its description.
+func commandHelp() string {
+	var sb strings.Builder
+	sb.WriteString("**Commands**\n")
+	for _, cmd := range schniffCommands() {
+		for _, opt := range cmd.Options {
+			sb.WriteString(fmt.Sprintf("`/%s %s` - %s\n", cmd.Name, opt.Name, opt.Description))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestRequestAdhocScrape_ConcurrentIsIdempotent(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	const clicks = 10
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		created int
+		ids     = map[int]bool{}
+	)
+	for i := 0; i < clicks; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, ok, err := store.RequestAdhocScrape(ctx, "p", "cg1", "user", "user1")
+			if err != nil {
+				t.Errorf("RequestAdhocScrape failed: %v", err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if ok {
+				created++
+			}
+			ids[req.ID] = true
+		}()
+	}
+	wg.Wait()
+
+	if created != 1 {
+		t.Errorf("Expected exactly 1 created request, got %d", created)
+	}
+	if len(ids) != 1 {
+		t.Errorf("Expected all callers to get the same request, got ids %v", ids)
+	}
+
+	var count int
+	if err := store.DB.QueryRow(`SELECT COUNT(*) FROM adhoc_scrape_requests`).Scan(&count); err != nil {
+		t.Fatalf("count failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 stored request, got %d", count)
+	}
+
+	// A different campground is not debounced
+	_, ok, err := store.RequestAdhocScrape(ctx, "p", "cg2", "user", "user1")
+	if err != nil || !ok {
+		t.Errorf("Expected a new request for another campground, got created=%v err=%v", ok, err)
+	}
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BlockedCampground is a campground an admin has excluded from polling.
+type BlockedCampground struct {
+	Provider     string
+	CampgroundID string
+	Reason       string
+	CreatedBy    string
+	CreatedAt    time.Time
+}
+
+// BlockCampground excludes a campground from polling and returns the active schniffs on it,
+// which stay active but won't be polled until the campground is unblocked.
+// Blocking an already blocked campground updates the reason.
+func (s *Store) BlockCampground(ctx context.Context, provider, campgroundID, reason, createdBy string) ([]SchniffRequest, error) {
+	_, err := s.DB.ExecContext(ctx, `
+		INSERT INTO campground_blocklist(provider, campground_id, reason, created_by)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(provider, campground_id) DO UPDATE SET reason = excluded.reason
+	`, provider, campgroundID, reason, createdBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to block campground: %w", err)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT `+schniffRequestColumns+`
+		FROM schniff_requests
+		WHERE active = 1 AND provider = ? AND campground_id = ?
+		ORDER BY id
+	`, provider, campgroundID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blocked schniffs: %w", err)
+	}
+	defer rows.Close()
+
+	var out []SchniffRequest
+	for rows.Next() {
+		r, err := scanSchniffRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// UnblockCampground lets a campground be polled again.
+func (s *Store) UnblockCampground(ctx context.Context, provider, campgroundID string) error {
+	res, err := s.DB.ExecContext(ctx, `
+		DELETE FROM campground_blocklist WHERE provider = ? AND campground_id = ?
+	`, provider, campgroundID)
+	if err != nil {
+		return fmt.Errorf("failed to unblock campground: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("campground is not blocked")
+	}
+	return nil
+}
+
+// ListBlockedCampgrounds returns every blocked campground, newest first.
+func (s *Store) ListBlockedCampgrounds(ctx context.Context) ([]BlockedCampground, error) {
+	rows, err := s.ReadConnection().QueryContext(ctx, `
+		SELECT provider, campground_id, reason, coalesce(created_by, ''), created_at
+		FROM campground_blocklist
+		ORDER BY created_at DESC, provider, campground_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blocklist: %w", err)
+	}
+	defer rows.Close()
+
+	var out []BlockedCampground
+	for rows.Next() {
+		var b BlockedCampground
+		if err := rows.Scan(&b.Provider, &b.CampgroundID, &b.Reason, &b.CreatedBy, &b.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan blocked campground: %w", err)
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
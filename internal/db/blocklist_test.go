@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBlockCampground(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	checkin := normalizeDay(time.Now().AddDate(0, 0, 10))
+	for _, cg := range []string{"bad", "bad", "good"} {
+		_, err := store.AddRequest(ctx, SchniffRequest{UserID: "user1", Provider: "p", CampgroundID: cg, Checkin: checkin, Checkout: checkin.AddDate(0, 0, 2)})
+		if err != nil {
+			t.Fatalf("AddRequest failed: %v", err)
+		}
+	}
+
+	affected, err := store.BlockCampground(ctx, "p", "bad", "always 500s", "admin")
+	if err != nil {
+		t.Fatalf("BlockCampground failed: %v", err)
+	}
+	if len(affected) != 2 {
+		t.Fatalf("Expected 2 affected schniffs, got %d", len(affected))
+	}
+
+	// blocking again updates the reason rather than failing
+	if _, err := store.BlockCampground(ctx, "p", "bad", "closed for the season", "admin"); err != nil {
+		t.Fatalf("BlockCampground again failed: %v", err)
+	}
+	blocked, err := store.ListBlockedCampgrounds(ctx)
+	if err != nil {
+		t.Fatalf("ListBlockedCampgrounds failed: %v", err)
+	}
+	if len(blocked) != 1 || blocked[0].CampgroundID != "bad" || blocked[0].Reason != "closed for the season" {
+		t.Fatalf("Unexpected blocklist: %+v", blocked)
+	}
+
+	// schniffs stay active so they resume once unblocked
+	active, err := store.ListActiveRequests(ctx)
+	if err != nil {
+		t.Fatalf("ListActiveRequests failed: %v", err)
+	}
+	if len(active) != 3 {
+		t.Errorf("Expected blocked schniffs to stay active, got %d active", len(active))
+	}
+
+	if err := store.UnblockCampground(ctx, "p", "bad"); err != nil {
+		t.Fatalf("UnblockCampground failed: %v", err)
+	}
+	if err := store.UnblockCampground(ctx, "p", "bad"); err == nil {
+		t.Error("Expected unblocking twice to fail")
+	}
+	blocked, _ = store.ListBlockedCampgrounds(ctx)
+	if len(blocked) != 0 {
+		t.Errorf("Expected empty blocklist, got %+v", blocked)
+	}
+}
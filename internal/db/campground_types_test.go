@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRefreshCampgroundTypes(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	_, err := store.DB.Exec(`
+		INSERT INTO campsite_metadata(provider, campground_id, campsite_id, name, campsite_type, last_updated) VALUES
+			('p', 'cg1', 's1', 'Site 1', 'STANDARD', datetime('now')),
+			('p', 'cg1', 's2', 'Site 2', 'STANDARD', datetime('now')),
+			('p', 'cg1', 's3', 'Site 3', 'RV', datetime('now'));
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert fixtures: %v", err)
+	}
+
+	// refreshing twice must replace rather than duplicate rows
+	for i := 0; i < 2; i++ {
+		if err := store.RefreshCampgroundTypes(ctx); err != nil {
+			t.Fatalf("RefreshCampgroundTypes failed: %v", err)
+		}
+	}
+
+	types, err := store.getCampgroundTypesBatch(ctx, []string{"p:cg1"})
+	if err != nil {
+		t.Fatalf("getCampgroundTypesBatch failed: %v", err)
+	}
+	if got := types["p:cg1"]; len(got) != 2 || got[0] != "RV" || got[1] != "STANDARD" {
+		t.Errorf("Unexpected campground types: %v", got)
+	}
+}
+
+func TestBackfillCampsiteTypes(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	_, err := store.DB.Exec(`
+		INSERT INTO campgrounds(provider, campground_id, name, campsite_types, last_updated) VALUES
+			('p', 'cg1', 'Lakeside', '["cabin"]', datetime('now'));
+		INSERT INTO campsite_metadata(provider, campground_id, campsite_id, name, campsite_type, last_updated) VALUES
+			('p', 'cg1', 's1', 'Cabin 1', 'cabin', datetime('now')),
+			('p', 'cg1', 's2', 'Site 2', '', datetime('now')),
+			('p', 'cg1', 's3', 'Tent Loop 3', '', datetime('now')),
+			('p', 'cg1', 's4', 'Site 4', '', datetime('now'));
+		INSERT INTO campsite_equipment(provider, campground_id, campsite_id, equipment_type) VALUES
+			('p', 'cg1', 's1', 'tent'),
+			('p', 'cg1', 's2', 'RV'),
+			('p', 'cg1', 's3', 'RV');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert fixtures: %v", err)
+	}
+
+	n, err := store.BackfillCampsiteTypes(ctx)
+	if err != nil {
+		t.Fatalf("BackfillCampsiteTypes failed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Expected 2 campsites backfilled, got %d", n)
+	}
+
+	want := map[string]string{
+		"s1": "cabin", // explicit type kept even though equipment says tent
+		"s2": "rv",    // from equipment
+		"s3": "tent",  // name wins over equipment
+		"s4": "",      // nothing to go on
+	}
+	for id, typ := range want {
+		var got string
+		if err := store.DB.QueryRow(`SELECT campsite_type FROM campsite_metadata WHERE campsite_id = ?`, id).Scan(&got); err != nil {
+			t.Fatalf("query %s failed: %v", id, err)
+		}
+		if got != typ {
+			t.Errorf("campsite %s: got type %q, want %q", id, got, typ)
+		}
+	}
+
+	var types string
+	if err := store.DB.QueryRow(`SELECT campsite_types FROM campgrounds WHERE campground_id = 'cg1'`).Scan(&types); err != nil {
+		t.Fatalf("query campground failed: %v", err)
+	}
+	if types != `["cabin","rv","tent"]` && types != `["cabin","tent","rv"]` {
+		t.Errorf("Unexpected campground campsite_types: %s", types)
+	}
+}
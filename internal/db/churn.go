@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ChurnStats counts a campground's availability changes over a window. Campgrounds with lots of
+// churn are where cancellations actually turn up.
+type ChurnStats struct {
+	Provider       string
+	CampgroundID   string
+	CampgroundName string
+	Opens          int // campsite-nights that became available
+	Closes         int // campsite-nights that were booked
+}
+
+// Changes returns the total number of state changes, opens and closes together.
+func (c ChurnStats) Changes() int {
+	return c.Opens + c.Closes
+}
+
+// GetChurnStats counts the campground's state changes since the given time.
+func (s *Store) GetChurnStats(ctx context.Context, provider, campgroundID string, since time.Time) (ChurnStats, error) {
+	stats := ChurnStats{Provider: provider, CampgroundID: campgroundID}
+	err := s.ReadConnection().QueryRowContext(ctx, `
+		SELECT coalesce((SELECT name FROM campgrounds WHERE provider = ? AND campground_id = ?), ?),
+		       coalesce(SUM(CASE WHEN new_available THEN 1 ELSE 0 END), 0),
+		       coalesce(SUM(CASE WHEN new_available THEN 0 ELSE 1 END), 0)
+		FROM state_changes
+		WHERE provider = ? AND campground_id = ? AND julianday(changed_at) >= julianday(?)
+	`, provider, campgroundID, campgroundID, provider, campgroundID, since.UTC()).Scan(&stats.CampgroundName, &stats.Opens, &stats.Closes)
+	if err != nil {
+		return ChurnStats{}, fmt.Errorf("failed to count state changes: %w", err)
+	}
+	return stats, nil
+}
+
+// TopCampgroundsByChurn ranks campgrounds by how many state changes they've had since the given time.
+func (s *Store) TopCampgroundsByChurn(ctx context.Context, since time.Time, limit int) ([]ChurnStats, error) {
+	rows, err := s.ReadConnection().QueryContext(ctx, `
+		SELECT sc.provider, sc.campground_id, coalesce(c.name, sc.campground_id),
+		       SUM(CASE WHEN sc.new_available THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN sc.new_available THEN 0 ELSE 1 END)
+		FROM state_changes sc
+		LEFT JOIN campgrounds c ON c.provider = sc.provider AND c.campground_id = sc.campground_id
+		WHERE julianday(sc.changed_at) >= julianday(?)
+		GROUP BY sc.provider, sc.campground_id
+		ORDER BY COUNT(*) DESC, sc.provider, sc.campground_id
+		LIMIT ?
+	`, since.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query campground churn: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ChurnStats
+	for rows.Next() {
+		var c ChurnStats
+		if err := rows.Scan(&c.Provider, &c.CampgroundID, &c.CampgroundName, &c.Opens, &c.Closes); err != nil {
+			return nil, fmt.Errorf("failed to scan campground churn: %w", err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestChurnStats(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := store.DB.Exec(`INSERT INTO campgrounds(provider, campground_id, name, last_updated) VALUES ('p', 'busy', 'Busy Camp', datetime('now'))`); err != nil {
+		t.Fatalf("Failed to insert campground: %v", err)
+	}
+
+	now := time.Now().UTC()
+	night := normalizeDay(now.AddDate(0, 0, 10))
+	seed := []struct {
+		cg        string
+		site      string
+		available bool
+		ago       time.Duration
+	}{
+		{"busy", "s1", true, time.Hour},
+		{"busy", "s1", false, 2 * time.Hour},
+		{"busy", "s2", true, 3 * time.Hour},
+		{"busy", "s2", false, 72 * time.Hour}, // before the window
+		{"quiet", "s1", true, time.Hour},
+	}
+	for _, sc := range seed {
+		_, err := store.DB.Exec(`
+			INSERT INTO state_changes(provider, campground_id, campsite_id, date, new_available, changed_at)
+			VALUES ('p', ?, ?, ?, ?, ?)`,
+			sc.cg, sc.site, night, sc.available, now.Add(-sc.ago).Format("2006-01-02 15:04:05"))
+		if err != nil {
+			t.Fatalf("Failed to insert state change: %v", err)
+		}
+	}
+
+	since := now.Add(-24 * time.Hour)
+	stats, err := store.GetChurnStats(ctx, "p", "busy", since)
+	if err != nil {
+		t.Fatalf("GetChurnStats failed: %v", err)
+	}
+	if stats.Opens != 2 || stats.Closes != 1 || stats.Changes() != 3 || stats.CampgroundName != "Busy Camp" {
+		t.Errorf("Unexpected churn for busy: %+v", stats)
+	}
+
+	none, err := store.GetChurnStats(ctx, "p", "missing", since)
+	if err != nil {
+		t.Fatalf("GetChurnStats failed: %v", err)
+	}
+	if none.Changes() != 0 || none.CampgroundName != "missing" {
+		t.Errorf("Unexpected churn for unknown campground: %+v", none)
+	}
+
+	top, err := store.TopCampgroundsByChurn(ctx, since, 10)
+	if err != nil {
+		t.Fatalf("TopCampgroundsByChurn failed: %v", err)
+	}
+	if len(top) != 2 || top[0].CampgroundID != "busy" || top[0].Changes() != 3 || top[1].CampgroundName != "quiet" {
+		t.Errorf("Unexpected churn ranking: %+v", top)
+	}
+}
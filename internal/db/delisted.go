@@ -0,0 +1,24 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// SetCampgroundDelisted marks a campground as no longer listed by its provider, or clears the mark.
+// Delisted campgrounds are kept so their availability history survives.
+func (s *Store) SetCampgroundDelisted(ctx context.Context, provider, campgroundID string, delisted bool) error {
+	query := `UPDATE campgrounds SET delisted_at = NULL WHERE provider = ? AND campground_id = ?`
+	if delisted {
+		// keep the original timestamp if it was already delisted
+		query = `UPDATE campgrounds SET delisted_at = coalesce(delisted_at, datetime('now')) WHERE provider = ? AND campground_id = ?`
+	}
+	res, err := s.DB.ExecContext(ctx, query, provider, campgroundID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("campground not found")
+	}
+	return nil
+}
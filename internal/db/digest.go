@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// AddDigestItems queues newly available campsites for the user's next digest. A state change that
+// matches several of the user's requests is only queued once.
+func (s *Store) AddDigestItems(ctx context.Context, userID string, changes []StateChangeForRequest) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// If not committed due to early return, rollback
+		_ = tx.Rollback()
+	}()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT OR IGNORE INTO digest_items(user_id, state_change_id, request_id, provider, campground_id, campsite_id, date, changed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, c := range changes {
+		if _, err := stmt.ExecContext(ctx, userID, c.ID, c.RequestID, c.Provider, c.CampgroundID, c.CampsiteID, c.Date, c.ChangedAt); err != nil {
+			return fmt.Errorf("failed to queue digest item: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetPendingDigestItems returns every queued digest item grouped by user.
+func (s *Store) GetPendingDigestItems(ctx context.Context) (map[string][]StateChangeForRequest, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT user_id, state_change_id, request_id, provider, campground_id, campsite_id, date, changed_at
+		FROM digest_items
+		ORDER BY user_id, provider, campground_id, campsite_id, date
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make(map[string][]StateChangeForRequest)
+	for rows.Next() {
+		var userID string
+		var changedAt sql.NullTime
+		c := StateChangeForRequest{NewAvailable: true}
+		if err := rows.Scan(&userID, &c.ID, &c.RequestID, &c.Provider, &c.CampgroundID, &c.CampsiteID, &c.Date, &changedAt); err != nil {
+			return nil, err
+		}
+		c.ChangedAt = changedAt.Time
+		out[userID] = append(out[userID], c)
+	}
+	return out, rows.Err()
+}
+
+// ClearDigest removes the given items once they have been sent. Items queued after they were read
+// stay for the next digest.
+func (s *Store) ClearDigest(ctx context.Context, userID string, items []StateChangeForRequest) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// If not committed due to early return, rollback
+		_ = tx.Rollback()
+	}()
+	for _, c := range items {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM digest_items WHERE user_id = ? AND state_change_id = ?`, userID, c.ID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDigestItemsQueueAndClear(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	night := time.Date(2025, 7, 4, 0, 0, 0, 0, time.UTC)
+
+	first := []StateChangeForRequest{
+		{ID: 1, Provider: "p", CampgroundID: "cg", CampsiteID: "a", Date: night, RequestID: 10},
+		{ID: 2, Provider: "p", CampgroundID: "cg", CampsiteID: "b", Date: night, RequestID: 10},
+	}
+	if err := store.AddDigestItems(ctx, "u1", first); err != nil {
+		t.Fatalf("AddDigestItems: %v", err)
+	}
+	// the same state change matched by another of the user's requests is not queued twice
+	if err := store.AddDigestItems(ctx, "u1", []StateChangeForRequest{{ID: 1, Provider: "p", CampgroundID: "cg", CampsiteID: "a", Date: night, RequestID: 11}}); err != nil {
+		t.Fatalf("AddDigestItems duplicate: %v", err)
+	}
+	if err := store.AddDigestItems(ctx, "u2", first[:1]); err != nil {
+		t.Fatalf("AddDigestItems u2: %v", err)
+	}
+
+	pending, err := store.GetPendingDigestItems(ctx)
+	if err != nil {
+		t.Fatalf("GetPendingDigestItems: %v", err)
+	}
+	if len(pending["u1"]) != 2 || len(pending["u2"]) != 1 {
+		t.Fatalf("unexpected pending items: %+v", pending)
+	}
+	if !pending["u1"][0].NewAvailable || pending["u1"][0].RequestID != 10 {
+		t.Errorf("unexpected item: %+v", pending["u1"][0])
+	}
+
+	// clearing what was sent leaves items queued afterwards
+	if err := store.AddDigestItems(ctx, "u1", []StateChangeForRequest{{ID: 3, Provider: "p", CampgroundID: "cg", CampsiteID: "c", Date: night}}); err != nil {
+		t.Fatalf("AddDigestItems late: %v", err)
+	}
+	if err := store.ClearDigest(ctx, "u1", pending["u1"]); err != nil {
+		t.Fatalf("ClearDigest: %v", err)
+	}
+	pending, _ = store.GetPendingDigestItems(ctx)
+	if len(pending["u1"]) != 1 || pending["u1"][0].ID != 3 || len(pending["u2"]) != 1 {
+		t.Fatalf("expected only the late u1 item and u2's item, got %+v", pending)
+	}
+}
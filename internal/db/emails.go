@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// emailTokenTTL is how long a verification code stays valid, in SQLite datetime modifier form.
+const emailTokenTTL = "-24 hours"
+
+// maxEmailCodeAttempts is how many codes a pending email accepts before it has to be registered
+// again, so the 6 digit code can't be guessed.
+const maxEmailCodeAttempts = 5
+
+// SetPendingUserEmail records an unverified email for the user with its verification token,
+// replacing any previous address.
+func (s *Store) SetPendingUserEmail(ctx context.Context, userID, email, token string) error {
+	_, err := s.DB.ExecContext(ctx, `
+		INSERT OR REPLACE INTO user_emails(user_id, email, token, verified, created_at, verified_at, attempts)
+		VALUES (?, ?, ?, false, datetime('now'), NULL, 0)
+	`, userID, email, token)
+	return err
+}
+
+// VerifyUserEmail marks the user's pending email verified if the token matches and hasn't expired.
+// Each wrong token counts against the pending email; after maxEmailCodeAttempts it can't be
+// verified until the address is registered again.
+func (s *Store) VerifyUserEmail(ctx context.Context, userID, token string) (string, error) {
+	var email string
+	err := s.DB.QueryRowContext(ctx, `
+		UPDATE user_emails SET verified = true, verified_at = datetime('now'), token = ''
+		WHERE user_id = ? AND token = ? AND token != '' AND NOT verified
+		AND created_at >= datetime('now', ?) AND coalesce(attempts, 0) < ?
+		RETURNING email
+	`, userID, token, emailTokenTTL, maxEmailCodeAttempts).Scan(&email)
+	if err != sql.ErrNoRows {
+		return email, err
+	}
+
+	var attempts int
+	err = s.DB.QueryRowContext(ctx, `
+		UPDATE user_emails SET attempts = coalesce(attempts, 0) + 1
+		WHERE user_id = ? AND token != '' AND NOT verified
+		RETURNING attempts
+	`, userID).Scan(&attempts)
+	if err != nil && err != sql.ErrNoRows {
+		return "", err
+	}
+	if attempts >= maxEmailCodeAttempts {
+		return "", errors.New("too many wrong codes, register the address again for a new one")
+	}
+	return "", errors.New("invalid or expired code")
+}
+
+// GetVerifiedUserEmail returns the user's verified email, if they have one.
+func (s *Store) GetVerifiedUserEmail(ctx context.Context, userID string) (string, bool, error) {
+	var email string
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT email FROM user_emails WHERE user_id = ? AND verified
+	`, userID).Scan(&email)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return email, true, nil
+}
+
+// RemoveUserEmail deletes the user's email registration, verified or not.
+func (s *Store) RemoveUserEmail(ctx context.Context, userID string) error {
+	_, err := s.DB.ExecContext(ctx, `DELETE FROM user_emails WHERE user_id = ?`, userID)
+	return err
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUserEmailVerification(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	if err := store.SetPendingUserEmail(ctx, "user1", "camper@example.com", "abc123"); err != nil {
+		t.Fatalf("SetPendingUserEmail failed: %v", err)
+	}
+	if _, ok, err := store.GetVerifiedUserEmail(ctx, "user1"); err != nil || ok {
+		t.Fatalf("Expected no verified email before verification, got ok=%v err=%v", ok, err)
+	}
+
+	if _, err := store.VerifyUserEmail(ctx, "user1", "wrong"); err == nil {
+		t.Error("Expected wrong code to fail")
+	}
+	email, err := store.VerifyUserEmail(ctx, "user1", "abc123")
+	if err != nil || email != "camper@example.com" {
+		t.Fatalf("VerifyUserEmail got %q, %v", email, err)
+	}
+	// codes are single use
+	if _, err := store.VerifyUserEmail(ctx, "user1", "abc123"); err == nil {
+		t.Error("Expected reused code to fail")
+	}
+	if got, ok, err := store.GetVerifiedUserEmail(ctx, "user1"); err != nil || !ok || got != "camper@example.com" {
+		t.Fatalf("GetVerifiedUserEmail got %q, %v, %v", got, ok, err)
+	}
+
+	// expired codes are rejected
+	if err := store.SetPendingUserEmail(ctx, "user2", "late@example.com", "old"); err != nil {
+		t.Fatalf("SetPendingUserEmail failed: %v", err)
+	}
+	if _, err := store.DB.Exec(`UPDATE user_emails SET created_at = datetime('now', '-2 days') WHERE user_id = 'user2'`); err != nil {
+		t.Fatalf("Failed to age token: %v", err)
+	}
+	if _, err := store.VerifyUserEmail(ctx, "user2", "old"); err == nil {
+		t.Error("Expected expired code to fail")
+	}
+
+	if err := store.RemoveUserEmail(ctx, "user1"); err != nil {
+		t.Fatalf("RemoveUserEmail failed: %v", err)
+	}
+	if _, ok, _ := store.GetVerifiedUserEmail(ctx, "user1"); ok {
+		t.Error("Expected email to be removed")
+	}
+}
+
+func TestVerifyUserEmailLimitsAttempts(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	if err := store.SetPendingUserEmail(ctx, "user1", "camper@example.com", "123456"); err != nil {
+		t.Fatalf("SetPendingUserEmail failed: %v", err)
+	}
+	for i := 0; i < maxEmailCodeAttempts; i++ {
+		if _, err := store.VerifyUserEmail(ctx, "user1", "000000"); err == nil {
+			t.Fatal("Expected wrong code to fail")
+		}
+	}
+	if _, err := store.VerifyUserEmail(ctx, "user1", "123456"); err == nil {
+		t.Fatal("Expected the right code to fail once the attempts are used up")
+	}
+
+	// registering again starts over
+	if err := store.SetPendingUserEmail(ctx, "user1", "camper@example.com", "654321"); err != nil {
+		t.Fatalf("SetPendingUserEmail failed: %v", err)
+	}
+	if _, err := store.VerifyUserEmail(ctx, "user1", "000000"); err == nil {
+		t.Fatal("Expected wrong code to fail")
+	}
+	if email, err := store.VerifyUserEmail(ctx, "user1", "654321"); err != nil || email != "camper@example.com" {
+		t.Fatalf("VerifyUserEmail got %q, %v", email, err)
+	}
+}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// FeatureCount is the number of campsites at a campground with a given feature value.
+type FeatureCount struct {
+	Feature string `json:"feature"`
+	Value   string `json:"value"`
+	Count   int    `json:"count"`
+}
+
+// FeatureDistribution summarises a campground's campsite features as counts per (feature, value),
+// e.g. how many sites are "STANDARD NONELECTRIC" or permit "Trailer". Campsite types come from
+// campsite_metadata and permitted equipment from campsite_equipment.
+func (s *Store) FeatureDistribution(ctx context.Context, provider, campgroundID string) ([]FeatureCount, error) {
+	rows, err := s.ReadConnection().QueryContext(ctx, `
+		SELECT 'campsite_type' AS feature, campsite_type AS value, COUNT(*) AS n
+		FROM campsite_metadata
+		WHERE provider = ? AND campground_id = ? AND campsite_type != ''
+		GROUP BY campsite_type
+		UNION ALL
+		SELECT 'equipment', equipment_type, COUNT(DISTINCT campsite_id)
+		FROM campsite_equipment
+		WHERE provider = ? AND campground_id = ?
+		GROUP BY equipment_type
+		ORDER BY feature, n DESC, value
+	`, provider, campgroundID, provider, campgroundID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query feature distribution: %w", err)
+	}
+	defer rows.Close()
+
+	var out []FeatureCount
+	for rows.Next() {
+		var fc FeatureCount
+		if err := rows.Scan(&fc.Feature, &fc.Value, &fc.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan feature count: %w", err)
+		}
+		out = append(out, fc)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestFeatureDistribution(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	_, err := store.DB.Exec(`
+		INSERT INTO campsite_metadata(provider, campground_id, campsite_id, name, campsite_type, last_updated) VALUES
+			('p', 'cg1', 's1', 'Site 1', 'STANDARD', datetime('now')),
+			('p', 'cg1', 's2', 'Site 2', 'STANDARD', datetime('now')),
+			('p', 'cg1', 's3', 'Site 3', 'RV', datetime('now')),
+			('p', 'cg1', 's4', 'Site 4', '', datetime('now')),
+			('p', 'cg2', 's1', 'Other', 'STANDARD', datetime('now'));
+		INSERT INTO campsite_equipment(provider, campground_id, campsite_id, equipment_type) VALUES
+			('p', 'cg1', 's1', 'Tent'),
+			('p', 'cg1', 's2', 'Tent'),
+			('p', 'cg1', 's3', 'Trailer'),
+			('p', 'cg1', 's3', 'Tent');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert fixtures: %v", err)
+	}
+
+	got, err := store.FeatureDistribution(ctx, "p", "cg1")
+	if err != nil {
+		t.Fatalf("FeatureDistribution failed: %v", err)
+	}
+	want := []FeatureCount{
+		{Feature: "campsite_type", Value: "STANDARD", Count: 2},
+		{Feature: "campsite_type", Value: "RV", Count: 1},
+		{Feature: "equipment", Value: "Tent", Count: 3},
+		{Feature: "equipment", Value: "Trailer", Count: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected distribution:\n got %+v\nwant %+v", got, want)
+	}
+}
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+func TestUpsertCampsiteMetadataBatch_DetectsNewCampsites(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	sites := []providers.CampsiteInfo{{ID: "s1", Name: "Site 1"}, {ID: "s2", Name: "Site 2"}}
+	newSites, err := store.UpsertCampsiteMetadataBatch(ctx, "p", "cg1", sites)
+	if err != nil {
+		t.Fatalf("UpsertCampsiteMetadataBatch failed: %v", err)
+	}
+	if len(newSites) != 0 {
+		t.Errorf("Expected first sync to establish a baseline, got new campsites %v", newSites)
+	}
+
+	// resync with one added campsite
+	sites = append(sites, providers.CampsiteInfo{ID: "s3", Name: "Site 3"})
+	newSites, err = store.UpsertCampsiteMetadataBatch(ctx, "p", "cg1", sites)
+	if err != nil {
+		t.Fatalf("UpsertCampsiteMetadataBatch failed: %v", err)
+	}
+	if !reflect.DeepEqual(newSites, []string{"s3"}) {
+		t.Errorf("Expected s3 to be new, got %v", newSites)
+	}
+
+	// unchanged resync reports nothing
+	newSites, err = store.UpsertCampsiteMetadataBatch(ctx, "p", "cg1", sites)
+	if err != nil {
+		t.Fatalf("UpsertCampsiteMetadataBatch failed: %v", err)
+	}
+	if len(newSites) != 0 {
+		t.Errorf("Expected no new campsites, got %v", newSites)
+	}
+
+	var count int
+	if err := store.DB.QueryRow(`SELECT COUNT(*) FROM campsite_first_seen WHERE provider='p' AND campground_id='cg1'`).Scan(&count); err != nil {
+		t.Fatalf("Failed to count first_seen rows: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 first_seen rows, got %d", count)
+	}
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// GetAvailabilityAsOf reconstructs a campground's availability at a past time by replaying
+// state_changes: for each campsite/date the latest change at or before `at` wins. LastChecked
+// holds the time of that change. Campsite/dates with no change recorded by then are omitted,
+// since we only learn about a campsite once we first record it.
+func (s *Store) GetAvailabilityAsOf(ctx context.Context, provider, campgroundID string, at time.Time) ([]CampsiteAvailability, error) {
+	// changed_at is written with CURRENT_TIMESTAMP, so compare in the same UTC text format
+	cutoff := at.UTC().Format("2006-01-02 15:04:05")
+	rows, err := s.ReadConnection().QueryContext(ctx, `
+		SELECT sc.campsite_id, sc.date, sc.new_available, sc.changed_at
+		FROM state_changes sc
+		WHERE sc.provider = ? AND sc.campground_id = ? AND sc.changed_at <= ?
+		AND sc.changed_at = (
+			SELECT MAX(x.changed_at) FROM state_changes x
+			WHERE x.provider = sc.provider
+			  AND x.campground_id = sc.campground_id
+			  AND x.campsite_id = sc.campsite_id
+			  AND x.date = sc.date
+			  AND x.changed_at <= ?
+		)
+		ORDER BY sc.campsite_id, sc.date
+	`, provider, campgroundID, cutoff, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query availability as of %s: %w", cutoff, err)
+	}
+	defer rows.Close()
+
+	var out []CampsiteAvailability
+	for rows.Next() {
+		a := CampsiteAvailability{Provider: provider, CampgroundID: campgroundID}
+		if err := rows.Scan(&a.CampsiteID, &a.Date, &a.Available, &a.LastChecked); err != nil {
+			return nil, fmt.Errorf("failed to scan availability: %w", err)
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// GetCampsiteAvailabilityHistory returns a campsite's stored availability for every date from
+// since (by day) onwards, ordered by date. LastChecked is when that date was last scraped.
+func (s *Store) GetCampsiteAvailabilityHistory(ctx context.Context, provider, campgroundID, campsiteID string, since time.Time) ([]CampsiteAvailability, error) {
+	rows, err := s.ReadConnection().QueryContext(ctx, `
+		SELECT date, available, last_checked, coalesce(cost_per_night, 0)
+		FROM campsite_availability
+		WHERE provider = ? AND campground_id = ? AND campsite_id = ? AND date >= ?
+		ORDER BY date
+	`, provider, campgroundID, campsiteID, normalizeDay(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query campsite history: %w", err)
+	}
+	defer rows.Close()
+
+	var out []CampsiteAvailability
+	for rows.Next() {
+		a := CampsiteAvailability{Provider: provider, CampgroundID: campgroundID, CampsiteID: campsiteID}
+		if err := rows.Scan(&a.Date, &a.Available, &a.LastChecked, &a.CostPerNight); err != nil {
+			return nil, fmt.Errorf("failed to scan campsite history: %w", err)
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
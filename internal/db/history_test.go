@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetAvailabilityAsOf(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	date := normalizeDay(time.Now().AddDate(0, 0, 10))
+	insertChange := func(campsiteID string, available bool, ago string) {
+		t.Helper()
+		_, err := store.DB.Exec(`
+			INSERT INTO state_changes(provider, campground_id, campsite_id, date, new_available, changed_at)
+			VALUES ('p', 'cg1', ?, ?, ?, datetime('now', ?))
+		`, campsiteID, date, available, ago)
+		if err != nil {
+			t.Fatalf("Failed to insert state change: %v", err)
+		}
+	}
+
+	insertChange("site1", true, "-3 hours")
+	insertChange("site1", false, "-1 hours")
+	insertChange("site2", false, "-3 hours")
+	insertChange("site3", true, "-30 minutes")
+
+	asOf := func(at time.Time) map[string]bool {
+		t.Helper()
+		got, err := store.GetAvailabilityAsOf(ctx, "p", "cg1", at)
+		if err != nil {
+			t.Fatalf("GetAvailabilityAsOf failed: %v", err)
+		}
+		m := map[string]bool{}
+		for _, a := range got {
+			m[a.CampsiteID] = a.Available
+		}
+		return m
+	}
+
+	twoHoursAgo := asOf(time.Now().Add(-2 * time.Hour))
+	if len(twoHoursAgo) != 2 || !twoHoursAgo["site1"] || twoHoursAgo["site2"] {
+		t.Errorf("Unexpected availability two hours ago: %v", twoHoursAgo)
+	}
+
+	now := asOf(time.Now())
+	if len(now) != 3 || now["site1"] || now["site2"] || !now["site3"] {
+		t.Errorf("Unexpected availability now: %v", now)
+	}
+
+	if before := asOf(time.Now().Add(-5 * time.Hour)); len(before) != 0 {
+		t.Errorf("Expected nothing before the first change, got %v", before)
+	}
+}
+
+func TestGetCampsiteAvailabilityHistory(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	start := normalizeDay(time.Now().AddDate(0, 0, -5))
+	checked := time.Now().UTC().Truncate(time.Second)
+	var states []CampsiteAvailability
+	for d := 4; d >= 0; d-- { // inserted out of order to check the ordering
+		for _, site := range []string{"site1", "site2"} {
+			states = append(states, CampsiteAvailability{
+				Provider: "p", CampgroundID: "cg1", CampsiteID: site,
+				Date: start.AddDate(0, 0, d), Available: d%2 == 0, LastChecked: checked,
+			})
+		}
+	}
+	if err := store.UpsertCampsiteAvailabilityBatch(ctx, states); err != nil {
+		t.Fatalf("UpsertCampsiteAvailabilityBatch failed: %v", err)
+	}
+
+	// since is truncated to the day
+	got, err := store.GetCampsiteAvailabilityHistory(ctx, "p", "cg1", "site1", start.AddDate(0, 0, 2).Add(5*time.Hour))
+	if err != nil {
+		t.Fatalf("GetCampsiteAvailabilityHistory failed: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 days from the cutoff, got %+v", got)
+	}
+	for i, a := range got {
+		wantDate := start.AddDate(0, 0, 2+i)
+		if !a.Date.Equal(wantDate) || a.CampsiteID != "site1" || a.Available != (i%2 == 0) {
+			t.Errorf("row %d = %+v, want site1 on %s available=%v", i, a, wantDate.Format("2006-01-02"), i%2 == 0)
+		}
+		if !a.LastChecked.Equal(checked) {
+			t.Errorf("row %d last checked %v, want %v", i, a.LastChecked, checked)
+		}
+	}
+}
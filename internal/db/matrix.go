@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// AvailabilityMatrix is a campground's stored availability as a campsite × date grid, shared by
+// every view of it so they agree on which campsites and dates are shown.
+type AvailabilityMatrix struct {
+	CampsiteIDs []string    // sorted
+	Dates       []time.Time // every day from start to end inclusive, in order
+	cells       map[string]map[string]bool
+}
+
+// NewAvailabilityMatrix builds the grid for [start, end] from stored states. Its campsites are
+// campsiteIDs plus any campsite that has a state in range.
+func NewAvailabilityMatrix(campsiteIDs []string, start, end time.Time, states []CampsiteAvailability) *AvailabilityMatrix {
+	m := &AvailabilityMatrix{cells: make(map[string]map[string]bool)}
+	for _, id := range campsiteIDs {
+		m.addCampsite(id)
+	}
+	for _, st := range states {
+		m.addCampsite(st.CampsiteID)
+		m.cells[st.CampsiteID][normalizeDay(st.Date).Format("2006-01-02")] = st.Available
+	}
+	sort.Strings(m.CampsiteIDs)
+
+	for d := normalizeDay(start); !d.After(normalizeDay(end)); d = d.AddDate(0, 0, 1) {
+		m.Dates = append(m.Dates, d)
+	}
+	return m
+}
+
+func (m *AvailabilityMatrix) addCampsite(id string) {
+	if _, ok := m.cells[id]; ok {
+		return
+	}
+	m.cells[id] = make(map[string]bool)
+	m.CampsiteIDs = append(m.CampsiteIDs, id)
+}
+
+// Lookup returns a campsite's stored state on a date. known is false when nothing is stored.
+func (m *AvailabilityMatrix) Lookup(campsiteID string, date time.Time) (available, known bool) {
+	available, known = m.cells[campsiteID][normalizeDay(date).Format("2006-01-02")]
+	return available, known
+}
+
+// AvailableNights returns the dates the campsite is available, in order.
+func (m *AvailabilityMatrix) AvailableNights(campsiteID string) []time.Time {
+	var out []time.Time
+	for _, d := range m.Dates {
+		if available, _ := m.Lookup(campsiteID, d); available {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// GetAvailabilityMatrix returns the campground's stored availability for [start, end] inclusive,
+// covering its synced campsites and any others with stored states in range.
+func (s *Store) GetAvailabilityMatrix(ctx context.Context, provider, campgroundID string, start, end time.Time) (*AvailabilityMatrix, error) {
+	rows, err := s.ReadConnection().QueryContext(ctx, `
+		SELECT campsite_id FROM campsite_metadata WHERE provider=? AND campground_id=?
+	`, provider, campgroundID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query campsites: %w", err)
+	}
+	defer rows.Close()
+	var campsiteIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan campsite: %w", err)
+		}
+		campsiteIDs = append(campsiteIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	states, err := s.GetStoredAvailability(ctx, provider, campgroundID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return NewAvailabilityMatrix(campsiteIDs, start, end, states), nil
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+func TestGetAvailabilityMatrix(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2025, 7, d, 0, 0, 0, 0, time.UTC) }
+
+	if _, err := store.UpsertCampsiteMetadataBatch(ctx, "p", "cg", []providers.CampsiteInfo{{ID: "2"}, {ID: "10"}}); err != nil {
+		t.Fatalf("UpsertCampsiteMetadataBatch: %v", err)
+	}
+	now := time.Now()
+	err := store.UpsertCampsiteAvailabilityBatch(ctx, []CampsiteAvailability{
+		{Provider: "p", CampgroundID: "cg", CampsiteID: "2", Date: day(1), Available: true, LastChecked: now},
+		{Provider: "p", CampgroundID: "cg", CampsiteID: "2", Date: day(2), Available: false, LastChecked: now},
+		{Provider: "p", CampgroundID: "cg", CampsiteID: "2", Date: day(3), Available: true, LastChecked: now},
+		// no metadata synced for this one yet
+		{Provider: "p", CampgroundID: "cg", CampsiteID: "7", Date: day(2), Available: true, LastChecked: now},
+		// outside the range
+		{Provider: "p", CampgroundID: "cg", CampsiteID: "10", Date: day(9), Available: true, LastChecked: now},
+		{Provider: "p", CampgroundID: "other", CampsiteID: "2", Date: day(1), Available: false, LastChecked: now},
+	})
+	if err != nil {
+		t.Fatalf("UpsertCampsiteAvailabilityBatch: %v", err)
+	}
+
+	m, err := store.GetAvailabilityMatrix(ctx, "p", "cg", day(1), day(3))
+	if err != nil {
+		t.Fatalf("GetAvailabilityMatrix: %v", err)
+	}
+	if got := strings.Join(m.CampsiteIDs, ","); got != "10,2,7" {
+		t.Errorf("CampsiteIDs = %s, want 10,2,7", got)
+	}
+	if len(m.Dates) != 3 || !m.Dates[0].Equal(day(1)) || !m.Dates[2].Equal(day(3)) {
+		t.Errorf("Dates = %v, want Jul 1-3", m.Dates)
+	}
+
+	tests := []struct {
+		site             string
+		day              int
+		available, known bool
+	}{
+		{"2", 1, true, true},
+		{"2", 2, false, true},
+		{"7", 2, true, true},
+		{"7", 1, false, false},
+		{"10", 1, false, false},
+	}
+	for _, tt := range tests {
+		available, known := m.Lookup(tt.site, day(tt.day))
+		if available != tt.available || known != tt.known {
+			t.Errorf("Lookup(%s, Jul %d) = %v, %v; want %v, %v", tt.site, tt.day, available, known, tt.available, tt.known)
+		}
+	}
+
+	nights := m.AvailableNights("2")
+	if len(nights) != 2 || !nights[0].Equal(day(1)) || !nights[1].Equal(day(3)) {
+		t.Errorf("AvailableNights(2) = %v, want Jul 1 and 3", nights)
+	}
+}
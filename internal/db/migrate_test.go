@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestMigrateAddsMissingColumns(t *testing.T) {
+	sqlDB, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	defer sqlDB.Close()
+
+	// A schniff_requests table from before min_rating existed
+	_, err = sqlDB.Exec(`CREATE TABLE schniff_requests (
+		id INTEGER PRIMARY KEY AUTOINCREMENT, user_id TEXT NOT NULL, provider TEXT NOT NULL,
+		campground_id TEXT NOT NULL, checkin DATE NOT NULL, checkout DATE NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP, active BOOLEAN DEFAULT TRUE)`)
+	if err != nil {
+		t.Fatalf("Failed to create old table: %v", err)
+	}
+	if err := migrate(sqlDB); err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+	// Running again must be a no-op
+	if err := migrate(sqlDB); err != nil {
+		t.Fatalf("second migrate failed: %v", err)
+	}
+
+	store := &Store{DB: sqlDB}
+	ctx := context.Background()
+	checkin := normalizeDay(time.Now().AddDate(0, 0, 5))
+	_, err = store.AddRequest(ctx, SchniffRequest{
+		UserID: "u", Provider: "p", CampgroundID: "cg",
+		Checkin: checkin, Checkout: checkin.AddDate(0, 0, 2),
+		MinRating: 4.5, RequireRating: true,
+	})
+	if err != nil {
+		t.Fatalf("AddRequest failed: %v", err)
+	}
+	reqs, err := store.ListActiveRequests(ctx)
+	if err != nil {
+		t.Fatalf("ListActiveRequests failed: %v", err)
+	}
+	if len(reqs) != 1 || reqs[0].MinRating != 4.5 || !reqs[0].RequireRating {
+		t.Fatalf("Unexpected requests: %+v", reqs)
+	}
+}
+
+func TestMigrateRecordsVersionsAndIsIdempotent(t *testing.T) {
+	sqlDB, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	defer sqlDB.Close()
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations failed: %v", err)
+	}
+	for i, m := range migrations {
+		if m.version != i+1 {
+			t.Fatalf("migration versions must be 1..n without gaps, got %s at position %d", m.name, i)
+		}
+	}
+
+	if err := migrate(sqlDB); err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+	recorded := func() map[int]time.Time {
+		t.Helper()
+		rows, err := sqlDB.Query(`SELECT version, applied_at FROM schema_migrations`)
+		if err != nil {
+			t.Fatalf("query schema_migrations: %v", err)
+		}
+		defer rows.Close()
+		out := map[int]time.Time{}
+		for rows.Next() {
+			var v int
+			var at time.Time
+			if err := rows.Scan(&v, &at); err != nil {
+				t.Fatalf("scan: %v", err)
+			}
+			out[v] = at
+		}
+		return out
+	}
+	first := recorded()
+	if len(first) != len(migrations) {
+		t.Fatalf("expected %d recorded migrations, got %v", len(migrations), first)
+	}
+
+	// Re-running applies nothing new and keeps the original records
+	if _, err := sqlDB.Exec(`UPDATE schema_migrations SET applied_at = '2000-01-01 00:00:00'`); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := migrate(sqlDB); err != nil {
+		t.Fatalf("second migrate failed: %v", err)
+	}
+	for v, at := range recorded() {
+		if at.Year() != 2000 {
+			t.Errorf("migration %d was re-applied (applied_at %v)", v, at)
+		}
+	}
+}
+
+func TestApplyMigrationRollsBackOnFailure(t *testing.T) {
+	store := newTestStore(t)
+	bad := migration{version: 9999, name: "9999_bad.sql", sql: `CREATE TABLE half_done (id INTEGER); SELECT * FROM no_such_table;`}
+	if err := applyMigrations(store.DB, []migration{bad}); err == nil {
+		t.Fatal("expected the bad migration to fail")
+	}
+	applied, err := appliedMigrations(store.DB)
+	if err != nil {
+		t.Fatalf("appliedMigrations: %v", err)
+	}
+	if applied[9999] {
+		t.Error("failed migration should not be recorded")
+	}
+	var n int
+	if err := store.DB.QueryRow(`SELECT count(*) FROM sqlite_master WHERE name = 'half_done'`).Scan(&n); err != nil || n != 0 {
+		t.Errorf("failed migration should be rolled back, found %d half_done tables (err %v)", n, err)
+	}
+}
@@ -1,4 +1,5 @@
--- schema for schniffer (SQLite)
+-- schema for schniffer (SQLite): migration 1, the baseline.
+-- Later schema changes go in new numbered files in this directory rather than edits here.
 
 CREATE TABLE IF NOT EXISTS schniff_requests (
     id          INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -8,7 +9,14 @@ CREATE TABLE IF NOT EXISTS schniff_requests (
     checkin     DATE NOT NULL,
     checkout    DATE NOT NULL,
     created_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
-    active      BOOLEAN DEFAULT TRUE
+    active      BOOLEAN DEFAULT TRUE,
+    min_rating  REAL DEFAULT 0,
+    require_rating BOOLEAN DEFAULT FALSE,
+    include_day_use BOOLEAN DEFAULT FALSE,
+    specialty_only BOOLEAN DEFAULT FALSE,
+    paused      BOOLEAN DEFAULT FALSE, -- still active and expires, but not polled
+    night_filter TEXT DEFAULT 'any', -- any, weekends (Fri/Sat nights) or weekdays
+    min_nights  INTEGER DEFAULT 0 -- only notify for runs of at least this many consecutive nights
 );
 
 CREATE INDEX IF NOT EXISTS idx_schniff_requests_active ON schniff_requests(active);
@@ -29,6 +37,7 @@ CREATE TABLE IF NOT EXISTS campsite_availability (
     date         DATE NOT NULL,
     available    BOOLEAN NOT NULL,
     last_checked DATETIME NOT NULL,
+    cost_per_night REAL, -- NULL when neither the provider nor campsite metadata knows the price
     PRIMARY KEY (provider, campground_id, campsite_id, date)
 );
 
@@ -66,6 +75,7 @@ CREATE TABLE IF NOT EXISTS campgrounds (
     -- adding these for more efficient queries
     campsite_types TEXT DEFAULT '[]', -- JSON array of campsite types
     equipment    TEXT DEFAULT '[]', -- JSON array of equipment types
+    delisted_at  DATETIME, -- set when the provider stops listing the campground
 
     PRIMARY KEY (provider, campground_id)
 );
@@ -85,6 +95,7 @@ CREATE TABLE IF NOT EXISTS campsite_metadata (
     rating       REAL DEFAULT 0,
     last_updated DATETIME NOT NULL,
     image_url    TEXT DEFAULT '',
+    type_of_use  TEXT DEFAULT '', -- e.g. Overnight or Day, when the provider reports it
     PRIMARY KEY (provider, campground_id, campsite_id)
 );
 
@@ -114,10 +125,20 @@ CREATE TABLE IF NOT EXISTS campsite_equipment (
 --     UNIQUE(provider, campground_id, campsite_type)
 -- );
 
--- Indexes for campground_types table (commented out since table is commented out)
+-- Indexes for campground_types table (commented out since table is commented out;
+-- RefreshCampgroundTypes creates both on demand)
 -- CREATE INDEX IF NOT EXISTS idx_campground_types_lookup ON campground_types(provider, campground_id);
 -- CREATE INDEX IF NOT EXISTS idx_campground_types_composite ON campground_types(provider, campground_id, campsite_type);
 
+-- When each campsite was first seen in a metadata sync, kept separately since campsite_metadata rows are replaced wholesale
+CREATE TABLE IF NOT EXISTS campsite_first_seen (
+    provider      TEXT NOT NULL,
+    campground_id TEXT NOT NULL,
+    campsite_id   TEXT NOT NULL,
+    first_seen    DATETIME NOT NULL,
+    PRIMARY KEY (provider, campground_id, campsite_id)
+);
+
 CREATE INDEX IF NOT EXISTS idx_campsite_equipment_campground ON campsite_equipment(provider, campground_id);
 CREATE INDEX IF NOT EXISTS idx_campsite_equipment_type ON campsite_equipment(equipment_type);
 
@@ -151,6 +172,7 @@ CREATE TABLE IF NOT EXISTS state_changes (
     UNIQUE(provider, campground_id, campsite_id, date, changed_at)
 );
 
+-- Also serves (provider, campground_id, date) lookups via its prefix
 CREATE INDEX IF NOT EXISTS idx_state_changes_lookup ON state_changes(provider, campground_id, date, changed_at);
 CREATE INDEX IF NOT EXISTS idx_state_changes_campsite ON state_changes(provider, campground_id, campsite_id, date, changed_at);
 
@@ -224,3 +246,69 @@ CREATE TABLE IF NOT EXISTS adhoc_scrape_requests (
 CREATE INDEX IF NOT EXISTS idx_adhoc_requests_lookup ON adhoc_scrape_requests(provider, campground_id, requested_at DESC);
 CREATE INDEX IF NOT EXISTS idx_adhoc_requests_status ON adhoc_scrape_requests(status, requested_at);
 CREATE INDEX IF NOT EXISTS idx_adhoc_requests_recent ON adhoc_scrape_requests(provider, campground_id, requested_at DESC) WHERE status IN ('pending', 'completed');
+
+-- Admin-curated campground tags (e.g. lakeside, dog-friendly), separate from ingested amenities
+CREATE TABLE IF NOT EXISTS campground_tags (
+    provider      TEXT NOT NULL,
+    campground_id TEXT NOT NULL,
+    tag           TEXT NOT NULL,
+    created_by    TEXT,
+    created_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, campground_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_campground_tags_tag ON campground_tags(tag);
+
+-- Email addresses users registered for notifications, confirmed with a verification code
+CREATE TABLE IF NOT EXISTS user_emails (
+    user_id     TEXT PRIMARY KEY,
+    email       TEXT NOT NULL,
+    token       TEXT NOT NULL DEFAULT '',
+    verified    BOOLEAN DEFAULT FALSE,
+    created_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+    verified_at DATETIME
+);
+
+-- Campgrounds excluded from polling by an admin, e.g. permanently closed or constantly erroring
+CREATE TABLE IF NOT EXISTS campground_blocklist (
+    provider      TEXT NOT NULL,
+    campground_id TEXT NOT NULL,
+    reason        TEXT NOT NULL DEFAULT '',
+    created_by    TEXT,
+    created_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, campground_id)
+);
+
+-- Per-user notification preferences; quiet hours are wall-clock HH:MM in the user's timezone
+CREATE TABLE IF NOT EXISTS notification_prefs (
+    user_id     TEXT PRIMARY KEY,
+    quiet_start TEXT NOT NULL DEFAULT '',
+    quiet_end   TEXT NOT NULL DEFAULT '',
+    timezone    TEXT NOT NULL DEFAULT 'UTC',
+    enabled     BOOLEAN NOT NULL DEFAULT FALSE, -- quiet hours apply only when enabled
+    digest      BOOLEAN DEFAULT FALSE,          -- batch openings into a periodic DM instead of one per request
+    webhook_url TEXT DEFAULT '',                -- also POST notifications here as JSON when set
+    renotify_after INTEGER DEFAULT 0,           -- minutes a campsite/night must stay booked before a reopening is notified again
+    updated_at  DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Notifications held back during a user's quiet hours, sent with fresh availability once they end
+CREATE TABLE IF NOT EXISTS pending_notifications (
+    user_id    TEXT NOT NULL,
+    request_id INTEGER NOT NULL,
+    queued_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, request_id)
+);
+
+-- Newly available campsites waiting for a digest user's next combined DM
+CREATE TABLE IF NOT EXISTS digest_items (
+    user_id         TEXT NOT NULL,
+    state_change_id INTEGER NOT NULL,
+    request_id      INTEGER NOT NULL,
+    provider        TEXT NOT NULL,
+    campground_id   TEXT NOT NULL,
+    campsite_id     TEXT NOT NULL,
+    date            DATE NOT NULL,
+    changed_at      DATETIME,
+    PRIMARY KEY (user_id, state_change_id)
+);
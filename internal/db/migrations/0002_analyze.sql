@@ -0,0 +1,3 @@
+-- Refresh planner statistics so the baseline indexes get picked up on databases that
+-- predate them (this replaces running cmd/add-indexes by hand).
+ANALYZE;
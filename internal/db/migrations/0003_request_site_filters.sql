@@ -0,0 +1,3 @@
+-- Optional per-request campsite filters; empty means any equipment / any campsite type.
+ALTER TABLE schniff_requests ADD COLUMN equipment TEXT DEFAULT '';
+ALTER TABLE schniff_requests ADD COLUMN campsite_type TEXT DEFAULT '';
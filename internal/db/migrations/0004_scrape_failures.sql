@@ -0,0 +1,10 @@
+-- Campgrounds whose availability fetches keep failing, e.g. facilities the provider removed.
+-- Rows are deleted on the next successful fetch.
+CREATE TABLE IF NOT EXISTS scrape_failures (
+    provider             TEXT NOT NULL,
+    campground_id        TEXT NOT NULL,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    last_error           TEXT NOT NULL DEFAULT '',
+    last_failed_at       DATETIME NOT NULL,
+    PRIMARY KEY (provider, campground_id)
+);
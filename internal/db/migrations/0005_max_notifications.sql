@@ -0,0 +1,2 @@
+-- Per-user cap on how many times a single request may notify in 24 hours; 0 uses the default.
+ALTER TABLE notification_prefs ADD COLUMN max_per_day INTEGER DEFAULT 0;
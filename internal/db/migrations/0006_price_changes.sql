@@ -0,0 +1,19 @@
+-- Nightly price changes for campsite/nights already being tracked. old_cost is NULL when the
+-- night had no known price before. Rows stay unprocessed until price alerts have checked them.
+CREATE TABLE IF NOT EXISTS price_changes (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    provider      TEXT NOT NULL,
+    campground_id TEXT NOT NULL,
+    campsite_id   TEXT NOT NULL,
+    date          DATE NOT NULL,
+    old_cost      REAL,
+    new_cost      REAL NOT NULL,
+    changed_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
+    processed     BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_changes_unprocessed ON price_changes(provider, processed);
+CREATE INDEX IF NOT EXISTS idx_price_changes_campsite ON price_changes(provider, campground_id, campsite_id, date, changed_at);
+
+-- Per-user price alert: DM when a tracked campsite/night drops below this nightly price; 0 is off.
+ALTER TABLE notification_prefs ADD COLUMN price_alert_below REAL DEFAULT 0;
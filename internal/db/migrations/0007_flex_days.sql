@@ -0,0 +1,2 @@
+-- Days either side of a request's dates that are also watched; 0 watches only the exact dates.
+ALTER TABLE schniff_requests ADD COLUMN flex_days INTEGER DEFAULT 0;
@@ -0,0 +1,11 @@
+-- Notifications whose Discord DM failed with a transient error (rate limit, 5xx), retried with
+-- backoff. Rows are deleted once the DM goes through or the retries give up.
+CREATE TABLE IF NOT EXISTS undelivered_notifications (
+    user_id         TEXT NOT NULL,
+    request_id      INTEGER NOT NULL,
+    attempts        INTEGER NOT NULL DEFAULT 1,
+    last_error      TEXT NOT NULL DEFAULT '',
+    next_attempt_at DATETIME NOT NULL,
+    queued_at       DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, request_id)
+);
@@ -0,0 +1,4 @@
+-- How a campsite is booked, e.g. "Site-Specific" or "Walk-Up"; empty when the provider doesn't say.
+ALTER TABLE campsite_metadata ADD COLUMN reserve_type TEXT DEFAULT '';
+-- Only notify about campsites that can be reserved ahead by site.
+ALTER TABLE schniff_requests ADD COLUMN reservable_only BOOLEAN DEFAULT FALSE;
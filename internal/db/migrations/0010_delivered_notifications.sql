@@ -0,0 +1,4 @@
+-- Whether the notification's round actually DMed the user; only those count towards max_per_day.
+ALTER TABLE notifications ADD COLUMN delivered BOOLEAN DEFAULT FALSE;
+-- When the user was last told the request hit its daily cap, so they're told at most once a day.
+ALTER TABLE schniff_requests ADD COLUMN throttle_notice_at DATETIME;
@@ -0,0 +1,2 @@
+-- When the request was last reactivated; notifications sent before it no longer count towards it.
+ALTER TABLE schniff_requests ADD COLUMN notified_since DATETIME;
@@ -0,0 +1,2 @@
+-- Wrong verification codes entered for the pending address; too many and the code stops working.
+ALTER TABLE user_emails ADD COLUMN attempts INTEGER DEFAULT 0;
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MissedOpening is a campsite/date that opened inside one of the user's schniff windows
+// and was booked again before they got to it.
+type MissedOpening struct {
+	Provider       string
+	CampgroundID   string
+	CampgroundName string
+	CampsiteID     string
+	Date           time.Time
+	OpenedAt       time.Time
+	ClosedAt       time.Time
+}
+
+// Open returns how long the campsite stayed available.
+func (m MissedOpening) Open() time.Duration {
+	return m.ClosedAt.Sub(m.OpenedAt)
+}
+
+// GetMissedOpenings pairs available→unavailable state changes that fall within any of the user's
+// schniff windows (active or not) where the opening was recorded in the last `days` days.
+func (s *Store) GetMissedOpenings(ctx context.Context, userID string, days int) ([]MissedOpening, error) {
+	rows, err := s.ReadConnection().QueryContext(ctx, `
+		SELECT DISTINCT o.provider, o.campground_id, coalesce(c.name, o.campground_id), o.campsite_id,
+		       o.date, o.changed_at, cl.changed_at
+		FROM schniff_requests sr
+		JOIN state_changes o
+			ON  o.provider = sr.provider
+			AND o.campground_id = sr.campground_id
+			AND o.date >= sr.checkin
+			AND o.date < sr.checkout
+			AND o.new_available = 1
+		JOIN state_changes cl
+			ON  cl.provider = o.provider
+			AND cl.campground_id = o.campground_id
+			AND cl.campsite_id = o.campsite_id
+			AND cl.date = o.date
+			AND cl.new_available = 0
+			AND cl.changed_at > o.changed_at
+		LEFT JOIN campgrounds c ON c.provider = o.provider AND c.campground_id = o.campground_id
+		WHERE sr.user_id = ?
+		AND o.changed_at >= datetime('now', '-' || ? || ' days')
+		AND NOT EXISTS (
+			SELECT 1 FROM state_changes x
+			WHERE x.provider = o.provider
+			  AND x.campground_id = o.campground_id
+			  AND x.campsite_id = o.campsite_id
+			  AND x.date = o.date
+			  AND x.changed_at > o.changed_at
+			  AND x.changed_at < cl.changed_at
+		)
+		ORDER BY o.changed_at DESC, o.campground_id, o.campsite_id
+	`, userID, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query missed openings: %w", err)
+	}
+	defer rows.Close()
+
+	var out []MissedOpening
+	for rows.Next() {
+		var m MissedOpening
+		err := rows.Scan(&m.Provider, &m.CampgroundID, &m.CampgroundName, &m.CampsiteID, &m.Date, &m.OpenedAt, &m.ClosedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan missed opening: %w", err)
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
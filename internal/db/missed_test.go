@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// newTestStore opens an in-memory database with the full schema applied.
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	// in-memory databases are per connection, so pin to one
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if err := migrate(db); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	store := &Store{DB: db}
+	store.detectCampgroundFTS()
+	return store
+}
+
+func TestGetMissedOpenings(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	checkin := normalizeDay(time.Now().AddDate(0, 0, 10))
+	checkout := checkin.AddDate(0, 0, 3)
+	_, err := store.AddRequest(ctx, SchniffRequest{UserID: "user1", Provider: "p", CampgroundID: "cg1", Checkin: checkin, Checkout: checkout})
+	if err != nil {
+		t.Fatalf("AddRequest failed: %v", err)
+	}
+	_, err = store.DB.Exec(`INSERT INTO campgrounds(provider, campground_id, name, last_updated) VALUES ('p', 'cg1', 'Lakeside', datetime('now'))`)
+	if err != nil {
+		t.Fatalf("Failed to insert campground: %v", err)
+	}
+
+	insertChange := func(campsiteID string, date time.Time, available bool, ago string) {
+		t.Helper()
+		_, err := store.DB.Exec(`
+			INSERT INTO state_changes(provider, campground_id, campsite_id, date, new_available, changed_at)
+			VALUES ('p', 'cg1', ?, ?, ?, datetime('now', ?))
+		`, campsiteID, date, available, ago)
+		if err != nil {
+			t.Fatalf("Failed to insert state change: %v", err)
+		}
+	}
+
+	// opened then closed inside the window: missed
+	insertChange("site1", checkin, true, "-2 hours")
+	insertChange("site1", checkin, false, "-1 hours")
+	// opened and still open: not missed
+	insertChange("site2", checkin, true, "-2 hours")
+	// opened then closed outside the window: ignored
+	insertChange("site3", checkout, true, "-2 hours")
+	insertChange("site3", checkout, false, "-1 hours")
+	// opened and closed too long ago: ignored
+	insertChange("site4", checkin, true, "-10 days")
+	insertChange("site4", checkin, false, "-9 days")
+
+	missed, err := store.GetMissedOpenings(ctx, "user1", 7)
+	if err != nil {
+		t.Fatalf("GetMissedOpenings failed: %v", err)
+	}
+	if len(missed) != 1 {
+		t.Fatalf("Expected 1 missed opening, got %d: %+v", len(missed), missed)
+	}
+	m := missed[0]
+	if m.CampsiteID != "site1" || m.CampgroundName != "Lakeside" {
+		t.Errorf("Unexpected missed opening: %+v", m)
+	}
+	if m.Open() <= 0 {
+		t.Errorf("Expected positive open duration, got %v", m.Open())
+	}
+
+	other, err := store.GetMissedOpenings(ctx, "user2", 7)
+	if err != nil {
+		t.Fatalf("GetMissedOpenings failed: %v", err)
+	}
+	if len(other) != 0 {
+		t.Errorf("Expected no missed openings for another user, got %d", len(other))
+	}
+}
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Night filters for SchniffRequest.NightFilter. A night is named by its check-in day, so
+// "weekends" means Friday and Saturday nights.
+const (
+	NightsAny      = "any"
+	NightsWeekends = "weekends"
+	NightsWeekdays = "weekdays"
+)
+
+// ParseNightFilter normalises a user-supplied night filter, treating blank as NightsAny.
+func ParseNightFilter(s string) (string, error) {
+	switch f := strings.ToLower(strings.TrimSpace(s)); f {
+	case "", NightsAny:
+		return NightsAny, nil
+	case NightsWeekends, NightsWeekdays:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown nights filter %q, use weekends, weekdays or any", s)
+	}
+}
+
+// NightMatches reports whether the night starting on day passes the filter. Unknown or empty
+// filters match every night.
+func NightMatches(filter string, day time.Time) bool {
+	weekend := day.Weekday() == time.Friday || day.Weekday() == time.Saturday
+	switch filter {
+	case NightsWeekends:
+		return weekend
+	case NightsWeekdays:
+		return !weekend
+	default:
+		return true
+	}
+}
@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// NotificationPrefs are a user's delivery preferences. QuietStart and QuietEnd are "HH:MM" in
+// Timezone; the window may wrap past midnight (e.g. 22:00-07:00).
+type NotificationPrefs struct {
+	UserID     string
+	QuietStart string
+	QuietEnd   string
+	Timezone   string
+	Enabled    bool
+	Digest     bool   // collect openings into one periodic DM
+	WebhookURL string // notifications are also POSTed here as JSON when set
+	// RenotifyAfter is how long a campsite/night must stay booked before its reopening is notified
+	// again; quicker flaps are ignored. 0 notifies every reopening.
+	RenotifyAfter time.Duration
+	// MaxPerDay caps how many times one request notifies in 24 hours; 0 means
+	// DefaultMaxNotificationsPerDay.
+	MaxPerDay int
+	// PriceAlertBelow DMs the user when a campsite/night one of their schniffs covers drops to a
+	// nightly price under this. 0 turns price alerts off.
+	PriceAlertBelow float64
+}
+
+// DefaultMaxNotificationsPerDay is the per-request daily cap for users who haven't set their own.
+const DefaultMaxNotificationsPerDay = 20
+
+// DailyLimit returns the per-request daily notification cap, applying the default.
+func (p NotificationPrefs) DailyLimit() int {
+	if p.MaxPerDay <= 0 {
+		return DefaultMaxNotificationsPerDay
+	}
+	return p.MaxPerDay
+}
+
+// PendingNotification is a request whose notification was held back during quiet hours.
+type PendingNotification struct {
+	UserID    string
+	RequestID int64
+	QueuedAt  time.Time
+}
+
+// ParseClock parses a wall-clock "HH:MM" into minutes after midnight.
+func ParseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// InQuietHours reports whether now falls inside the quiet window in the user's timezone. The
+// start is inclusive and the end exclusive; equal start and end means no quiet hours.
+func (p NotificationPrefs) InQuietHours(now time.Time) bool {
+	if !p.Enabled {
+		return false
+	}
+	start, err := ParseClock(p.QuietStart)
+	if err != nil {
+		return false
+	}
+	end, err := ParseClock(p.QuietEnd)
+	if err != nil {
+		return false
+	}
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	// wraps past midnight
+	return minute >= start || minute < end
+}
+
+// GetNotificationPrefs returns the user's preferences, or defaults (no quiet hours) when unset.
+func (s *Store) GetNotificationPrefs(ctx context.Context, userID string) (NotificationPrefs, error) {
+	p := NotificationPrefs{UserID: userID, Timezone: "UTC"}
+	var renotifyMinutes int64
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT quiet_start, quiet_end, timezone, enabled, coalesce(digest, false), coalesce(webhook_url, ''),
+		       coalesce(renotify_after, 0), coalesce(max_per_day, 0), coalesce(price_alert_below, 0)
+		FROM notification_prefs WHERE user_id = ?
+	`, userID).Scan(&p.QuietStart, &p.QuietEnd, &p.Timezone, &p.Enabled, &p.Digest, &p.WebhookURL, &renotifyMinutes, &p.MaxPerDay, &p.PriceAlertBelow)
+	if err == sql.ErrNoRows {
+		return p, nil
+	}
+	p.RenotifyAfter = time.Duration(renotifyMinutes) * time.Minute
+	return p, err
+}
+
+// UpsertNotificationPrefs stores the user's preferences, replacing any previous ones.
+func (s *Store) UpsertNotificationPrefs(ctx context.Context, p NotificationPrefs) error {
+	_, err := s.DB.ExecContext(ctx, `
+		INSERT INTO notification_prefs(user_id, quiet_start, quiet_end, timezone, enabled, digest, webhook_url, renotify_after, max_per_day, price_alert_below, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'))
+		ON CONFLICT(user_id) DO UPDATE SET
+			quiet_start = excluded.quiet_start,
+			quiet_end = excluded.quiet_end,
+			timezone = excluded.timezone,
+			enabled = excluded.enabled,
+			digest = excluded.digest,
+			webhook_url = excluded.webhook_url,
+			renotify_after = excluded.renotify_after,
+			max_per_day = excluded.max_per_day,
+			price_alert_below = excluded.price_alert_below,
+			updated_at = excluded.updated_at
+	`, p.UserID, p.QuietStart, p.QuietEnd, p.Timezone, p.Enabled, p.Digest, p.WebhookURL, int64(p.RenotifyAfter/time.Minute), p.MaxPerDay, p.PriceAlertBelow)
+	return err
+}
+
+// QueuePendingNotification holds a request's notification until the user's quiet hours end.
+// Queuing the same request again keeps the original time.
+func (s *Store) QueuePendingNotification(ctx context.Context, userID string, requestID int64) error {
+	_, err := s.DB.ExecContext(ctx, `
+		INSERT OR IGNORE INTO pending_notifications(user_id, request_id, queued_at) VALUES (?, ?, datetime('now'))
+	`, userID, requestID)
+	return err
+}
+
+// ListPendingNotifications returns every held notification, oldest first.
+func (s *Store) ListPendingNotifications(ctx context.Context) ([]PendingNotification, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT user_id, request_id, queued_at FROM pending_notifications ORDER BY queued_at, request_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []PendingNotification
+	for rows.Next() {
+		var p PendingNotification
+		if err := rows.Scan(&p.UserID, &p.RequestID, &p.QueuedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// DeletePendingNotification removes a held notification once it has been sent or dropped.
+func (s *Store) DeletePendingNotification(ctx context.Context, userID string, requestID int64) error {
+	_, err := s.DB.ExecContext(ctx, `
+		DELETE FROM pending_notifications WHERE user_id = ? AND request_id = ?
+	`, userID, requestID)
+	return err
+}
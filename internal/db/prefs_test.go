@@ -0,0 +1,140 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInQuietHours(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2025, 7, 1, hour, minute, 0, 0, time.UTC) }
+	overnight := NotificationPrefs{QuietStart: "22:00", QuietEnd: "07:00", Timezone: "UTC", Enabled: true}
+	daytime := NotificationPrefs{QuietStart: "09:00", QuietEnd: "17:30", Timezone: "UTC", Enabled: true}
+
+	tests := []struct {
+		name  string
+		prefs NotificationPrefs
+		now   time.Time
+		want  bool
+	}{
+		{"wrap: before start", overnight, at(21, 59), false},
+		{"wrap: at start", overnight, at(22, 0), true},
+		{"wrap: before midnight", overnight, at(23, 30), true},
+		{"wrap: after midnight", overnight, at(3, 0), true},
+		{"wrap: just before end", overnight, at(6, 59), true},
+		{"wrap: at end", overnight, at(7, 0), false},
+		{"wrap: midday", overnight, at(12, 0), false},
+		{"same day: inside", daytime, at(12, 0), true},
+		{"same day: at end", daytime, at(17, 30), false},
+		{"same day: before start", daytime, at(8, 59), false},
+		{"disabled", NotificationPrefs{QuietStart: "22:00", QuietEnd: "07:00", Timezone: "UTC"}, at(23, 0), false},
+		{"empty window", NotificationPrefs{QuietStart: "22:00", QuietEnd: "22:00", Timezone: "UTC", Enabled: true}, at(22, 0), false},
+		// 06:00 UTC is 23:00 the previous day in Los Angeles (PDT)
+		{"user timezone", NotificationPrefs{QuietStart: "22:00", QuietEnd: "07:00", Timezone: "America/Los_Angeles", Enabled: true}, at(6, 0), true},
+		{"user timezone: daytime", NotificationPrefs{QuietStart: "22:00", QuietEnd: "07:00", Timezone: "America/Los_Angeles", Enabled: true}, at(20, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.prefs.InQuietHours(tt.now); got != tt.want {
+				t.Errorf("InQuietHours(%s) = %v, want %v", tt.now.Format("15:04"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotificationPrefsAndPendingQueue(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	prefs, err := store.GetNotificationPrefs(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetNotificationPrefs: %v", err)
+	}
+	if prefs.Enabled || prefs.Timezone != "UTC" {
+		t.Fatalf("expected defaults for a new user, got %+v", prefs)
+	}
+
+	want := NotificationPrefs{UserID: "u1", QuietStart: "22:00", QuietEnd: "07:00", Timezone: "America/Denver", Enabled: true}
+	if err := store.UpsertNotificationPrefs(ctx, want); err != nil {
+		t.Fatalf("UpsertNotificationPrefs: %v", err)
+	}
+	want.QuietEnd = "06:30"
+	if err := store.UpsertNotificationPrefs(ctx, want); err != nil {
+		t.Fatalf("UpsertNotificationPrefs update: %v", err)
+	}
+	if got, _ := store.GetNotificationPrefs(ctx, "u1"); got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	for _, id := range []int64{1, 2, 1} {
+		if err := store.QueuePendingNotification(ctx, "u1", id); err != nil {
+			t.Fatalf("QueuePendingNotification: %v", err)
+		}
+	}
+	pending, err := store.ListPendingNotifications(ctx)
+	if err != nil {
+		t.Fatalf("ListPendingNotifications: %v", err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("re-queuing a request should not duplicate it, got %+v", pending)
+	}
+	if err := store.DeletePendingNotification(ctx, "u1", 1); err != nil {
+		t.Fatalf("DeletePendingNotification: %v", err)
+	}
+	if pending, _ = store.ListPendingNotifications(ctx); len(pending) != 1 || pending[0].RequestID != 2 {
+		t.Fatalf("expected only request 2 left, got %+v", pending)
+	}
+}
+
+func TestRenotifyCooldownSuppressesFlapping(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	if err := store.UpsertNotificationPrefs(ctx, NotificationPrefs{UserID: "u1", Timezone: "UTC", RenotifyAfter: time.Hour}); err != nil {
+		t.Fatalf("UpsertNotificationPrefs: %v", err)
+	}
+	night := time.Date(2030, 7, 1, 0, 0, 0, 0, time.UTC)
+	req := SchniffRequest{ID: 1, UserID: "u1", Provider: "p", CampgroundID: "cg", Checkin: night, Checkout: night.AddDate(0, 0, 1), Active: true}
+	reconcile := func(available bool) map[string][]AvailabilityItem {
+		t.Helper()
+		newly, err := store.ReconcileNotifications(ctx, "p", "cg", []SchniffRequest{req}, []IncomingCampsiteState{{CampsiteID: "s1", Date: night, Available: available}})
+		if err != nil {
+			t.Fatalf("ReconcileNotifications: %v", err)
+		}
+		return newly
+	}
+	countOpens := func() int {
+		t.Helper()
+		var n int
+		if err := store.DB.QueryRowContext(ctx, `SELECT count(*) FROM notifications WHERE state = 'available'`).Scan(&n); err != nil {
+			t.Fatalf("count: %v", err)
+		}
+		return n
+	}
+
+	if newly := reconcile(true); len(newly["u1"]) != 1 {
+		t.Fatalf("first opening should notify, got %+v", newly)
+	}
+	reconcile(false)
+	closed, err := store.RecentlyClosedCampsites(ctx, "u1", "p", "cg", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("RecentlyClosedCampsites: %v", err)
+	}
+	if !closed[CampsiteNightKey("s1", night)] {
+		t.Fatalf("expected s1 to be recently closed, got %v", closed)
+	}
+
+	if newly := reconcile(true); len(newly) != 0 || countOpens() != 1 {
+		t.Fatalf("reopening within the cooldown should not notify, got %+v", newly)
+	}
+
+	if _, err := store.DB.ExecContext(ctx, `UPDATE notifications SET sent_at = CASE state WHEN 'available' THEN datetime('now', '-3 hours') ELSE datetime('now', '-2 hours') END`); err != nil {
+		t.Fatalf("backdate: %v", err)
+	}
+	if closed, _ := store.RecentlyClosedCampsites(ctx, "u1", "p", "cg", time.Now().Add(-time.Hour)); len(closed) != 0 {
+		t.Fatalf("close older than the cooldown should not count, got %v", closed)
+	}
+	if newly := reconcile(true); len(newly["u1"]) != 1 || countOpens() != 2 {
+		t.Fatalf("reopening after the cooldown should notify, got %+v", newly)
+	}
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PriceChange is a change in a tracked campsite/night's nightly price, recorded by
+// UpsertCampsiteAvailabilityBatch.
+type PriceChange struct {
+	ID           int64
+	Provider     string
+	CampgroundID string
+	CampsiteID   string
+	Date         time.Time
+	OldCost      float64 // 0 if the night had no known price before
+	NewCost      float64
+	ChangedAt    time.Time
+}
+
+// IsDrop reports whether the night got cheaper than a previously known price.
+func (c PriceChange) IsDrop() bool {
+	return c.OldCost > 0 && c.NewCost < c.OldCost
+}
+
+// ListUnprocessedPriceChanges returns the provider's price changes that price alerts haven't
+// checked yet, oldest first.
+func (s *Store) ListUnprocessedPriceChanges(ctx context.Context, provider string) ([]PriceChange, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT id, provider, campground_id, campsite_id, date, coalesce(old_cost, 0), new_cost, changed_at
+		FROM price_changes
+		WHERE provider = ? AND NOT processed
+		ORDER BY id
+	`, provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query price changes: %w", err)
+	}
+	defer rows.Close()
+
+	var out []PriceChange
+	for rows.Next() {
+		var c PriceChange
+		if err := rows.Scan(&c.ID, &c.Provider, &c.CampgroundID, &c.CampsiteID, &c.Date, &c.OldCost, &c.NewCost, &c.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan price change: %w", err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// MarkPriceChangesProcessed marks the provider's price changes up to and including upToID as
+// checked, so they aren't alerted on again. Changes in keep stay unprocessed, e.g. ones whose alert
+// couldn't be sent.
+func (s *Store) MarkPriceChangesProcessed(ctx context.Context, provider string, upToID int64, keep ...int64) error {
+	query := `
+		UPDATE price_changes SET processed = TRUE
+		WHERE provider = ? AND id <= ? AND NOT processed`
+	args := []interface{}{provider, upToID}
+	if len(keep) > 0 {
+		query += ` AND id NOT IN (` + strings.TrimSuffix(strings.Repeat("?,", len(keep)), ",") + `)`
+		for _, id := range keep {
+			args = append(args, id)
+		}
+	}
+	_, err := s.DB.ExecContext(ctx, query, args...)
+	return err
+}
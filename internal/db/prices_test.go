@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestUpsertCampsiteAvailabilityBatch_PriceChanges(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	date := normalizeDay(time.Now().AddDate(0, 0, 10))
+	upsert := func(campsiteID string, cost float64) {
+		t.Helper()
+		err := store.UpsertCampsiteAvailabilityBatch(ctx, []CampsiteAvailability{{
+			Provider: "p", CampgroundID: "cg1", CampsiteID: campsiteID,
+			Date: date, Available: true, LastChecked: time.Now(), CostPerNight: cost,
+		}})
+		if err != nil {
+			t.Fatalf("UpsertCampsiteAvailabilityBatch failed: %v", err)
+		}
+	}
+	changes := func() []PriceChange {
+		t.Helper()
+		got, err := store.ListUnprocessedPriceChanges(ctx, "p")
+		if err != nil {
+			t.Fatalf("ListUnprocessedPriceChanges failed: %v", err)
+		}
+		if err := store.MarkPriceChangesProcessed(ctx, "p", 1<<62); err != nil {
+			t.Fatalf("MarkPriceChangesProcessed failed: %v", err)
+		}
+		return got
+	}
+
+	// the first sighting of a night sets its price without counting as a change
+	upsert("priced", 30)
+	upsert("unpriced", 0)
+	if got := changes(); len(got) != 0 {
+		t.Fatalf("Expected no price changes for new nights, got %+v", got)
+	}
+
+	// a night with no known price gaining one is a new price, not a drop
+	upsert("unpriced", 25)
+	got := changes()
+	if len(got) != 1 || got[0].CampsiteID != "unpriced" || got[0].OldCost != 0 || got[0].NewCost != 25 || got[0].IsDrop() {
+		t.Fatalf("Expected a new price of 25 for unpriced, got %+v", got)
+	}
+
+	upsert("priced", 40)
+	got = changes()
+	if len(got) != 1 || got[0].OldCost != 30 || got[0].NewCost != 40 || got[0].IsDrop() {
+		t.Fatalf("Expected a rise from 30 to 40, got %+v", got)
+	}
+
+	upsert("priced", 20)
+	got = changes()
+	if len(got) != 1 || got[0].OldCost != 40 || got[0].NewCost != 20 || !got[0].IsDrop() {
+		t.Fatalf("Expected a drop from 40 to 20, got %+v", got)
+	}
+
+	// unchanged, or no price this time (the stored one is kept), records nothing
+	upsert("priced", 20)
+	upsert("priced", 0)
+	if got := changes(); len(got) != 0 {
+		t.Fatalf("Expected no price changes when the price holds, got %+v", got)
+	}
+}
+
+func TestMarkPriceChangesProcessed(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	date := normalizeDay(time.Now().AddDate(0, 0, 10))
+	for i, cost := range []float64{10, 20, 30} {
+		_, err := store.DB.Exec(`
+			INSERT INTO price_changes(provider, campground_id, campsite_id, date, old_cost, new_cost)
+			VALUES ('p', 'cg1', 'site1', ?, ?, ?)
+		`, date.AddDate(0, 0, i), cost+5, cost)
+		if err != nil {
+			t.Fatalf("Failed to insert price change: %v", err)
+		}
+	}
+
+	all, err := store.ListUnprocessedPriceChanges(ctx, "p")
+	if err != nil || len(all) != 3 {
+		t.Fatalf("Expected 3 unprocessed price changes, got %d (err %v)", len(all), err)
+	}
+	if err := store.MarkPriceChangesProcessed(ctx, "p", all[1].ID); err != nil {
+		t.Fatalf("MarkPriceChangesProcessed failed: %v", err)
+	}
+	rest, err := store.ListUnprocessedPriceChanges(ctx, "p")
+	if err != nil {
+		t.Fatalf("ListUnprocessedPriceChanges failed: %v", err)
+	}
+	if len(rest) != 1 || rest[0].ID != all[2].ID {
+		t.Errorf("Expected only the newest change left, got %+v", rest)
+	}
+
+	// kept changes stay unprocessed
+	if err := store.MarkPriceChangesProcessed(ctx, "p", all[2].ID, all[2].ID); err != nil {
+		t.Fatalf("MarkPriceChangesProcessed failed: %v", err)
+	}
+	if rest, _ := store.ListUnprocessedPriceChanges(ctx, "p"); len(rest) != 1 || rest[0].ID != all[2].ID {
+		t.Errorf("Expected the kept change left unprocessed, got %+v", rest)
+	}
+	if other, _ := store.ListUnprocessedPriceChanges(ctx, "other"); len(other) != 0 {
+		t.Errorf("Expected no changes for another provider, got %+v", other)
+	}
+}
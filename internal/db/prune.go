@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PruneStats counts the rows removed by PruneHistory.
+type PruneStats struct {
+	Availability  int64
+	StateChanges  int64
+	PriceChanges  int64
+	Notifications int64
+}
+
+// PruneHistory deletes availability, state-change and price-change rows for nights before olderThan, and
+// notifications sent before notificationsBefore (zero keeps every notification). Everything runs
+// in one transaction so a failure leaves the tables untouched.
+func (s *Store) PruneHistory(ctx context.Context, olderThan, notificationsBefore time.Time) (PruneStats, error) {
+	var stats PruneStats
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return stats, err
+	}
+	defer func() {
+		// If not committed due to early return, rollback
+		_ = tx.Rollback()
+	}()
+
+	if !notificationsBefore.IsZero() {
+		res, err := tx.ExecContext(ctx, `DELETE FROM notifications WHERE sent_at < ?`, notificationsBefore.UTC())
+		if err != nil {
+			return stats, fmt.Errorf("failed to prune notifications: %w", err)
+		}
+		stats.Notifications, _ = res.RowsAffected()
+	}
+
+	// Notifications we keep may still point at state changes about to go
+	_, err = tx.ExecContext(ctx, `
+		UPDATE notifications SET state_change_id = NULL
+		WHERE state_change_id IN (SELECT id FROM state_changes WHERE date < ?)
+	`, olderThan)
+	if err != nil {
+		return stats, fmt.Errorf("failed to detach notifications from state changes: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM state_changes WHERE date < ?`, olderThan)
+	if err != nil {
+		return stats, fmt.Errorf("failed to prune state changes: %w", err)
+	}
+	stats.StateChanges, _ = res.RowsAffected()
+
+	res, err = tx.ExecContext(ctx, `DELETE FROM price_changes WHERE date < ?`, olderThan)
+	if err != nil {
+		return stats, fmt.Errorf("failed to prune price changes: %w", err)
+	}
+	stats.PriceChanges, _ = res.RowsAffected()
+
+	res, err = tx.ExecContext(ctx, `DELETE FROM campsite_availability WHERE date < ?`, olderThan)
+	if err != nil {
+		return stats, fmt.Errorf("failed to prune availability: %w", err)
+	}
+	stats.Availability, _ = res.RowsAffected()
+
+	return stats, tx.Commit()
+}
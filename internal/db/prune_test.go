@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPruneHistoryRemovesOnlyOldRows(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	// the production DSN enables foreign keys, so prune must cope with notifications referencing state changes
+	if _, err := store.DB.Exec(`PRAGMA foreign_keys = ON`); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC()
+	cutoff := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	oldNight, recentNight := cutoff.AddDate(0, 0, -1), cutoff
+
+	reqID, err := store.AddRequest(ctx, SchniffRequest{
+		UserID: "u1", Provider: "p", CampgroundID: "cg",
+		Checkin: oldNight, Checkout: recentNight.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		t.Fatalf("AddRequest: %v", err)
+	}
+
+	for _, night := range []time.Time{oldNight, recentNight} {
+		_, err := store.DB.Exec(`INSERT INTO campsite_availability(provider, campground_id, campsite_id, date, available, last_checked) VALUES ('p', 'cg', 's1', ?, true, ?)`, night, now)
+		if err != nil {
+			t.Fatalf("insert availability: %v", err)
+		}
+	}
+	var oldChangeID int64
+	for _, night := range []time.Time{oldNight, recentNight} {
+		res, err := store.DB.Exec(`INSERT INTO state_changes(provider, campground_id, campsite_id, date, new_available, changed_at) VALUES ('p', 'cg', 's1', ?, true, ?)`, night, now)
+		if err != nil {
+			t.Fatalf("insert state change: %v", err)
+		}
+		if night.Equal(oldNight) {
+			oldChangeID, _ = res.LastInsertId()
+		}
+	}
+
+	notificationCutoff := now.Add(-24 * time.Hour)
+	insertNotification := func(sentAt time.Time, stateChangeID any) {
+		t.Helper()
+		_, err := store.DB.Exec(`INSERT INTO notifications(batch_id, request_id, user_id, provider, campground_id, campsite_id, date, state, state_change_id, sent_at) VALUES ('b', ?, 'u1', 'p', 'cg', 's1', ?, 'available', ?, ?)`,
+			reqID, oldNight, stateChangeID, sentAt)
+		if err != nil {
+			t.Fatalf("insert notification: %v", err)
+		}
+	}
+	insertNotification(now.Add(-48*time.Hour), nil)
+	// recent notification about an old night keeps its row but loses the state change link
+	insertNotification(now, oldChangeID)
+
+	stats, err := store.PruneHistory(ctx, cutoff, notificationCutoff)
+	if err != nil {
+		t.Fatalf("PruneHistory: %v", err)
+	}
+	if stats != (PruneStats{Availability: 1, StateChanges: 1, Notifications: 1}) {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	count := func(query string) int {
+		t.Helper()
+		var n int
+		if err := store.DB.QueryRow(query).Scan(&n); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		return n
+	}
+	if n := count(`SELECT count(*) FROM campsite_availability`); n != 1 {
+		t.Errorf("expected 1 availability row left, got %d", n)
+	}
+	if n := count(`SELECT count(*) FROM state_changes`); n != 1 {
+		t.Errorf("expected 1 state change left, got %d", n)
+	}
+	if n := count(`SELECT count(*) FROM notifications WHERE state_change_id IS NULL`); n != 1 {
+		t.Errorf("expected the recent notification to survive unlinked, got %d", n)
+	}
+	if n := count(`SELECT count(*) FROM notifications`); n != 1 {
+		t.Errorf("expected 1 notification left, got %d", n)
+	}
+}
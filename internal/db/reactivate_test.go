@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReactivateRequest(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	today := normalizeDay(time.Now())
+
+	id, err := store.AddRequest(ctx, SchniffRequest{UserID: "owner", Provider: "p", CampgroundID: "cg", Checkin: today.AddDate(0, 0, 3), Checkout: today.AddDate(0, 0, 5)})
+	if err != nil {
+		t.Fatalf("AddRequest: %v", err)
+	}
+	newIn, newOut := today.AddDate(0, 1, 0), today.AddDate(0, 1, 2)
+
+	if err := store.ReactivateRequest(ctx, id, "owner", newIn, newOut); err == nil || !strings.Contains(err.Error(), "already active") {
+		t.Fatalf("reactivating an active request should fail, got %v", err)
+	}
+
+	if err := store.DeactivateRequest(ctx, id, "owner"); err != nil {
+		t.Fatalf("DeactivateRequest: %v", err)
+	}
+	err = store.InsertNotificationsBatch(ctx, []Notification{{
+		RequestID: id, UserID: "owner", Provider: "p", CampgroundID: "cg", CampsiteID: "s1",
+		Date: today.AddDate(0, 0, 3), State: "available", SentAt: time.Now(),
+	}}, "batch")
+	if err != nil {
+		t.Fatalf("InsertNotificationsBatch: %v", err)
+	}
+
+	inactive, err := store.ListUserInactiveRequests(ctx, "owner", 25)
+	if err != nil {
+		t.Fatalf("ListUserInactiveRequests: %v", err)
+	}
+	if len(inactive) != 1 || inactive[0].ID != id {
+		t.Fatalf("expected the removed request to be listed, got %+v", inactive)
+	}
+	if other, _ := store.ListUserInactiveRequests(ctx, "someone-else", 25); len(other) != 0 {
+		t.Fatalf("other users shouldn't see the request, got %+v", other)
+	}
+
+	tests := []struct {
+		name     string
+		userID   string
+		in, out  time.Time
+		contains string
+	}{
+		{"not the owner", "someone-else", newIn, newOut, "not found or not owner"},
+		{"checkout before checkin", "owner", newOut, newIn, "checkin must be before checkout"},
+		{"same day", "owner", newIn, newIn, "checkin must be before checkout"},
+		{"checkin in the past", "owner", today.AddDate(0, 0, -1), newOut, "in the past"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := store.ReactivateRequest(ctx, id, tt.userID, tt.in, tt.out)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Fatalf("expected error containing %q, got %v", tt.contains, err)
+			}
+		})
+	}
+	if active, _ := store.ListUserActiveRequests(ctx, "owner"); len(active) != 0 {
+		t.Fatalf("failed reactivations must leave the request inactive, got %+v", active)
+	}
+
+	if err := store.ReactivateRequest(ctx, id, "owner", newIn, newOut); err != nil {
+		t.Fatalf("ReactivateRequest: %v", err)
+	}
+	active, err := store.ListUserActiveRequests(ctx, "owner")
+	if err != nil {
+		t.Fatalf("ListUserActiveRequests: %v", err)
+	}
+	if len(active) != 1 || !active[0].Checkin.Equal(newIn) || !active[0].Checkout.Equal(newOut) {
+		t.Fatalf("expected the request back with new dates, got %+v", active)
+	}
+	var sent int
+	if err := store.DB.QueryRowContext(ctx, `SELECT count(*) FROM notifications WHERE request_id=?`, id).Scan(&sent); err != nil {
+		t.Fatalf("count notifications: %v", err)
+	}
+	if sent != 1 {
+		t.Fatalf("reactivating should keep sent notifications, %d left", sent)
+	}
+
+	// notifications sent before reactivating no longer count as notifying the request
+	err = store.UpsertCampsiteAvailabilityBatch(ctx, []CampsiteAvailability{{
+		Provider: "p", CampgroundID: "cg", CampsiteID: "s1", Date: newIn, Available: true, LastChecked: time.Now(),
+	}})
+	if err != nil {
+		t.Fatalf("UpsertCampsiteAvailabilityBatch: %v", err)
+	}
+	changes, err := store.GetUnnotifiedStateChanges(ctx, active)
+	if err != nil || len(changes) != 1 {
+		t.Fatalf("expected one state change for the new window, got %+v, %v", changes, err)
+	}
+	err = store.InsertNotificationsBatch(ctx, []Notification{{
+		RequestID: id, UserID: "owner", Provider: "p", CampgroundID: "cg", CampsiteID: "s1",
+		Date: newIn, State: "available", StateChangeID: &changes[0].ID, SentAt: time.Now().Add(-time.Hour), Delivered: true,
+	}}, "before")
+	if err != nil {
+		t.Fatalf("InsertNotificationsBatch: %v", err)
+	}
+	if changes, err := store.GetUnnotifiedStateChanges(ctx, active); err != nil || len(changes) != 1 {
+		t.Fatalf("expected a notification from before reactivating to be ignored, got %+v, %v", changes, err)
+	}
+	if n, err := store.CountNotificationsLast24hByRequest(ctx, id); err != nil || n != 0 {
+		t.Fatalf("expected no notifications counted since reactivating, got %d, %v", n, err)
+	}
+}
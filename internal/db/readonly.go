@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// lockProbeTimeout is how long OpenReadOnlyWithFallback waits on a locked database before
+	// falling back to a copy.
+	lockProbeTimeout = 500 * time.Millisecond
+	// lockCopyTimeout is how long the fallback waits for the lock holder to let it take the copy.
+	lockCopyTimeout = 10 * time.Second
+)
+
+// OpenReadOnlyWithFallback opens path read-only. If another process holds a lock that stops it
+// being read, it waits up to lockCopyTimeout to take a consistent copy into a temp dir and opens
+// that instead, so later reads can't be locked out again. The copy holds what was committed when it
+// was taken. The returned cleanup closes the store and removes any copy; call it instead of Close.
+func OpenReadOnlyWithFallback(path string) (*Store, func(), error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil, err
+	}
+	store, err := openReadOnly(path, lockProbeTimeout)
+	if err == nil {
+		// Opening doesn't touch the file, so read the schema to find out whether it's locked.
+		_, err = store.ReadDB.Exec(`SELECT COUNT(*) FROM sqlite_master`)
+		if err == nil {
+			return store, func() { store.Close() }, nil
+		}
+		store.Close()
+	}
+	if !isLockedErr(err) {
+		return nil, nil, err
+	}
+
+	slog.Warn("database locked, opening a copy", slog.String("path", path))
+	dir, err := os.MkdirTemp("", "schniffer-readonly-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create copy dir: %w", err)
+	}
+	copyPath := filepath.Join(dir, filepath.Base(path))
+	if err := copyDatabase(context.Background(), path, copyPath, lockCopyTimeout); err != nil {
+		os.RemoveAll(dir)
+		return nil, nil, err
+	}
+	store, err = openReadOnly(copyPath, lockProbeTimeout)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, nil, fmt.Errorf("failed to open database copy: %w", err)
+	}
+	return store, func() {
+		store.Close()
+		os.RemoveAll(dir)
+	}, nil
+}
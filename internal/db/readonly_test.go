@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOpenReadOnlyWithFallback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "locked.db")
+	writer, err := sql.Open("sqlite3", path+"?_busy_timeout=0")
+	if err != nil {
+		t.Fatalf("open writer: %v", err)
+	}
+	defer writer.Close()
+	if _, err := writer.Exec(`CREATE TABLE t (v INTEGER); INSERT INTO t VALUES (1), (2)`); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	count := func(store *Store) int {
+		t.Helper()
+		var n int
+		if err := store.ReadDB.QueryRow(`SELECT COUNT(*) FROM t`).Scan(&n); err != nil {
+			t.Fatalf("count: %v", err)
+		}
+		return n
+	}
+
+	store, cleanup, err := OpenReadOnlyWithFallback(path)
+	if err != nil {
+		t.Fatalf("unlocked open: %v", err)
+	}
+	if n := count(store); n != 2 {
+		t.Errorf("unlocked open: got %d rows, want 2", n)
+	}
+	cleanup()
+
+	// An exclusive transaction locks readers out of a rollback-journal database.
+	conn, err := writer.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("writer conn: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(context.Background(), `BEGIN EXCLUSIVE`); err != nil {
+		t.Fatalf("begin exclusive: %v", err)
+	}
+	if _, err := conn.ExecContext(context.Background(), `INSERT INTO t VALUES (3)`); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	// The copy waits for the lock rather than copying the file mid-write
+	go func() {
+		time.Sleep(2 * lockProbeTimeout)
+		conn.ExecContext(context.Background(), `ROLLBACK`)
+	}()
+
+	store, cleanup, err = OpenReadOnlyWithFallback(path)
+	if err != nil {
+		t.Fatalf("locked open: %v", err)
+	}
+	if n := count(store); n != 2 {
+		t.Errorf("locked open: got %d rows, want the 2 committed", n)
+	}
+	var seq int
+	var name, file string
+	if err := store.ReadDB.QueryRow(`PRAGMA database_list`).Scan(&seq, &name, &file); err != nil || file == path {
+		t.Errorf("locked open: expected a copy, got %q (err %v)", file, err)
+	}
+	copied := store.ReadDB
+	cleanup()
+	if err := copied.Ping(); err == nil {
+		t.Error("cleanup should close the store")
+	}
+	matches, _ := filepath.Glob(filepath.Join(os.TempDir(), "schniffer-readonly-*", "locked.db"))
+	if len(matches) != 0 {
+		t.Errorf("cleanup should remove the copy, found %v", matches)
+	}
+
+	if _, _, err := OpenReadOnlyWithFallback(filepath.Join(t.TempDir(), "missing.db")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// minScarcitySample is the fewest tracked campsite-nights a campground needs to be ranked,
+// so a single booked night doesn't top the list.
+const minScarcitySample = 10
+
+// CampgroundScarcity summarises how hard a campground is to book.
+type CampgroundScarcity struct {
+	Provider       string
+	CampgroundID   string
+	CampgroundName string
+	TrackedNights  int     // campsite-nights in the window with known availability
+	BookedNights   int     // of those, how many are booked
+	Openings       int     // campsite-nights that became available over the last window days
+	Scarcity       float64 // BookedNights / TrackedNights
+}
+
+// TopCampgroundsByScarcity ranks campgrounds by the share of their campsite-nights over the next
+// `days` days that are booked. Ties go to the campground with fewer recent openings.
+func (s *Store) TopCampgroundsByScarcity(ctx context.Context, days, limit int) ([]CampgroundScarcity, error) {
+	rows, err := s.ReadConnection().QueryContext(ctx, `
+		WITH avail AS (
+			SELECT provider, campground_id,
+			       COUNT(*) AS tracked,
+			       SUM(CASE WHEN available THEN 0 ELSE 1 END) AS booked
+			FROM campsite_availability
+			WHERE date >= date('now') AND date < date('now', '+' || ? || ' days')
+			GROUP BY provider, campground_id
+			HAVING COUNT(*) >= ?
+		), opens AS (
+			SELECT provider, campground_id, COUNT(*) AS openings
+			FROM state_changes
+			WHERE new_available = 1 AND changed_at >= datetime('now', '-' || ? || ' days')
+			GROUP BY provider, campground_id
+		)
+		SELECT a.provider, a.campground_id, coalesce(c.name, a.campground_id),
+		       a.tracked, a.booked, coalesce(o.openings, 0)
+		FROM avail a
+		LEFT JOIN opens o ON o.provider = a.provider AND o.campground_id = a.campground_id
+		LEFT JOIN campgrounds c ON c.provider = a.provider AND c.campground_id = a.campground_id
+		ORDER BY CAST(a.booked AS REAL) / a.tracked DESC, coalesce(o.openings, 0) ASC, a.tracked DESC
+		LIMIT ?
+	`, days, minScarcitySample, days, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query campground scarcity: %w", err)
+	}
+	defer rows.Close()
+
+	var out []CampgroundScarcity
+	for rows.Next() {
+		var c CampgroundScarcity
+		if err := rows.Scan(&c.Provider, &c.CampgroundID, &c.CampgroundName, &c.TrackedNights, &c.BookedNights, &c.Openings); err != nil {
+			return nil, fmt.Errorf("failed to scan campground scarcity: %w", err)
+		}
+		c.Scarcity = float64(c.BookedNights) / float64(c.TrackedNights)
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTopCampgroundsByScarcity(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	_, err := store.DB.Exec(`INSERT INTO campgrounds(provider, campground_id, name, last_updated) VALUES
+		('p', 'full', 'Always Full', datetime('now')),
+		('p', 'half', 'Half Full', datetime('now')),
+		('p', 'tiny', 'Tiny', datetime('now'))`)
+	if err != nil {
+		t.Fatalf("Failed to insert campgrounds: %v", err)
+	}
+
+	start := normalizeDay(time.Now().AddDate(0, 0, 1))
+	var states []CampsiteAvailability
+	add := func(cg string, sites int, available func(i int) bool) {
+		for i := 0; i < sites; i++ {
+			for d := 0; d < 2; d++ {
+				states = append(states, CampsiteAvailability{
+					Provider: "p", CampgroundID: cg, CampsiteID: fmt.Sprintf("s%d", i),
+					Date: start.AddDate(0, 0, d), Available: available(i), LastChecked: time.Now(),
+				})
+			}
+		}
+	}
+	add("full", 6, func(int) bool { return false })
+	add("half", 6, func(i int) bool { return i%2 == 0 })
+	add("tiny", 1, func(int) bool { return false }) // too few nights to rank
+	if err := store.UpsertCampsiteAvailabilityBatch(ctx, states); err != nil {
+		t.Fatalf("UpsertCampsiteAvailabilityBatch failed: %v", err)
+	}
+
+	top, err := store.TopCampgroundsByScarcity(ctx, 30, 10)
+	if err != nil {
+		t.Fatalf("TopCampgroundsByScarcity failed: %v", err)
+	}
+	if len(top) != 2 {
+		t.Fatalf("Expected 2 ranked campgrounds, got %+v", top)
+	}
+	if top[0].CampgroundID != "full" || top[0].CampgroundName != "Always Full" || top[0].Scarcity != 1 {
+		t.Errorf("Unexpected top campground: %+v", top[0])
+	}
+	if top[1].CampgroundID != "half" || top[1].TrackedNights != 12 || top[1].BookedNights != 6 {
+		t.Errorf("Unexpected second campground: %+v", top[1])
+	}
+
+	limited, err := store.TopCampgroundsByScarcity(ctx, 30, 1)
+	if err != nil {
+		t.Fatalf("TopCampgroundsByScarcity failed: %v", err)
+	}
+	if len(limited) != 1 {
+		t.Errorf("Expected limit to apply, got %d", len(limited))
+	}
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// ScrapeFailureThreshold is how many fetches in a row a campground can fail before polling skips
+// it and the daily summary flags it.
+const ScrapeFailureThreshold = 10
+
+// ScrapeFailure is a campground whose availability fetches have been failing.
+type ScrapeFailure struct {
+	Provider            string
+	CampgroundID        string
+	CampgroundName      string // falls back to the campground ID when the campground isn't synced
+	ConsecutiveFailures int
+	LastError           string
+	LastFailedAt        time.Time
+}
+
+// RecordScrapeFailure counts another failed fetch for the campground.
+func (s *Store) RecordScrapeFailure(ctx context.Context, provider, campgroundID, lastError string, at time.Time) error {
+	_, err := s.DB.ExecContext(ctx, `
+		INSERT INTO scrape_failures (provider, campground_id, consecutive_failures, last_error, last_failed_at)
+		VALUES (?, ?, 1, ?, ?)
+		ON CONFLICT(provider, campground_id) DO UPDATE SET
+			consecutive_failures = consecutive_failures + 1,
+			last_error = excluded.last_error,
+			last_failed_at = excluded.last_failed_at
+	`, provider, campgroundID, lastError, at.UTC())
+	return err
+}
+
+// ClearScrapeFailures resets the campground's failure count after a successful fetch.
+func (s *Store) ClearScrapeFailures(ctx context.Context, provider, campgroundID string) error {
+	_, err := s.DB.ExecContext(ctx, `
+		DELETE FROM scrape_failures WHERE provider = ? AND campground_id = ?
+	`, provider, campgroundID)
+	return err
+}
+
+// ListScrapeFailures returns campgrounds that have failed at least minFailures fetches in a row,
+// most failures first.
+func (s *Store) ListScrapeFailures(ctx context.Context, minFailures int) ([]ScrapeFailure, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT f.provider, f.campground_id, coalesce(c.name, f.campground_id), f.consecutive_failures,
+			f.last_error, f.last_failed_at
+		FROM scrape_failures f
+		LEFT JOIN campgrounds c ON c.provider = f.provider AND c.campground_id = f.campground_id
+		WHERE f.consecutive_failures >= ?
+		ORDER BY f.consecutive_failures DESC, f.provider, f.campground_id
+	`, minFailures)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []ScrapeFailure
+	for rows.Next() {
+		var f ScrapeFailure
+		if err := rows.Scan(&f.Provider, &f.CampgroundID, &f.CampgroundName, &f.ConsecutiveFailures, &f.LastError, &f.LastFailedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestScrapeFailures(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	if err := store.UpsertCampground(ctx, "p", "cg1", "Pine Flat", 0, 0, 0, nil, "", 0, 0, ""); err != nil {
+		t.Fatalf("UpsertCampground: %v", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for i := 0; i < 3; i++ {
+		if err := store.RecordScrapeFailure(ctx, "p", "cg1", "status 404", now.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("RecordScrapeFailure: %v", err)
+		}
+	}
+	if err := store.RecordScrapeFailure(ctx, "p", "gone", "status 500", now); err != nil {
+		t.Fatalf("RecordScrapeFailure: %v", err)
+	}
+
+	failures, err := store.ListScrapeFailures(ctx, 2)
+	if err != nil {
+		t.Fatalf("ListScrapeFailures: %v", err)
+	}
+	if len(failures) != 1 {
+		t.Fatalf("Expected only cg1 at 2+ failures, got %+v", failures)
+	}
+	f := failures[0]
+	if f.CampgroundName != "Pine Flat" || f.ConsecutiveFailures != 3 || f.LastError != "status 404" || !f.LastFailedAt.Equal(now.Add(2*time.Minute)) {
+		t.Errorf("Unexpected failure %+v", f)
+	}
+
+	all, _ := store.ListScrapeFailures(ctx, 1)
+	if len(all) != 2 || all[1].CampgroundName != "gone" {
+		t.Errorf("Expected both campgrounds, unsynced one named by ID, got %+v", all)
+	}
+
+	if err := store.ClearScrapeFailures(ctx, "p", "cg1"); err != nil {
+		t.Fatalf("ClearScrapeFailures: %v", err)
+	}
+	if err := store.RecordScrapeFailure(ctx, "p", "cg1", "status 404", now); err != nil {
+		t.Fatalf("RecordScrapeFailure: %v", err)
+	}
+	failures, _ = store.ListScrapeFailures(ctx, 1)
+	for _, f := range failures {
+		if f.CampgroundID == "cg1" && f.ConsecutiveFailures != 1 {
+			t.Errorf("Expected the count to restart after clearing, got %d", f.ConsecutiveFailures)
+		}
+	}
+}
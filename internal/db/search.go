@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// campgroundFTSTable mirrors campgrounds(name) for full-text search. It needs SQLite built with
+// FTS5 (go build -tags sqlite_fts5); without it search falls back to LIKE.
+const campgroundFTSTable = "campgrounds_fts"
+
+// migrateCampgroundFTS creates the FTS index when FTS5 is available and rebuilds it if it has
+// drifted from campgrounds, e.g. on first run or after writes made by an FTS-less binary.
+func migrateCampgroundFTS(db *sql.DB) error {
+	_, err := db.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS ` + campgroundFTSTable + ` USING fts5(name, provider UNINDEXED, campground_id UNINDEXED)`)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such module") {
+			slog.Info("sqlite built without FTS5, campground search will use LIKE")
+			return nil
+		}
+		return fmt.Errorf("failed to create campground search index: %w", err)
+	}
+
+	var indexed, total int
+	err = db.QueryRow(`SELECT (SELECT count(*) FROM `+campgroundFTSTable+`), (SELECT count(*) FROM campgrounds)`).Scan(&indexed, &total)
+	if err != nil {
+		return fmt.Errorf("failed to count campground search index: %w", err)
+	}
+	if indexed == total {
+		return nil
+	}
+	_, err = db.Exec(`
+		DELETE FROM ` + campgroundFTSTable + `;
+		INSERT INTO ` + campgroundFTSTable + `(name, provider, campground_id) SELECT name, provider, campground_id FROM campgrounds;
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild campground search index: %w", err)
+	}
+	return nil
+}
+
+// hasCampgroundFTS reports whether the campground FTS index exists on this connection's database.
+func hasCampgroundFTS(ctx context.Context, db *sql.DB) bool {
+	var n int
+	err := db.QueryRowContext(ctx, `SELECT count(*) FROM sqlite_master WHERE type='table' AND name=?`, campgroundFTSTable).Scan(&n)
+	return err == nil && n > 0
+}
+
+// detectCampgroundFTS records whether the campground FTS index exists, once the schema is
+// migrated, so upserts and searches don't look it up every time.
+func (s *Store) detectCampgroundFTS() {
+	db := s.DB
+	if db == nil {
+		db = s.ReadDB
+	}
+	s.campgroundFTS = hasCampgroundFTS(context.Background(), db)
+}
+
+// indexCampgroundName keeps the FTS index in step with an upserted campground.
+func (s *Store) indexCampgroundName(ctx context.Context, provider, id, name string) error {
+	if !s.campgroundFTS {
+		return nil
+	}
+	_, err := s.DB.ExecContext(ctx, `
+		DELETE FROM `+campgroundFTSTable+` WHERE provider = ? AND campground_id = ?;
+		INSERT INTO `+campgroundFTSTable+`(name, provider, campground_id) VALUES (?, ?, ?);
+	`, provider, id, name, provider, id)
+	if err != nil {
+		return fmt.Errorf("failed to index campground name: %w", err)
+	}
+	return nil
+}
+
+// searchTokens splits a query into lowercase words, dropping punctuation FTS5 would treat as syntax.
+func searchTokens(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 127)
+	})
+}
+
+// SearchCampgrounds finds campgrounds whose names contain every word of query, in any order.
+// With FTS5 results are ranked by bm25; otherwise by the same exact/prefix/contains order as
+// ListCampgrounds.
+func (s *Store) SearchCampgrounds(ctx context.Context, query string, limit int) ([]Campground, error) {
+	if limit <= 0 {
+		limit = 25
+	}
+	tokens := searchTokens(query)
+	conn := s.ReadConnection()
+	if len(tokens) == 0 || !s.campgroundFTS {
+		return s.searchCampgroundsLike(ctx, conn, query, tokens, limit)
+	}
+
+	// prefix-match every token so partially typed words still match while autocompleting
+	terms := make([]string, len(tokens))
+	for i, t := range tokens {
+		terms[i] = `"` + t + `"*`
+	}
+	rows, err := conn.QueryContext(ctx, `
+		SELECT c.provider, c.campground_id, c.name, coalesce(c.latitude, 0.0), coalesce(c.longitude, 0.0), coalesce(c.rating, 0.0)
+		FROM `+campgroundFTSTable+` f
+		JOIN campgrounds c ON c.provider = f.provider AND c.campground_id = f.campground_id
+		WHERE `+campgroundFTSTable+` MATCH ?
+		ORDER BY bm25(`+campgroundFTSTable+`), c.name
+		LIMIT ?
+	`, strings.Join(terms, " AND "), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search campgrounds: %w", err)
+	}
+	return scanCampgroundRows(rows)
+}
+
+// searchCampgroundsLike requires every token to appear somewhere in the name.
+func (s *Store) searchCampgroundsLike(ctx context.Context, conn *sql.DB, query string, tokens []string, limit int) ([]Campground, error) {
+	where := []string{"1=1"}
+	args := make([]any, 0, len(tokens)+3)
+	for _, t := range tokens {
+		where = append(where, `lower(name) LIKE '%' || ? || '%'`)
+		args = append(args, t)
+	}
+	args = append(args, query, query, limit)
+	rows, err := conn.QueryContext(ctx, `
+		SELECT provider, campground_id, name, coalesce(latitude, 0.0), coalesce(longitude, 0.0), coalesce(rating, 0.0)
+		FROM campgrounds
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY
+			CASE
+				WHEN lower(name) = lower(?) THEN 0
+				WHEN lower(name) LIKE lower(?) || '%' THEN 1
+				ELSE 2
+			END,
+			name
+		LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search campgrounds: %w", err)
+	}
+	return scanCampgroundRows(rows)
+}
+
+func scanCampgroundRows(rows *sql.Rows) ([]Campground, error) {
+	defer rows.Close()
+	var out []Campground
+	for rows.Next() {
+		var c Campground
+		if err := rows.Scan(&c.Provider, &c.ID, &c.Name, &c.Lat, &c.Lon, &c.Rating); err != nil {
+			return nil, fmt.Errorf("failed to scan campground: %w", err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSearchCampgroundsMatchesTokensInAnyOrder(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	for id, name := range map[string]string{
+		"1": "Upper Pines (Yosemite)",
+		"2": "Lower Pines",
+		"3": "Yosemite Creek",
+		"4": "Pinecrest",
+	} {
+		if err := store.UpsertCampground(ctx, "recreation_gov", id, name, 0, 0, 0, nil, "", 0, 0, ""); err != nil {
+			t.Fatalf("UpsertCampground(%s): %v", id, err)
+		}
+	}
+
+	results, err := store.SearchCampgrounds(ctx, "yosemite upper pines", 25)
+	if err != nil {
+		t.Fatalf("SearchCampgrounds: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "1" {
+		t.Fatalf("expected only Upper Pines (Yosemite), got %+v", results)
+	}
+
+	results, err = store.SearchCampgrounds(ctx, "pine", 25)
+	if err != nil {
+		t.Fatalf("SearchCampgrounds: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 prefix matches for 'pine', got %+v", results)
+	}
+
+	results, err = store.SearchCampgrounds(ctx, "", 2)
+	if err != nil {
+		t.Fatalf("SearchCampgrounds: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected empty query to return limit rows, got %d", len(results))
+	}
+}
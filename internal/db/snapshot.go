@@ -0,0 +1,163 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// lockedRetries is how many times a read is retried on "database is locked" before falling back
+// to the snapshot.
+const lockedRetries = 3
+
+// snapshotBusyTimeout is how long a snapshot refresh waits for a read lock on the live database.
+const snapshotBusyTimeout = 5 * time.Second
+
+// snapshot is a read-only copy of the database used when the live database is locked by the writer.
+// db is nil once the fallback has shut down.
+type snapshot struct {
+	mu      sync.RWMutex
+	db      *sql.DB
+	path    string
+	takenAt time.Time
+}
+
+// EnableSnapshotFallback takes a copy of the database into a temp file and refreshes it every
+// interval until ctx is done. Reads through QueryReadContext that keep failing with
+// "database is locked" are then served from the copy, so they can be up to interval stale
+// (plus however long a refresh takes). When ctx is done the copy is closed, its temp files are
+// removed, and reads stop falling back.
+func (s *Store) EnableSnapshotFallback(ctx context.Context, interval time.Duration) error {
+	dir, err := os.MkdirTemp("", "schniffer-snapshot-")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot dir: %w", err)
+	}
+	s.snap = &snapshot{}
+	if err := s.refreshSnapshot(ctx, dir); err != nil {
+		s.snap = nil
+		os.RemoveAll(dir)
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		defer os.RemoveAll(dir)
+		for {
+			select {
+			case <-ctx.Done():
+				s.snap.mu.Lock()
+				s.snap.db.Close()
+				s.snap.db = nil
+				s.snap.mu.Unlock()
+				return
+			case <-ticker.C:
+				if err := s.refreshSnapshot(ctx, dir); err != nil {
+					slog.Warn("failed to refresh read snapshot", slog.Any("err", err))
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// refreshSnapshot copies the database, then swaps the copy in and removes the previous one. Reads
+// start on the snapshot while holding its read lock, so the previous copy isn't closed under a
+// query that's starting; queries already running keep their connection until their rows close.
+func (s *Store) refreshSnapshot(ctx context.Context, dir string) error {
+	var seq int
+	var name, source string
+	err := s.ReadConnection().QueryRowContext(ctx, `PRAGMA database_list`).Scan(&seq, &name, &source)
+	if err != nil {
+		return fmt.Errorf("failed to find database file: %w", err)
+	}
+	if source == "" {
+		return fmt.Errorf("snapshots need a file database")
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("snapshot-%d.sqlite", time.Now().UnixNano()))
+	if err := copyDatabase(ctx, source, path, snapshotBusyTimeout); err != nil {
+		return err
+	}
+	db, err := sql.Open("sqlite3", path+"?mode=ro&_query_only=true")
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("failed to open snapshot: %w", err)
+	}
+
+	s.snap.mu.Lock()
+	oldDB, oldPath := s.snap.db, s.snap.path
+	s.snap.db, s.snap.path, s.snap.takenAt = db, path, time.Now()
+	s.snap.mu.Unlock()
+
+	if oldDB != nil {
+		oldDB.Close()
+		os.Remove(oldPath)
+	}
+	return nil
+}
+
+// copyDatabase writes a consistent copy of the database at source to dest with VACUUM INTO, waiting
+// up to busyTimeout for a read lock. Copying the files directly can tear a copy taken mid-write.
+// The copy goes through a short-lived read-only connection, since the pooled read connections are
+// query_only, which also forbids VACUUM INTO.
+func copyDatabase(ctx context.Context, source, dest string, busyTimeout time.Duration) error {
+	src, err := sql.Open("sqlite3", fmt.Sprintf("%s?mode=ro&_busy_timeout=%d", source, busyTimeout.Milliseconds()))
+	if err != nil {
+		return fmt.Errorf("failed to open database for copy: %w", err)
+	}
+	defer src.Close()
+	if _, err := src.ExecContext(ctx, `VACUUM INTO ?`, dest); err != nil {
+		os.Remove(dest)
+		return fmt.Errorf("failed to copy database: %w", err)
+	}
+	return nil
+}
+
+// QueryReadContext runs a read query, retrying briefly if the database is locked and falling back
+// to the snapshot (when enabled) if it stays locked.
+func (s *Store) QueryReadContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var err error
+	for attempt := 0; attempt < lockedRetries; attempt++ {
+		var rows *sql.Rows
+		rows, err = s.ReadConnection().QueryContext(ctx, query, args...)
+		if err == nil || !isLockedErr(err) {
+			return rows, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Duration(attempt+1) * 50 * time.Millisecond):
+		}
+	}
+	if s.snap == nil {
+		return nil, err
+	}
+	if rows, ok, serr := s.querySnapshot(ctx, query, args...); ok {
+		return rows, serr
+	}
+	return nil, err
+}
+
+// querySnapshot runs a read query on the snapshot. ok is false when the fallback has shut down.
+func (s *Store) querySnapshot(ctx context.Context, query string, args ...interface{}) (rows *sql.Rows, ok bool, err error) {
+	// Hold the read lock until the query has its connection so a refresh can't close the copy first
+	s.snap.mu.RLock()
+	defer s.snap.mu.RUnlock()
+	if s.snap.db == nil {
+		return nil, false, nil
+	}
+	slog.Warn("database locked, serving read from snapshot", slog.Duration("age", time.Since(s.snap.takenAt)))
+	rows, err = s.snap.db.QueryContext(ctx, query, args...)
+	return rows, true, err
+}
+
+func isLockedErr(err error) bool {
+	return strings.Contains(err.Error(), "database is locked")
+}
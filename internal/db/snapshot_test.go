@@ -0,0 +1,183 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSnapshotFallback(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	checkin := normalizeDay(time.Now().AddDate(0, 0, 10))
+	_, err = store.AddRequest(ctx, SchniffRequest{UserID: "user1", Provider: "p", CampgroundID: "cg1", Checkin: checkin, Checkout: checkin.AddDate(0, 0, 1)})
+	if err != nil {
+		t.Fatalf("AddRequest failed: %v", err)
+	}
+
+	if err := store.EnableSnapshotFallback(ctx, time.Hour); err != nil {
+		t.Fatalf("EnableSnapshotFallback failed: %v", err)
+	}
+
+	// written after the snapshot, so only visible on the live database
+	_, err = store.AddRequest(ctx, SchniffRequest{UserID: "user1", Provider: "p", CampgroundID: "cg2", Checkin: checkin, Checkout: checkin.AddDate(0, 0, 1)})
+	if err != nil {
+		t.Fatalf("AddRequest failed: %v", err)
+	}
+
+	count := func() int {
+		t.Helper()
+		rows, err := store.QueryReadContext(ctx, `SELECT COUNT(*) FROM schniff_requests`)
+		if err != nil {
+			t.Fatalf("QueryReadContext failed: %v", err)
+		}
+		defer rows.Close()
+		var n int
+		for rows.Next() {
+			if err := rows.Scan(&n); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+		}
+		return n
+	}
+	if n := count(); n != 2 {
+		t.Errorf("Expected live read to see 2 requests, got %d", n)
+	}
+
+	// close the live read pool so reads fail; a non-locked error must not fall back
+	store.ReadDB.Close()
+	if _, err := store.QueryReadContext(ctx, `SELECT 1`); err == nil {
+		t.Error("Expected error from closed read pool")
+	}
+
+	if !isLockedErr(errors.New("database is locked (5) (SQLITE_BUSY)")) {
+		t.Error("Expected locked error to be detected")
+	}
+
+	// serve straight from the snapshot to check it holds the stale copy
+	rows, err := store.snap.db.QueryContext(ctx, `SELECT COUNT(*) FROM schniff_requests`)
+	if err != nil {
+		t.Fatalf("snapshot query failed: %v", err)
+	}
+	defer rows.Close()
+	var n int
+	for rows.Next() {
+		rows.Scan(&n)
+	}
+	if n != 1 {
+		t.Errorf("Expected snapshot to hold 1 request, got %d", n)
+	}
+}
+
+func TestQueryReadContextFallsBackWhileLocked(t *testing.T) {
+	// A rollback-journal database, so an exclusive transaction locks readers out
+	path := filepath.Join(t.TempDir(), "locked.db")
+	writer, err := sql.Open("sqlite3", path+"?_busy_timeout=0")
+	if err != nil {
+		t.Fatalf("open writer: %v", err)
+	}
+	defer writer.Close()
+	if _, err := writer.Exec(`CREATE TABLE t (v INTEGER); INSERT INTO t VALUES (1), (2)`); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	reader, err := sql.Open("sqlite3", path+"?mode=ro&_busy_timeout=0")
+	if err != nil {
+		t.Fatalf("open reader: %v", err)
+	}
+	// A connection reads the schema when it first prepares a query, which is where the lock shows up
+	// for the query itself rather than partway through its rows
+	reader.SetMaxIdleConns(0)
+	store := &Store{DB: writer, ReadDB: reader}
+	defer store.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := store.EnableSnapshotFallback(ctx, time.Hour); err != nil {
+		t.Fatalf("EnableSnapshotFallback failed: %v", err)
+	}
+
+	count := func(rows *sql.Rows) int {
+		t.Helper()
+		defer rows.Close()
+		var n int
+		for rows.Next() {
+			if err := rows.Scan(&n); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatalf("rows failed: %v", err)
+		}
+		return n
+	}
+
+	// Refreshes swap and close the previous copy while snapshot reads are running
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 10; i++ {
+			if err := store.refreshSnapshot(ctx, t.TempDir()); err != nil {
+				t.Errorf("refreshSnapshot failed: %v", err)
+			}
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		rows, ok, err := store.querySnapshot(ctx, `SELECT COUNT(*) FROM t`)
+		if !ok || err != nil {
+			t.Fatalf("snapshot read %d failed during refresh: ok=%v err=%v", i, ok, err)
+		}
+		if n := count(rows); n != 2 {
+			t.Fatalf("snapshot read %d: got %d rows, want 2", i, n)
+		}
+	}
+	wg.Wait()
+
+	conn, err := writer.Conn(ctx)
+	if err != nil {
+		t.Fatalf("writer conn: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, `BEGIN EXCLUSIVE`); err != nil {
+		t.Fatalf("begin exclusive: %v", err)
+	}
+	defer conn.ExecContext(context.Background(), `ROLLBACK`)
+	if _, err := conn.ExecContext(ctx, `INSERT INTO t VALUES (3)`); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	rows, err := store.QueryReadContext(ctx, `SELECT COUNT(*) FROM t`)
+	if err != nil {
+		t.Fatalf("QueryReadContext while locked: %v", err)
+	}
+	if n := count(rows); n != 2 {
+		t.Errorf("locked read: got %d rows from the snapshot, want 2", n)
+	}
+
+	// Once shut down, locked reads report the lock instead of using the closed copy
+	cancel()
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(10 * time.Millisecond) {
+		store.snap.mu.RLock()
+		closed := store.snap.db == nil
+		store.snap.mu.RUnlock()
+		if closed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("snapshot wasn't shut down")
+		}
+	}
+	if _, err := store.QueryReadContext(context.Background(), `SELECT COUNT(*) FROM t`); err == nil || !isLockedErr(err) {
+		t.Errorf("Expected the lock error after shutdown, got %v", err)
+	}
+}
@@ -3,27 +3,29 @@ package db
 import (
 	"context"
 	"database/sql"
-	"embed"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"time"
 
 	"strings"
 
+	"github.com/brensch/schniffer/internal/logctx"
 	"github.com/brensch/schniffer/internal/providers"
 	"github.com/google/uuid"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stephennancekivell/querypulse"
 )
 
-//go:embed schema.sql
-var schemaFS embed.FS
-
 type Store struct {
 	DB     *sql.DB // Read-write connection (single connection)
 	ReadDB *sql.DB // Read-only connection pool (multiple connections)
+
+	snap *snapshot // nil unless EnableSnapshotFallback was called
+
+	campgroundFTS bool // the campground FTS index exists, see detectCampgroundFTS
 }
 
 func Open(path string) (*Store, error) {
@@ -31,7 +33,7 @@ func Open(path string) (*Store, error) {
 	driverName, err := querypulse.Register("sqlite3", querypulse.Options{
 		OnSuccess: func(ctx context.Context, query string, args []any, duration time.Duration) {
 			if duration > 500*time.Millisecond {
-				slog.Info("slow query succeeded", slog.Any("args", args), slog.String("query", query), slog.Duration("took", duration))
+				slog.Info("slow query succeeded", logctx.Attr(ctx), slog.Any("args", args), slog.String("query", query), slog.Duration("took", duration))
 			}
 		},
 	})
@@ -99,7 +101,9 @@ func Open(path string) (*Store, error) {
 		return nil, err
 	}
 
-	return &Store{DB: writeDB, ReadDB: readDB}, nil
+	store := &Store{DB: writeDB, ReadDB: readDB}
+	store.detectCampgroundFTS()
+	return store, nil
 }
 
 // ReadConnection returns the appropriate database connection for read operations
@@ -115,6 +119,15 @@ func (s *Store) WriteConnection() *sql.DB {
 	return s.DB
 }
 
+// Ping checks the database is reachable. It uses the read pool when there is one, since the
+// single write connection can be held by a long batch write without the database being down.
+func (s *Store) Ping(ctx context.Context) error {
+	if s.ReadDB != nil {
+		return s.ReadDB.PingContext(ctx)
+	}
+	return s.DB.PingContext(ctx)
+}
+
 // Close closes both database connections
 func (s *Store) Close() error {
 	var err1, err2 error
@@ -206,11 +219,15 @@ func applyReadOnlyPragmas(db *sql.DB) error {
 
 // OpenReadOnly opens the database in READ_ONLY mode
 func OpenReadOnly(path string) (*Store, error) {
+	return openReadOnly(path, 5*time.Second)
+}
+
+func openReadOnly(path string, busyTimeout time.Duration) (*Store, error) {
 	// Register the wrapped SQLite driver with query logging
 	driverName, err := querypulse.Register("sqlite3", querypulse.Options{
 		OnSuccess: func(ctx context.Context, query string, args []any, duration time.Duration) {
 			if duration > 10*time.Millisecond {
-				slog.Debug("query succeeded", slog.String("query", query), slog.Duration("took", duration))
+				slog.Debug("query succeeded", logctx.Attr(ctx), slog.String("query", query), slog.Duration("took", duration))
 			}
 		},
 	})
@@ -219,7 +236,7 @@ func OpenReadOnly(path string) (*Store, error) {
 	}
 
 	// Read-only mode with optimizations
-	dsn := fmt.Sprintf("%s?mode=ro&_cache_size=-32000&_temp_store=memory&_mmap_size=268435456", path)
+	dsn := fmt.Sprintf("%s?mode=ro&_busy_timeout=%d&_cache_size=-32000&_temp_store=memory&_mmap_size=268435456", path, busyTimeout.Milliseconds())
 
 	db, err := sql.Open(driverName, dsn)
 	if err != nil {
@@ -235,16 +252,94 @@ func OpenReadOnly(path string) (*Store, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Store{ReadDB: db}, nil
+	store := &Store{ReadDB: db}
+	store.detectCampgroundFTS()
+	return store, nil
 }
 
+// migrate applies the embedded migrations that haven't run yet. The baseline (migration 1) is
+// followed by columnMigrations, which brings tables created before versioning up to the baseline,
+// and then by the later numbered migrations.
 func migrate(db *sql.DB) error {
-	schemaBytes, err := schemaFS.ReadFile("schema.sql")
+	migrations, err := loadMigrations()
 	if err != nil {
 		return err
 	}
-	_, err = db.Exec(string(schemaBytes))
-	return err
+	if len(migrations) == 0 || migrations[0].version != 1 {
+		return fmt.Errorf("missing baseline migration")
+	}
+	if err := applyMigrations(db, migrations[:1]); err != nil {
+		return err
+	}
+	if err := migrateColumns(db); err != nil {
+		return err
+	}
+	if err := applyMigrations(db, migrations[1:]); err != nil {
+		return err
+	}
+	return migrateCampgroundFTS(db)
+}
+
+// columnMigrations lists columns added to tables before migrations were versioned. The baseline
+// leaves existing tables alone, so older databases get these here. New columns go in a numbered
+// migration instead.
+var columnMigrations = []struct {
+	table, column, definition string
+}{
+	{"schniff_requests", "min_rating", "REAL DEFAULT 0"},
+	{"schniff_requests", "require_rating", "BOOLEAN DEFAULT FALSE"},
+	{"campgrounds", "delisted_at", "DATETIME"},
+	{"campsite_metadata", "type_of_use", "TEXT DEFAULT ''"},
+	{"schniff_requests", "include_day_use", "BOOLEAN DEFAULT FALSE"},
+	{"schniff_requests", "specialty_only", "BOOLEAN DEFAULT FALSE"},
+	{"campsite_availability", "cost_per_night", "REAL"},
+	{"schniff_requests", "paused", "BOOLEAN DEFAULT FALSE"},
+	{"schniff_requests", "night_filter", "TEXT DEFAULT 'any'"},
+	{"schniff_requests", "min_nights", "INTEGER DEFAULT 0"},
+	{"notification_prefs", "digest", "BOOLEAN DEFAULT FALSE"},
+	{"notification_prefs", "webhook_url", "TEXT DEFAULT ''"},
+	{"notification_prefs", "renotify_after", "INTEGER DEFAULT 0"},
+}
+
+// migrateColumns adds any missing columns from columnMigrations.
+func migrateColumns(db *sql.DB) error {
+	for _, m := range columnMigrations {
+		exists, err := columnExists(db, m.table, m.column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", m.table, m.column, m.definition)); err != nil {
+			return fmt.Errorf("failed to add %s.%s: %w", m.table, m.column, err)
+		}
+	}
+	return nil
+}
+
+func columnExists(db *sql.DB, table, column string) (bool, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			cid       int
+			name, typ string
+			notNull   int
+			dflt      sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &dflt, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
 }
 
 // Models
@@ -258,6 +353,55 @@ type SchniffRequest struct {
 	Checkout     time.Time
 	CreatedAt    time.Time
 	Active       bool
+	// MinRating only notifies about campsites rated at least this (campsite rating, else campground rating).
+	// Unrated campsites are still included unless RequireRating is set.
+	MinRating     float64
+	RequireRating bool
+	// IncludeDayUse also notifies about day-use sites, which are skipped by default.
+	IncludeDayUse bool
+	// SpecialtyOnly restricts notifications to cabins, yurts, lookouts and similar lodging.
+	SpecialtyOnly bool
+	// ReservableOnly skips walk-up and other campsites that can't be reserved ahead by site.
+	ReservableOnly bool
+	// Paused requests stay active (and still expire) but aren't polled until resumed.
+	Paused bool
+	// NightFilter limits which nights in the window count: NightsAny, NightsWeekends or NightsWeekdays.
+	NightFilter string
+	// MinNights only notifies about campsites with at least this many consecutive available nights.
+	// 0 or 1 notifies about any single night.
+	MinNights int
+	// Equipment only notifies about campsites that allow this equipment (e.g. "Tent"); empty allows any.
+	Equipment string
+	// CampsiteType only notifies about campsites of this type (e.g. "STANDARD NONELECTRIC"); empty allows any.
+	CampsiteType string
+	// FlexDays also watches this many days either side of the requested dates. 0 watches only the
+	// exact dates.
+	FlexDays int
+}
+
+// Window returns the dates watched for the request: checkin to checkout widened by FlexDays on
+// each side.
+func (r SchniffRequest) Window() (start, end time.Time) {
+	return r.Checkin.AddDate(0, 0, -r.FlexDays), r.Checkout.AddDate(0, 0, r.FlexDays)
+}
+
+// schniffRequestColumns is the column list scanned by scanSchniffRequest.
+const schniffRequestColumns = `id, user_id, provider, campground_id, checkin, checkout, created_at, active,
+		coalesce(min_rating, 0), coalesce(require_rating, false), coalesce(include_day_use, false),
+		coalesce(specialty_only, false), coalesce(paused, false), coalesce(night_filter, 'any'),
+		coalesce(min_nights, 0), coalesce(equipment, ''), coalesce(campsite_type, ''), coalesce(flex_days, 0),
+		coalesce(reservable_only, false)`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSchniffRequest(row rowScanner) (SchniffRequest, error) {
+	var r SchniffRequest
+	err := row.Scan(&r.ID, &r.UserID, &r.Provider, &r.CampgroundID, &r.Checkin, &r.Checkout, &r.CreatedAt, &r.Active,
+		&r.MinRating, &r.RequireRating, &r.IncludeDayUse, &r.SpecialtyOnly, &r.Paused, &r.NightFilter, &r.MinNights,
+		&r.Equipment, &r.CampsiteType, &r.FlexDays, &r.ReservableOnly)
+	return r, err
 }
 
 type CampsiteAvailability struct {
@@ -267,6 +411,7 @@ type CampsiteAvailability struct {
 	Date         time.Time
 	Available    bool
 	LastChecked  time.Time
+	CostPerNight float64 // 0 if unknown
 }
 
 type LookupLog struct {
@@ -314,6 +459,7 @@ type Notification struct {
 	State         string    `db:"state"`
 	StateChangeID *int64    `db:"state_change_id"`
 	SentAt        time.Time `db:"sent_at"`
+	Delivered     bool      `db:"delivered"` // the round DMed the user, rather than recording the change silently
 }
 
 // NotificationResult represents the result of checking if notifications should be sent
@@ -339,8 +485,9 @@ type IncomingCampsiteState struct {
 
 // AvailabilityItem describes a newly opened availability to notify a user about.
 type AvailabilityItem struct {
-	CampsiteID string
-	Date       time.Time
+	CampsiteID   string
+	Date         time.Time
+	CostPerNight float64 // 0 if unknown
 }
 
 type MetadataSyncType string
@@ -382,9 +529,9 @@ type DetailedSummaryStats struct {
 
 func (s *Store) AddRequest(ctx context.Context, r SchniffRequest) (int64, error) {
 	result, err := s.DB.ExecContext(ctx, `
-		INSERT INTO schniff_requests(user_id, provider, campground_id, checkin, checkout, created_at, active)
-		VALUES (?, ?, ?, ?, ?, datetime('now'), true)
-	`, r.UserID, r.Provider, r.CampgroundID, r.Checkin, r.Checkout)
+		INSERT INTO schniff_requests(user_id, provider, campground_id, checkin, checkout, created_at, active, min_rating, require_rating, include_day_use, specialty_only, night_filter, min_nights, equipment, campsite_type, flex_days, reservable_only)
+		VALUES (?, ?, ?, ?, ?, datetime('now'), true, ?, ?, ?, ?, coalesce(nullif(?, ''), 'any'), ?, ?, ?, ?, ?)
+	`, r.UserID, r.Provider, r.CampgroundID, r.Checkin, r.Checkout, r.MinRating, r.RequireRating, r.IncludeDayUse, r.SpecialtyOnly, r.NightFilter, r.MinNights, r.Equipment, r.CampsiteType, r.FlexDays, r.ReservableOnly)
 	if err != nil {
 		return 0, err
 	}
@@ -393,8 +540,8 @@ func (s *Store) AddRequest(ctx context.Context, r SchniffRequest) (int64, error)
 
 func (s *Store) ListActiveRequests(ctx context.Context) ([]SchniffRequest, error) {
 	rows, err := s.DB.QueryContext(ctx, `
-		SELECT id, user_id, provider, campground_id, checkin, checkout, created_at, active
-		FROM schniff_requests WHERE active=true
+		SELECT `+schniffRequestColumns+`
+		FROM schniff_requests WHERE active=true AND NOT coalesce(paused, false)
 	`)
 	if err != nil {
 		return nil, err
@@ -402,8 +549,7 @@ func (s *Store) ListActiveRequests(ctx context.Context) ([]SchniffRequest, error
 	defer rows.Close()
 	var out []SchniffRequest
 	for rows.Next() {
-		var r SchniffRequest
-		err := rows.Scan(&r.ID, &r.UserID, &r.Provider, &r.CampgroundID, &r.Checkin, &r.Checkout, &r.CreatedAt, &r.Active)
+		r, err := scanSchniffRequest(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -412,6 +558,23 @@ func (s *Store) ListActiveRequests(ctx context.Context) ([]SchniffRequest, error
 	return out, rows.Err()
 }
 
+// GetRequestByID loads a single request whatever its owner or state. found is false when there is
+// no request with that ID.
+func (s *Store) GetRequestByID(ctx context.Context, id int64) (SchniffRequest, bool, error) {
+	row := s.DB.QueryRowContext(ctx, `
+		SELECT `+schniffRequestColumns+`
+		FROM schniff_requests WHERE id=?
+	`, id)
+	r, err := scanSchniffRequest(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return SchniffRequest{}, false, nil
+		}
+		return SchniffRequest{}, false, err
+	}
+	return r, true, nil
+}
+
 func (s *Store) DeactivateRequest(ctx context.Context, id int64, userID string) error {
 	res, err := s.DB.ExecContext(ctx, `
 		UPDATE schniff_requests SET active=false WHERE id=? AND user_id=?
@@ -426,10 +589,95 @@ func (s *Store) DeactivateRequest(ctx context.Context, id int64, userID string)
 	return nil
 }
 
+// ReactivateRequest brings back one of the user's removed or expired requests for new dates. The
+// request's earlier notifications stop counting towards it (see notifiedSinceReactivated), so
+// availability in the new window is alerted afresh; they're kept for history and stats.
+func (s *Store) ReactivateRequest(ctx context.Context, id int64, userID string, newCheckin, newCheckout time.Time) error {
+	newCheckin, newCheckout = normalizeDay(newCheckin), normalizeDay(newCheckout)
+	if !newCheckin.Before(newCheckout) {
+		return errors.New("checkin must be before checkout")
+	}
+	if newCheckin.Before(normalizeDay(time.Now())) {
+		return errors.New("checkin can't be in the past")
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// If not committed due to early return, rollback
+		_ = tx.Rollback()
+	}()
+
+	var active bool
+	err = tx.QueryRowContext(ctx, `SELECT active FROM schniff_requests WHERE id=? AND user_id=?`, id, userID).Scan(&active)
+	if err == sql.ErrNoRows {
+		return errors.New("not found or not owner")
+	}
+	if err != nil {
+		return err
+	}
+	if active {
+		return fmt.Errorf("schniff %d is already active", id)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE schniff_requests
+		SET active=true, paused=false, checkin=?, checkout=?, notified_since=?, throttle_notice_at=NULL
+		WHERE id=?
+	`, newCheckin, newCheckout, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetRequestActive pauses (active=false) or resumes (active=true) one of the user's active requests
+// by setting its paused flag; the active column itself isn't touched. Paused requests still show in
+// the user's list and still expire, but ListActiveRequests skips them. Requests that were removed
+// or expired can't be paused or resumed.
+func (s *Store) SetRequestActive(ctx context.Context, id int64, userID string, active bool) error {
+	res, err := s.DB.ExecContext(ctx, `
+		UPDATE schniff_requests SET paused=? WHERE id=? AND user_id=? AND active=true
+	`, !active, id, userID)
+	if err != nil {
+		return err
+	}
+	a, _ := res.RowsAffected()
+	if a == 0 {
+		return errors.New("not found or not owner")
+	}
+	return nil
+}
+
+// DeactivateUserRequests marks all of a user's active requests inactive and returns how many were changed.
+func (s *Store) DeactivateUserRequests(ctx context.Context, userID string) (int64, error) {
+	res, err := s.DB.ExecContext(ctx, `
+		UPDATE schniff_requests SET active=false WHERE user_id=? AND active=true
+	`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// DeactivateUserRequestsBefore marks the user's active requests that check out before date
+// inactive and returns how many were changed.
+func (s *Store) DeactivateUserRequestsBefore(ctx context.Context, userID string, date time.Time) (int64, error) {
+	res, err := s.DB.ExecContext(ctx, `
+		UPDATE schniff_requests SET active=false WHERE user_id=? AND active=true AND date(checkout) < ?
+	`, userID, normalizeDay(date).Format("2006-01-02"))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
 // Convenience: list active requests for a specific user
 func (s *Store) ListUserActiveRequests(ctx context.Context, userID string) ([]SchniffRequest, error) {
 	rows, err := s.DB.QueryContext(ctx, `
-		SELECT id, user_id, provider, campground_id, checkin, checkout, created_at, active
+		SELECT `+schniffRequestColumns+`
 		FROM schniff_requests WHERE active=true AND user_id=?
 	`, userID)
 	if err != nil {
@@ -438,8 +686,67 @@ func (s *Store) ListUserActiveRequests(ctx context.Context, userID string) ([]Sc
 	defer rows.Close()
 	var out []SchniffRequest
 	for rows.Next() {
-		var r SchniffRequest
-		err := rows.Scan(&r.ID, &r.UserID, &r.Provider, &r.CampgroundID, &r.Checkin, &r.Checkout, &r.CreatedAt, &r.Active)
+		r, err := scanSchniffRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// SchniffRequestDetailed is a SchniffRequest joined with the campground's display name.
+type SchniffRequestDetailed struct {
+	SchniffRequest
+	CampgroundName string // falls back to the campground ID when the campground isn't synced
+}
+
+// ListUserActiveRequestsDetailed lists a user's active requests with campground names in one query.
+func (s *Store) ListUserActiveRequestsDetailed(ctx context.Context, userID string) ([]SchniffRequestDetailed, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT `+schniffRequestDetailedColumns+`
+		FROM schniff_requests sr
+		LEFT JOIN campgrounds c ON c.provider = sr.provider AND c.campground_id = sr.campground_id
+		WHERE sr.active=true AND sr.user_id=?
+		ORDER BY sr.id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanSchniffRequestsDetailed(rows)
+}
+
+// ListUserInactiveRequests lists up to limit of a user's removed or expired requests, newest first,
+// with campground names.
+func (s *Store) ListUserInactiveRequests(ctx context.Context, userID string, limit int) ([]SchniffRequestDetailed, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT `+schniffRequestDetailedColumns+`
+		FROM schniff_requests sr
+		LEFT JOIN campgrounds c ON c.provider = sr.provider AND c.campground_id = sr.campground_id
+		WHERE sr.active=false AND sr.user_id=?
+		ORDER BY sr.id DESC
+		LIMIT ?
+	`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanSchniffRequestsDetailed(rows)
+}
+
+const schniffRequestDetailedColumns = `sr.id, sr.user_id, sr.provider, sr.campground_id, sr.checkin, sr.checkout, sr.created_at, sr.active,
+		       coalesce(sr.min_rating, 0), coalesce(sr.require_rating, false), coalesce(sr.include_day_use, false),
+		       coalesce(sr.specialty_only, false), coalesce(sr.paused, false), coalesce(sr.night_filter, 'any'),
+		       coalesce(sr.min_nights, 0), coalesce(sr.equipment, ''), coalesce(sr.campsite_type, ''), coalesce(sr.flex_days, 0),
+		       coalesce(sr.reservable_only, false), coalesce(c.name, sr.campground_id)`
+
+func scanSchniffRequestsDetailed(rows *sql.Rows) ([]SchniffRequestDetailed, error) {
+	defer rows.Close()
+	var out []SchniffRequestDetailed
+	for rows.Next() {
+		var r SchniffRequestDetailed
+		err := rows.Scan(&r.ID, &r.UserID, &r.Provider, &r.CampgroundID, &r.Checkin, &r.Checkout, &r.CreatedAt, &r.Active,
+			&r.MinRating, &r.RequireRating, &r.IncludeDayUse, &r.SpecialtyOnly, &r.Paused, &r.NightFilter, &r.MinNights,
+			&r.Equipment, &r.CampsiteType, &r.FlexDays, &r.ReservableOnly, &r.CampgroundName)
 		if err != nil {
 			return nil, err
 		}
@@ -499,7 +806,7 @@ func (s *Store) DeactivateExpiredRequests(ctx context.Context) ([]SchniffRequest
 	return deactivatedRequests, nil
 }
 
-// UpsertCampsiteAvailabilityBatch updates availability and detects state changes
+// UpsertCampsiteAvailabilityBatch updates availability and detects state and price changes
 func (s *Store) UpsertCampsiteAvailabilityBatch(ctx context.Context, states []CampsiteAvailability) error {
 	if len(states) == 0 {
 		return nil
@@ -549,7 +856,8 @@ func (s *Store) upsertCampsiteAvailabilityChunk(ctx context.Context, states []Ca
             campsite_id TEXT,
             date TEXT,
             available INTEGER,
-            last_checked TEXT
+            last_checked TEXT,
+            cost_per_night REAL
         );
     `, tableName)
 
@@ -568,8 +876,8 @@ func (s *Store) upsertCampsiteAvailabilityChunk(ctx context.Context, states []Ca
 	// Prepare the insert statement with the unique table name.
 	insertSQL := fmt.Sprintf(`
         INSERT INTO %s 
-        (provider, campground_id, campsite_id, date, available, last_checked) 
-        VALUES (?, ?, ?, ?, ?, ?);
+        (provider, campground_id, campsite_id, date, available, last_checked, cost_per_night) 
+        VALUES (?, ?, ?, ?, ?, ?, nullif(?, 0));
     `, tableName)
 	stmt, err := tx.PrepareContext(ctx, insertSQL)
 	if err != nil {
@@ -579,7 +887,7 @@ func (s *Store) upsertCampsiteAvailabilityChunk(ctx context.Context, states []Ca
 
 	// 2. Insert all states into the temporary table.
 	for _, st := range states {
-		_, err := stmt.ExecContext(ctx, st.Provider, st.CampgroundID, st.CampsiteID, st.Date, st.Available, st.LastChecked)
+		_, err := stmt.ExecContext(ctx, st.Provider, st.CampgroundID, st.CampsiteID, st.Date, st.Available, st.LastChecked, st.CostPerNight)
 		if err != nil {
 			return fmt.Errorf("insert into temp table: %w", err)
 		}
@@ -603,16 +911,48 @@ func (s *Store) upsertCampsiteAvailabilityChunk(ctx context.Context, states []Ca
 		return fmt.Errorf("insert state_changes from temp table: %w", err)
 	}
 
-	// 4. Upsert into the main availability table.
+	// 4. Record price changes on campsite/nights we already track, using the price the upsert
+	// below will store. Brand new nights only set a starting price, so they aren't changes.
+	sqlPrices := fmt.Sprintf(`
+        INSERT INTO price_changes (provider, campground_id, campsite_id, date, old_cost, new_cost, changed_at)
+        SELECT provider, campground_id, campsite_id, date, old_cost, new_cost, CURRENT_TIMESTAMP
+        FROM (
+            SELECT ns.provider, ns.campground_id, ns.campsite_id, ns.date, ca.cost_per_night AS old_cost,
+                coalesce(ns.cost_per_night, nullif(cm.cost_per_night, 0)) AS new_cost
+            FROM %s AS ns
+            JOIN campsite_availability AS ca
+                ON  ca.provider = ns.provider
+                AND ca.campground_id = ns.campground_id
+                AND ca.campsite_id = ns.campsite_id
+                AND ca.date = ns.date
+            LEFT JOIN campsite_metadata AS cm
+                ON  cm.provider = ns.provider
+                AND cm.campground_id = ns.campground_id
+                AND cm.campsite_id = ns.campsite_id
+        )
+        WHERE new_cost IS NOT NULL AND (old_cost IS NULL OR old_cost != new_cost);
+    `, tableName)
+	if _, err := tx.ExecContext(ctx, sqlPrices); err != nil {
+		return fmt.Errorf("insert price_changes from temp table: %w", err)
+	}
+
+	// 5. Upsert into the main availability table. Availability endpoints rarely carry prices, so
+	// fall back to the campsite's listed nightly cost from metadata.
 	sqlUpsert := fmt.Sprintf(`
-        INSERT INTO campsite_availability (provider, campground_id, campsite_id, date, available, last_checked)
-        SELECT provider, campground_id, campsite_id, date, available, last_checked
-        FROM %s
+        INSERT INTO campsite_availability (provider, campground_id, campsite_id, date, available, last_checked, cost_per_night)
+        SELECT ns.provider, ns.campground_id, ns.campsite_id, ns.date, ns.available, ns.last_checked,
+            coalesce(ns.cost_per_night, nullif(cm.cost_per_night, 0))
+        FROM %s AS ns
+        LEFT JOIN campsite_metadata AS cm
+            ON  cm.provider = ns.provider
+            AND cm.campground_id = ns.campground_id
+            AND cm.campsite_id = ns.campsite_id
         WHERE true
         ON CONFLICT (provider, campground_id, campsite_id, date)
         DO UPDATE SET
             available = excluded.available,
-            last_checked = excluded.last_checked;
+            last_checked = excluded.last_checked,
+            cost_per_night = coalesce(excluded.cost_per_night, campsite_availability.cost_per_night);
     `, tableName)
 	if _, err := tx.ExecContext(ctx, sqlUpsert); err != nil {
 		return fmt.Errorf("upsert availability from temp table: %w", err)
@@ -774,9 +1114,9 @@ func (s *Store) InsertNotificationsBatch(ctx context.Context, notifications []No
 	stmt, err := tx.PrepareContext(ctx, `
 		INSERT INTO notifications(
 			batch_id, request_id, user_id, provider, campground_id, 
-			campsite_id, date, state, state_change_id, sent_at
+			campsite_id, date, state, state_change_id, sent_at, delivered
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return err
@@ -786,7 +1126,7 @@ func (s *Store) InsertNotificationsBatch(ctx context.Context, notifications []No
 	for _, n := range notifications {
 		_, err := stmt.ExecContext(ctx,
 			batchID, n.RequestID, n.UserID, n.Provider, n.CampgroundID,
-			n.CampsiteID, n.Date, n.State, n.StateChangeID, n.SentAt,
+			n.CampsiteID, n.Date, n.State, n.StateChangeID, n.SentAt, n.Delivered,
 		)
 		if err != nil {
 			return err
@@ -813,6 +1153,8 @@ func (s *Store) RecordNotification(ctx context.Context, n Notification) error {
 }
 
 // ReconcileNotifications uses current campsite states to open or close notifications per user.
+// A campsite/night that was closed less than the user's renotify_after ago stays closed until it
+// reopens after the cooldown.
 func (s *Store) ReconcileNotifications(ctx context.Context, provider, campgroundID string, reqs []SchniffRequest, states []IncomingCampsiteState) (map[string][]AvailabilityItem, error) {
 	// Build per-date user -> requestID mapping from active requests
 	perDateUserReq := map[string]map[string]int64{}
@@ -822,6 +1164,9 @@ func (s *Store) ReconcileNotifications(ctx context.Context, provider, campground
 			continue
 		}
 		for d := normalizeDay(r.Checkin); d.Before(normalizeDay(r.Checkout)); d = d.AddDate(0, 0, 1) {
+			if !NightMatches(r.NightFilter, d) {
+				continue
+			}
 			key := d.Format("2006-01-02")
 			m, ok := perDateUserReq[key]
 			if !ok {
@@ -846,9 +1191,9 @@ func (s *Store) ReconcileNotifications(ctx context.Context, provider, campground
 	}()
 
 	stLast, err := tx.PrepareContext(ctx, `
-		SELECT state FROM notifications
+		SELECT state, (julianday('now') - julianday(sent_at)) * 1440 FROM notifications
 		WHERE user_id=? AND provider=? AND campground_id=? AND campsite_id=? AND date=?
-		ORDER BY sent_at DESC LIMIT 1
+		ORDER BY sent_at DESC, id DESC LIMIT 1
 	`)
 	if err != nil {
 		return nil, err
@@ -856,13 +1201,30 @@ func (s *Store) ReconcileNotifications(ctx context.Context, provider, campground
 	defer stLast.Close()
 
 	stInsert, err := tx.PrepareContext(ctx, `
-		INSERT INTO notifications(request_id, user_id, provider, campground_id, campsite_id, date, state, sent_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, datetime('now'))
+		INSERT INTO notifications(batch_id, request_id, user_id, provider, campground_id, campsite_id, date, state, sent_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, datetime('now'))
 	`)
 	if err != nil {
 		return nil, err
 	}
 	defer stInsert.Close()
+	batchID := uuid.New().String()
+
+	// renotify_after per user, in minutes
+	cooldowns := map[string]float64{}
+	for _, userReqs := range perDateUserReq {
+		for userID := range userReqs {
+			if _, ok := cooldowns[userID]; ok {
+				continue
+			}
+			var minutes float64
+			err := tx.QueryRowContext(ctx, `SELECT coalesce(renotify_after, 0) FROM notification_prefs WHERE user_id=?`, userID).Scan(&minutes)
+			if err != nil && err != sql.ErrNoRows {
+				return nil, err
+			}
+			cooldowns[userID] = minutes
+		}
+	}
 
 	newly := map[string][]AvailabilityItem{}
 	for _, st := range states {
@@ -874,14 +1236,17 @@ func (s *Store) ReconcileNotifications(ctx context.Context, provider, campground
 		for userID, reqID := range userReqs {
 			// check last
 			var last string
-			err := stLast.QueryRowContext(ctx, userID, provider, campgroundID, st.CampsiteID, normalizeDay(st.Date)).Scan(&last)
+			var minutesSince float64
+			err := stLast.QueryRowContext(ctx, userID, provider, campgroundID, st.CampsiteID, normalizeDay(st.Date)).Scan(&last, &minutesSince)
 			if err != nil && err != sql.ErrNoRows {
 				return nil, err
 			}
 			hadOpen := (err == nil && strings.EqualFold(last, "available"))
+			// closed too recently to announce a reopening yet
+			coolingDown := err == nil && !hadOpen && minutesSince < cooldowns[userID]
 			if st.Available {
-				if !hadOpen { // open it
-					_, err := stInsert.ExecContext(ctx, reqID, userID, provider, campgroundID, st.CampsiteID, normalizeDay(st.Date), "available")
+				if !hadOpen && !coolingDown { // open it
+					_, err := stInsert.ExecContext(ctx, batchID, reqID, userID, provider, campgroundID, st.CampsiteID, normalizeDay(st.Date), "available")
 					if err != nil {
 						return nil, err
 					}
@@ -889,7 +1254,7 @@ func (s *Store) ReconcileNotifications(ctx context.Context, provider, campground
 				}
 			} else { // unavailable
 				if hadOpen { // close it
-					_, err := stInsert.ExecContext(ctx, reqID, userID, provider, campgroundID, st.CampsiteID, normalizeDay(st.Date), "unavailable")
+					_, err := stInsert.ExecContext(ctx, batchID, reqID, userID, provider, campgroundID, st.CampsiteID, normalizeDay(st.Date), "unavailable")
 					if err != nil {
 						return nil, err
 					}
@@ -905,6 +1270,116 @@ func (s *Store) ReconcileNotifications(ctx context.Context, provider, campground
 	return newly, nil
 }
 
+// RecentlyClosedCampsites returns the campsite/nights of a campground whose latest notification for
+// the user is a close sent at or after since, keyed by CampsiteNightKey.
+func (s *Store) RecentlyClosedCampsites(ctx context.Context, userID, provider, campgroundID string, since time.Time) (map[string]bool, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT n.campsite_id, n.date
+		FROM notifications n
+		WHERE n.user_id = ? AND n.provider = ? AND n.campground_id = ?
+		  AND n.state = 'unavailable'
+		  AND julianday(n.sent_at) >= julianday(?)
+		  AND NOT EXISTS (
+			SELECT 1 FROM notifications later
+			WHERE later.user_id = n.user_id AND later.provider = n.provider
+			  AND later.campground_id = n.campground_id AND later.campsite_id = n.campsite_id
+			  AND later.date = n.date AND later.state = 'available'
+			  AND julianday(later.sent_at) > julianday(n.sent_at)
+		  )
+	`, userID, provider, campgroundID, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make(map[string]bool)
+	for rows.Next() {
+		var campsiteID string
+		var date time.Time
+		if err := rows.Scan(&campsiteID, &date); err != nil {
+			return nil, err
+		}
+		out[CampsiteNightKey(campsiteID, date)] = true
+	}
+	return out, rows.Err()
+}
+
+// CampsiteNightKey identifies a campsite on a given night.
+func CampsiteNightKey(campsiteID string, date time.Time) string {
+	return campsiteID + "|" + date.Format("2006-01-02")
+}
+
+// notifiedSinceReactivated is a condition on notifications n joined to their request sr that keeps
+// only those sent since the request was last reactivated.
+const notifiedSinceReactivated = `(sr.notified_since IS NULL OR julianday(n.sent_at) >= julianday(sr.notified_since))`
+
+// GetFirstAvailable returns, for requests that haven't been notified about anything yet, the
+// campsite/nights open in their window that have no "available" state change on record. They come
+// back as openings with ID 0 so a new request hears about sites that opened before state changes
+// were kept; nothing is written, and once the request has a notification they stop appearing.
+func (s *Store) GetFirstAvailable(ctx context.Context, requests []SchniffRequest) ([]StateChangeForRequest, error) {
+	if len(requests) == 0 {
+		return nil, nil
+	}
+	ids := make([]interface{}, len(requests))
+	for i, req := range requests {
+		ids[i] = req.ID
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT DISTINCT n.request_id
+		FROM notifications n JOIN schniff_requests sr ON sr.id = n.request_id
+		WHERE n.request_id IN (`+placeholders+`) AND `+notifiedSinceReactivated, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notified requests: %w", err)
+	}
+	notified := make(map[int64]bool)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		notified[id] = true
+	}
+	rows.Close()
+
+	var out []StateChangeForRequest
+	for _, req := range requests {
+		if notified[req.ID] {
+			continue
+		}
+		start, end := req.Window()
+		rows, err := s.DB.QueryContext(ctx, `
+			SELECT ca.provider, ca.campground_id, ca.campsite_id, ca.date, ca.last_checked
+			FROM campsite_availability ca
+			WHERE ca.provider = ?
+			  AND ca.campground_id = ?
+			  AND ca.date >= ?
+			  AND ca.date < ?
+			  AND ca.available = 1
+			  AND NOT EXISTS (
+				SELECT 1 FROM state_changes sc
+				WHERE sc.provider = ca.provider AND sc.campground_id = ca.campground_id
+				  AND sc.campsite_id = ca.campsite_id AND sc.date = ca.date AND sc.new_available
+			  )
+			ORDER BY ca.campsite_id, ca.date
+		`, req.Provider, req.CampgroundID, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query first available for request %d: %w", req.ID, err)
+		}
+		for rows.Next() {
+			c := StateChangeForRequest{NewAvailable: true, RequestID: req.ID}
+			if err := rows.Scan(&c.Provider, &c.CampgroundID, &c.CampsiteID, &c.Date, &c.ChangedAt); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			out = append(out, c)
+		}
+		rows.Close()
+	}
+	return out, nil
+}
+
 // GetUnnotifiedStateChanges gets state changes that haven't been notified for specific requests
 func (s *Store) GetUnnotifiedStateChanges(ctx context.Context, requests []SchniffRequest) ([]StateChangeForRequest, error) {
 	if len(requests) == 0 {
@@ -924,14 +1399,16 @@ func (s *Store) GetUnnotifiedStateChanges(ctx context.Context, requests []Schnif
 			  AND sc.date >= ? 
 			  AND sc.date < ?
 			  AND NOT EXISTS (
-				SELECT 1 FROM notifications n 
+				SELECT 1 FROM notifications n JOIN schniff_requests sr ON sr.id = n.request_id
 				WHERE n.state_change_id = sc.id 
 				  AND n.request_id = ?
+				  AND ` + notifiedSinceReactivated + `
 			  )
 			ORDER BY sc.changed_at ASC`
 
+		start, end := req.Window()
 		args := []interface{}{
-			req.ID, req.Provider, req.CampgroundID, req.Checkin, req.Checkout, req.ID,
+			req.ID, req.Provider, req.CampgroundID, start, end, req.ID,
 		}
 
 		rows, err := s.DB.QueryContext(ctx, query, args...)
@@ -956,7 +1433,7 @@ func (s *Store) GetUnnotifiedStateChanges(ctx context.Context, requests []Schnif
 } // GetCurrentlyAvailableCampsites gets all currently available campsites in a date range
 func (s *Store) GetCurrentlyAvailableCampsites(ctx context.Context, provider, campgroundID string, startDate, endDate time.Time) ([]AvailabilityItem, error) {
 	rows, err := s.DB.QueryContext(ctx, `
-		SELECT campsite_id, date
+		SELECT campsite_id, date, coalesce(cost_per_night, 0)
 		FROM campsite_availability 
 		WHERE provider=? AND campground_id=? AND date >= ? AND date < ? AND available=1
 		ORDER BY date, campsite_id
@@ -969,7 +1446,7 @@ func (s *Store) GetCurrentlyAvailableCampsites(ctx context.Context, provider, ca
 	var items []AvailabilityItem
 	for rows.Next() {
 		var item AvailabilityItem
-		err := rows.Scan(&item.CampsiteID, &item.Date)
+		err := rows.Scan(&item.CampsiteID, &item.Date, &item.CostPerNight)
 		if err != nil {
 			return nil, err
 		}
@@ -1006,16 +1483,35 @@ func (s *Store) CountLookupsSinceTime(ctx context.Context, provider, campgroundI
 	return n, row.Scan(&n)
 }
 
+// CountNotificationsLast24hByRequest counts the notification rounds that DMed the user about the
+// request in the last 24 hours. Each round records all its changes under one batch, so this is how
+// many messages the request has produced rather than how many campsite/nights changed. Rounds that
+// were recorded without a DM, e.g. throttled or deduplicated ones, don't count.
 func (s *Store) CountNotificationsLast24hByRequest(ctx context.Context, requestID int64) (int64, error) {
 	row := s.DB.QueryRowContext(ctx, `
-		SELECT coalesce(count(*),0)
-		FROM notifications
-		WHERE request_id=? AND sent_at >= datetime('now', '-1 day')
+		SELECT count(DISTINCT n.batch_id)
+		FROM notifications n JOIN schniff_requests sr ON sr.id = n.request_id
+		WHERE n.request_id=? AND n.delivered AND julianday(n.sent_at) >= julianday('now', '-1 day')
+		  AND `+notifiedSinceReactivated+`
 	`, requestID)
 	var n int64
 	return n, row.Scan(&n)
 }
 
+// ClaimThrottleNotice records that the user is being told the request hit its daily cap, unless
+// they were already told in the 24 hours before now. It reports whether the notice should be sent.
+func (s *Store) ClaimThrottleNotice(ctx context.Context, requestID int64, now time.Time) (bool, error) {
+	res, err := s.DB.ExecContext(ctx, `
+		UPDATE schniff_requests SET throttle_notice_at=?
+		WHERE id=? AND (throttle_notice_at IS NULL OR julianday(throttle_notice_at) < julianday(?))
+	`, now, requestID, now.Add(-24*time.Hour))
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n == 1, nil
+}
+
 type AvailabilityByDate struct {
 	Date  time.Time
 	Total int
@@ -1048,6 +1544,54 @@ func (s *Store) LatestAvailabilityByDate(ctx context.Context, provider, campgrou
 	return out, rows.Err()
 }
 
+// GetStoredAvailability returns the stored per-campsite state for dates in [start, end] inclusive.
+func (s *Store) GetStoredAvailability(ctx context.Context, provider, campgroundID string, start, end time.Time) ([]CampsiteAvailability, error) {
+	rows, err := s.ReadConnection().QueryContext(ctx, `
+		SELECT campsite_id, date, available, last_checked
+		FROM campsite_availability
+		WHERE provider=? AND campground_id=? AND date BETWEEN ? AND ?
+		ORDER BY campsite_id, date
+	`, provider, campgroundID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stored availability: %w", err)
+	}
+	defer rows.Close()
+
+	var out []CampsiteAvailability
+	for rows.Next() {
+		a := CampsiteAvailability{Provider: provider, CampgroundID: campgroundID}
+		if err := rows.Scan(&a.CampsiteID, &a.Date, &a.Available, &a.LastChecked); err != nil {
+			return nil, fmt.Errorf("failed to scan stored availability: %w", err)
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// LastSuccessfulLookups returns when each provider last completed a successful availability lookup.
+func (s *Store) LastSuccessfulLookups(ctx context.Context) (map[string]time.Time, error) {
+	rows, err := s.ReadConnection().QueryContext(ctx, `
+		SELECT l.provider, l.checked_at
+		FROM lookup_log l
+		JOIN (SELECT MAX(id) AS id FROM lookup_log WHERE success GROUP BY provider) latest ON latest.id = l.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query last lookups: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]time.Time)
+	for rows.Next() {
+		var provider string
+		var checkedAt time.Time
+		if err := rows.Scan(&provider, &checkedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan last lookup: %w", err)
+		}
+		out[provider] = checkedAt
+	}
+	return out, rows.Err()
+}
+
 // StatsToday returns active, lookups today, notifications today
 func (s *Store) StatsToday(ctx context.Context) (active int64, lookups int64, notes int64, err error) {
 	row := s.DB.QueryRowContext(ctx, `
@@ -1085,13 +1629,90 @@ func (s *Store) UpsertCampground(ctx context.Context, provider, id, name string,
 		INSERT OR REPLACE INTO campgrounds(provider, campground_id, name, latitude, longitude, rating, amenities, image_url, price_min, price_max, price_unit, last_updated)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, provider, id, name, lat, lon, rating, string(amenitiesJSON), imageURL, priceMin, priceMax, priceUnit, time.Now())
-	return err
+	if err != nil {
+		return err
+	}
+	return s.indexCampgroundName(ctx, provider, id, name)
+}
+
+// UpsertCampgroundsBatch stores a provider's campgrounds like UpsertCampground, committing each
+// chunk in one transaction with prepared statements instead of a round trip per campground.
+func (s *Store) UpsertCampgroundsBatch(ctx context.Context, provider string, cgs []providers.CampgroundInfo) error {
+	chunkSize := 500
+	for i := 0; i < len(cgs); i += chunkSize {
+		end := i + chunkSize
+		if end > len(cgs) {
+			end = len(cgs)
+		}
+		if err := s.upsertCampgroundsChunk(ctx, provider, cgs[i:end]); err != nil {
+			return fmt.Errorf("failed to process campground chunk %d-%d: %w", i, end, err)
+		}
+	}
+	return nil
 }
 
-// UpsertCampsiteMetadataBatch inserts all campsite metadata in a batch
-func (s *Store) UpsertCampsiteMetadataBatch(ctx context.Context, provider string, campgroundID string, metadata []providers.CampsiteInfo) error {
+func (s *Store) upsertCampgroundsChunk(ctx context.Context, provider string, cgs []providers.CampgroundInfo) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	campgroundStmt, err := tx.PrepareContext(ctx, `
+		INSERT OR REPLACE INTO campgrounds(provider, campground_id, name, latitude, longitude, rating, amenities, image_url, price_min, price_max, price_unit, last_updated)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer campgroundStmt.Close()
+
+	var unindexStmt, indexStmt *sql.Stmt
+	if s.campgroundFTS {
+		unindexStmt, err = tx.PrepareContext(ctx, `DELETE FROM `+campgroundFTSTable+` WHERE provider = ? AND campground_id = ?`)
+		if err != nil {
+			return err
+		}
+		defer unindexStmt.Close()
+		indexStmt, err = tx.PrepareContext(ctx, `INSERT INTO `+campgroundFTSTable+`(name, provider, campground_id) VALUES (?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer indexStmt.Close()
+	}
+
+	now := time.Now()
+	for _, cg := range cgs {
+		amenitiesJSON, _ := json.Marshal(cg.Amenities)
+		_, err := campgroundStmt.ExecContext(ctx, provider, cg.ID, cg.Name, cg.Lat, cg.Lon, cg.Rating, string(amenitiesJSON), cg.ImageURL, cg.PriceMin, cg.PriceMax, cg.PriceUnit, now)
+		if err != nil {
+			return err
+		}
+		if !s.campgroundFTS {
+			continue
+		}
+		if _, err := unindexStmt.ExecContext(ctx, provider, cg.ID); err != nil {
+			return fmt.Errorf("failed to index campground name: %w", err)
+		}
+		if _, err := indexStmt.ExecContext(ctx, cg.Name, provider, cg.ID); err != nil {
+			return fmt.Errorf("failed to index campground name: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UpsertCampsiteMetadataBatch inserts all campsite metadata in a batch and returns the IDs of
+// campsites not previously seen at this campground. The first sync of a campground returns none,
+// so only campsites the provider adds later count as new.
+func (s *Store) UpsertCampsiteMetadataBatch(ctx context.Context, provider string, campgroundID string, metadata []providers.CampsiteInfo) ([]string, error) {
 	if len(metadata) == 0 {
-		return nil
+		return nil, nil
+	}
+
+	newCampsites, err := s.recordCampsitesFirstSeen(ctx, provider, campgroundID, metadata)
+	if err != nil {
+		return nil, err
 	}
 
 	// Process in smaller chunks to reduce lock time
@@ -1104,7 +1725,7 @@ func (s *Store) UpsertCampsiteMetadataBatch(ctx context.Context, provider string
 			WHERE provider = ? AND campground_id = ?
 		`, provider, campgroundID)
 		if err != nil {
-			return fmt.Errorf("failed to clear existing equipment: %w", err)
+			return nil, fmt.Errorf("failed to clear existing equipment: %w", err)
 		}
 	}
 
@@ -1117,11 +1738,62 @@ func (s *Store) UpsertCampsiteMetadataBatch(ctx context.Context, provider string
 		chunk := metadata[i:end]
 		err := s.upsertCampsiteMetadataChunk(ctx, provider, campgroundID, chunk)
 		if err != nil {
-			return fmt.Errorf("failed to process metadata chunk %d-%d: %w", i, end, err)
+			return nil, fmt.Errorf("failed to process metadata chunk %d-%d: %w", i, end, err)
 		}
 	}
 
-	return nil
+	return newCampsites, nil
+}
+
+// recordCampsitesFirstSeen stamps first_seen for campsites not yet recorded and returns the ones
+// that are new to a campground we already knew campsites for.
+func (s *Store) recordCampsitesFirstSeen(ctx context.Context, provider, campgroundID string, metadata []providers.CampsiteInfo) ([]string, error) {
+	// Campgrounds synced before first_seen existed have metadata but no first_seen rows, so
+	// either table counts as having seen the campsite before.
+	existing := make(map[string]struct{})
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT campsite_id FROM campsite_metadata WHERE provider = ? AND campground_id = ?
+		UNION
+		SELECT campsite_id FROM campsite_first_seen WHERE provider = ? AND campground_id = ?
+	`, provider, campgroundID, provider, campgroundID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query known campsites: %w", err)
+	}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan known campsite: %w", err)
+		}
+		existing[id] = struct{}{}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	known := len(existing) > 0
+	now := time.Now()
+	var newCampsites []string
+	for _, m := range metadata {
+		_, err := tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO campsite_first_seen(provider, campground_id, campsite_id, first_seen)
+			VALUES (?, ?, ?, ?)
+		`, provider, campgroundID, m.ID, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record campsite first seen: %w", err)
+		}
+		if _, ok := existing[m.ID]; !ok && known {
+			newCampsites = append(newCampsites, m.ID)
+		}
+	}
+	return newCampsites, tx.Commit()
 }
 
 // upsertCampsiteMetadataChunk processes a single chunk of metadata
@@ -1137,8 +1809,8 @@ func (s *Store) upsertCampsiteMetadataChunk(ctx context.Context, provider string
 
 	// Prepare statements for efficiency
 	metadataStmt, err := tx.PrepareContext(ctx, `
-		INSERT OR REPLACE INTO campsite_metadata(provider, campground_id, campsite_id, name, campsite_type, cost_per_night, rating, last_updated, image_url)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT OR REPLACE INTO campsite_metadata(provider, campground_id, campsite_id, name, campsite_type, cost_per_night, rating, last_updated, image_url, type_of_use, reserve_type)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return err
@@ -1156,7 +1828,7 @@ func (s *Store) upsertCampsiteMetadataChunk(ctx context.Context, provider string
 
 	// Process all metadata in batch
 	for _, m := range metadata {
-		_, err := metadataStmt.ExecContext(ctx, provider, campgroundID, m.ID, m.Name, m.Type, m.CostPerNight, m.Rating, now, m.PreviewImageURL)
+		_, err := metadataStmt.ExecContext(ctx, provider, campgroundID, m.ID, m.Name, m.Type, m.CostPerNight, m.Rating, now, m.PreviewImageURL, m.TypeOfUse, m.ReserveType)
 		if err != nil {
 			return err
 		}
@@ -1173,8 +1845,9 @@ func (s *Store) upsertCampsiteMetadataChunk(ctx context.Context, provider string
 	return tx.Commit()
 }
 
-// UpdateCampgroundBasedOnCampsites updates a campground with provided campsite types and equipment arrays, plus max and min cost
-func (s *Store) UpdateCampgroundBasedOnCampsites(ctx context.Context, provider, campgroundID string, campsiteTypes, equipment []string, minPrice, maxPrice float64) error {
+// UpdateCampgroundBasedOnCampsites updates a campground with provided campsite types and equipment arrays, plus max and min cost.
+// A zero price range or empty unit leaves the stored pricing alone so unpriced syncs don't wipe it.
+func (s *Store) UpdateCampgroundBasedOnCampsites(ctx context.Context, provider, campgroundID string, campsiteTypes, equipment []string, minPrice, maxPrice float64, priceUnit string) error {
 	// Marshal to JSON
 	campsiteTypesJSON, _ := json.Marshal(campsiteTypes)
 	equipmentJSON, _ := json.Marshal(equipment)
@@ -1182,9 +1855,12 @@ func (s *Store) UpdateCampgroundBasedOnCampsites(ctx context.Context, provider,
 	// Update the campground with aggregated data
 	_, err := s.DB.ExecContext(ctx, `
 		UPDATE campgrounds 
-		SET campsite_types = ?, equipment = ?, last_updated = ?, price_min = ?, price_max = ?
+		SET campsite_types = ?, equipment = ?, last_updated = ?,
+		    price_min = CASE WHEN ? > 0 THEN ? ELSE price_min END,
+		    price_max = CASE WHEN ? > 0 THEN ? ELSE price_max END,
+		    price_unit = coalesce(nullif(?, ''), price_unit)
 		WHERE provider = ? AND campground_id = ?
-	`, string(campsiteTypesJSON), string(equipmentJSON), time.Now(), minPrice, maxPrice, provider, campgroundID)
+	`, string(campsiteTypesJSON), string(equipmentJSON), time.Now(), maxPrice, minPrice, maxPrice, maxPrice, priceUnit, provider, campgroundID)
 
 	return err
 }
@@ -1245,7 +1921,12 @@ type Campground struct {
 	Lon         float64
 	Rating      float64
 	Amenities   []string
+	ImageURL    string
+	PriceMin    float64 // 0 if unknown
+	PriceMax    float64 // 0 if unknown
+	PriceUnit   string
 	LastUpdated time.Time
+	DelistedAt  *time.Time // set when the provider no longer lists the campground
 }
 
 type CampgroundRef struct {
@@ -1263,6 +1944,22 @@ type Group struct {
 }
 
 func (s *Store) ListCampgrounds(ctx context.Context, like string) ([]Campground, error) {
+	out, _, err := s.ListCampgroundsPaged(ctx, like, 25, 0)
+	return out, err
+}
+
+// ListCampgroundsPaged is ListCampgrounds with caller-controlled paging. It returns one page of
+// rows plus the total number of matches. Ties are broken by provider and ID so pages don't
+// overlap or skip rows.
+func (s *Store) ListCampgroundsPaged(ctx context.Context, like string, limit, offset int) ([]Campground, int, error) {
+	var total int
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT count(*) FROM campgrounds WHERE lower(name) LIKE '%' || lower(?) || '%'
+	`, like).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	// Fuzzy search across campground names with simple ranking.
 	rows, err := s.DB.QueryContext(ctx, `
 		SELECT provider, campground_id, name, coalesce(latitude, 0.0), coalesce(longitude, 0.0), rating
@@ -1275,11 +1972,11 @@ func (s *Store) ListCampgrounds(ctx context.Context, like string) ([]Campground,
 				WHEN lower(name) LIKE '%' || lower(?) || '%' THEN 2
 				ELSE 3
 			END,
-			name
-		LIMIT 25
-	`, like, like, like, like)
+			name, provider, campground_id
+		LIMIT ? OFFSET ?
+	`, like, like, like, like, limit, offset)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 	var out []Campground
@@ -1287,22 +1984,37 @@ func (s *Store) ListCampgrounds(ctx context.Context, like string) ([]Campground,
 		var c Campground
 		err := rows.Scan(&c.Provider, &c.ID, &c.Name, &c.Lat, &c.Lon, &c.Rating)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		out = append(out, c)
 	}
-	return out, rows.Err()
+	return out, total, rows.Err()
 }
 
 // GetAllCampgrounds returns all campgrounds without any limit
 func (s *Store) GetAllCampgrounds(ctx context.Context) ([]Campground, error) {
-	rows, err := s.ReadConnection().QueryContext(ctx, `
+	out, _, err := s.GetAllCampgroundsPaged(ctx, -1, 0)
+	return out, err
+}
+
+// GetAllCampgroundsPaged returns one page of campgrounds ordered by name, plus the total count.
+// A negative limit returns every row from offset onwards.
+func (s *Store) GetAllCampgroundsPaged(ctx context.Context, limit, offset int) ([]Campground, int, error) {
+	conn := s.ReadConnection()
+	var total int
+	err := conn.QueryRowContext(ctx, `SELECT count(*) FROM campgrounds`).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := conn.QueryContext(ctx, `
 		SELECT provider, campground_id, name, coalesce(latitude, 0.0), coalesce(longitude, 0.0)
 		FROM campgrounds
-		ORDER BY name
-	`)
+		ORDER BY name, provider, campground_id
+		LIMIT ? OFFSET ?
+	`, limit, offset)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 	var out []Campground
@@ -1310,30 +2022,108 @@ func (s *Store) GetAllCampgrounds(ctx context.Context) ([]Campground, error) {
 		var c Campground
 		err := rows.Scan(&c.Provider, &c.ID, &c.Name, &c.Lat, &c.Lon)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		out = append(out, c)
 	}
-	return out, rows.Err()
+	return out, total, rows.Err()
 }
 
 func (s *Store) GetCampgroundByID(ctx context.Context, provider, campgroundID string) (Campground, bool, error) {
 	row := s.DB.QueryRowContext(ctx, `
-		SELECT provider, campground_id, name, coalesce(latitude, 0.0), coalesce(longitude, 0.0)
+		SELECT provider, campground_id, name, coalesce(latitude, 0.0), coalesce(longitude, 0.0), coalesce(rating, 0.0),
+		       coalesce(amenities, '[]'), coalesce(image_url, ''), coalesce(price_min, 0), coalesce(price_max, 0),
+		       coalesce(price_unit, ''), delisted_at
 		FROM campgrounds
 		WHERE provider=? AND campground_id=?
 	`, provider, campgroundID)
 	var c Campground
-	err := row.Scan(&c.Provider, &c.ID, &c.Name, &c.Lat, &c.Lon)
+	var amenitiesJSON string
+	var delistedAt sql.NullTime
+	err := row.Scan(&c.Provider, &c.ID, &c.Name, &c.Lat, &c.Lon, &c.Rating,
+		&amenitiesJSON, &c.ImageURL, &c.PriceMin, &c.PriceMax, &c.PriceUnit, &delistedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return Campground{}, false, nil
 		}
 		return Campground{}, false, err
 	}
+	if amenitiesJSON != "" {
+		if err := json.Unmarshal([]byte(amenitiesJSON), &c.Amenities); err != nil {
+			return Campground{}, false, fmt.Errorf("failed to unmarshal amenities for campground %s: %w", c.ID, err)
+		}
+	}
+	if delistedAt.Valid {
+		c.DelistedAt = &delistedAt.Time
+	}
 	return c, true, nil
 }
 
+// campgroundsByIDsChunk caps the refs looked up per query in GetCampgroundsByIDs, keeping it well
+// under SQLite's bound variable limit.
+const campgroundsByIDsChunk = 250
+
+// GetCampgroundsByIDs loads the campgrounds for refs in a query per chunk rather than one each.
+// Campgrounds are returned in refs order; refs that don't exist are left out.
+func (s *Store) GetCampgroundsByIDs(ctx context.Context, refs []CampgroundRef) ([]Campground, error) {
+	found := make(map[CampgroundRef]Campground, len(refs))
+	for start := 0; start < len(refs); start += campgroundsByIDsChunk {
+		end := min(start+campgroundsByIDsChunk, len(refs))
+		var conditions []string
+		var args []interface{}
+		for _, ref := range refs[start:end] {
+			conditions = append(conditions, "(provider = ? AND campground_id = ?)")
+			args = append(args, ref.Provider, ref.CampgroundID)
+		}
+
+		query := fmt.Sprintf(`
+			SELECT provider, campground_id, name, coalesce(latitude, 0.0), coalesce(longitude, 0.0), coalesce(rating, 0.0),
+			       coalesce(amenities, '[]'), coalesce(image_url, ''), coalesce(price_min, 0), coalesce(price_max, 0),
+			       coalesce(price_unit, ''), delisted_at
+			FROM campgrounds
+			WHERE %s
+		`, strings.Join(conditions, " OR "))
+		rows, err := s.DB.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query campgrounds by ID: %w", err)
+		}
+		for rows.Next() {
+			var c Campground
+			var amenitiesJSON string
+			var delistedAt sql.NullTime
+			if err := rows.Scan(&c.Provider, &c.ID, &c.Name, &c.Lat, &c.Lon, &c.Rating,
+				&amenitiesJSON, &c.ImageURL, &c.PriceMin, &c.PriceMax, &c.PriceUnit, &delistedAt); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan campground: %w", err)
+			}
+			if amenitiesJSON != "" {
+				if err := json.Unmarshal([]byte(amenitiesJSON), &c.Amenities); err != nil {
+					rows.Close()
+					return nil, fmt.Errorf("failed to unmarshal amenities for campground %s: %w", c.ID, err)
+				}
+			}
+			if delistedAt.Valid {
+				c.DelistedAt = &delistedAt.Time
+			}
+			found[CampgroundRef{Provider: c.Provider, CampgroundID: c.ID}] = c
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]Campground, 0, len(found))
+	for _, ref := range refs {
+		if c, ok := found[ref]; ok {
+			out = append(out, c)
+			delete(found, ref) // a repeated ref is returned once
+		}
+	}
+	return out, nil
+}
+
 // Sync helpers
 func (s *Store) RecordMetadataSync(ctx context.Context, l MetadataSyncLog) error {
 	_, err := s.DB.ExecContext(ctx, `
@@ -1481,9 +2271,15 @@ func (s *Store) GetTrackedCampgrounds(ctx context.Context) ([]string, error) {
 
 // Group methods
 
+// MaxGroupCampgrounds caps how many campgrounds a group can hold.
+const MaxGroupCampgrounds = 10
+
+// ErrGroupNotFound is returned when a group doesn't exist or belongs to someone else.
+var ErrGroupNotFound = errors.New("not found or not owner")
+
 func (s *Store) CreateGroup(ctx context.Context, userID, name string, campgrounds []CampgroundRef) (*Group, error) {
-	if len(campgrounds) > 10 {
-		return nil, errors.New("cannot create group with more than 10 campgrounds")
+	if len(campgrounds) > MaxGroupCampgrounds {
+		return nil, fmt.Errorf("cannot create group with more than %d campgrounds", MaxGroupCampgrounds)
 	}
 
 	campgroundsJSON, err := json.Marshal(campgrounds)
@@ -1572,10 +2368,50 @@ func (s *Store) GetGroup(ctx context.Context, groupID int64, userID string) (*Gr
 	return &group, nil
 }
 
+// DeleteGroup deletes one of the user's groups.
+func (s *Store) DeleteGroup(ctx context.Context, groupID int64, userID string) error {
+	res, err := s.DB.ExecContext(ctx, `
+		DELETE FROM groups WHERE id = ? AND user_id = ?
+	`, groupID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+	a, _ := res.RowsAffected()
+	if a == 0 {
+		return ErrGroupNotFound
+	}
+	return nil
+}
+
+// UpdateGroup renames one of the user's groups and replaces its campgrounds.
+func (s *Store) UpdateGroup(ctx context.Context, groupID int64, userID, name string, campgrounds []CampgroundRef) (*Group, error) {
+	if len(campgrounds) > MaxGroupCampgrounds {
+		return nil, fmt.Errorf("cannot update group to more than %d campgrounds", MaxGroupCampgrounds)
+	}
+
+	campgroundsJSON, err := json.Marshal(campgrounds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal campgrounds: %w", err)
+	}
+
+	res, err := s.DB.ExecContext(ctx, `
+		UPDATE groups SET name = ?, campgrounds = ?, updated_at = datetime('now')
+		WHERE id = ? AND user_id = ?
+	`, name, string(campgroundsJSON), groupID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update group: %w", err)
+	}
+	a, _ := res.RowsAffected()
+	if a == 0 {
+		return nil, ErrGroupNotFound
+	}
+	return s.GetGroup(ctx, groupID, userID)
+}
+
 // GetCampgroundsByProvider retrieves all campgrounds for a specific provider
 func (s *Store) GetCampgroundsByProvider(ctx context.Context, provider string) ([]Campground, error) {
 	rows, err := s.DB.QueryContext(ctx, `
-		SELECT provider, campground_id, name, latitude, longitude, rating, amenities, last_updated
+		SELECT provider, campground_id, name, latitude, longitude, rating, amenities, last_updated, delisted_at
 		FROM campgrounds 
 		WHERE provider = ?
 		ORDER BY name
@@ -1589,7 +2425,8 @@ func (s *Store) GetCampgroundsByProvider(ctx context.Context, provider string) (
 	for rows.Next() {
 		var c Campground
 		var amenitiesJSON string
-		err := rows.Scan(&c.Provider, &c.ID, &c.Name, &c.Lat, &c.Lon, &c.Rating, &amenitiesJSON, &c.LastUpdated)
+		var delistedAt sql.NullTime
+		err := rows.Scan(&c.Provider, &c.ID, &c.Name, &c.Lat, &c.Lon, &c.Rating, &amenitiesJSON, &c.LastUpdated, &delistedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan campground: %w", err)
 		}
@@ -1602,6 +2439,10 @@ func (s *Store) GetCampgroundsByProvider(ctx context.Context, provider string) (
 			}
 		}
 
+		if delistedAt.Valid {
+			c.DelistedAt = &delistedAt.Time
+		}
+
 		campgrounds = append(campgrounds, c)
 	}
 
@@ -1638,16 +2479,46 @@ func (s *Store) GetCampsiteTypesForCampground(ctx context.Context, provider, cam
 	return types, rows.Err()
 }
 
-// RefreshCampgroundTypes rebuilds the campground_types table from campsite_metadata
+// RefreshCampgroundTypes rebuilds the campground_types table from campsite_metadata.
+// The clear and repopulate run in one transaction so concurrent readers see either the old
+// or the new rows, never an empty table mid-refresh. The table and its indexes are created
+// here since they're no longer part of the schema migrations.
 func (s *Store) RefreshCampgroundTypes(ctx context.Context) error {
+	if _, err := s.BackfillCampsiteTypes(ctx); err != nil {
+		return err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin campground_types refresh: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS campground_types (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			provider TEXT NOT NULL,
+			campground_id TEXT NOT NULL,
+			campsite_type TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(provider, campground_id, campsite_type)
+		);
+		CREATE INDEX IF NOT EXISTS idx_campground_types_lookup ON campground_types(provider, campground_id);
+		CREATE INDEX IF NOT EXISTS idx_campground_types_composite ON campground_types(provider, campground_id, campsite_type);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create campground_types: %w", err)
+	}
+
 	// Clear existing data
-	_, err := s.DB.ExecContext(ctx, "DELETE FROM campground_types")
+	_, err = tx.ExecContext(ctx, "DELETE FROM campground_types")
 	if err != nil {
 		return fmt.Errorf("failed to clear campground_types: %w", err)
 	}
 
 	// Populate with current campsite type data
-	_, err = s.DB.ExecContext(ctx, `
+	_, err = tx.ExecContext(ctx, `
 		INSERT INTO campground_types (provider, campground_id, campsite_type)
 		SELECT DISTINCT provider, campground_id, campsite_type
 		FROM campsite_metadata 
@@ -1657,9 +2528,110 @@ func (s *Store) RefreshCampgroundTypes(ctx context.Context) error {
 		return fmt.Errorf("failed to populate campground_types: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit campground_types refresh: %w", err)
+	}
 	return nil
 }
 
+// BackfillCampsiteTypes infers a type for campsites synced without one, from the campsite name and
+// then its equipment, and adds the inferred types to the campground's campsite_types so they show up in
+// type filters. Campsites with an explicit type are never touched. Returns how many campsites were updated.
+func (s *Store) BackfillCampsiteTypes(ctx context.Context) (int, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT m.provider, m.campground_id, m.campsite_id, coalesce(m.name, ''),
+		       coalesce(group_concat(e.equipment_type, char(31)), '')
+		FROM campsite_metadata m
+		LEFT JOIN campsite_equipment e
+			ON e.provider = m.provider AND e.campground_id = m.campground_id AND e.campsite_id = m.campsite_id
+		WHERE m.campsite_type IS NULL OR m.campsite_type = ''
+		GROUP BY m.provider, m.campground_id, m.campsite_id
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query untyped campsites: %w", err)
+	}
+	type inferred struct {
+		provider, campgroundID, campsiteID, campsiteType string
+	}
+	var updates []inferred
+	for rows.Next() {
+		var u inferred
+		var name, equipment string
+		if err := rows.Scan(&u.provider, &u.campgroundID, &u.campsiteID, &name, &equipment); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan untyped campsite: %w", err)
+		}
+		texts := append([]string{name}, strings.Split(equipment, "\x1f")...)
+		if u.campsiteType = providers.InferCampsiteType(texts...); u.campsiteType != "" {
+			updates = append(updates, u)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(updates) == 0 {
+		return 0, nil
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	byCampground := make(map[[2]string][]string)
+	for _, u := range updates {
+		// Re-check the type is still empty so a concurrent sync's explicit type wins
+		_, err := tx.ExecContext(ctx, `
+			UPDATE campsite_metadata SET campsite_type = ?
+			WHERE provider = ? AND campground_id = ? AND campsite_id = ?
+			AND (campsite_type IS NULL OR campsite_type = '')
+		`, u.campsiteType, u.provider, u.campgroundID, u.campsiteID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to backfill campsite type: %w", err)
+		}
+		key := [2]string{u.provider, u.campgroundID}
+		byCampground[key] = append(byCampground[key], u.campsiteType)
+	}
+
+	for key, types := range byCampground {
+		var raw string
+		err := tx.QueryRowContext(ctx, `
+			SELECT coalesce(campsite_types, '[]') FROM campgrounds WHERE provider = ? AND campground_id = ?
+		`, key[0], key[1]).Scan(&raw)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		var existing []string
+		_ = json.Unmarshal([]byte(raw), &existing)
+		merged := existing
+		for _, t := range types {
+			if !slices.Contains(merged, t) {
+				merged = append(merged, t)
+			}
+		}
+		if len(merged) == len(existing) {
+			continue
+		}
+		mergedJSON, _ := json.Marshal(merged)
+		_, err = tx.ExecContext(ctx, `
+			UPDATE campgrounds SET campsite_types = ? WHERE provider = ? AND campground_id = ?
+		`, string(mergedJSON), key[0], key[1])
+		if err != nil {
+			return 0, fmt.Errorf("failed to update campground campsite types: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(updates), nil
+}
+
 // getCampgroundTypesBatch processes a small batch of campground keys
 func (s *Store) getCampgroundTypesBatch(ctx context.Context, campgroundKeys []string) (map[string][]string, error) {
 	if len(campgroundKeys) == 0 {
@@ -1718,6 +2690,20 @@ type CampsiteDetails struct {
 	Rating       float64
 	Equipment    []string
 	ImageURL     string
+	TypeOfUse    string // e.g. "Overnight" or "Day"; empty when the provider doesn't say
+	ReserveType  string // e.g. "Site-Specific" or "Walk-Up"; empty when the provider doesn't say
+}
+
+// IsDayUse reports whether the campsite is for day use only rather than overnight stays.
+func (d CampsiteDetails) IsDayUse() bool {
+	return strings.EqualFold(strings.TrimSpace(d.TypeOfUse), "day")
+}
+
+// IsReservable reports whether the campsite can be reserved ahead by site. Walk-up and non
+// site-specific campsites can't; campsites whose provider doesn't say are assumed to be.
+func (d CampsiteDetails) IsReservable() bool {
+	t := strings.TrimSpace(d.ReserveType)
+	return t == "" || strings.EqualFold(t, "site-specific")
 }
 
 // GetCampsiteDetails retrieves detailed information for a specific campsite
@@ -1795,7 +2781,8 @@ func (s *Store) GetCampsiteDetailsBatch(ctx context.Context, provider, campgroun
 	// Get metadata for all campsites
 	metadataQuery := fmt.Sprintf(`
 		SELECT campsite_id, coalesce(name, ''), coalesce(campsite_type, ''), 
-		       coalesce(cost_per_night, 0.0), coalesce(rating, 0.0), coalesce(image_url, '')
+		       coalesce(cost_per_night, 0.0), coalesce(rating, 0.0), coalesce(image_url, ''), coalesce(type_of_use, ''),
+		       coalesce(reserve_type, '')
 		FROM campsite_metadata
 		WHERE provider=? AND campground_id=? AND campsite_id IN (%s)
 	`, strings.Join(placeholders, ","))
@@ -1804,15 +2791,17 @@ func (s *Store) GetCampsiteDetailsBatch(ctx context.Context, provider, campgroun
 	if err == nil {
 		defer metadataRows.Close()
 		for metadataRows.Next() {
-			var campsiteID, name, campsiteType, imageURL string
+			var campsiteID, name, campsiteType, imageURL, typeOfUse, reserveType string
 			var costPerNight, rating float64
-			if err := metadataRows.Scan(&campsiteID, &name, &campsiteType, &costPerNight, &rating, &imageURL); err == nil {
+			if err := metadataRows.Scan(&campsiteID, &name, &campsiteType, &costPerNight, &rating, &imageURL, &typeOfUse, &reserveType); err == nil {
 				if details, exists := result[campsiteID]; exists {
 					details.Name = name
 					details.Type = campsiteType
 					details.CostPerNight = costPerNight
 					details.Rating = rating
 					details.ImageURL = imageURL
+					details.TypeOfUse = typeOfUse
+					details.ReserveType = reserveType
 					result[campsiteID] = details
 				}
 			}
@@ -1895,33 +2884,33 @@ func (s *Store) CanRequestAdhocScrapeWithTimeout(ctx context.Context, provider,
 	return count == 0, nil
 }
 
-// RequestAdhocScrape creates a new ad-hoc scrape request if one doesn't exist recently
-func (s *Store) RequestAdhocScrape(ctx context.Context, provider, campgroundID, triggeredBy, userID string) (*AdhocScrapeRequest, error) {
-	// Check if we can request a scrape
-	canRequest, err := s.CanRequestAdhocScrape(ctx, provider, campgroundID)
-	if err != nil {
-		return nil, err
-	}
-
-	if !canRequest {
-		// Return the most recent request instead of creating a new one
-		return s.GetMostRecentAdhocScrape(ctx, provider, campgroundID)
-	}
-
-	// Create new request
+// RequestAdhocScrape creates a new ad-hoc scrape request unless a pending or completed one was made
+// in the last 10 minutes, in which case that one is returned and created is false.
+// The check and insert are a single statement so concurrent callers can't both create a request.
+func (s *Store) RequestAdhocScrape(ctx context.Context, provider, campgroundID, triggeredBy, userID string) (req *AdhocScrapeRequest, created bool, err error) {
 	var id int
 	err = s.DB.QueryRowContext(ctx, `
 		INSERT INTO adhoc_scrape_requests (provider, campground_id, triggered_by, user_id)
-		VALUES (?, ?, ?, ?)
+		SELECT ?, ?, ?, ?
+		WHERE NOT EXISTS (
+			SELECT 1 FROM adhoc_scrape_requests
+			WHERE provider = ? AND campground_id = ?
+			AND requested_at > datetime('now', '-10 minutes')
+			AND status IN ('pending', 'completed')
+		)
 		RETURNING id
-	`, provider, campgroundID, triggeredBy, userID).Scan(&id)
-
+	`, provider, campgroundID, triggeredBy, userID, provider, campgroundID).Scan(&id)
+	if err == sql.ErrNoRows {
+		// A recent request already exists; hand that back instead of creating a new one
+		req, err = s.GetMostRecentAdhocScrape(ctx, provider, campgroundID)
+		return req, false, err
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to create adhoc scrape request: %w", err)
+		return nil, false, fmt.Errorf("failed to create adhoc scrape request: %w", err)
 	}
 
-	// Get the created request
-	return s.GetAdhocScrapeRequest(ctx, id)
+	req, err = s.GetAdhocScrapeRequest(ctx, id)
+	return req, err == nil, err
 }
 
 // GetAdhocScrapeRequest retrieves an ad-hoc scrape request by ID
@@ -1930,7 +2919,7 @@ func (s *Store) GetAdhocScrapeRequest(ctx context.Context, id int) (*AdhocScrape
 	var completedAt sql.NullTime
 	var errorMsg sql.NullString
 
-	err := s.ReadDB.QueryRowContext(ctx, `
+	err := s.ReadConnection().QueryRowContext(ctx, `
 		SELECT id, provider, campground_id, requested_at, triggered_by, status, completed_at, error_msg
 		FROM adhoc_scrape_requests
 		WHERE id = ?
@@ -1957,7 +2946,7 @@ func (s *Store) GetMostRecentAdhocScrape(ctx context.Context, provider, campgrou
 	var completedAt sql.NullTime
 	var errorMsg sql.NullString
 
-	err := s.ReadDB.QueryRowContext(ctx, `
+	err := s.ReadConnection().QueryRowContext(ctx, `
 		SELECT id, provider, campground_id, requested_at, triggered_by, status, completed_at, error_msg
 		FROM adhoc_scrape_requests
 		WHERE provider = ? AND campground_id = ?
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestListUserActiveRequestsDetailed(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	checkin := normalizeDay(time.Now().AddDate(0, 0, 5))
+	checkout := checkin.AddDate(0, 0, 2)
+	for _, cg := range []string{"cg1", "cg2"} {
+		_, err := store.AddRequest(ctx, SchniffRequest{UserID: "user1", Provider: "p", CampgroundID: cg, Checkin: checkin, Checkout: checkout})
+		if err != nil {
+			t.Fatalf("AddRequest failed: %v", err)
+		}
+	}
+	_, err := store.AddRequest(ctx, SchniffRequest{UserID: "user2", Provider: "p", CampgroundID: "cg1", Checkin: checkin, Checkout: checkout})
+	if err != nil {
+		t.Fatalf("AddRequest failed: %v", err)
+	}
+	err = store.UpsertCampground(ctx, "p", "cg1", "Lakeside", 0, 0, 0, nil, "", 0, 0, "night")
+	if err != nil {
+		t.Fatalf("UpsertCampground failed: %v", err)
+	}
+
+	reqs, err := store.ListUserActiveRequestsDetailed(ctx, "user1")
+	if err != nil {
+		t.Fatalf("ListUserActiveRequestsDetailed failed: %v", err)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(reqs))
+	}
+	if reqs[0].CampgroundName != "Lakeside" {
+		t.Errorf("Expected joined name 'Lakeside', got %q", reqs[0].CampgroundName)
+	}
+	if reqs[1].CampgroundName != "cg2" {
+		t.Errorf("Expected fallback to campground ID 'cg2', got %q", reqs[1].CampgroundName)
+	}
+}
+
+func TestDeactivateUserRequests(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	checkin := normalizeDay(time.Now().AddDate(0, 0, 5))
+	checkout := checkin.AddDate(0, 0, 2)
+	for _, uid := range []string{"user1", "user1", "user2"} {
+		_, err := store.AddRequest(ctx, SchniffRequest{UserID: uid, Provider: "p", CampgroundID: "cg1", Checkin: checkin, Checkout: checkout})
+		if err != nil {
+			t.Fatalf("AddRequest failed: %v", err)
+		}
+	}
+
+	count, err := store.DeactivateUserRequests(ctx, "user1")
+	if err != nil {
+		t.Fatalf("DeactivateUserRequests failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 deactivated, got %d", count)
+	}
+
+	remaining, err := store.ListActiveRequests(ctx)
+	if err != nil {
+		t.Fatalf("ListActiveRequests failed: %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].UserID != "user2" {
+		t.Errorf("Expected only user2's request to remain active, got %+v", remaining)
+	}
+}
+
+func TestLastSuccessfulLookups(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	day := normalizeDay(time.Now())
+	record := func(provider string, checkedAt time.Time, success bool) {
+		t.Helper()
+		err := store.RecordLookup(ctx, LookupLog{Provider: provider, CampgroundID: "cg1", StartDate: day, EndDate: day, CheckedAt: checkedAt, Success: success})
+		if err != nil {
+			t.Fatalf("RecordLookup failed: %v", err)
+		}
+	}
+	earlier := time.Now().Add(-time.Hour).Truncate(time.Second)
+	later := time.Now().Truncate(time.Second)
+	record("a", earlier, true)
+	record("a", later, true)
+	record("b", earlier, true)
+	record("b", later, false)
+
+	last, err := store.LastSuccessfulLookups(ctx)
+	if err != nil {
+		t.Fatalf("LastSuccessfulLookups failed: %v", err)
+	}
+	if !last["a"].Equal(later) {
+		t.Errorf("Expected provider a last lookup %v, got %v", later, last["a"])
+	}
+	if !last["b"].Equal(earlier) {
+		t.Errorf("Expected failed lookup to be ignored for provider b, got %v", last["b"])
+	}
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDeleteGroup(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	group, err := store.CreateGroup(ctx, "user1", "coast", []CampgroundRef{{Provider: "p", CampgroundID: "cg1"}})
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %v", err)
+	}
+
+	if err := store.DeleteGroup(ctx, group.ID, "user2"); err == nil {
+		t.Error("Expected error deleting another user's group")
+	}
+	if err := store.DeleteGroup(ctx, group.ID, "user1"); err != nil {
+		t.Fatalf("DeleteGroup failed: %v", err)
+	}
+	groups, err := store.GetUserGroups(ctx, "user1")
+	if err != nil {
+		t.Fatalf("GetUserGroups failed: %v", err)
+	}
+	if len(groups) != 0 {
+		t.Errorf("Expected no groups after delete, got %d", len(groups))
+	}
+}
+
+func TestUpdateGroup(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	group, err := store.CreateGroup(ctx, "user1", "coast", []CampgroundRef{{Provider: "p", CampgroundID: "cg1"}})
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %v", err)
+	}
+
+	if _, err := store.UpdateGroup(ctx, group.ID, "user2", "mine now", nil); !errors.Is(err, ErrGroupNotFound) {
+		t.Errorf("Expected ErrGroupNotFound updating another user's group, got %v", err)
+	}
+
+	tooMany := make([]CampgroundRef, MaxGroupCampgrounds+1)
+	for i := range tooMany {
+		tooMany[i] = CampgroundRef{Provider: "p", CampgroundID: fmt.Sprintf("cg%d", i)}
+	}
+	if _, err := store.UpdateGroup(ctx, group.ID, "user1", "coast", tooMany); err == nil {
+		t.Error("Expected error updating a group past the campground cap")
+	}
+
+	updated, err := store.UpdateGroup(ctx, group.ID, "user1", "north coast", tooMany[:MaxGroupCampgrounds])
+	if err != nil {
+		t.Fatalf("UpdateGroup failed: %v", err)
+	}
+	if updated.Name != "north coast" || len(updated.Campgrounds) != MaxGroupCampgrounds {
+		t.Errorf("Unexpected group after update: %+v", updated)
+	}
+	got, err := store.GetGroup(ctx, group.ID, "user1")
+	if err != nil {
+		t.Fatalf("GetGroup failed: %v", err)
+	}
+	if got.Name != "north coast" || len(got.Campgrounds) != MaxGroupCampgrounds {
+		t.Errorf("Update not persisted: %+v", got)
+	}
+}
+
+func TestGetCampgroundsByIDs(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	for _, cg := range []struct{ provider, id, name string }{
+		{"recreation_gov", "1", "Upper Pines"},
+		{"recreation_gov", "2", "Lower Pines"},
+		{"reservecalifornia", "1", "Big Basin"},
+	} {
+		if err := store.UpsertCampground(ctx, cg.provider, cg.id, cg.name, 37, -119, 4, []string{"Water"}, "", 0, 0, ""); err != nil {
+			t.Fatalf("UpsertCampground failed: %v", err)
+		}
+	}
+
+	refs := []CampgroundRef{
+		{Provider: "reservecalifornia", CampgroundID: "1"},
+		{Provider: "recreation_gov", CampgroundID: "missing"},
+		{Provider: "recreation_gov", CampgroundID: "2"},
+		{Provider: "reservecalifornia", CampgroundID: "2"}, // exists under another provider only
+		{Provider: "reservecalifornia", CampgroundID: "1"},
+	}
+	got, err := store.GetCampgroundsByIDs(ctx, refs)
+	if err != nil {
+		t.Fatalf("GetCampgroundsByIDs failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Name != "Big Basin" || got[1].Name != "Lower Pines" {
+		t.Fatalf("Expected Big Basin then Lower Pines, got %+v", got)
+	}
+	if got[1].Provider != "recreation_gov" || len(got[1].Amenities) != 1 || got[1].Rating != 4 {
+		t.Errorf("Expected full campground details, got %+v", got[1])
+	}
+
+	if got, err := store.GetCampgroundsByIDs(ctx, nil); err != nil || len(got) != 0 {
+		t.Errorf("Expected nothing for no refs, got %+v (err %v)", got, err)
+	}
+}
@@ -3,9 +3,12 @@ package db
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/brensch/schniffer/internal/providers"
 	_ "github.com/mattn/go-sqlite3"
 )
 
@@ -112,3 +115,484 @@ func TestDeactivateExpiredRequests(t *testing.T) {
 		i++
 	}
 }
+
+func TestUpsertCampsiteAvailabilityCost(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	_, err := store.DB.Exec(`
+		INSERT INTO campsite_metadata(provider, campground_id, campsite_id, name, cost_per_night, last_updated)
+		VALUES ('p', 'cg1', 'listed', 'Listed', 25, datetime('now'))
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert metadata: %v", err)
+	}
+
+	day := normalizeDay(time.Now().AddDate(0, 0, 5))
+	now := time.Now()
+	err = store.UpsertCampsiteAvailabilityBatch(ctx, []CampsiteAvailability{
+		{Provider: "p", CampgroundID: "cg1", CampsiteID: "priced", Date: day, Available: true, LastChecked: now, CostPerNight: 40},
+		{Provider: "p", CampgroundID: "cg1", CampsiteID: "listed", Date: day, Available: true, LastChecked: now},
+		{Provider: "p", CampgroundID: "cg1", CampsiteID: "unknown", Date: day, Available: true, LastChecked: now},
+	})
+	if err != nil {
+		t.Fatalf("UpsertCampsiteAvailabilityBatch failed: %v", err)
+	}
+	// a later poll without a price keeps the one we already have
+	err = store.UpsertCampsiteAvailabilityBatch(ctx, []CampsiteAvailability{
+		{Provider: "p", CampgroundID: "cg1", CampsiteID: "priced", Date: day, Available: true, LastChecked: now},
+	})
+	if err != nil {
+		t.Fatalf("UpsertCampsiteAvailabilityBatch failed: %v", err)
+	}
+
+	items, err := store.GetCurrentlyAvailableCampsites(ctx, "p", "cg1", day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("GetCurrentlyAvailableCampsites failed: %v", err)
+	}
+	want := map[string]float64{"priced": 40, "listed": 25, "unknown": 0}
+	if len(items) != len(want) {
+		t.Fatalf("Expected %d items, got %+v", len(want), items)
+	}
+	for _, it := range items {
+		if it.CostPerNight != want[it.CampsiteID] {
+			t.Errorf("%s: expected cost %v, got %v", it.CampsiteID, want[it.CampsiteID], it.CostPerNight)
+		}
+	}
+}
+
+func TestGetCampgroundByIDDetails(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	_, err := store.DB.Exec(`
+		INSERT INTO campgrounds(provider, campground_id, name, latitude, longitude, rating, amenities, image_url, price_min, price_max, price_unit, last_updated)
+		VALUES ('p', '1260/2181', 'Lakeside', 37.5, -119.5, 4.2, '["water","toilets"]', 'https://example.com/a.jpg', 20, 45, 'night', datetime('now'))
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert campground: %v", err)
+	}
+
+	cg, found, err := store.GetCampgroundByID(ctx, "p", "1260/2181")
+	if err != nil || !found {
+		t.Fatalf("GetCampgroundByID failed: found=%v err=%v", found, err)
+	}
+	if len(cg.Amenities) != 2 || cg.ImageURL != "https://example.com/a.jpg" || cg.PriceMin != 20 || cg.PriceMax != 45 || cg.PriceUnit != "night" {
+		t.Errorf("Unexpected campground details: %+v", cg)
+	}
+	if cg.DelistedAt != nil {
+		t.Errorf("Expected campground to be listed, got %v", cg.DelistedAt)
+	}
+
+	if _, found, err := store.GetCampgroundByID(ctx, "p", "missing"); err != nil || found {
+		t.Errorf("Expected missing campground to be not found, got found=%v err=%v", found, err)
+	}
+}
+
+func TestSetRequestActivePausesPolling(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	checkin := normalizeDay(time.Now().AddDate(0, 0, 10))
+	id, err := store.AddRequest(ctx, SchniffRequest{UserID: "user1", Provider: "p", CampgroundID: "cg1", Checkin: checkin, Checkout: checkin.AddDate(0, 0, 2)})
+	if err != nil {
+		t.Fatalf("AddRequest failed: %v", err)
+	}
+
+	if err := store.SetRequestActive(ctx, id, "someone-else", false); err == nil {
+		t.Fatal("Expected pausing another user's request to fail")
+	}
+	if err := store.SetRequestActive(ctx, id, "user1", false); err != nil {
+		t.Fatalf("SetRequestActive failed: %v", err)
+	}
+
+	active, err := store.ListActiveRequests(ctx)
+	if err != nil {
+		t.Fatalf("ListActiveRequests failed: %v", err)
+	}
+	if len(active) != 0 {
+		t.Errorf("Expected paused request to be excluded from polling, got %+v", active)
+	}
+	mine, err := store.ListUserActiveRequests(ctx, "user1")
+	if err != nil {
+		t.Fatalf("ListUserActiveRequests failed: %v", err)
+	}
+	if len(mine) != 1 || !mine[0].Paused {
+		t.Fatalf("Expected paused request in the user's list, got %+v", mine)
+	}
+	detailed, err := store.ListUserActiveRequestsDetailed(ctx, "user1")
+	if err != nil {
+		t.Fatalf("ListUserActiveRequestsDetailed failed: %v", err)
+	}
+	if len(detailed) != 1 || !detailed[0].Paused {
+		t.Errorf("Expected paused indicator in detailed list, got %+v", detailed)
+	}
+
+	if err := store.SetRequestActive(ctx, id, "user1", true); err != nil {
+		t.Fatalf("SetRequestActive resume failed: %v", err)
+	}
+	active, _ = store.ListActiveRequests(ctx)
+	if len(active) != 1 || active[0].Paused {
+		t.Errorf("Expected resumed request to be polled again, got %+v", active)
+	}
+}
+
+func TestDeactivateExpiredRequestsIncludesPaused(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	_, err := store.DB.Exec(`
+		INSERT INTO schniff_requests(user_id, provider, campground_id, checkin, checkout, active, paused)
+		VALUES ('user1', 'p', 'cg1', date('now', '-3 days'), date('now', '-1 days'), true, true)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert paused request: %v", err)
+	}
+
+	expired, err := store.DeactivateExpiredRequests(ctx)
+	if err != nil {
+		t.Fatalf("DeactivateExpiredRequests failed: %v", err)
+	}
+	if len(expired) != 1 {
+		t.Fatalf("Expected the paused request to expire, got %d", len(expired))
+	}
+	mine, _ := store.ListUserActiveRequests(ctx, "user1")
+	if len(mine) != 0 {
+		t.Errorf("Expected no active requests after expiry, got %+v", mine)
+	}
+}
+
+func TestNightFilterStored(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	fri := time.Date(2030, 3, 1, 0, 0, 0, 0, time.UTC)
+	for user, filter := range map[string]string{"user1": NightsWeekends, "user2": ""} {
+		_, err := store.AddRequest(ctx, SchniffRequest{UserID: user, Provider: "p", CampgroundID: "cg1", Checkin: fri, Checkout: fri.AddDate(0, 0, 7), NightFilter: filter})
+		if err != nil {
+			t.Fatalf("AddRequest failed: %v", err)
+		}
+	}
+
+	reqs, err := store.ListUserActiveRequests(ctx, "user1")
+	if err != nil || len(reqs) != 1 || reqs[0].NightFilter != NightsWeekends {
+		t.Fatalf("Expected stored weekends request, got %+v (err %v)", reqs, err)
+	}
+	// requests added without a filter default to any night
+	other, err := store.ListUserActiveRequests(ctx, "user2")
+	if err != nil || len(other) != 1 || other[0].NightFilter != NightsAny {
+		t.Errorf("Expected default night filter %q, got %+v (err %v)", NightsAny, other, err)
+	}
+}
+
+func TestCampgroundPagingIsStable(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// Duplicate names force the provider/ID tiebreak to decide page boundaries.
+	names := []string{"Pine Flat", "Pine Flat", "Pine Flat", "Pinecrest", "Oak Hollow", "Cedar Pine", "Pine Flat"}
+	for i, name := range names {
+		id := fmt.Sprintf("cg-%d", i)
+		if err := store.UpsertCampground(ctx, "recreation_gov", id, name, 0, 0, 0, nil, "", 0, 0, ""); err != nil {
+			t.Fatalf("UpsertCampground(%s): %v", id, err)
+		}
+	}
+
+	collect := func(page func(limit, offset int) ([]Campground, int, error), wantTotal int) []string {
+		t.Helper()
+		var ids []string
+		for offset := 0; ; offset += 2 {
+			rows, total, err := page(2, offset)
+			if err != nil {
+				t.Fatalf("page at offset %d: %v", offset, err)
+			}
+			if total != wantTotal {
+				t.Fatalf("total at offset %d = %d, want %d", offset, total, wantTotal)
+			}
+			if len(rows) == 0 {
+				break
+			}
+			for _, c := range rows {
+				ids = append(ids, c.ID)
+			}
+		}
+		return ids
+	}
+
+	liked, _, err := store.ListCampgroundsPaged(ctx, "pine", 100, 0)
+	if err != nil {
+		t.Fatalf("ListCampgroundsPaged: %v", err)
+	}
+	paged := collect(func(limit, offset int) ([]Campground, int, error) {
+		return store.ListCampgroundsPaged(ctx, "pine", limit, offset)
+	}, 6)
+	if len(paged) != len(liked) {
+		t.Fatalf("paged %d rows, single page %d rows", len(paged), len(liked))
+	}
+	for i := range liked {
+		if paged[i] != liked[i].ID {
+			t.Fatalf("row %d: paged %s, single page %s", i, paged[i], liked[i].ID)
+		}
+	}
+	if liked[len(liked)-1].Name != "Cedar Pine" {
+		t.Errorf("expected contains-only match last, got %q", liked[len(liked)-1].Name)
+	}
+
+	all, err := store.GetAllCampgrounds(ctx)
+	if err != nil {
+		t.Fatalf("GetAllCampgrounds: %v", err)
+	}
+	pagedAll := collect(func(limit, offset int) ([]Campground, int, error) {
+		return store.GetAllCampgroundsPaged(ctx, limit, offset)
+	}, len(names))
+	if len(all) != len(names) || len(pagedAll) != len(names) {
+		t.Fatalf("expected %d campgrounds, got all=%d paged=%d", len(names), len(all), len(pagedAll))
+	}
+	seen := make(map[string]bool)
+	for i := range all {
+		if pagedAll[i] != all[i].ID {
+			t.Fatalf("row %d: paged %s, unpaged %s", i, pagedAll[i], all[i].ID)
+		}
+		if seen[pagedAll[i]] {
+			t.Fatalf("campground %s returned on more than one page", pagedAll[i])
+		}
+		seen[pagedAll[i]] = true
+	}
+}
+
+func TestGetRequestByID(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	checkin := normalizeDay(time.Now().AddDate(0, 0, 10))
+	id, err := store.AddRequest(ctx, SchniffRequest{UserID: "user1", Provider: "p", CampgroundID: "cg1", Checkin: checkin, Checkout: checkin.AddDate(0, 0, 2), MinNights: 2})
+	if err != nil {
+		t.Fatalf("AddRequest failed: %v", err)
+	}
+	if err := store.DeactivateRequest(ctx, id, "user1"); err != nil {
+		t.Fatalf("DeactivateRequest failed: %v", err)
+	}
+
+	req, found, err := store.GetRequestByID(ctx, id)
+	if err != nil || !found {
+		t.Fatalf("GetRequestByID: found=%v err=%v", found, err)
+	}
+	if req.ID != id || req.UserID != "user1" || !req.Checkin.Equal(checkin) || req.MinNights != 2 || req.Active {
+		t.Errorf("Unexpected request %+v", req)
+	}
+
+	_, found, err = store.GetRequestByID(ctx, id+1)
+	if err != nil || found {
+		t.Errorf("Expected a missing request to be not found without error, got found=%v err=%v", found, err)
+	}
+}
+
+func TestDeactivateUserRequestsBefore(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	base := normalizeDay(time.Now().AddDate(0, 0, 10))
+	add := func(user string, checkoutDays int) int64 {
+		t.Helper()
+		checkout := base.AddDate(0, 0, checkoutDays)
+		id, err := store.AddRequest(ctx, SchniffRequest{UserID: user, Provider: "p", CampgroundID: "cg1", Checkin: base, Checkout: checkout})
+		if err != nil {
+			t.Fatalf("AddRequest failed: %v", err)
+		}
+		return id
+	}
+	early := add("user1", 1)
+	onCutoff := add("user1", 5)
+	late := add("user1", 9)
+	otherUser := add("user2", 1)
+
+	count, err := store.DeactivateUserRequestsBefore(ctx, "user1", base.AddDate(0, 0, 5))
+	if err != nil {
+		t.Fatalf("DeactivateUserRequestsBefore failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 request removed, got %d", count)
+	}
+	for id, wantActive := range map[int64]bool{early: false, onCutoff: true, late: true, otherUser: true} {
+		req, _, err := store.GetRequestByID(ctx, id)
+		if err != nil {
+			t.Fatalf("GetRequestByID failed: %v", err)
+		}
+		if req.Active != wantActive {
+			t.Errorf("Request %d active=%v, want %v", id, req.Active, wantActive)
+		}
+	}
+
+	// already-removed requests aren't counted again
+	if count, _ := store.DeactivateUserRequestsBefore(ctx, "user1", base.AddDate(0, 0, 5)); count != 0 {
+		t.Errorf("Expected nothing left to remove, got %d", count)
+	}
+}
+
+func TestUpsertCampgroundsBatch(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	var cgs []providers.CampgroundInfo
+	for i := 0; i < 1200; i++ {
+		cgs = append(cgs, providers.CampgroundInfo{
+			ID: fmt.Sprintf("cg-%d", i), Name: fmt.Sprintf("Campground %d", i),
+			Lat: 37, Lon: -119, Rating: 4.5,
+			Amenities: []string{"Hiking", fmt.Sprintf("Amenity \"%d\"", i)},
+			PriceMin:  20, PriceMax: 40, PriceUnit: "night",
+		})
+	}
+	cgs[1].Amenities = nil
+	if err := store.UpsertCampgroundsBatch(ctx, "recreation_gov", cgs); err != nil {
+		t.Fatalf("UpsertCampgroundsBatch failed: %v", err)
+	}
+
+	var n int
+	if err := store.DB.QueryRow(`SELECT count(*) FROM campgrounds WHERE provider = 'recreation_gov'`).Scan(&n); err != nil || n != len(cgs) {
+		t.Fatalf("Expected %d campgrounds stored, got %d (err %v)", len(cgs), n, err)
+	}
+	var raw string
+	if err := store.DB.QueryRow(`SELECT amenities FROM campgrounds WHERE campground_id = 'cg-7'`).Scan(&raw); err != nil {
+		t.Fatalf("read amenities: %v", err)
+	}
+	if raw != `["Hiking","Amenity \"7\""]` {
+		t.Errorf("Expected amenities stored as JSON, got %s", raw)
+	}
+
+	cg, ok, err := store.GetCampgroundByID(ctx, "recreation_gov", "cg-1100")
+	if err != nil || !ok {
+		t.Fatalf("GetCampgroundByID failed: ok=%v err=%v", ok, err)
+	}
+	if cg.Name != "Campground 1100" || cg.Rating != 4.5 || cg.PriceMax != 40 || cg.PriceUnit != "night" ||
+		len(cg.Amenities) != 2 || cg.Amenities[1] != `Amenity "1100"` {
+		t.Errorf("Unexpected campground read back: %+v", cg)
+	}
+	if cg, _, _ := store.GetCampgroundByID(ctx, "recreation_gov", "cg-1"); len(cg.Amenities) != 0 {
+		t.Errorf("Expected no amenities for cg-1, got %v", cg.Amenities)
+	}
+
+	// a second sync replaces rows rather than duplicating them
+	cgs[0].Name = "Renamed"
+	if err := store.UpsertCampgroundsBatch(ctx, "recreation_gov", cgs[:1]); err != nil {
+		t.Fatalf("UpsertCampgroundsBatch failed: %v", err)
+	}
+	found, err := store.SearchCampgrounds(ctx, "Renamed", 5)
+	if err != nil || len(found) != 1 || found[0].ID != "cg-0" {
+		t.Errorf("Expected the renamed campground to be searchable, got %+v (err %v)", found, err)
+	}
+}
+
+func BenchmarkUpsertCampgrounds(b *testing.B) {
+	var cgs []providers.CampgroundInfo
+	for i := 0; i < 1000; i++ {
+		cgs = append(cgs, providers.CampgroundInfo{ID: fmt.Sprintf("cg-%d", i), Name: fmt.Sprintf("Campground %d", i), Amenities: []string{"Hiking"}})
+	}
+	open := func(b *testing.B) *Store {
+		b.Helper()
+		store, err := Open(filepath.Join(b.TempDir(), "bench.db"))
+		if err != nil {
+			b.Fatalf("Failed to open store: %v", err)
+		}
+		b.Cleanup(func() { store.Close() })
+		return store
+	}
+	ctx := context.Background()
+
+	b.Run("single", func(b *testing.B) {
+		store := open(b)
+		for i := 0; i < b.N; i++ {
+			for _, cg := range cgs {
+				if err := store.UpsertCampground(ctx, "p", cg.ID, cg.Name, cg.Lat, cg.Lon, cg.Rating, cg.Amenities, cg.ImageURL, cg.PriceMin, cg.PriceMax, cg.PriceUnit); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		store := open(b)
+		for i := 0; i < b.N; i++ {
+			if err := store.UpsertCampgroundsBatch(ctx, "p", cgs); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestGetUnnotifiedStateChanges_FlexDays(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	checkin := normalizeDay(time.Now().AddDate(0, 0, 10))
+	id, err := store.AddRequest(ctx, SchniffRequest{UserID: "u1", Provider: "p", CampgroundID: "cg1", Checkin: checkin, Checkout: checkin.AddDate(0, 0, 2), FlexDays: 2})
+	if err != nil {
+		t.Fatalf("AddRequest failed: %v", err)
+	}
+	req, found, err := store.GetRequestByID(ctx, id)
+	if err != nil || !found || req.FlexDays != 2 {
+		t.Fatalf("Expected the request stored with 2 flex days, got %+v (found %v, err %v)", req, found, err)
+	}
+
+	// two days either side are watched, three are not
+	for _, offset := range []int{-3, -2, 0, 3, 4} {
+		_, err := store.DB.Exec(`INSERT INTO state_changes(provider, campground_id, campsite_id, date, new_available) VALUES ('p', 'cg1', 's1', ?, true)`,
+			checkin.AddDate(0, 0, offset))
+		if err != nil {
+			t.Fatalf("Failed to insert state change: %v", err)
+		}
+	}
+	changes, err := store.GetUnnotifiedStateChanges(ctx, []SchniffRequest{req})
+	if err != nil {
+		t.Fatalf("GetUnnotifiedStateChanges failed: %v", err)
+	}
+	got := map[string]bool{}
+	for _, c := range changes {
+		got[c.Date.Format("2006-01-02")] = true
+	}
+	for offset, want := range map[int]bool{-3: false, -2: true, 0: true, 3: true, 4: false} {
+		if day := checkin.AddDate(0, 0, offset).Format("2006-01-02"); got[day] != want {
+			t.Errorf("day %+d: expected included=%v, got %v", offset, want, got[day])
+		}
+	}
+}
+
+func TestGetFirstAvailable(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	day := normalizeDay(time.Now().AddDate(0, 0, 10))
+	// s1 opened while tracked; s2 is open with no state change on record; s3 is booked
+	if err := store.UpsertCampsiteAvailabilityBatch(ctx, []CampsiteAvailability{{
+		Provider: "p", CampgroundID: "cg1", CampsiteID: "s1", Date: day, Available: true, LastChecked: time.Now(),
+	}}); err != nil {
+		t.Fatalf("UpsertCampsiteAvailabilityBatch failed: %v", err)
+	}
+	for site, available := range map[string]bool{"s2": true, "s3": false} {
+		if _, err := store.DB.Exec(`INSERT INTO campsite_availability(provider, campground_id, campsite_id, date, available, last_checked)
+			VALUES ('p', 'cg1', ?, ?, ?, ?)`, site, day, available, time.Now()); err != nil {
+			t.Fatalf("Failed to seed availability: %v", err)
+		}
+	}
+	id, err := store.AddRequest(ctx, SchniffRequest{UserID: "u1", Provider: "p", CampgroundID: "cg1", Checkin: day, Checkout: day.AddDate(0, 0, 1)})
+	if err != nil {
+		t.Fatalf("AddRequest failed: %v", err)
+	}
+	req, _, _ := store.GetRequestByID(ctx, id)
+
+	first, err := store.GetFirstAvailable(ctx, []SchniffRequest{req})
+	if err != nil || len(first) != 1 || first[0].CampsiteID != "s2" || first[0].ID != 0 || first[0].RequestID != id {
+		t.Fatalf("Expected only s2 as a first-available opening, got %+v (err %v)", first, err)
+	}
+	var stateChanges int
+	if err := store.DB.QueryRow(`SELECT count(*) FROM state_changes`).Scan(&stateChanges); err != nil || stateChanges != 1 {
+		t.Errorf("Expected only s1's state change on record, got %d (err %v)", stateChanges, err)
+	}
+
+	if err := store.InsertNotificationsBatch(ctx, []Notification{{
+		RequestID: id, UserID: "u1", Provider: "p", CampgroundID: "cg1", CampsiteID: "s2", Date: day, State: "available", SentAt: time.Now(),
+	}}, "b1"); err != nil {
+		t.Fatalf("InsertNotificationsBatch failed: %v", err)
+	}
+	if first, _ := store.GetFirstAvailable(ctx, []SchniffRequest{req}); len(first) != 0 {
+		t.Errorf("Expected nothing once the request has been notified, got %+v", first)
+	}
+}
@@ -14,8 +14,13 @@ type SummaryData struct {
 	NotificationUsernames []string
 	ActiveUsernames       []string
 	TrackedCampgrounds    []string
+	FailingCampgrounds    []ScrapeFailure // campgrounds polling is skipping, for an admin to look into
+	ChurnCampgrounds      []ChurnStats    // campgrounds with the most availability changes in the last day
 }
 
+// summaryChurnLimit is how many of the busiest campgrounds the summary lists.
+const summaryChurnLimit = 5
+
 // GetDetailedSummary returns a formatted summary string with comprehensive statistics
 func (s *Store) GetDetailedSummary(ctx context.Context) (string, error) {
 	// Get detailed stats
@@ -109,11 +114,23 @@ func (s *Store) GetSummaryData(ctx context.Context) (SummaryData, error) {
 		return SummaryData{}, fmt.Errorf("failed to get tracked campgrounds: %w", err)
 	}
 
+	failing, err := s.ListScrapeFailures(ctx, ScrapeFailureThreshold)
+	if err != nil {
+		return SummaryData{}, fmt.Errorf("failed to get failing campgrounds: %w", err)
+	}
+
+	churn, err := s.TopCampgroundsByChurn(ctx, time.Now().Add(-24*time.Hour), summaryChurnLimit)
+	if err != nil {
+		return SummaryData{}, fmt.Errorf("failed to get campground churn: %w", err)
+	}
+
 	return SummaryData{
 		Stats:                 stats,
 		NotificationUsernames: usersWithNotifications,
 		ActiveUsernames:       usersWithActiveRequests,
 		TrackedCampgrounds:    trackedCampgrounds,
+		FailingCampgrounds:    failing,
+		ChurnCampgrounds:      churn,
 	}, nil
 }
 
@@ -182,5 +199,36 @@ func MakeSummaryEmbed(summaryData SummaryData) *discordgo.MessageEmbed {
 		},
 	}
 
+	if len(summaryData.ChurnCampgrounds) > 0 {
+		lines := make([]string, 0, len(summaryData.ChurnCampgrounds))
+		for _, c := range summaryData.ChurnCampgrounds {
+			lines = append(lines, fmt.Sprintf("%s (%s): %d opened, %d booked", c.CampgroundName, c.Provider, c.Opens, c.Closes))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "🔥 Most Churn (cancellations turn up here)",
+			Value: strings.Join(lines, "\n"),
+		})
+	}
+
+	if len(summaryData.FailingCampgrounds) > 0 {
+		// Keep to a few short lines so the field stays under Discord's 1024 character limit
+		lines := make([]string, 0, 6)
+		for i, f := range summaryData.FailingCampgrounds {
+			if i == 5 {
+				lines = append(lines, fmt.Sprintf("*...and %d more*", len(summaryData.FailingCampgrounds)-5))
+				break
+			}
+			lastError := f.LastError
+			if len(lastError) > 60 {
+				lastError = lastError[:60] + "..."
+			}
+			lines = append(lines, fmt.Sprintf("%s (%s/%s): %d failures, last `%s`", f.CampgroundName, f.Provider, f.CampgroundID, f.ConsecutiveFailures, lastError))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "🚧 Campgrounds Failing To Scrape",
+			Value: strings.Join(lines, "\n"),
+		})
+	}
+
 	return embed
 }
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NormalizeTag lowercases a tag and joins words with hyphens so "Dog Friendly" and "dog-friendly" match.
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+}
+
+// AddCampgroundTag tags a campground. Adding an existing tag is a no-op.
+func (s *Store) AddCampgroundTag(ctx context.Context, provider, campgroundID, tag, createdBy string) error {
+	tag = NormalizeTag(tag)
+	if tag == "" {
+		return errors.New("tag cannot be empty")
+	}
+	_, err := s.DB.ExecContext(ctx, `
+		INSERT OR IGNORE INTO campground_tags(provider, campground_id, tag, created_by)
+		VALUES (?, ?, ?, ?)
+	`, provider, campgroundID, tag, createdBy)
+	if err != nil {
+		return fmt.Errorf("failed to add campground tag: %w", err)
+	}
+	return nil
+}
+
+// RemoveCampgroundTag removes a tag from a campground.
+func (s *Store) RemoveCampgroundTag(ctx context.Context, provider, campgroundID, tag string) error {
+	res, err := s.DB.ExecContext(ctx, `
+		DELETE FROM campground_tags WHERE provider=? AND campground_id=? AND tag=?
+	`, provider, campgroundID, NormalizeTag(tag))
+	if err != nil {
+		return fmt.Errorf("failed to remove campground tag: %w", err)
+	}
+	a, _ := res.RowsAffected()
+	if a == 0 {
+		return errors.New("tag not found")
+	}
+	return nil
+}
+
+// GetCampgroundTags returns the tags on a campground in alphabetical order.
+func (s *Store) GetCampgroundTags(ctx context.Context, provider, campgroundID string) ([]string, error) {
+	return s.queryTags(ctx, `
+		SELECT tag FROM campground_tags WHERE provider=? AND campground_id=? ORDER BY tag
+	`, provider, campgroundID)
+}
+
+// ListDistinctTags returns every tag in use, for filter options.
+func (s *Store) ListDistinctTags(ctx context.Context) ([]string, error) {
+	return s.queryTags(ctx, `SELECT DISTINCT tag FROM campground_tags ORDER BY tag`)
+}
+
+func (s *Store) queryTags(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	rows, err := s.ReadConnection().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
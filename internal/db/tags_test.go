@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCampgroundTags(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	if err := store.AddCampgroundTag(ctx, "p", "cg1", "Dog Friendly", "admin"); err != nil {
+		t.Fatalf("AddCampgroundTag failed: %v", err)
+	}
+	// duplicate after normalization is a no-op
+	if err := store.AddCampgroundTag(ctx, "p", "cg1", "dog-friendly", "admin"); err != nil {
+		t.Fatalf("AddCampgroundTag duplicate failed: %v", err)
+	}
+	if err := store.AddCampgroundTag(ctx, "p", "cg2", "lakeside", "admin"); err != nil {
+		t.Fatalf("AddCampgroundTag failed: %v", err)
+	}
+	if err := store.AddCampgroundTag(ctx, "p", "cg2", "  ", "admin"); err == nil {
+		t.Error("Expected error for empty tag")
+	}
+
+	tags, err := store.GetCampgroundTags(ctx, "p", "cg1")
+	if err != nil {
+		t.Fatalf("GetCampgroundTags failed: %v", err)
+	}
+	if !reflect.DeepEqual(tags, []string{"dog-friendly"}) {
+		t.Errorf("Unexpected tags: %v", tags)
+	}
+
+	all, err := store.ListDistinctTags(ctx)
+	if err != nil {
+		t.Fatalf("ListDistinctTags failed: %v", err)
+	}
+	if !reflect.DeepEqual(all, []string{"dog-friendly", "lakeside"}) {
+		t.Errorf("Unexpected distinct tags: %v", all)
+	}
+
+	if err := store.RemoveCampgroundTag(ctx, "p", "cg1", "DOG FRIENDLY"); err != nil {
+		t.Fatalf("RemoveCampgroundTag failed: %v", err)
+	}
+	if err := store.RemoveCampgroundTag(ctx, "p", "cg1", "dog-friendly"); err == nil {
+		t.Error("Expected error removing missing tag")
+	}
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// UndeliveredNotification is a request whose notification DM failed and is waiting to be retried.
+type UndeliveredNotification struct {
+	UserID        string
+	RequestID     int64
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+}
+
+// QueueUndeliveredNotification records a failed notification DM to be retried at next. Queuing
+// the same request again counts another attempt.
+func (s *Store) QueueUndeliveredNotification(ctx context.Context, userID string, requestID int64, lastError string, next time.Time) error {
+	_, err := s.DB.ExecContext(ctx, `
+		INSERT INTO undelivered_notifications (user_id, request_id, attempts, last_error, next_attempt_at)
+		VALUES (?, ?, 1, ?, ?)
+		ON CONFLICT(user_id, request_id) DO UPDATE SET
+			attempts = attempts + 1,
+			last_error = excluded.last_error,
+			next_attempt_at = excluded.next_attempt_at
+	`, userID, requestID, lastError, next.UTC())
+	return err
+}
+
+// ListDueUndeliveredNotifications returns the failed notifications due for another attempt at
+// now, oldest first.
+func (s *Store) ListDueUndeliveredNotifications(ctx context.Context, now time.Time) ([]UndeliveredNotification, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT user_id, request_id, attempts, last_error, next_attempt_at
+		FROM undelivered_notifications
+		WHERE next_attempt_at <= ?
+		ORDER BY next_attempt_at, request_id
+	`, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []UndeliveredNotification
+	for rows.Next() {
+		var u UndeliveredNotification
+		if err := rows.Scan(&u.UserID, &u.RequestID, &u.Attempts, &u.LastError, &u.NextAttemptAt); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+// DeleteUndeliveredNotification removes a failed notification once it has been sent or given up on.
+func (s *Store) DeleteUndeliveredNotification(ctx context.Context, userID string, requestID int64) error {
+	_, err := s.DB.ExecContext(ctx, `
+		DELETE FROM undelivered_notifications WHERE user_id = ? AND request_id = ?
+	`, userID, requestID)
+	return err
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestUndeliveredNotifications(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	if err := store.QueueUndeliveredNotification(ctx, "u1", 1, "status 500", now.Add(-time.Minute)); err != nil {
+		t.Fatalf("QueueUndeliveredNotification: %v", err)
+	}
+	if err := store.QueueUndeliveredNotification(ctx, "u1", 1, "status 503", now); err != nil {
+		t.Fatalf("QueueUndeliveredNotification: %v", err)
+	}
+	if err := store.QueueUndeliveredNotification(ctx, "u2", 2, "status 429", now.Add(time.Hour)); err != nil {
+		t.Fatalf("QueueUndeliveredNotification: %v", err)
+	}
+
+	due, err := store.ListDueUndeliveredNotifications(ctx, now)
+	if err != nil {
+		t.Fatalf("ListDueUndeliveredNotifications: %v", err)
+	}
+	if len(due) != 1 {
+		t.Fatalf("Expected only u1's notification due, got %+v", due)
+	}
+	u := due[0]
+	if u.UserID != "u1" || u.RequestID != 1 || u.Attempts != 2 || u.LastError != "status 503" || !u.NextAttemptAt.Equal(now) {
+		t.Errorf("Unexpected undelivered notification %+v", u)
+	}
+
+	if err := store.DeleteUndeliveredNotification(ctx, "u1", 1); err != nil {
+		t.Fatalf("DeleteUndeliveredNotification: %v", err)
+	}
+	due, _ = store.ListDueUndeliveredNotifications(ctx, now.Add(2*time.Hour))
+	if len(due) != 1 || due[0].UserID != "u2" {
+		t.Errorf("Expected only u2's notification left, got %+v", due)
+	}
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// UserStats is one user's schniffing history.
+type UserStats struct {
+	TotalRequests    int64
+	ActiveRequests   int64
+	Notifications    int64 // openings alerted, all time
+	Notifications24h int64
+	TopCampsite      *UserTopCampsite // nil until the user has been alerted
+}
+
+// UserTopCampsite is the campsite a user has been alerted about most.
+type UserTopCampsite struct {
+	Provider       string
+	CampgroundID   string
+	CampgroundName string // falls back to the campground ID when the campground isn't synced
+	CampsiteID     string
+	Alerts         int64
+}
+
+// GetUserStats aggregates the user's schniff requests and the openings they were notified about.
+func (s *Store) GetUserStats(ctx context.Context, userID string) (UserStats, error) {
+	var st UserStats
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT
+			(SELECT count(*) FROM schniff_requests WHERE user_id = ?),
+			(SELECT count(*) FROM schniff_requests WHERE user_id = ? AND active = true),
+			(SELECT count(*) FROM notifications WHERE user_id = ? AND state = 'available'),
+			(SELECT count(*) FROM notifications WHERE user_id = ? AND state = 'available' AND sent_at >= datetime('now', '-1 day'))
+	`, userID, userID, userID, userID).Scan(&st.TotalRequests, &st.ActiveRequests, &st.Notifications, &st.Notifications24h)
+	if err != nil {
+		return UserStats{}, fmt.Errorf("failed to count user stats: %w", err)
+	}
+
+	var top UserTopCampsite
+	err = s.DB.QueryRowContext(ctx, `
+		SELECT n.provider, n.campground_id, coalesce(c.name, n.campground_id), n.campsite_id, count(*) AS alerts
+		FROM notifications n
+		LEFT JOIN campgrounds c ON c.provider = n.provider AND c.campground_id = n.campground_id
+		WHERE n.user_id = ? AND n.state = 'available'
+		GROUP BY n.provider, n.campground_id, n.campsite_id
+		ORDER BY alerts DESC, max(n.sent_at) DESC
+		LIMIT 1
+	`, userID).Scan(&top.Provider, &top.CampgroundID, &top.CampgroundName, &top.CampsiteID, &top.Alerts)
+	if err == sql.ErrNoRows {
+		return st, nil
+	}
+	if err != nil {
+		return UserStats{}, fmt.Errorf("failed to get top campsite: %w", err)
+	}
+	st.TopCampsite = &top
+	return st, nil
+}
+
+// MakeUserStatsEmbed renders a user's stats for /schniff stats.
+func MakeUserStatsEmbed(st UserStats) *discordgo.MessageEmbed {
+	top := "*No alerts yet*"
+	if st.TopCampsite != nil {
+		top = fmt.Sprintf("Site %s at %s (%d alerts)", st.TopCampsite.CampsiteID, st.TopCampsite.CampgroundName, st.TopCampsite.Alerts)
+	}
+	return &discordgo.MessageEmbed{
+		Title:     "📊 Your Schniff Stats",
+		Color:     0x5865F2, // Discord Blurple
+		Timestamp: time.Now().Format(time.RFC3339),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "👃 Schniffs Created", Value: fmt.Sprintf("%d", st.TotalRequests), Inline: true},
+			{Name: "🟢 Active Schniffs", Value: fmt.Sprintf("%d", st.ActiveRequests), Inline: true},
+			{Name: "🎯 Alerts Received", Value: fmt.Sprintf("%d (%d in the last 24h)", st.Notifications, st.Notifications24h), Inline: true},
+			{Name: "🏆 Most Alerted Campsite", Value: top, Inline: false},
+		},
+	}
+}
+
+// GetUserNotifications returns the notifications sent to the user at or after since, oldest first.
+func (s *Store) GetUserNotifications(ctx context.Context, userID string, since time.Time) ([]Notification, error) {
+	var out []Notification
+	err := s.EachUserNotification(ctx, userID, since, func(n Notification) error {
+		out = append(out, n)
+		return nil
+	})
+	return out, err
+}
+
+// EachUserNotification calls fn with each notification sent to the user at or after since, oldest
+// first, without holding them all in memory. It reads from the read connection so a slow fn doesn't
+// hold up writes, and stops at the first error fn returns.
+func (s *Store) EachUserNotification(ctx context.Context, userID string, since time.Time, fn func(Notification) error) error {
+	rows, err := s.ReadConnection().QueryContext(ctx, `
+		SELECT id, batch_id, request_id, user_id, provider, campground_id, campsite_id, date, state,
+			state_change_id, sent_at
+		FROM notifications
+		WHERE user_id = ? AND julianday(sent_at) >= julianday(?)
+		ORDER BY sent_at, id
+	`, userID, since.UTC())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var n Notification
+		err := rows.Scan(&n.ID, &n.BatchID, &n.RequestID, &n.UserID, &n.Provider, &n.CampgroundID, &n.CampsiteID,
+			&n.Date, &n.State, &n.StateChangeID, &n.SentAt)
+		if err != nil {
+			return err
+		}
+		if err := fn(n); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetUserStats(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	day := normalizeDay(time.Now()).AddDate(0, 0, 10)
+
+	empty, err := store.GetUserStats(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetUserStats: %v", err)
+	}
+	if empty != (UserStats{}) {
+		t.Fatalf("expected zero stats for a new user, got %+v", empty)
+	}
+
+	if err := store.UpsertCampground(ctx, "p", "cg", "Pine Flat", 0, 0, 0, nil, "", 0, 0, ""); err != nil {
+		t.Fatalf("UpsertCampground: %v", err)
+	}
+	var reqIDs []int64
+	for _, user := range []string{"u1", "u1", "u1", "u2"} {
+		id, err := store.AddRequest(ctx, SchniffRequest{UserID: user, Provider: "p", CampgroundID: "cg", Checkin: day, Checkout: day.AddDate(0, 0, 2)})
+		if err != nil {
+			t.Fatalf("AddRequest: %v", err)
+		}
+		reqIDs = append(reqIDs, id)
+	}
+	if err := store.DeactivateRequest(ctx, reqIDs[2], "u1"); err != nil {
+		t.Fatalf("DeactivateRequest: %v", err)
+	}
+
+	now := time.Now()
+	note := func(req int64, user, site, state string, sentAt time.Time) Notification {
+		return Notification{RequestID: req, UserID: user, Provider: "p", CampgroundID: "cg", CampsiteID: site, Date: day, State: state, SentAt: sentAt}
+	}
+	err = store.InsertNotificationsBatch(ctx, []Notification{
+		note(reqIDs[0], "u1", "7", "available", now.Add(-72*time.Hour)),
+		note(reqIDs[0], "u1", "7", "unavailable", now.Add(-71*time.Hour)),
+		note(reqIDs[1], "u1", "7", "available", now.Add(-time.Hour)),
+		note(reqIDs[1], "u1", "9", "available", now.Add(-2*time.Hour)),
+		note(reqIDs[3], "u2", "9", "available", now),
+		note(reqIDs[3], "u2", "9", "available", now),
+		note(reqIDs[3], "u2", "9", "available", now),
+	}, "batch")
+	if err != nil {
+		t.Fatalf("InsertNotificationsBatch: %v", err)
+	}
+
+	got, err := store.GetUserStats(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetUserStats: %v", err)
+	}
+	if got.TotalRequests != 3 || got.ActiveRequests != 2 {
+		t.Errorf("requests: got total %d active %d, want 3 and 2", got.TotalRequests, got.ActiveRequests)
+	}
+	if got.Notifications != 3 || got.Notifications24h != 2 {
+		t.Errorf("alerts: got %d total %d last 24h, want 3 and 2", got.Notifications, got.Notifications24h)
+	}
+	want := UserTopCampsite{Provider: "p", CampgroundID: "cg", CampgroundName: "Pine Flat", CampsiteID: "7", Alerts: 2}
+	if got.TopCampsite == nil || *got.TopCampsite != want {
+		t.Errorf("top campsite: got %+v, want %+v", got.TopCampsite, want)
+	}
+}
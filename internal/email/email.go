@@ -0,0 +1,181 @@
+// Package email sends notifications over SMTP as an alternative to Discord DMs.
+package email
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// Sender delivers HTML emails. *Mailer implements it.
+type Sender interface {
+	Send(to, subject, htmlBody string) error
+}
+
+// sendTimeout bounds one whole SMTP exchange, so a stalled server can't hold up the caller.
+const sendTimeout = 30 * time.Second
+
+// implicitTLSPort is the SMTPS port, where the connection is TLS from the start instead of being
+// upgraded with STARTTLS.
+const implicitTLSPort = "465"
+
+// Config holds SMTP settings.
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// ConfigFromEnv reads SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD and SMTP_FROM.
+// ok is false when SMTP_HOST is unset, meaning email is disabled. Port 465 uses implicit TLS,
+// any other port STARTTLS when the server offers it.
+func ConfigFromEnv() (cfg Config, ok bool) {
+	cfg = Config{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+	if cfg.Port == "" {
+		cfg.Port = "587"
+	}
+	if cfg.From == "" {
+		cfg.From = cfg.Username
+	}
+	return cfg, cfg.Host != ""
+}
+
+// Mailer sends HTML emails through an SMTP server.
+type Mailer struct {
+	cfg     Config
+	timeout time.Duration
+	send    func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewMailer returns a Mailer for cfg.
+func NewMailer(cfg Config) *Mailer {
+	m := &Mailer{cfg: cfg, timeout: sendTimeout}
+	m.send = m.sendMail
+	return m
+}
+
+// ValidateAddress parses a bare email address, rejecting display names and anything unparseable.
+func ValidateAddress(addr string) (string, error) {
+	parsed, err := mail.ParseAddress(strings.TrimSpace(addr))
+	if err != nil {
+		return "", fmt.Errorf("invalid email address: %w", err)
+	}
+	if parsed.Name != "" {
+		return "", errors.New("invalid email address: just the address please")
+	}
+	return parsed.Address, nil
+}
+
+// Send delivers an HTML email to a single recipient.
+func (m *Mailer) Send(to, subject, htmlBody string) error {
+	msg, err := buildMessage(m.cfg.From, to, subject, htmlBody)
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+	return m.send(net.JoinHostPort(m.cfg.Host, m.cfg.Port), auth, m.cfg.From, []string{to}, msg)
+}
+
+// sendMail is smtp.SendMail with a deadline on the whole exchange and support for implicit TLS.
+func (m *Mailer) sendMail(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+	dialer := &net.Dialer{Timeout: m.timeout}
+	tlsConfig := &tls.Config{ServerName: m.cfg.Host}
+	var conn net.Conn
+	var err error
+	if m.cfg.Port == implicitTLSPort {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(m.timeout)); err != nil {
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok && m.cfg.Port != implicitTLSPort {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if a != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(a); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// buildMessage assembles the RFC 5322 message, refusing header values that could inject headers.
+func buildMessage(from, to, subject, htmlBody string) ([]byte, error) {
+	for _, v := range []string{from, to, subject} {
+		if strings.ContainsAny(v, "\r\n") {
+			return nil, errors.New("email header contains a newline")
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString("From: " + from + "\r\n")
+	sb.WriteString("To: " + to + "\r\n")
+	sb.WriteString("Subject: " + mimeEncode(subject) + "\r\n")
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/html; charset=\"UTF-8\"\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(htmlBody)
+	return []byte(sb.String()), nil
+}
+
+// mimeEncode encodes non-ASCII subjects (emoji are common in notification titles).
+func mimeEncode(s string) string {
+	for _, r := range s {
+		if r > 127 {
+			return mime.QEncoding.Encode("utf-8", s)
+		}
+	}
+	return s
+}
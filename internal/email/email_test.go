@@ -0,0 +1,84 @@
+package email
+
+import (
+	"net"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMailerSend(t *testing.T) {
+	m := NewMailer(Config{Host: "smtp.example.com", Port: "587", Username: "bot", Password: "pw", From: "schniffer@example.com"})
+	var gotAddr string
+	var gotMsg []byte
+	m.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr = addr
+		gotMsg = msg
+		if from != "schniffer@example.com" || len(to) != 1 || to[0] != "camper@example.com" {
+			t.Errorf("unexpected envelope from=%s to=%v", from, to)
+		}
+		return nil
+	}
+
+	if err := m.Send("camper@example.com", "🏕️ Sites available", "<p>hello</p>"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if gotAddr != "smtp.example.com:587" {
+		t.Errorf("unexpected server address %q", gotAddr)
+	}
+	msg := string(gotMsg)
+	for _, want := range []string{"To: camper@example.com\r\n", "Subject: =?utf-8?q?", "Content-Type: text/html", "\r\n\r\n<p>hello</p>"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %q:\n%s", want, msg)
+		}
+	}
+
+	if err := m.Send("camper@example.com", "hi\r\nBcc: everyone@example.com", "x"); err == nil {
+		t.Error("expected header injection to be rejected")
+	}
+}
+
+func TestValidateAddress(t *testing.T) {
+	if got, err := ValidateAddress(" camper@example.com "); err != nil || got != "camper@example.com" {
+		t.Errorf("got %q, %v", got, err)
+	}
+	for _, bad := range []string{"", "not an email", "Camper <camper@example.com>"} {
+		if _, err := ValidateAddress(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestMailerSendTimesOut(t *testing.T) {
+	// a server that accepts the connection but never greets
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	for _, p := range []string{port, implicitTLSPort} {
+		m := NewMailer(Config{Host: host, Port: p, From: "schniffer@example.com"})
+		m.timeout = 100 * time.Millisecond
+		addr := ln.Addr().String() // the stalled server stands in for both ports
+		start := time.Now()
+		err := m.send(addr, nil, "schniffer@example.com", []string{"camper@example.com"}, []byte("x"))
+		if err == nil {
+			t.Fatalf("port %s: expected a stalled server to fail the send", p)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("port %s: send took %s, expected the timeout to cut it short", p, elapsed)
+		}
+	}
+}
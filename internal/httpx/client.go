@@ -4,33 +4,113 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
 	"time"
 )
 
-var defaultClient *http.Client
+var (
+	defaultClient     *http.Client
+	defaultClientOnce sync.Once
+)
 
-// Default returns a shared HTTP client with sensible timeouts.
+// Default returns a shared HTTP client with sensible timeouts. It's safe to call from any goroutine.
 func Default() *http.Client {
-	if defaultClient != nil {
-		return defaultClient
+	defaultClientOnce.Do(func() {
+		defaultClient = NewClient()
+	})
+	return defaultClient
+}
+
+// clientConfig holds the timeouts and pool limits of clients built by this package.
+type clientConfig struct {
+	timeout             time.Duration
+	dialTimeout         time.Duration
+	tlsHandshakeTimeout time.Duration
+	idleConnTimeout     time.Duration
+	maxIdleConns        int
+	publicOnly          bool
+}
+
+func defaultClientConfig() clientConfig {
+	return clientConfig{
+		timeout:             20 * time.Second,
+		dialTimeout:         10 * time.Second,
+		tlsHandshakeTimeout: 10 * time.Second,
+		idleConnTimeout:     90 * time.Second,
+		maxIdleConns:        100,
+	}
+}
+
+// Option overrides one of the defaults used by NewClient and WithProxyRotation.
+type Option func(*clientConfig)
+
+// WithTimeout limits the whole request, including reading the body.
+func WithTimeout(d time.Duration) Option {
+	return func(c *clientConfig) { c.timeout = d }
+}
+
+// WithDialTimeout limits establishing the TCP connection.
+func WithDialTimeout(d time.Duration) Option {
+	return func(c *clientConfig) { c.dialTimeout = d }
+}
+
+// WithTLSHandshakeTimeout limits the TLS handshake.
+func WithTLSHandshakeTimeout(d time.Duration) Option {
+	return func(c *clientConfig) { c.tlsHandshakeTimeout = d }
+}
+
+// WithIdleConnTimeout sets how long idle keep-alive connections are kept.
+func WithIdleConnTimeout(d time.Duration) Option {
+	return func(c *clientConfig) { c.idleConnTimeout = d }
+}
+
+// WithMaxIdleConns caps the idle keep-alive connections kept across all hosts.
+func WithMaxIdleConns(n int) Option {
+	return func(c *clientConfig) { c.maxIdleConns = n }
+}
+
+func newClientConfig(opts []Option) clientConfig {
+	cfg := defaultClientConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// NewClient returns a client like Default, honouring HTTP(S)_PROXY, with opts applied. Unlike
+// Default it isn't shared, so tests can use it with shorter timeouts.
+func NewClient(opts ...Option) *http.Client {
+	cfg := newClientConfig(opts)
+	return &http.Client{
+		Timeout:   cfg.timeout,
+		Transport: newTransport(http.ProxyFromEnvironment, cfg),
+	}
+}
+
+// newTransport builds the transport shared by every client in this package. Requests are
+// throttled per host before they reach the network.
+func newTransport(proxy func(*http.Request) (*url.URL, error), cfg clientConfig) http.RoundTripper {
+	dialer := &net.Dialer{
+		Timeout:   cfg.dialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	if cfg.publicOnly {
+		proxy = nil
+		dialer.Control = dialPublicOnly
 	}
 	transport := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout:   10 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
 		ForceAttemptHTTP2:     true,
-		MaxIdleConns:          100,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   10 * time.Second,
+		MaxIdleConns:          cfg.maxIdleConns,
+		IdleConnTimeout:       cfg.idleConnTimeout,
+		TLSHandshakeTimeout:   cfg.tlsHandshakeTimeout,
 		ExpectContinueTimeout: 1 * time.Second,
 	}
-	defaultClient = &http.Client{
-		Timeout:   20 * time.Second,
-		Transport: transport,
-	}
-	return defaultClient
+	return &rateLimitedTransport{next: transport}
 }
 
 // browserProfile represents a complete browser header set
@@ -278,13 +358,24 @@ var realBrowserProfiles = []browserProfile{
 	},
 }
 
+// PinnedUserAgent returns the operator-configured User-Agent for a provider, read from
+// UA_PIN_<provider> (e.g. UA_PIN_recreation_gov). Empty means randomize as usual.
+func PinnedUserAgent(provider string) string {
+	return strings.TrimSpace(os.Getenv("UA_PIN_" + provider))
+}
+
 // SpoofChromeHeaders sets a randomly selected realistic browser header set on the request.
-func SpoofChromeHeaders(r *http.Request) {
+// If a non-empty userAgent override is passed it replaces the randomized User-Agent,
+// for providers that only let through a specific client.
+func SpoofChromeHeaders(r *http.Request, userAgent ...string) {
 	// Select a random browser profile
 	profile := realBrowserProfiles[rand.Intn(len(realBrowserProfiles))]
 
 	// Set the headers from the selected profile
 	r.Header.Set("User-Agent", profile.UserAgent)
+	if len(userAgent) > 0 && userAgent[0] != "" {
+		r.Header.Set("User-Agent", userAgent[0])
+	}
 	r.Header.Set("Accept", profile.Accept)
 	r.Header.Set("Accept-Language", profile.AcceptLanguage)
 	// Don't set Accept-Encoding - let Go's HTTP client handle compression automatically
@@ -0,0 +1,55 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Run with -race: Default used to initialise the shared client without synchronisation.
+func TestDefaultConcurrent(t *testing.T) {
+	const goroutines = 50
+	clients := make([]*http.Client, goroutines)
+	var wg sync.WaitGroup
+	for i := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clients[i] = Default()
+		}()
+	}
+	wg.Wait()
+
+	for i, c := range clients {
+		if c == nil || c != clients[0] {
+			t.Fatalf("goroutine %d got client %p, want the shared %p", i, c, clients[0])
+		}
+	}
+}
+
+func TestNewClientOptions(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client := NewClient(WithTimeout(50*time.Millisecond), WithMaxIdleConns(1))
+	if client == Default() {
+		t.Fatal("NewClient returned the shared default client")
+	}
+	if client.Timeout != 50*time.Millisecond {
+		t.Errorf("Timeout = %v, want 50ms", client.Timeout)
+	}
+
+	start := time.Now()
+	if _, err := client.Get(srv.URL); err == nil {
+		t.Fatal("expected the request to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request took %v, the timeout option wasn't applied", elapsed)
+	}
+}
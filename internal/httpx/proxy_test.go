@@ -0,0 +1,59 @@
+package httpx
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+func TestProxyRotationAlternates(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+	newProxy := func(name string) *url.URL {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			seen = append(seen, name)
+			mu.Unlock()
+			io.WriteString(w, name)
+		}))
+		t.Cleanup(srv.Close)
+		u, _ := url.Parse(srv.URL)
+		return u
+	}
+
+	client := newRotatingClient([]*url.URL{newProxy("a"), newProxy("b")})
+	for range 4 {
+		resp, err := client.Get("http://upstream.invalid/availability")
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	want := []string{"a", "b", "a", "b"}
+	if len(seen) != len(want) {
+		t.Fatalf("expected %d proxied requests, got %v", len(want), seen)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("proxies used %v, want %v", seen, want)
+		}
+	}
+}
+
+func TestProxiesFromEnv(t *testing.T) {
+	t.Setenv("PROXIES", " http://one:8080 , not a url,,http://user:pw@two:3128")
+	proxies := ProxiesFromEnv()
+	if len(proxies) != 2 || proxies[0].Host != "one:8080" || proxies[1].Host != "two:3128" {
+		t.Fatalf("unexpected proxies: %v", proxies)
+	}
+
+	t.Setenv("PROXIES", "")
+	if WithProxyRotation() != Default() {
+		t.Fatal("expected the default client when no proxies are configured")
+	}
+}
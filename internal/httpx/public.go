@@ -0,0 +1,46 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+// ErrNonPublicAddress is returned when a client built WithPublicAddressesOnly would connect to an
+// address that isn't on the public internet.
+var ErrNonPublicAddress = errors.New("refusing to connect to a non-public address")
+
+// sharedAddressSpace is carrier-grade NAT (RFC 6598), which netip doesn't count as private.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// WithPublicAddressesOnly makes the client refuse to connect to loopback, private, link-local
+// (including cloud metadata at 169.254.169.254) and other non-public addresses. The check runs on
+// the resolved IP at dial time, so DNS pointing at an internal host is caught too, and the client
+// skips any proxy so the check applies to the destination. For URLs that users supply.
+func WithPublicAddressesOnly() Option {
+	return func(c *clientConfig) { c.publicOnly = true }
+}
+
+// IsPublicAddr reports whether ip is a globally routable unicast address.
+func IsPublicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !sharedAddressSpace.Contains(ip)
+}
+
+// dialPublicOnly is a net.Dialer Control hook that rejects connections to non-public addresses.
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !IsPublicAddr(ip) {
+		return fmt.Errorf("%w: %s", ErrNonPublicAddress, ip)
+	}
+	return nil
+}
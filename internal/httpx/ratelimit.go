@@ -0,0 +1,61 @@
+package httpx
+
+import (
+	"net/http"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultHostLimit is the request rate allowed to a host without its own SetHostLimit.
+	DefaultHostLimit rate.Limit = 10
+	// DefaultHostBurst is the burst allowed to a host without its own SetHostLimit.
+	DefaultHostBurst = 10
+)
+
+// hostLimiters holds one token bucket per request host, shared by every client in this package
+// so a host's budget is respected no matter which provider or client sends the request.
+var hostLimiters = struct {
+	sync.Mutex
+	byHost map[string]*rate.Limiter
+}{byHost: make(map[string]*rate.Limiter)}
+
+// SetHostLimit sets the request rate and burst for a host name (no port). It applies to requests
+// already waiting as well as future ones.
+func SetHostLimit(host string, r rate.Limit, burst int) {
+	hostLimiters.Lock()
+	defer hostLimiters.Unlock()
+	if l, ok := hostLimiters.byHost[host]; ok {
+		l.SetLimit(r)
+		l.SetBurst(burst)
+		return
+	}
+	hostLimiters.byHost[host] = rate.NewLimiter(r, burst)
+}
+
+func hostLimiter(host string) *rate.Limiter {
+	hostLimiters.Lock()
+	defer hostLimiters.Unlock()
+	l, ok := hostLimiters.byHost[host]
+	if !ok {
+		l = rate.NewLimiter(DefaultHostLimit, DefaultHostBurst)
+		hostLimiters.byHost[host] = l
+	}
+	return l
+}
+
+// rateLimitedTransport waits for the request host's token bucket before each round trip.
+type rateLimitedTransport struct {
+	next http.RoundTripper
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := hostLimiter(req.URL.Hostname()).Wait(req.Context()); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
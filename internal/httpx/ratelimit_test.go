@@ -0,0 +1,47 @@
+package httpx
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestHostLimitSpacesRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	// 20/s with no burst: 5 requests need at least 4 gaps of 50ms
+	SetHostLimit("127.0.0.1", 20, 1)
+	t.Cleanup(func() { SetHostLimit("127.0.0.1", DefaultHostLimit, DefaultHostBurst) })
+
+	client := &http.Client{Transport: newTransport(nil, defaultClientConfig())}
+	start := time.Now()
+	for range 5 {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	if elapsed := time.Since(start); elapsed < 190*time.Millisecond {
+		t.Fatalf("5 requests at 20/s took %v, expected at least 200ms", elapsed)
+	}
+}
+
+func TestHostLimitIsPerHost(t *testing.T) {
+	SetHostLimit("slow.example", rate.Every(time.Hour), 1)
+	if hostLimiter("slow.example") == hostLimiter("fast.example") {
+		t.Fatal("hosts should not share a limiter")
+	}
+	if got := hostLimiter("fast.example").Limit(); got != DefaultHostLimit {
+		t.Fatalf("unconfigured host limit = %v, want default %v", got, DefaultHostLimit)
+	}
+	if got := hostLimiter("slow.example").Limit(); got != rate.Every(time.Hour) {
+		t.Fatalf("configured host limit = %v", got)
+	}
+}
@@ -0,0 +1,107 @@
+package httpx
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how WithRetry retries throttled and failed responses.
+type RetryPolicy struct {
+	MaxAttempts int           // total tries including the first; <=1 disables retries
+	BaseDelay   time.Duration // first backoff, doubled on each further attempt
+	MaxDelay    time.Duration // cap on a single wait, including Retry-After; 0 means 30s
+}
+
+// DefaultRetryPolicy suits the provider APIs: a few quick retries before giving up.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: 200 * time.Millisecond}
+
+const defaultMaxRetryDelay = 30 * time.Second
+
+// WithRetry returns a copy of c that retries 429 and 5xx responses with exponential backoff and
+// jitter, honouring Retry-After. Waits stop early when the request context is cancelled. Requests
+// whose body can't be replayed (no GetBody) are sent once.
+func WithRetry(c *http.Client, policy RetryPolicy) *http.Client {
+	next := c.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	out := *c
+	out.Transport = &retryTransport{next: next, policy: policy}
+	return &out
+}
+
+type retryTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	canReplay := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	for attempt := 1; ; attempt++ {
+		attemptReq := req
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+		if err != nil || !retryableStatus(resp.StatusCode) || attempt >= t.policy.MaxAttempts || !canReplay {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt, resp.Header.Get("Retry-After"))
+		// drain so the connection can be reused
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the wait before the next attempt: Retry-After when the server sent one,
+// otherwise BaseDelay doubled per attempt plus up to 50% jitter.
+func (t *retryTransport) backoff(attempt int, retryAfter string) time.Duration {
+	maxDelay := t.policy.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxRetryDelay
+	}
+	if d, ok := parseRetryAfter(retryAfter, time.Now()); ok {
+		return min(d, maxDelay)
+	}
+	d := t.policy.BaseDelay << (attempt - 1)
+	if d > 0 {
+		d += time.Duration(rand.Int63n(int64(d)/2 + 1))
+	}
+	return min(d, maxDelay)
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// parseRetryAfter accepts both forms of Retry-After: delay seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,113 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// failingServer answers status for the first failures requests, then 200 with the request body.
+func failingServer(t *testing.T, failures int32, status int, retryAfter string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		io.Copy(w, r.Body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestRetryFailsTwiceThenSucceeds(t *testing.T) {
+	srv, calls := failingServer(t, 2, http.StatusServiceUnavailable, "")
+	client := WithRetry(srv.Client(), RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+
+	resp, err := client.Post(srv.URL, "application/json", bytes.NewReader([]byte(`{"ok":true}`)))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if string(body) != `{"ok":true}` {
+		t.Fatalf("body was not replayed on retry, got %q", body)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("server saw %d calls, want 3", got)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	srv, calls := failingServer(t, 10, http.StatusTooManyRequests, "")
+	client := WithRetry(srv.Client(), RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond})
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || calls.Load() != 2 {
+		t.Fatalf("got status %d after %d calls, want 429 after 2", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestRetryIgnoresClientErrors(t *testing.T) {
+	srv, calls := failingServer(t, 10, http.StatusNotFound, "")
+	client := WithRetry(srv.Client(), RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if calls.Load() != 1 {
+		t.Fatalf("404 should not be retried, server saw %d calls", calls.Load())
+	}
+}
+
+func TestRetryHonoursRetryAfterAndContext(t *testing.T) {
+	srv, calls := failingServer(t, 1, http.StatusTooManyRequests, "60")
+	client := WithRetry(srv.Client(), RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	start := time.Now()
+	_, err := client.Do(req)
+	if err == nil {
+		t.Fatal("expected the context deadline to interrupt the Retry-After wait")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("retry wait ignored cancellation, took %v", elapsed)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("server saw %d calls, want 1", calls.Load())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	if d, ok := parseRetryAfter("7", now); !ok || d != 7*time.Second {
+		t.Errorf("seconds form: got %v %v", d, ok)
+	}
+	if d, ok := parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now); !ok || d != 90*time.Second {
+		t.Errorf("date form: got %v %v", d, ok)
+	}
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Error("garbage should not parse")
+	}
+}
@@ -0,0 +1,38 @@
+// Package logctx carries log correlation IDs through a context, so the log lines of one poll cycle
+// (including slow queries it triggers) can be picked out together.
+package logctx
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+type pollIDKey struct{}
+
+// NewPollID returns a fresh ID for one poll cycle.
+func NewPollID() string {
+	return uuid.New().String()
+}
+
+// WithPollID returns a copy of ctx carrying the poll cycle ID.
+func WithPollID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, pollIDKey{}, id)
+}
+
+// PollID returns the poll cycle ID in ctx, or "" if there isn't one.
+func PollID(ctx context.Context) string {
+	id, _ := ctx.Value(pollIDKey{}).(string)
+	return id
+}
+
+// Attr returns the poll ID in ctx as a log attribute. Without one it returns the empty attribute,
+// which slog handlers drop, so it can be passed to every log call unconditionally.
+func Attr(ctx context.Context) slog.Attr {
+	id := PollID(ctx)
+	if id == "" {
+		return slog.Attr{}
+	}
+	return slog.String("pollID", id)
+}
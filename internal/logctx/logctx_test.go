@@ -0,0 +1,43 @@
+package logctx
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestPollIDRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	if got := PollID(ctx); got != "" {
+		t.Fatalf("PollID on a bare context = %q, want empty", got)
+	}
+
+	id := NewPollID()
+	ctx = WithPollID(ctx, id)
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if got := PollID(child); got != id {
+		t.Errorf("PollID on a derived context = %q, want %q", got, id)
+	}
+	if other := NewPollID(); other == id {
+		t.Errorf("NewPollID returned %q twice", id)
+	}
+}
+
+func TestAttr(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	logger.Info("without", Attr(context.Background()))
+	if strings.Contains(buf.String(), "pollID") {
+		t.Errorf("Expected no pollID without one in the context, got %q", buf.String())
+	}
+
+	buf.Reset()
+	logger.Info("with", Attr(WithPollID(context.Background(), "abc")))
+	if !strings.Contains(buf.String(), "pollID=abc") {
+		t.Errorf("Expected pollID=abc in the log line, got %q", buf.String())
+	}
+}
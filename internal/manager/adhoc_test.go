@@ -0,0 +1,62 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+// cancellingProvider cancels the scrape's context mid-fetch, as a shutdown would, and returns its
+// results anyway like a provider that ignores cancellation.
+type cancellingProvider struct {
+	slowProvider
+	cancel  context.CancelFunc
+	scraped []string
+}
+
+func (p *cancellingProvider) FetchAvailability(ctx context.Context, campgroundID string, start, end time.Time) ([]providers.CampsiteAvailability, error) {
+	p.scraped = append(p.scraped, campgroundID)
+	p.cancel()
+	return []providers.CampsiteAvailability{{ID: "s1", Date: start, Available: true}}, nil
+}
+
+func TestProcessAdhocScrapes_CancelLeavesPending(t *testing.T) {
+	m, _ := newPollTestManager(t, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	prov := &cancellingProvider{cancel: cancel}
+	m.reg.Register("cancelling", prov)
+
+	var ids []int
+	for _, cg := range []string{"cg1", "cg2"} {
+		req, created, err := m.store.RequestAdhocScrape(context.Background(), "cancelling", cg, "user", "u1")
+		if err != nil || !created {
+			t.Fatalf("RequestAdhocScrape(%s): created %v, err %v", cg, created, err)
+		}
+		ids = append(ids, req.ID)
+	}
+
+	m.processAdhocScrapes(ctx)
+
+	if len(prov.scraped) != 1 {
+		t.Errorf("Expected processing to stop after the interrupted scrape, scraped %v", prov.scraped)
+	}
+	for _, id := range ids {
+		req, err := m.store.GetAdhocScrapeRequest(context.Background(), id)
+		if err != nil {
+			t.Fatalf("GetAdhocScrapeRequest(%d): %v", id, err)
+		}
+		if req.Status != "pending" || req.CompletedAt != nil || req.ErrorMsg != nil {
+			t.Errorf("Expected request %d left pending, got status %q completed %v error %v", id, req.Status, req.CompletedAt, req.ErrorMsg)
+		}
+	}
+	var stored int
+	if err := m.store.DB.QueryRow(`SELECT count(*) FROM campsite_availability WHERE provider = 'cancelling'`).Scan(&stored); err != nil {
+		t.Fatalf("count availability: %v", err)
+	}
+	if stored != 0 {
+		t.Errorf("Expected nothing written after cancellation, got %d rows", stored)
+	}
+}
@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+func TestRequestCampsiteStats_ExcludesDayUseByDefault(t *testing.T) {
+	store, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	m := NewManager(store, providers.NewRegistry(), nil, "")
+
+	// a baseline sync so both campsites are stored with their type of use
+	_, err = store.UpsertCampsiteMetadataBatch(ctx, "p", "cg1", []providers.CampsiteInfo{
+		{ID: "overnight", Name: "Site 1", TypeOfUse: "Overnight"},
+		{ID: "picnic", Name: "Picnic Area", TypeOfUse: "Day"},
+	})
+	if err != nil {
+		t.Fatalf("UpsertCampsiteMetadataBatch failed: %v", err)
+	}
+
+	checkin := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 10)
+	var states []db.CampsiteAvailability
+	for _, id := range []string{"overnight", "picnic"} {
+		states = append(states, db.CampsiteAvailability{Provider: "p", CampgroundID: "cg1", CampsiteID: id, Date: checkin, Available: true, LastChecked: time.Now()})
+	}
+	if err := store.UpsertCampsiteAvailabilityBatch(ctx, states); err != nil {
+		t.Fatalf("UpsertCampsiteAvailabilityBatch failed: %v", err)
+	}
+
+	req := db.SchniffRequest{ID: 1, UserID: "u", Provider: "p", CampgroundID: "cg1", Checkin: checkin, Checkout: checkin.AddDate(0, 0, 1)}
+	stats, _, skipped := m.requestCampsiteStats(ctx, req)
+	if skipped || len(stats) != 1 || stats[0].CampsiteID != "overnight" {
+		t.Fatalf("Expected only the overnight site by default, got %+v (skipped=%v)", stats, skipped)
+	}
+
+	req.IncludeDayUse = true
+	stats, _, _ = m.requestCampsiteStats(ctx, req)
+	if len(stats) != 2 {
+		t.Fatalf("Expected day-use site when opted in, got %+v", stats)
+	}
+}
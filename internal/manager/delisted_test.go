@@ -0,0 +1,106 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+// catalogProvider returns a fixed campground catalog from FetchAllCampgrounds.
+type catalogProvider struct {
+	slowProvider
+	catalog []providers.CampgroundInfo
+}
+
+func (p *catalogProvider) FetchAllCampgrounds(ctx context.Context) ([]providers.CampgroundInfo, error) {
+	return p.catalog, nil
+}
+
+func TestSyncCampgrounds_FlagsDelisted(t *testing.T) {
+	store, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	prov := &catalogProvider{}
+	for i := 0; i < 10; i++ {
+		prov.catalog = append(prov.catalog, providers.CampgroundInfo{ID: fmt.Sprintf("cg%d", i), Name: fmt.Sprintf("Campground %d", i)})
+	}
+	reg := providers.NewRegistry()
+	reg.Register("slow", prov)
+	m := NewManager(store, reg, nil, "")
+
+	delisted := func() map[string]bool {
+		t.Helper()
+		cgs, err := store.GetCampgroundsByProvider(ctx, "slow")
+		if err != nil {
+			t.Fatalf("GetCampgroundsByProvider failed: %v", err)
+		}
+		out := map[string]bool{}
+		for _, cg := range cgs {
+			if cg.DelistedAt != nil {
+				out[cg.ID] = true
+			}
+		}
+		return out
+	}
+
+	if _, err := m.SyncCampgrounds(ctx, "slow"); err != nil {
+		t.Fatalf("SyncCampgrounds failed: %v", err)
+	}
+	if got := delisted(); len(got) != 0 {
+		t.Fatalf("Expected nothing delisted after first sync, got %v", got)
+	}
+
+	// cg9 disappears from the catalog
+	full := prov.catalog
+	prov.catalog = full[:9]
+	if _, err := m.SyncCampgrounds(ctx, "slow"); err != nil {
+		t.Fatalf("SyncCampgrounds failed: %v", err)
+	}
+	if got := delisted(); len(got) != 1 || !got["cg9"] {
+		t.Fatalf("Expected only cg9 delisted, got %v", got)
+	}
+
+	// a response missing most of the catalog looks broken, so nothing more is delisted
+	prov.catalog = full[:2]
+	if _, err := m.SyncCampgrounds(ctx, "slow"); err != nil {
+		t.Fatalf("SyncCampgrounds failed: %v", err)
+	}
+	if got := delisted(); len(got) != 1 {
+		t.Fatalf("Expected mass disappearance to be ignored, got %v", got)
+	}
+
+	// coming back clears the flag
+	prov.catalog = full
+	if _, err := m.SyncCampgrounds(ctx, "slow"); err != nil {
+		t.Fatalf("SyncCampgrounds failed: %v", err)
+	}
+	if got := delisted(); len(got) != 0 {
+		t.Fatalf("Expected relisted campground to be cleared, got %v", got)
+	}
+}
+
+func TestSync_RunsSyncHooks(t *testing.T) {
+	m, _ := newPollTestManager(t, 0)
+	ctx := context.Background()
+
+	var calls int
+	m.OnSync(func() { calls++ })
+
+	if _, err := m.SyncCampgrounds(ctx, "slow"); err != nil {
+		t.Fatalf("SyncCampgrounds failed: %v", err)
+	}
+	if _, err := m.SyncCampsites(ctx, "slow"); err != nil {
+		t.Fatalf("SyncCampsites failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected sync hook to run after both syncs, ran %d times", calls)
+	}
+}
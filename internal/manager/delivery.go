@@ -0,0 +1,224 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// discordSendAttempts is how many times a Discord call is tried before its error is returned.
+	discordSendAttempts = 3
+	// undeliveredRetryInterval is how often failed notifications are checked for another attempt.
+	undeliveredRetryInterval = time.Minute
+	// undeliveredMaxAttempts is how many times a failed notification is queued before giving up.
+	undeliveredMaxAttempts = 8
+	// undeliveredMaxBackoff caps the wait between attempts at a failed notification.
+	undeliveredMaxBackoff = time.Hour
+)
+
+// discordRetryDelay is the wait before the first retry of a Discord call; it doubles each retry.
+var discordRetryDelay = 500 * time.Millisecond
+
+// discordErrorTransient reports whether err is worth retrying: rate limits, Discord server errors,
+// and network failures.
+func discordErrorTransient(err error) bool {
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) {
+		if restErr.Response == nil {
+			return false
+		}
+		code := restErr.Response.StatusCode
+		return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// discordErrorUndeliverable reports whether err means the user can't be DMed at all, usually
+// because they closed DMs from server members.
+func discordErrorUndeliverable(err error) bool {
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) {
+		return false
+	}
+	if restErr.Message != nil && restErr.Message.Code == discordgo.ErrCodeCannotSendMessagesToThisUser {
+		return true
+	}
+	return restErr.Response != nil && restErr.Response.StatusCode == http.StatusForbidden
+}
+
+// retryDiscord runs send, retrying transient failures with backoff up to discordSendAttempts.
+func retryDiscord(ctx context.Context, send func() error) error {
+	delay := discordRetryDelay
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = send()
+		if err == nil || attempt == discordSendAttempts || !discordErrorTransient(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// sendDM DMs the embeds to the user, retrying transient failures. It stops at the first embed that
+// can't be sent.
+func (m *Manager) sendDM(ctx context.Context, userID string, embeds []*discordgo.MessageEmbed) error {
+	var channel *discordgo.Channel
+	err := retryDiscord(ctx, func() (err error) {
+		channel, err = m.notifier.UserChannelCreate(userID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, e := range embeds {
+		err := retryDiscord(ctx, func() error {
+			_, err := m.notifier.ChannelMessageSendEmbed(channel.ID, e)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// undeliveredBackoff is the wait before the next attempt at a notification that has failed
+// attempts times: a minute, doubling, capped at undeliveredMaxBackoff.
+func undeliveredBackoff(attempts int) time.Duration {
+	if attempts > 6 {
+		return undeliveredMaxBackoff
+	}
+	return min(time.Minute<<(attempts-1), undeliveredMaxBackoff)
+}
+
+// handleFailedNotification deals with a notification DM that failed even after retrying. Transient
+// failures are queued to try again later; users who can't be DMed get their request paused. Any
+// other error is returned.
+func (m *Manager) handleFailedNotification(ctx context.Context, req db.SchniffRequest, err error) error {
+	switch {
+	case discordErrorUndeliverable(err):
+		m.markUndeliverable(ctx, req, err)
+		return nil
+	case discordErrorTransient(err):
+		m.logger.Warn("notification failed; queued for retry",
+			slog.String("userID", req.UserID),
+			slog.Int64("requestID", req.ID),
+			slog.Any("err", err))
+		return m.store.QueueUndeliveredNotification(ctx, req.UserID, req.ID, err.Error(), time.Now().Add(undeliveredBackoff(1)))
+	default:
+		return err
+	}
+}
+
+// markUndeliverable pauses a request whose user can't be DMed, so it stops notifying into the void,
+// and lets the summary channel know so someone can tell them.
+func (m *Manager) markUndeliverable(ctx context.Context, req db.SchniffRequest, err error) {
+	m.logger.Warn("user can't be DMed; pausing request",
+		slog.String("userID", req.UserID),
+		slog.Int64("requestID", req.ID),
+		slog.Any("err", err))
+	if err := m.store.SetRequestActive(ctx, req.ID, req.UserID, false); err != nil {
+		m.logger.Warn("pause undeliverable request failed", slog.Int64("requestID", req.ID), slog.Any("err", err))
+	}
+	if err := m.store.DeleteUndeliveredNotification(ctx, req.UserID, req.ID); err != nil {
+		m.logger.Warn("delete undelivered notification failed", slog.Any("err", err))
+	}
+	msg := fmt.Sprintf("⚠️ <@%s> has sites open for schniff #%d but I can't DM them, so it's paused. "+
+		"Allow DMs from server members, then `/schniff resume` it.", req.UserID, req.ID)
+	if err := m.sendSummary(msg); err != nil {
+		m.logger.Warn("failed to send undeliverable notice", slog.Any("err", err))
+	}
+}
+
+// StartUndeliveredNotificationRetrier periodically retries notifications whose DMs failed.
+func (m *Manager) StartUndeliveredNotificationRetrier(ctx context.Context) {
+	ticker := time.NewTicker(undeliveredRetryInterval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.RetryUndeliveredNotifications(ctx)
+			}
+		}
+	}()
+}
+
+// RetryUndeliveredNotifications tries again to DM failed notifications that are due. Like held
+// notifications they're rebuilt from current availability, and dropped when the request has ended
+// or been paused. Only the DM is retried; the webhook and email went out the first time.
+func (m *Manager) RetryUndeliveredNotifications(ctx context.Context) {
+	now := time.Now()
+	due, err := m.store.ListDueUndeliveredNotifications(ctx, now)
+	if err != nil {
+		m.logger.Error("failed to list undelivered notifications", slog.Any("err", err))
+		return
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	active, err := m.store.ListActiveRequests(ctx)
+	if err != nil {
+		m.logger.Error("failed to list active requests", slog.Any("err", err))
+		return
+	}
+	requests := indexRequestsByID(active)
+
+	for _, u := range due {
+		req, ok := requests[u.RequestID]
+		if ok && req.UserID == u.UserID {
+			prefs, err := m.store.GetNotificationPrefs(ctx, u.UserID)
+			if err == nil && prefs.InQuietHours(now) {
+				// hand it to the quiet hours queue rather than waking the user
+				if err := m.store.QueuePendingNotification(ctx, u.UserID, u.RequestID); err != nil {
+					m.logger.Warn("queue pending notification failed", slog.Any("err", err))
+					continue
+				}
+			} else if _, _, err := m.deliverNotification(ctx, req, nil, true, true); err != nil {
+				if discordErrorUndeliverable(err) {
+					m.markUndeliverable(ctx, req, err)
+					continue
+				}
+				if discordErrorTransient(err) && u.Attempts < undeliveredMaxAttempts {
+					next := now.Add(undeliveredBackoff(u.Attempts + 1))
+					if err := m.store.QueueUndeliveredNotification(ctx, u.UserID, u.RequestID, err.Error(), next); err != nil {
+						m.logger.Warn("requeue undelivered notification failed", slog.Any("err", err))
+					}
+					continue
+				}
+				m.logger.Warn("giving up on undelivered notification",
+					slog.String("userID", u.UserID),
+					slog.Int64("requestID", u.RequestID),
+					slog.Int("attempts", u.Attempts+1),
+					slog.Any("err", err))
+			} else {
+				m.broadcastFound(ctx, req.UserID)
+				m.logger.Info("delivered notification on retry",
+					slog.String("userID", u.UserID),
+					slog.Int64("requestID", u.RequestID),
+					slog.Int("attempts", u.Attempts+1))
+			}
+		}
+		if err := m.store.DeleteUndeliveredNotification(ctx, u.UserID, u.RequestID); err != nil {
+			m.logger.Warn("delete undelivered notification failed", slog.Any("err", err))
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package manager
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/providers"
+	"github.com/bwmarrin/discordgo"
+)
+
+// flakyDiscord fails the next failures DM posts with status/body, then accepts them. Accepted DMs
+// and summary channel posts are recorded.
+type flakyDiscord struct {
+	mu       sync.Mutex
+	failures int
+	status   int
+	body     string
+	dms      []string
+	summary  []string
+}
+
+func (f *flakyDiscord) RoundTrip(r *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	status, body := http.StatusOK, `{"id":"1"}`
+	var posted []byte
+	if r.Body != nil {
+		posted, _ = io.ReadAll(r.Body)
+	}
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/users/@me/channels"):
+		body = `{"id":"dm"}`
+	case strings.Contains(r.URL.Path, "/channels/dm/messages"):
+		if f.failures > 0 {
+			f.failures--
+			status, body = f.status, f.body
+		} else {
+			f.dms = append(f.dms, string(posted))
+		}
+	case strings.Contains(r.URL.Path, "/channels/summary/messages"):
+		f.summary = append(f.summary, string(posted))
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    r,
+	}, nil
+}
+
+func (f *flakyDiscord) counts() (dms, summary int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.dms), len(f.summary)
+}
+
+// newDeliveryTest sets up a store with one request that has a site open, and a manager whose
+// Discord calls go to discord.
+func newDeliveryTest(t *testing.T, discord *flakyDiscord) (*Manager, *db.Store, int64) {
+	t.Helper()
+	prevDelay := discordRetryDelay
+	discordRetryDelay = time.Millisecond
+	t.Cleanup(func() { discordRetryDelay = prevDelay })
+
+	ctx := context.Background()
+	store, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	checkin := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 10)
+	reqID, err := store.AddRequest(ctx, db.SchniffRequest{
+		UserID: "user1", Provider: "p", CampgroundID: "cg1",
+		Checkin: checkin, Checkout: checkin.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		t.Fatalf("AddRequest failed: %v", err)
+	}
+	err = store.UpsertCampsiteAvailabilityBatch(ctx, []db.CampsiteAvailability{{
+		Provider: "p", CampgroundID: "cg1", CampsiteID: "s1", Date: checkin, Available: true, LastChecked: time.Now(),
+	}})
+	if err != nil {
+		t.Fatalf("UpsertCampsiteAvailabilityBatch failed: %v", err)
+	}
+
+	session, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("discordgo.New failed: %v", err)
+	}
+	session.Client = &http.Client{Transport: discord}
+	return NewManager(store, providers.NewRegistry(), session, "summary"), store, reqID
+}
+
+func processNotifications(t *testing.T, m *Manager, store *db.Store) {
+	t.Helper()
+	ctx := context.Background()
+	reqs, err := store.ListActiveRequests(ctx)
+	if err != nil {
+		t.Fatalf("ListActiveRequests failed: %v", err)
+	}
+	if err := m.ProcessNotificationsWithBatches(ctx, reqs); err != nil {
+		t.Fatalf("ProcessNotificationsWithBatches failed: %v", err)
+	}
+}
+
+func countUndelivered(t *testing.T, store *db.Store) int {
+	t.Helper()
+	var n int
+	if err := store.DB.QueryRow(`SELECT count(*) FROM undelivered_notifications`).Scan(&n); err != nil {
+		t.Fatalf("count undelivered notifications: %v", err)
+	}
+	return n
+}
+
+func TestNotification_RetriesTransientFailures(t *testing.T) {
+	discord := &flakyDiscord{failures: 2, status: http.StatusInternalServerError, body: `{"message":"Internal Server Error"}`}
+	m, store, _ := newDeliveryTest(t, discord)
+
+	processNotifications(t, m, store)
+	dms, summary := discord.counts()
+	if dms != 1 {
+		t.Errorf("Expected the DM to go through on the third try, got %d DMs", dms)
+	}
+	if summary != 1 {
+		t.Errorf("Expected the delivered DM broadcast to the summary channel, got %d posts", summary)
+	}
+	if n := countUndelivered(t, store); n != 0 {
+		t.Errorf("Expected nothing queued for retry, got %d", n)
+	}
+}
+
+func TestNotification_QueuesAfterRetriesAndRedelivers(t *testing.T) {
+	discord := &flakyDiscord{failures: discordSendAttempts, status: http.StatusServiceUnavailable, body: `{"message":"Service Unavailable"}`}
+	m, store, _ := newDeliveryTest(t, discord)
+	ctx := context.Background()
+
+	processNotifications(t, m, store)
+	if dms, summary := discord.counts(); dms != 0 || summary != 0 {
+		t.Fatalf("Expected every attempt to fail without a broadcast, got %d DMs and %d summary posts", dms, summary)
+	}
+	if n := countUndelivered(t, store); n != 1 {
+		t.Fatalf("Expected the notification queued for retry, got %d", n)
+	}
+
+	// not due yet
+	m.RetryUndeliveredNotifications(ctx)
+	if dms, _ := discord.counts(); dms != 0 {
+		t.Fatalf("Expected no retry before the backoff, got %d DMs", dms)
+	}
+
+	// as if the backoff passed, e.g. across a restart
+	if _, err := store.DB.Exec(`UPDATE undelivered_notifications SET next_attempt_at = ?`, time.Now().UTC().Add(-time.Second)); err != nil {
+		t.Fatalf("Failed to make the retry due: %v", err)
+	}
+	m.RetryUndeliveredNotifications(ctx)
+	if dms, summary := discord.counts(); dms != 1 || summary != 1 {
+		t.Errorf("Expected the retry to deliver the DM and its broadcast, got %d DMs and %d summary posts", dms, summary)
+	}
+	if n := countUndelivered(t, store); n != 0 {
+		t.Errorf("Expected the delivered notification removed, got %d queued", n)
+	}
+}
+
+func TestNotification_ClosedDMsPauseRequest(t *testing.T) {
+	discord := &flakyDiscord{failures: 1, status: http.StatusForbidden, body: `{"code":50007,"message":"Cannot send messages to this user"}`}
+	m, store, reqID := newDeliveryTest(t, discord)
+
+	processNotifications(t, m, store)
+	_, summary := discord.counts()
+	if summary != 1 {
+		// only the undeliverable notice; nothing was found for anyone to hear about
+		t.Errorf("Expected 1 summary channel post, got %d", summary)
+	}
+	if n := countUndelivered(t, store); n != 0 {
+		t.Errorf("Expected nothing queued for a user who can't be DMed, got %d", n)
+	}
+	var paused bool
+	if err := store.DB.QueryRow(`SELECT paused FROM schniff_requests WHERE id = ?`, reqID).Scan(&paused); err != nil {
+		t.Fatalf("read paused: %v", err)
+	}
+	if !paused {
+		t.Errorf("Expected the request paused")
+	}
+	discord.mu.Lock()
+	notice := discord.summary[0]
+	discord.mu.Unlock()
+	if !strings.Contains(notice, "can't DM") {
+		t.Errorf("Expected the undeliverable notice, got %q", notice)
+	}
+}
+
+func TestNotification_QuietHoursHoldBroadcast(t *testing.T) {
+	discord := &flakyDiscord{}
+	m, store, _ := newDeliveryTest(t, discord)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	prefs := db.NotificationPrefs{
+		UserID:     "user1",
+		QuietStart: now.Add(-time.Hour).Format("15:04"),
+		QuietEnd:   now.Add(time.Hour).Format("15:04"),
+		Timezone:   "UTC",
+		Enabled:    true,
+	}
+	if err := store.UpsertNotificationPrefs(ctx, prefs); err != nil {
+		t.Fatalf("UpsertNotificationPrefs failed: %v", err)
+	}
+
+	processNotifications(t, m, store)
+	if dms, summary := discord.counts(); dms != 0 || summary != 0 {
+		t.Fatalf("Expected nothing sent during quiet hours, got %d DMs and %d summary posts", dms, summary)
+	}
+
+	// quiet hours end
+	prefs.Enabled = false
+	if err := store.UpsertNotificationPrefs(ctx, prefs); err != nil {
+		t.Fatalf("UpsertNotificationPrefs failed: %v", err)
+	}
+	m.FlushPendingNotifications(ctx)
+	if dms, summary := discord.counts(); dms != 1 || summary != 1 {
+		t.Errorf("Expected the held DM and its broadcast, got %d DMs and %d summary posts", dms, summary)
+	}
+}
@@ -0,0 +1,281 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// digestInterval is how often digest users get their combined DM.
+	digestInterval = 30 * time.Minute
+	// digestFieldsPerEmbed stays under Discord's 25 fields per embed.
+	digestFieldsPerEmbed = 25
+	// digestEmbedsPerMessage is Discord's limit on embeds in one message.
+	digestEmbedsPerMessage = 10
+)
+
+// digestChanges picks the changes worth a digest entry: newly available nights that made it through
+// the request's filters, i.e. that are among stats from requestCampsiteStats. Digest items are keyed
+// by state change, so first-available openings (ID 0) aren't queued.
+func digestChanges(changes []db.StateChangeForRequest, stats []CampsiteStats) []db.StateChangeForRequest {
+	byCampsite := make(map[string]CampsiteStats, len(stats))
+	for _, st := range stats {
+		byCampsite[st.CampsiteID] = st
+	}
+	var out []db.StateChangeForRequest
+	for _, c := range changes {
+		st, ok := byCampsite[c.CampsiteID]
+		if c.NewAvailable && c.ID != 0 && ok && statsIncludeNight(st, c.Date) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// statsIncludeNight reports whether the night is one the campsite's notification would show: in
+// one of its qualifying runs when the request has a minimum stay, otherwise among its dates.
+func statsIncludeNight(st CampsiteStats, night time.Time) bool {
+	day := normalizeDay(night)
+	if len(st.Runs) > 0 {
+		for _, r := range st.Runs {
+			if !day.Before(normalizeDay(r.Start)) && !day.After(normalizeDay(r.End)) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, d := range st.Dates {
+		if normalizeDay(d).Equal(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// StartDigestFlusher sends each digest user their accumulated openings every digestInterval.
+func (m *Manager) StartDigestFlusher(ctx context.Context) {
+	ticker := time.NewTicker(digestInterval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.FlushDigests(ctx)
+			}
+		}
+	}()
+}
+
+// FlushDigests DMs every user with queued digest items one combined message, then clears what was
+// sent. Users in quiet hours keep accumulating until the window ends.
+func (m *Manager) FlushDigests(ctx context.Context) {
+	pending, err := m.store.GetPendingDigestItems(ctx)
+	if err != nil {
+		m.logger.Error("failed to get pending digest items", slog.Any("err", err))
+		return
+	}
+
+	now := time.Now()
+	for userID, items := range pending {
+		prefs, err := m.store.GetNotificationPrefs(ctx, userID)
+		if err != nil {
+			m.logger.Warn("get notification prefs failed", slog.String("userID", userID), slog.Any("err", err))
+			continue
+		}
+		if prefs.InQuietHours(now) {
+			continue
+		}
+
+		campgrounds := make(map[string]db.Campground)
+		for _, it := range items {
+			key := it.Provider + "/" + it.CampgroundID
+			if _, ok := campgrounds[key]; ok {
+				continue
+			}
+			cg, found, err := m.store.GetCampgroundByID(ctx, it.Provider, it.CampgroundID)
+			if err != nil || !found {
+				cg = db.Campground{Provider: it.Provider, ID: it.CampgroundID, Name: it.CampgroundID}
+			}
+			campgrounds[key] = cg
+		}
+
+		embeds := BuildDigestEmbeds(items, campgrounds, m.CampgroundURL)
+		if err := m.sendDigest(userID, embeds); err != nil {
+			m.logger.Warn("send digest failed", slog.String("userID", userID), slog.Any("err", err))
+			continue
+		}
+		m.emailNotification(ctx, userID, embeds)
+		if err := m.store.ClearDigest(ctx, userID, items); err != nil {
+			m.logger.Warn("clear digest failed", slog.String("userID", userID), slog.Any("err", err))
+		}
+		m.logger.Info("sent digest", slog.String("userID", userID), slog.Int("items", len(items)))
+	}
+}
+
+func (m *Manager) sendDigest(userID string, embeds []*discordgo.MessageEmbed) error {
+	channel, err := m.notifier.UserChannelCreate(userID)
+	if err != nil {
+		return err
+	}
+	for _, batch := range embedMessages(embeds) {
+		if _, err := m.notifier.ChannelMessageSendEmbeds(channel.ID, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// embedMessages groups embeds into messages that stay within Discord's limits on embeds per
+// message and characters across them. Each embed is assumed to fit on its own.
+func embedMessages(embeds []*discordgo.MessageEmbed) [][]*discordgo.MessageEmbed {
+	var out [][]*discordgo.MessageEmbed
+	size := 0
+	for _, e := range embeds {
+		n := embedSize(e)
+		if len(out) == 0 || len(out[len(out)-1]) == digestEmbedsPerMessage || size+n > embedTotalLimit {
+			out = append(out, nil)
+			size = 0
+		}
+		out[len(out)-1] = append(out[len(out)-1], e)
+		size += n
+	}
+	return out
+}
+
+// embedSize counts the characters of an embed that Discord holds against embedTotalLimit.
+func embedSize(e *discordgo.MessageEmbed) int {
+	n := utf8.RuneCountInString(e.Title) + utf8.RuneCountInString(e.Description)
+	for _, f := range e.Fields {
+		n += utf8.RuneCountInString(f.Name) + utf8.RuneCountInString(f.Value)
+	}
+	if e.Footer != nil {
+		n += utf8.RuneCountInString(e.Footer.Text)
+	}
+	if e.Author != nil {
+		n += utf8.RuneCountInString(e.Author.Name)
+	}
+	return n
+}
+
+// BuildDigestEmbeds renders queued openings as one field per campground, each listing its
+// campsites and their newly available nights. campgrounds is keyed by "provider/campgroundID";
+// campgroundURL may return "" when there is no link. Unavailable changes are ignored.
+func BuildDigestEmbeds(
+	changes []db.StateChangeForRequest,
+	campgrounds map[string]db.Campground,
+	campgroundURL func(provider, campgroundID string) string,
+) []*discordgo.MessageEmbed {
+	embeds, openings, places := buildOpeningsEmbeds(changes, campgrounds, campgroundURL)
+	if embeds == nil {
+		return nil
+	}
+	embeds[0].Title = clip(fmt.Sprintf("Schniff digest: %d new openings at %d campgrounds", openings, places), embedTitleLimit)
+	embeds[0].Description = "Nights that opened up since your last digest. Book fast, they may already be gone."
+	return embeds
+}
+
+// openingsHeaderRoom is left free in each openings embed for the title and description the caller
+// adds to the first one.
+const openingsHeaderRoom = embedTitleLimit + 256
+
+// buildOpeningsEmbeds lays out the available changes as one field per campground, ordered by name,
+// starting a new embed whenever the next field would break Discord's field or size limits. It
+// returns nil when there are no openings, along with how many openings and campgrounds it covers.
+func buildOpeningsEmbeds(
+	changes []db.StateChangeForRequest,
+	campgrounds map[string]db.Campground,
+	campgroundURL func(provider, campgroundID string) string,
+) (embeds []*discordgo.MessageEmbed, openings, places int) {
+	type group struct {
+		provider, id, name string
+		sites              map[string][]time.Time
+	}
+	groups := make(map[string]*group)
+	for _, c := range changes {
+		if !c.NewAvailable {
+			continue
+		}
+		key := c.Provider + "/" + c.CampgroundID
+		g, ok := groups[key]
+		if !ok {
+			name := campgrounds[key].Name
+			if name == "" {
+				name = c.CampgroundID
+			}
+			g = &group{provider: c.Provider, id: c.CampgroundID, name: name, sites: make(map[string][]time.Time)}
+			groups[key] = g
+		}
+		g.sites[c.CampsiteID] = append(g.sites[c.CampsiteID], normalizeDay(c.Date))
+		openings++
+	}
+	if len(groups) == 0 {
+		return nil, 0, 0
+	}
+
+	ordered := make([]*group, 0, len(groups))
+	for _, g := range groups {
+		ordered = append(ordered, g)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].name != ordered[j].name {
+			return ordered[i].name < ordered[j].name
+		}
+		return ordered[i].provider+ordered[i].id < ordered[j].provider+ordered[j].id
+	})
+
+	for _, g := range ordered {
+		field := &discordgo.MessageEmbedField{
+			Name:  clip(g.name, embedFieldNameLimit),
+			Value: digestFieldValue(g.sites, campgroundURL(g.provider, g.id)),
+		}
+		fieldSize := utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+		if len(embeds) == 0 || len(embeds[len(embeds)-1].Fields) == digestFieldsPerEmbed ||
+			embedSize(embeds[len(embeds)-1])+fieldSize > embedTotalLimit-openingsHeaderRoom {
+			embeds = append(embeds, &discordgo.MessageEmbed{Color: 0x00ff00})
+		}
+		embed := embeds[len(embeds)-1]
+		embed.Fields = append(embed.Fields, field)
+	}
+	return embeds, openings, len(ordered)
+}
+
+// digestFieldValue lists one line per campsite, e.g. "`012` Fri Jul 4, Sat Jul 5", keeping
+// within the field value limit by summarising the remainder.
+func digestFieldValue(sites map[string][]time.Time, url string) string {
+	ids := collectMapKeys(sites)
+	sort.Strings(ids)
+
+	var lines []string
+	if url != "" {
+		lines = append(lines, fmt.Sprintf("[View campground](%s)", url))
+	}
+	size := len(strings.Join(lines, "\n"))
+	for i, id := range ids {
+		dates := sites[id]
+		sort.Slice(dates, func(a, b int) bool { return dates[a].Before(dates[b]) })
+		formatted := make([]string, len(dates))
+		for j, d := range dates {
+			formatted[j] = d.Format("Mon Jan 2")
+		}
+		line := clip(fmt.Sprintf("`%s` %s", id, strings.Join(formatted, ", ")), embedFieldValueLimit/2)
+		// leave room for the summary line
+		if size+len(line)+1 > embedFieldValueLimit-40 {
+			lines = append(lines, fmt.Sprintf("…and %d more sites", len(ids)-i))
+			break
+		}
+		lines = append(lines, line)
+		size += len(line) + 1
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,126 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+)
+
+func TestBuildDigestEmbedsGroupsByCampground(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 7, d, 0, 0, 0, 0, time.UTC) }
+	changes := []db.StateChangeForRequest{
+		{ID: 1, Provider: "recreation_gov", CampgroundID: "232447", CampsiteID: "012", Date: day(5), NewAvailable: true, RequestID: 1},
+		{ID: 2, Provider: "recreation_gov", CampgroundID: "232447", CampsiteID: "012", Date: day(4), NewAvailable: true, RequestID: 1},
+		{ID: 3, Provider: "recreation_gov", CampgroundID: "232447", CampsiteID: "003", Date: day(4), NewAvailable: true, RequestID: 1},
+		{ID: 4, Provider: "recreation_gov", CampgroundID: "232447", CampsiteID: "099", Date: day(4), NewAvailable: false, RequestID: 1},
+		{ID: 5, Provider: "reservecalifornia", CampgroundID: "1260-2181", CampsiteID: "77", Date: day(6), NewAvailable: true, RequestID: 2},
+	}
+	campgrounds := map[string]db.Campground{
+		"recreation_gov/232447":       {Name: "Upper Pines"},
+		"reservecalifornia/1260-2181": {Name: "Big Basin"},
+	}
+	url := func(provider, id string) string {
+		if provider == "recreation_gov" {
+			return "https://example.com/" + id
+		}
+		return ""
+	}
+
+	embeds := BuildDigestEmbeds(changes, campgrounds, url)
+	if len(embeds) != 1 {
+		t.Fatalf("expected 1 embed, got %d", len(embeds))
+	}
+	e := embeds[0]
+	if !strings.Contains(e.Title, "4 new openings at 2 campgrounds") {
+		t.Errorf("title should count available openings only, got %q", e.Title)
+	}
+	if len(e.Fields) != 2 || e.Fields[0].Name != "Big Basin" || e.Fields[1].Name != "Upper Pines" {
+		t.Fatalf("expected one field per campground sorted by name, got %+v", e.Fields)
+	}
+
+	wantPines := "[View campground](https://example.com/232447)\n`003` Fri Jul 4\n`012` Fri Jul 4, Sat Jul 5"
+	if e.Fields[1].Value != wantPines {
+		t.Errorf("Upper Pines field =\n%s\nwant\n%s", e.Fields[1].Value, wantPines)
+	}
+	if e.Fields[0].Value != "`77` Sun Jul 6" {
+		t.Errorf("Big Basin field = %q", e.Fields[0].Value)
+	}
+	if strings.Contains(e.Fields[1].Value, "099") {
+		t.Error("unavailable campsite should not appear in the digest")
+	}
+}
+
+func TestBuildDigestEmbedsLimits(t *testing.T) {
+	if embeds := BuildDigestEmbeds([]db.StateChangeForRequest{{CampgroundID: "1", NewAvailable: false}}, nil, func(string, string) string { return "" }); embeds != nil {
+		t.Fatalf("expected no embeds without openings, got %d", len(embeds))
+	}
+
+	var changes []db.StateChangeForRequest
+	for cg := 0; cg < 30; cg++ {
+		for site := 0; site < 80; site++ {
+			changes = append(changes, db.StateChangeForRequest{
+				Provider:     "p",
+				CampgroundID: fmt.Sprintf("cg-%02d", cg),
+				CampsiteID:   fmt.Sprintf("site-%03d", site),
+				Date:         time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+				NewAvailable: true,
+			})
+		}
+	}
+	embeds := BuildDigestEmbeds(changes, nil, func(string, string) string { return "" })
+	fields := 0
+	for _, e := range embeds {
+		fields += len(e.Fields)
+		if len(e.Fields) > digestFieldsPerEmbed || embedSize(e) > embedTotalLimit {
+			t.Fatalf("embed has %d fields and %d chars, over the limits", len(e.Fields), embedSize(e))
+		}
+	}
+	if fields != 30 {
+		t.Fatalf("expected a field for each of the 30 campgrounds, got %d", fields)
+	}
+	for _, batch := range embedMessages(embeds) {
+		size := 0
+		for _, e := range batch {
+			size += embedSize(e)
+		}
+		if len(batch) > digestEmbedsPerMessage || size > embedTotalLimit {
+			t.Fatalf("message has %d embeds and %d chars, over the limits", len(batch), size)
+		}
+	}
+	for _, e := range embeds {
+		for _, f := range e.Fields {
+			if len(f.Value) > embedFieldValueLimit {
+				t.Fatalf("field %s is %d chars, over the limit", f.Name, len(f.Value))
+			}
+			if !strings.Contains(f.Value, "more sites") {
+				t.Fatalf("expected long field %s to be summarised", f.Name)
+			}
+		}
+	}
+}
+
+func TestDigestChangesKeepsWantedOpenings(t *testing.T) {
+	fri := time.Date(2025, 7, 4, 0, 0, 0, 0, time.UTC)
+	changes := []db.StateChangeForRequest{
+		{ID: 1, CampsiteID: "s1", Date: fri, NewAvailable: true},
+		{ID: 2, CampsiteID: "s1", Date: fri.AddDate(0, 0, 3), NewAvailable: true}, // a night the request filtered out
+		{ID: 3, CampsiteID: "s1", Date: fri, NewAvailable: false},
+		{ID: 4, CampsiteID: "s2", Date: fri, NewAvailable: true}, // a campsite the request filtered out
+		{ID: 0, CampsiteID: "s1", Date: fri, NewAvailable: true}, // first available, no state change
+	}
+	stats := []CampsiteStats{{CampsiteID: "s1", Dates: []time.Time{fri}}}
+	got := digestChanges(changes, stats)
+	if len(got) != 1 || got[0].ID != 1 {
+		t.Fatalf("expected only the opening that passed the filters, got %+v", got)
+	}
+
+	// With a minimum stay only nights in a qualifying run count
+	stats = []CampsiteStats{{CampsiteID: "s1", Dates: []time.Time{fri, fri.AddDate(0, 0, 3)}, Runs: []DateRun{{Start: fri.AddDate(0, 0, 3), End: fri.AddDate(0, 0, 4)}}}}
+	got = digestChanges(changes, stats)
+	if len(got) != 1 || got[0].ID != 2 {
+		t.Fatalf("expected only the opening inside the run, got %+v", got)
+	}
+}
@@ -0,0 +1,87 @@
+package manager
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/providers"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestAllOpeningsDelivered(t *testing.T) {
+	night := func(d int) time.Time { return time.Date(2025, 7, d, 0, 0, 0, 0, time.UTC) }
+	opening := func(d int) db.StateChangeForRequest {
+		return db.StateChangeForRequest{Provider: "p", CampgroundID: "cg1", CampsiteID: "s1", Date: night(d), NewAvailable: true}
+	}
+
+	delivered := make(map[string]bool)
+	if allOpeningsDelivered([]db.StateChangeForRequest{opening(3)}, delivered) {
+		t.Error("nothing delivered yet, but the opening was treated as a duplicate")
+	}
+
+	// A DM that showed s1 on night 3 covers that opening, and only that one
+	addDeliveredOpenings(delivered, "p", "cg1", []CampsiteStats{{CampsiteID: "s1", NewDates: map[time.Time]bool{night(3): true}}})
+	if !allOpeningsDelivered([]db.StateChangeForRequest{opening(3)}, delivered) {
+		t.Error("night 3 was delivered but the request wasn't treated as a duplicate")
+	}
+	if allOpeningsDelivered([]db.StateChangeForRequest{opening(3), opening(7)}, delivered) {
+		t.Error("night 7 hasn't been delivered but the request was treated as a duplicate")
+	}
+
+	// Closures alone aren't openings to dedupe
+	closed := opening(3)
+	closed.NewAvailable = false
+	if allOpeningsDelivered([]db.StateChangeForRequest{closed}, delivered) {
+		t.Error("a closure with no openings was treated as a duplicate")
+	}
+}
+
+func TestProcessNotifications_DedupesOnlyDeliveredOpenings(t *testing.T) {
+	ctx := context.Background()
+	store, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+
+	checkin := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 10)
+	// Request 1 wants a longer stay than is open, so it filters the opening out and sends nothing.
+	// Request 2 overlaps it and should still notify; request 3 repeats request 2 and shouldn't.
+	for _, req := range []db.SchniffRequest{
+		{UserID: "user1", Provider: "p", CampgroundID: "cg1", Checkin: checkin, Checkout: checkin.AddDate(0, 0, 3), MinNights: 3},
+		{UserID: "user1", Provider: "p", CampgroundID: "cg1", Checkin: checkin, Checkout: checkin.AddDate(0, 0, 1)},
+		{UserID: "user1", Provider: "p", CampgroundID: "cg1", Checkin: checkin, Checkout: checkin.AddDate(0, 0, 2)},
+	} {
+		if _, err := store.AddRequest(ctx, req); err != nil {
+			t.Fatalf("AddRequest failed: %v", err)
+		}
+	}
+	if err := store.UpsertCampsiteAvailabilityBatch(ctx, []db.CampsiteAvailability{{
+		Provider: "p", CampgroundID: "cg1", CampsiteID: "s1", Date: checkin, Available: true, LastChecked: time.Now(),
+	}}); err != nil {
+		t.Fatalf("UpsertCampsiteAvailabilityBatch failed: %v", err)
+	}
+
+	discord := &fakeDiscord{}
+	session, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("discordgo.New failed: %v", err)
+	}
+	session.Client = &http.Client{Transport: discord}
+	m := NewManager(store, providers.NewRegistry(), session, "summary")
+
+	reqs, err := store.ListActiveRequests(ctx)
+	if err != nil {
+		t.Fatalf("ListActiveRequests failed: %v", err)
+	}
+	if err := m.ProcessNotificationsWithBatches(ctx, reqs); err != nil {
+		t.Fatalf("ProcessNotificationsWithBatches failed: %v", err)
+	}
+	if dms := discord.sent(); len(dms) != 1 {
+		t.Fatalf("Expected one DM from request 2, got %d: %q", len(dms), dms)
+	}
+}
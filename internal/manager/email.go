@@ -0,0 +1,76 @@
+package manager
+
+import (
+	"context"
+	"html"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/brensch/schniffer/internal/email"
+	"github.com/bwmarrin/discordgo"
+)
+
+// SetEmailSender enables email copies of notifications for users with a verified address.
+// Discord stays the primary channel; email delivery is best-effort.
+func (m *Manager) SetEmailSender(sender email.Sender) {
+	m.email = sender
+}
+
+// emailNotification sends the notification embeds to the user's verified email, if they have one.
+func (m *Manager) emailNotification(ctx context.Context, userID string, embeds []*discordgo.MessageEmbed) {
+	if m.email == nil || len(embeds) == 0 {
+		return
+	}
+	to, ok, err := m.store.GetVerifiedUserEmail(ctx, userID)
+	if err != nil {
+		m.logger.Warn("get user email failed", slog.String("userID", userID), slog.Any("err", err))
+		return
+	}
+	if !ok {
+		return
+	}
+	if err := m.email.Send(to, embeds[0].Title, renderEmbedsHTML(embeds)); err != nil {
+		m.logger.Warn("email notification failed", slog.String("userID", userID), slog.Any("err", err))
+	}
+}
+
+var (
+	markdownLink = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`)
+	markdownBold = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+)
+
+// markdownToHTML escapes Discord-flavoured text and converts the links, bold and line breaks we use.
+func markdownToHTML(s string) string {
+	s = html.EscapeString(s)
+	s = markdownLink.ReplaceAllString(s, `<a href="$2">$1</a>`)
+	s = markdownBold.ReplaceAllString(s, `<strong>$1</strong>`)
+	return strings.ReplaceAll(s, "\n", "<br>\n")
+}
+
+// renderEmbedsHTML renders notification embeds as a simple HTML email body.
+func renderEmbedsHTML(embeds []*discordgo.MessageEmbed) string {
+	var sb strings.Builder
+	sb.WriteString(`<html><body style="font-family: sans-serif;">` + "\n")
+	for _, e := range embeds {
+		if e.Title != "" {
+			title := html.EscapeString(e.Title)
+			if e.URL != "" {
+				title = `<a href="` + html.EscapeString(e.URL) + `">` + title + `</a>`
+			}
+			sb.WriteString("<h2>" + title + "</h2>\n")
+		}
+		if e.Description != "" {
+			sb.WriteString("<p>" + markdownToHTML(e.Description) + "</p>\n")
+		}
+		for _, f := range e.Fields {
+			sb.WriteString("<h3>" + markdownToHTML(f.Name) + "</h3>\n")
+			sb.WriteString("<p>" + markdownToHTML(f.Value) + "</p>\n")
+		}
+		if e.Footer != nil && e.Footer.Text != "" {
+			sb.WriteString(`<p style="color: #777;">` + markdownToHTML(e.Footer.Text) + "</p>\n")
+		}
+	}
+	sb.WriteString("</body></html>\n")
+	return sb.String()
+}
@@ -0,0 +1,68 @@
+package manager
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/providers"
+	"github.com/bwmarrin/discordgo"
+)
+
+type fakeEmailSender struct {
+	to, subject, body string
+	sent              int
+}
+
+func (f *fakeEmailSender) Send(to, subject, body string) error {
+	f.to, f.subject, f.body = to, subject, body
+	f.sent++
+	return nil
+}
+
+func TestEmailNotification(t *testing.T) {
+	store, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	m := NewManager(store, providers.NewRegistry(), nil, "")
+	sender := &fakeEmailSender{}
+	m.SetEmailSender(sender)
+
+	embeds := []*discordgo.MessageEmbed{{
+		Title:       "🏕️ Lakeside <has sites>",
+		URL:         "https://example.com/cg",
+		Description: "**3 sites** open",
+		Fields:      []*discordgo.MessageEmbedField{{Name: "Site 1", Value: "[Book](https://example.com/site/1)\nMon 2025-07-07"}},
+	}}
+
+	// unverified users get nothing
+	if err := store.SetPendingUserEmail(ctx, "user1", "camper@example.com", "code"); err != nil {
+		t.Fatalf("SetPendingUserEmail failed: %v", err)
+	}
+	m.emailNotification(ctx, "user1", embeds)
+	if sender.sent != 0 {
+		t.Fatalf("Expected no email before verification")
+	}
+
+	if _, err := store.VerifyUserEmail(ctx, "user1", "code"); err != nil {
+		t.Fatalf("VerifyUserEmail failed: %v", err)
+	}
+	m.emailNotification(ctx, "user1", embeds)
+	if sender.sent != 1 || sender.to != "camper@example.com" || sender.subject != embeds[0].Title {
+		t.Fatalf("Unexpected email: %+v", sender)
+	}
+	for _, want := range []string{
+		`<a href="https://example.com/cg">🏕️ Lakeside &lt;has sites&gt;</a>`,
+		`<strong>3 sites</strong> open`,
+		`<a href="https://example.com/site/1">Book</a><br>`,
+	} {
+		if !strings.Contains(sender.body, want) {
+			t.Errorf("email body missing %q:\n%s", want, sender.body)
+		}
+	}
+}
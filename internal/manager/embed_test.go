@@ -144,3 +144,24 @@ func TestBuildDeactivationEmbed_SingleRequest(t *testing.T) {
 func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
+
+func TestMarkChanges(t *testing.T) {
+	day := time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)
+	stats := []CampsiteStats{{CampsiteID: "a"}, {CampsiteID: "b"}}
+	markChanges(stats, []db.StateChangeForRequest{
+		{CampsiteID: "a", Date: day.Add(3 * time.Hour), NewAvailable: true},
+		{CampsiteID: "a", Date: day.AddDate(0, 0, 2), NewAvailable: false},
+		{CampsiteID: "a", Date: day.AddDate(0, 0, 1), NewAvailable: false},
+		{CampsiteID: "gone", Date: day, NewAvailable: false},
+	})
+
+	if !stats[0].NewDates[day] || len(stats[0].NewDates) != 1 {
+		t.Errorf("Expected a's first night marked new, got %v", stats[0].NewDates)
+	}
+	if got := stats[0].MissedDates; len(got) != 2 || !got[0].Equal(day.AddDate(0, 0, 1)) || !got[1].Equal(day.AddDate(0, 0, 2)) {
+		t.Errorf("Expected a's booked nights in date order, got %v", got)
+	}
+	if stats[1].NewDates != nil || stats[1].MissedDates != nil {
+		t.Errorf("Expected b unmarked, got %+v", stats[1])
+	}
+}
@@ -0,0 +1,270 @@
+package manager
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+func TestFilterStatsByRating(t *testing.T) {
+	stats := []CampsiteStats{
+		{CampsiteID: "good", Details: db.CampsiteDetails{Rating: 4.5}},
+		{CampsiteID: "poor", Details: db.CampsiteDetails{Rating: 2.0}},
+		{CampsiteID: "unrated"},
+	}
+	ids := func(in []CampsiteStats) []string {
+		var out []string
+		for _, s := range in {
+			out = append(out, s.CampsiteID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name             string
+		campgroundRating float64
+		minRating        float64
+		requireRating    bool
+		want             []string
+	}{
+		{name: "no filter", want: []string{"good", "poor", "unrated"}},
+		{name: "min rating keeps unrated", minRating: 4, want: []string{"good", "unrated"}},
+		{name: "require rating drops unrated", minRating: 4, requireRating: true, want: []string{"good"}},
+		{name: "falls back to campground rating", campgroundRating: 4.2, minRating: 4, requireRating: true, want: []string{"good", "unrated"}},
+		{name: "campground fallback below threshold", campgroundRating: 3, minRating: 4, want: []string{"good"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ids(filterStatsByRating(stats, tt.campgroundRating, tt.minRating, tt.requireRating))
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterSpecialty(t *testing.T) {
+	stats := []CampsiteStats{
+		{CampsiteID: "cabin", Details: db.CampsiteDetails{Type: "cabin nonelectric"}},
+		{CampsiteID: "named-yurt", Details: db.CampsiteDetails{Type: "standard", Name: "Yurt 3"}},
+		{CampsiteID: "lookout", Details: db.CampsiteDetails{Type: "lookout"}},
+		{CampsiteID: "tent", Details: db.CampsiteDetails{Type: "tent only nonelectric"}},
+		{CampsiteID: "unknown"},
+	}
+	got := filterSpecialty(stats)
+	want := []string{"cabin", "named-yurt", "lookout"}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].CampsiteID != want[i] {
+			t.Errorf("got %s at %d, want %s", got[i].CampsiteID, i, want[i])
+		}
+	}
+}
+
+func TestApplyNightlyCosts(t *testing.T) {
+	stats := []CampsiteStats{
+		{CampsiteID: "priced"},
+		{CampsiteID: "listed", Details: db.CampsiteDetails{CostPerNight: 25}},
+		{CampsiteID: "unknown"},
+	}
+	items := []db.AvailabilityItem{
+		{CampsiteID: "priced", CostPerNight: 30},
+		{CampsiteID: "priced", CostPerNight: 45},
+		{CampsiteID: "listed"},
+		{CampsiteID: "unknown"},
+	}
+	applyNightlyCosts(stats, items)
+
+	want := map[string]float64{"priced": 45, "listed": 25, "unknown": 0}
+	for _, st := range stats {
+		if st.CostPerNight != want[st.CampsiteID] {
+			t.Errorf("%s: expected cost %v, got %v", st.CampsiteID, want[st.CampsiteID], st.CostPerNight)
+		}
+	}
+}
+
+func TestConsecutiveRuns(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 8, d, 0, 0, 0, 0, time.UTC) }
+	// runs: 1-3 (3 nights), 5 (1 night), 7-8 (2 nights), 31 Aug-2 Sep across the month boundary (3 nights)
+	dates := []time.Time{day(1), day(2), day(3), day(5), day(7), day(8), day(31), day(32), day(33)}
+
+	tests := []struct {
+		minNights int
+		want      []DateRun
+	}{
+		{1, []DateRun{{day(1), day(3)}, {day(5), day(5)}, {day(7), day(8)}, {day(31), day(33)}}},
+		{2, []DateRun{{day(1), day(3)}, {day(7), day(8)}, {day(31), day(33)}}},
+		{3, []DateRun{{day(1), day(3)}, {day(31), day(33)}}}, // exactly-min-length runs qualify
+		{4, nil},
+	}
+	for _, tt := range tests {
+		got := ConsecutiveRuns(dates, tt.minNights)
+		if len(got) != len(tt.want) {
+			t.Errorf("min %d: expected %d runs, got %+v", tt.minNights, len(tt.want), got)
+			continue
+		}
+		for i := range got {
+			if !got[i].Start.Equal(tt.want[i].Start) || !got[i].End.Equal(tt.want[i].End) {
+				t.Errorf("min %d run %d: expected %v, got %v", tt.minNights, i, tt.want[i], got[i])
+			}
+		}
+	}
+
+	if runs := ConsecutiveRuns(nil, 2); len(runs) != 0 {
+		t.Errorf("Expected no runs for no dates, got %+v", runs)
+	}
+	if runs := ConsecutiveRuns([]time.Time{day(9)}, 1); len(runs) != 1 || runs[0].Nights() != 1 {
+		t.Errorf("Expected a single one-night run, got %+v", runs)
+	}
+}
+
+func TestFilterMinNights(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 8, d, 0, 0, 0, 0, time.UTC) }
+	stats := []CampsiteStats{
+		{CampsiteID: "gappy", Dates: []time.Time{day(1), day(3), day(5)}},
+		{CampsiteID: "stay", Dates: []time.Time{day(1), day(2), day(4), day(5), day(6)}},
+	}
+	got := filterMinNights(stats, 3)
+	if len(got) != 1 || got[0].CampsiteID != "stay" {
+		t.Fatalf("Expected only the campsite with a 3 night run, got %+v", got)
+	}
+	if len(got[0].Runs) != 1 || !got[0].Runs[0].Start.Equal(day(4)) || got[0].Runs[0].Nights() != 3 {
+		t.Errorf("Expected the 4th-6th run, got %+v", got[0].Runs)
+	}
+}
+
+func TestFilterSiteKind(t *testing.T) {
+	stats := []CampsiteStats{
+		{CampsiteID: "tent", Details: db.CampsiteDetails{Type: "STANDARD NONELECTRIC", Equipment: []string{"Tent", "Small Tent"}}},
+		{CampsiteID: "rv", Details: db.CampsiteDetails{Type: "STANDARD ELECTRIC", Equipment: []string{"RV", "Trailer"}}},
+		{CampsiteID: "both", Details: db.CampsiteDetails{Type: "STANDARD ELECTRIC", Equipment: []string{"RV", "Tent"}}},
+		{CampsiteID: "unknown"},
+	}
+	tests := []struct {
+		name              string
+		equipment, cgType string
+		want              []string
+	}{
+		{name: "no filter", want: []string{"tent", "rv", "both", "unknown"}},
+		{name: "equipment", equipment: "Tent", want: []string{"tent", "both"}},
+		{name: "equipment ignores case", equipment: "tent", want: []string{"tent", "both"}},
+		{name: "campsite type", cgType: "standard electric", want: []string{"rv", "both"}},
+		{name: "both filters", equipment: "Tent", cgType: "STANDARD ELECTRIC", want: []string{"both"}},
+		{name: "nothing matches", equipment: "Boat", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, st := range filterSiteKind(stats, tt.equipment, tt.cgType) {
+				got = append(got, st.CampsiteID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestCampsiteStatsFiltersBySiteKind(t *testing.T) {
+	store, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	_, err = store.UpsertCampsiteMetadataBatch(ctx, "p", "cg", []providers.CampsiteInfo{
+		{ID: "tent", Type: "STANDARD NONELECTRIC", Equipment: []string{"Tent"}},
+		{ID: "rv", Type: "STANDARD ELECTRIC", Equipment: []string{"RV"}},
+	})
+	if err != nil {
+		t.Fatalf("UpsertCampsiteMetadataBatch: %v", err)
+	}
+	night := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 10)
+	err = store.UpsertCampsiteAvailabilityBatch(ctx, []db.CampsiteAvailability{
+		{Provider: "p", CampgroundID: "cg", CampsiteID: "tent", Date: night, Available: true, LastChecked: time.Now()},
+		{Provider: "p", CampgroundID: "cg", CampsiteID: "rv", Date: night, Available: true, LastChecked: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("UpsertCampsiteAvailabilityBatch: %v", err)
+	}
+
+	m := &Manager{store: store, logger: slog.Default()}
+	req := db.SchniffRequest{Provider: "p", CampgroundID: "cg", Checkin: night, Checkout: night.AddDate(0, 0, 1), IncludeDayUse: true}
+
+	req.Equipment = "tent"
+	stats, _, skipped := m.requestCampsiteStats(ctx, req)
+	if skipped || len(stats) != 1 || stats[0].CampsiteID != "tent" {
+		t.Fatalf("tent filter: got %+v (skipped %v)", stats, skipped)
+	}
+
+	req.Equipment, req.CampsiteType = "", "STANDARD ELECTRIC"
+	if stats, _, _ = m.requestCampsiteStats(ctx, req); len(stats) != 1 || stats[0].CampsiteID != "rv" {
+		t.Fatalf("type filter: got %+v", stats)
+	}
+
+	req.Equipment = "Tent"
+	if stats, _, skipped = m.requestCampsiteStats(ctx, req); len(stats) != 0 || !skipped {
+		t.Fatalf("no site is an electric tent site, got %+v (skipped %v)", stats, skipped)
+	}
+}
+
+func TestRequestCampsiteStatsReservableOnly(t *testing.T) {
+	store, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	_, err = store.UpsertCampsiteMetadataBatch(ctx, "p", "cg", []providers.CampsiteInfo{
+		{ID: "site", ReserveType: "Site-Specific"},
+		{ID: "walkup", ReserveType: "Walk-Up"},
+		{ID: "overflow", ReserveType: "Non Site-Specific"},
+		{ID: "unknown"},
+	})
+	if err != nil {
+		t.Fatalf("UpsertCampsiteMetadataBatch: %v", err)
+	}
+	night := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 10)
+	var avail []db.CampsiteAvailability
+	for _, id := range []string{"site", "walkup", "overflow", "unknown"} {
+		avail = append(avail, db.CampsiteAvailability{Provider: "p", CampgroundID: "cg", CampsiteID: id, Date: night, Available: true, LastChecked: time.Now()})
+	}
+	if err := store.UpsertCampsiteAvailabilityBatch(ctx, avail); err != nil {
+		t.Fatalf("UpsertCampsiteAvailabilityBatch: %v", err)
+	}
+
+	m := &Manager{store: store, logger: slog.Default()}
+	req := db.SchniffRequest{Provider: "p", CampgroundID: "cg", Checkin: night, Checkout: night.AddDate(0, 0, 1)}
+	ids := func() []string {
+		stats, _, _ := m.requestCampsiteStats(ctx, req)
+		var out []string
+		for _, st := range stats {
+			out = append(out, st.CampsiteID)
+		}
+		slices.Sort(out)
+		return out
+	}
+
+	if got := ids(); !slices.Equal(got, []string{"overflow", "site", "unknown", "walkup"}) {
+		t.Errorf("without the filter got %v, want every site", got)
+	}
+	req.ReservableOnly = true
+	if got := ids(); !slices.Equal(got, []string{"site", "unknown"}) {
+		t.Errorf("reservable only got %v, want the site-specific and unlabelled sites", got)
+	}
+}
@@ -0,0 +1,85 @@
+package manager
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/providers"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestProcessNotifications_NewRequestGetsExistingAvailability(t *testing.T) {
+	checkin := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 10)
+
+	cases := []struct {
+		name string
+		seed func(t *testing.T, store *db.Store)
+	}{
+		{"opened while tracked", func(t *testing.T, store *db.Store) {
+			err := store.UpsertCampsiteAvailabilityBatch(context.Background(), []db.CampsiteAvailability{{
+				Provider: "p", CampgroundID: "cg1", CampsiteID: "s1", Date: checkin, Available: true, LastChecked: time.Now(),
+			}})
+			if err != nil {
+				t.Fatalf("UpsertCampsiteAvailabilityBatch failed: %v", err)
+			}
+		}},
+		// availability with no state change behind it, e.g. recorded before state changes were kept
+		{"no state change history", func(t *testing.T, store *db.Store) {
+			_, err := store.DB.Exec(`INSERT INTO campsite_availability(provider, campground_id, campsite_id, date, available, last_checked)
+				VALUES ('p', 'cg1', 's1', ?, true, ?)`, checkin, time.Now())
+			if err != nil {
+				t.Fatalf("Failed to seed availability: %v", err)
+			}
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			store, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+			if err != nil {
+				t.Fatalf("Failed to open store: %v", err)
+			}
+			defer store.Close()
+			tc.seed(t, store)
+
+			if _, err := store.AddRequest(ctx, db.SchniffRequest{
+				UserID: "user1", Provider: "p", CampgroundID: "cg1",
+				Checkin: checkin, Checkout: checkin.AddDate(0, 0, 1),
+			}); err != nil {
+				t.Fatalf("AddRequest failed: %v", err)
+			}
+
+			discord := &fakeDiscord{}
+			session, err := discordgo.New("Bot test")
+			if err != nil {
+				t.Fatalf("discordgo.New failed: %v", err)
+			}
+			session.Client = &http.Client{Transport: discord}
+			m := NewManager(store, providers.NewRegistry(), session, "summary")
+
+			process := func() {
+				t.Helper()
+				reqs, err := store.ListActiveRequests(ctx)
+				if err != nil {
+					t.Fatalf("ListActiveRequests failed: %v", err)
+				}
+				if err := m.ProcessNotificationsWithBatches(ctx, reqs); err != nil {
+					t.Fatalf("ProcessNotificationsWithBatches failed: %v", err)
+				}
+			}
+
+			process()
+			if dms := discord.sent(); len(dms) != 1 {
+				t.Fatalf("Expected the first reconcile to notify about the open site, got %d DMs", len(dms))
+			}
+			process()
+			if dms := discord.sent(); len(dms) != 1 {
+				t.Errorf("Expected no repeat notification on the next reconcile, got %d DMs", len(dms))
+			}
+		})
+	}
+}
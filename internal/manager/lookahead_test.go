@@ -0,0 +1,69 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+// windowProvider publishes days of availability and records the last range it was asked for.
+type windowProvider struct {
+	slowProvider
+	days     int
+	lastEnd  time.Time
+	lastCall bool
+}
+
+func (p *windowProvider) MaxLookaheadDays() int { return p.days }
+func (p *windowProvider) FetchAvailability(ctx context.Context, campgroundID string, start, end time.Time) ([]providers.CampsiteAvailability, error) {
+	p.lastEnd, p.lastCall = end, true
+	return nil, nil
+}
+
+func TestAdhocScrapeRespectsProviderLookahead(t *testing.T) {
+	m, _ := newPollTestManager(t, 0)
+	short := &windowProvider{days: 30}
+	long := &windowProvider{days: 365}
+	m.reg.Register("short", short)
+	m.reg.Register("long", long)
+
+	for name, prov := range map[string]*windowProvider{"short": short, "long": long} {
+		before := normalizeDay(time.Now())
+		if err := m.ProcessAdhocScrapeRequest(context.Background(), &db.AdhocScrapeRequest{Provider: name, CampgroundID: "cg1"}); err != nil {
+			t.Fatalf("%s: ProcessAdhocScrapeRequest failed: %v", name, err)
+		}
+		if !prov.lastCall {
+			t.Fatalf("%s: provider wasn't scraped", name)
+		}
+		// Allow for the test straddling midnight
+		want := before.AddDate(0, 0, prov.days)
+		if got := prov.lastEnd; !got.Equal(want) && !got.Equal(want.AddDate(0, 0, 1)) {
+			t.Errorf("%s: scrape ended %s, want %s", name, got.Format("2006-01-02"), want.Format("2006-01-02"))
+		}
+	}
+
+	if got := lookaheadEnd(&slowProvider{}, time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC)); !got.Equal(time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("default lookahead ended %s, want 2025-06-30", got.Format("2006-01-02"))
+	}
+}
+
+func TestWithinLookahead(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	near := pc{prov: "p", cg: "near"}
+	far := pc{prov: "p", cg: "far"}
+	datesByPC := map[pc]map[time.Time]struct{}{
+		near: {day(1): {}, day(10): {}},
+		far:  {day(10): {}, day(20): {}},
+	}
+
+	got := withinLookahead(datesByPC, day(10))
+	if len(got) != 1 {
+		t.Fatalf("got %d campgrounds, want only the one with dates inside the window", len(got))
+	}
+	if _, ok := got[near][day(1)]; !ok || len(got[near]) != 1 {
+		t.Errorf("near dates = %v, want only Jan 1", got[near])
+	}
+}
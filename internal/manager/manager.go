@@ -2,13 +2,17 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/email"
+	"github.com/brensch/schniffer/internal/logctx"
 	"github.com/brensch/schniffer/internal/providers"
 	"github.com/bwmarrin/discordgo"
 	"github.com/robfig/cron/v3"
@@ -26,8 +30,28 @@ type Manager struct {
 	mu               sync.Mutex
 	notifier         *discordgo.Session
 	summaryChannelID string
+	summaryOverride  string // SetSummaryChannel's channel, used instead of summaryChannelID when set
+	summaryFallback  string // user DMed summaries the channel refuses; empty only logs
 	logger           *slog.Logger
 	dbWriteChan      chan dbWriteRequest
+	dbWriteMu        sync.RWMutex          // held for writing only to close dbWriteChan
+	dbWritesClosed   bool                  // set by Shutdown, guarded by dbWriteMu
+	dbWriterDone     chan struct{}         // closed once dbWriter has drained dbWriteChan
+	template         *NotificationTemplate // nil uses DefaultNotificationTemplate
+	embedOptions     EmbedOptions          // zero limits use DefaultEmbedOptions
+
+	announceNewCampsites bool // broadcast campsites that appear in a metadata sync
+	pollConcurrency      int  // campgrounds fetched in parallel per provider poll; <=1 is serial
+
+	pollIntervals map[string]time.Duration // current interval per provider loop, guarded by mu
+	polled        map[pc]campgroundPoll    // last poll of each campground, guarded by mu
+	lastPolls     map[string]time.Time     // last poll cycle per provider that finished without error, guarded by mu
+	syncHooks     []func()                 // called after each campground or campsite sync, guarded by mu
+
+	email email.Sender // optional email copies of notifications
+
+	historyRetention      time.Duration // past nights kept before pruning; 0 uses defaultHistoryRetention
+	notificationRetention time.Duration // sent notifications kept; 0 uses defaultNotificationRetention
 }
 
 func NewManager(store *db.Store, reg *providers.Registry, notifier *discordgo.Session, summaryChannelID string) *Manager {
@@ -38,18 +62,46 @@ func NewManager(store *db.Store, reg *providers.Registry, notifier *discordgo.Se
 		summaryChannelID: summaryChannelID,
 		logger:           slog.Default(),
 		dbWriteChan:      make(chan dbWriteRequest, 100), // Buffer to prevent blocking
+		dbWriterDone:     make(chan struct{}),
 	}
 	// Start database writer goroutine
 	go m.dbWriter()
 	return m
 }
 
-func (m *Manager) GetSummaryChannel() string {
-	return m.summaryChannelID
+// SetNotificationTemplate overrides the wording of availability notifications.
+func (m *Manager) SetNotificationTemplate(t *NotificationTemplate) {
+	m.template = t
+}
+
+// SetEmbedOptions changes how much of each availability notification is shown.
+func (m *Manager) SetEmbedOptions(opts EmbedOptions) {
+	m.embedOptions = opts
+}
+
+// SetPollConcurrency sets how many campgrounds a provider poll fetches at once.
+func (m *Manager) SetPollConcurrency(n int) {
+	m.pollConcurrency = n
+}
+
+// SetAnnounceNewCampsites turns on broadcast notes when a provider adds campsites to a campground.
+func (m *Manager) SetAnnounceNewCampsites(enabled bool) {
+	m.announceNewCampsites = enabled
+}
+
+func (m *Manager) notificationTemplate() *NotificationTemplate {
+	if m.template == nil {
+		return defaultNotificationTemplate
+	}
+	return m.template
 }
 
+// ErrShuttingDown is returned for database writes queued after Shutdown.
+var ErrShuttingDown = errors.New("manager is shutting down")
+
 // dbWriter processes database write operations sequentially to avoid lock contention
 func (m *Manager) dbWriter() {
+	defer close(m.dbWriterDone)
 	for req := range m.dbWriteChan {
 		req.result <- req.operation()
 		close(req.result)
@@ -64,12 +116,38 @@ func (m *Manager) executeDBOperation(operation func() error) error {
 		result:    result,
 	}
 
+	// The read lock keeps Shutdown from closing the channel mid-send
+	m.dbWriteMu.RLock()
+	if m.dbWritesClosed {
+		m.dbWriteMu.RUnlock()
+		return ErrShuttingDown
+	}
 	// This will block and wait if the channel is full,
 	// guaranteeing sequential execution.
 	m.dbWriteChan <- req
+	m.dbWriteMu.RUnlock()
 	return <-result
 }
 
+// Shutdown stops accepting database writes and waits for the ones already queued to run, until
+// ctx is done. Call it after the pollers have stopped and before closing the store. Later writes
+// fail with ErrShuttingDown.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.dbWriteMu.Lock()
+	if !m.dbWritesClosed {
+		m.dbWritesClosed = true
+		close(m.dbWriteChan)
+	}
+	m.dbWriteMu.Unlock()
+
+	select {
+	case <-m.dbWriterDone:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d queued database writes not flushed: %w", len(m.dbWriteChan), ctx.Err())
+	}
+}
+
 // Run polls providers at dynamic intervals based on their rate limit status
 func (m *Manager) Run(ctx context.Context) {
 	m.logger.Info("Starting manager")
@@ -77,6 +155,15 @@ func (m *Manager) Run(ctx context.Context) {
 	// Start the ad-hoc scrape processor
 	m.StartAdhocScrapeProcessor(ctx)
 
+	// Send notifications held during users' quiet hours once they end
+	m.StartPendingNotificationFlusher(ctx)
+
+	// Retry notifications whose DMs failed with transient Discord errors
+	m.StartUndeliveredNotificationRetrier(ctx)
+
+	// Send digest users their combined openings
+	m.StartDigestFlusher(ctx)
+
 	// Start a goroutine for each provider
 	for _, providerName := range m.reg.GetProviderNames() {
 		go m.runProviderLoop(ctx, providerName)
@@ -86,11 +173,81 @@ func (m *Manager) Run(ctx context.Context) {
 	<-ctx.Done()
 }
 
-const fastestPoll = 10 * time.Second
-const pollIncrement = 10 * time.Second
+// PollIntervals returns the current polling interval of each running provider loop.
+func (m *Manager) PollIntervals() map[string]time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string]time.Duration, len(m.pollIntervals))
+	for k, v := range m.pollIntervals {
+		out[k] = v
+	}
+	return out
+}
+
+// LastSuccessfulPolls returns when each provider loop last finished a poll cycle without error.
+func (m *Manager) LastSuccessfulPolls() map[string]time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string]time.Time, len(m.lastPolls))
+	for k, v := range m.lastPolls {
+		out[k] = v
+	}
+	return out
+}
+
+func (m *Manager) recordSuccessfulPoll(providerName string, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.lastPolls == nil {
+		m.lastPolls = make(map[string]time.Time)
+	}
+	m.lastPolls[providerName] = at
+}
+
+func (m *Manager) setPollInterval(providerName string, interval time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.pollIntervals == nil {
+		m.pollIntervals = make(map[string]time.Duration)
+	}
+	m.pollIntervals[providerName] = interval
+}
+
+// providerRateLimit returns the provider's polling limits, falling back to the defaults for
+// unknown providers or non-positive values.
+func (m *Manager) providerRateLimit(providerName string) (min, increment time.Duration) {
+	min, increment = providers.DefaultMinPollInterval, providers.DefaultPollIncrement
+	prov, ok := m.reg.Get(providerName)
+	if !ok {
+		return min, increment
+	}
+	pmin, pinc := prov.RateLimit()
+	if pmin > 0 {
+		min = pmin
+	}
+	if pinc > 0 {
+		increment = pinc
+	}
+	return min, increment
+}
+
+// nextPollInterval backs off by increment after a poll the provider rate limited and drops back to
+// min otherwise. Other failures (bad responses, missing campgrounds, outages) aren't made any
+// better by polling slower, so they don't back off.
+func nextPollInterval(current, min, increment time.Duration, pollErr error) time.Duration {
+	if !errors.Is(pollErr, providers.ErrRateLimited) {
+		return min
+	}
+	if current < min {
+		current = min
+	}
+	return current + increment
+}
 
 func (m *Manager) runProviderLoop(ctx context.Context, providerName string) {
-	interval := fastestPoll
+	minInterval, increment := m.providerRateLimit(providerName)
+	interval := minInterval
+	m.setPollInterval(providerName, interval)
 
 	m.logger.Info("Starting provider loop", "provider", providerName, "interval", interval)
 
@@ -100,34 +257,39 @@ func (m *Manager) runProviderLoop(ctx context.Context, providerName string) {
 			return
 		case <-time.After(interval):
 			err := m.PollProvider(ctx, providerName)
-			if err != nil {
-				// Double the interval on errors
-				interval += pollIncrement
+			interval = nextPollInterval(interval, minInterval, increment, err)
+			switch {
+			case err == nil:
+				m.recordSuccessfulPoll(providerName, time.Now())
+			case errors.Is(err, providers.ErrRateLimited):
 				m.logger.Warn("Rate limited, increasing interval", "provider", providerName, "new_interval", interval)
 
 				msg := fmt.Sprintf("⚠️🐽🛑 %s rate limit detected while schniffing. Increased polling interval to %v", providerName, interval)
-				_, err = m.notifier.ChannelMessageSend(m.summaryChannelID, msg)
-				if err != nil {
+				if err := m.sendSummary(msg); err != nil {
 					m.logger.Warn("failed to send rate limit notification", slog.Any("err", err))
 				}
-
-			} else {
-				interval = fastestPoll // Reset to fastest poll on success
+			default:
+				m.logger.Warn("poll failed", "provider", providerName, slog.Any("err", err))
 			}
+			m.setPollInterval(providerName, interval)
 		}
 	}
 }
 
-// PollProvider performs one poll cycle for a specific provider and returns a summary
+// PollProvider performs one poll cycle for a specific provider and returns a summary.
+// The cycle's log lines (and slow queries) carry a poll ID, unless ctx already has one.
 func (m *Manager) PollProvider(ctx context.Context, targetProvider string) error {
+	if logctx.PollID(ctx) == "" {
+		ctx = logctx.WithPollID(ctx, logctx.NewPollID())
+	}
 	deactivatedRequests, err := m.store.DeactivateExpiredRequests(ctx)
 	if err != nil {
-		m.logger.Warn("failed to deactivate expired requests", slog.Any("err", err))
+		m.logger.Warn("failed to deactivate expired requests", logctx.Attr(ctx), slog.Any("err", err))
 		return err
 	}
 
 	if len(deactivatedRequests) > 0 {
-		m.logger.Info("deactivated expired requests", slog.Int("count", len(deactivatedRequests)))
+		m.logger.Info("deactivated expired requests", logctx.Attr(ctx), slog.Int("count", len(deactivatedRequests)))
 
 		// Send notification to each user about their deactivated requests
 		m.notifyUsersOfDeactivatedRequests(ctx, deactivatedRequests)
@@ -135,110 +297,51 @@ func (m *Manager) PollProvider(ctx context.Context, targetProvider string) error
 
 	requests, err := m.store.ListActiveRequests(ctx)
 	if err != nil {
-		m.logger.Error("list requests failed", slog.Any("err", err))
+		m.logger.Error("list requests failed", logctx.Attr(ctx), slog.Any("err", err))
 		return nil
 	}
 
-	// Filter requests for the target provider
-	var filteredRequests []db.SchniffRequest
-	for _, req := range requests {
-		if req.Provider == targetProvider {
-			filteredRequests = append(filteredRequests, req)
-		}
+	// Filter requests for the target provider, dropping anything that expired this cycle
+	filteredRequests := pollableRequests(requests, deactivatedRequests, targetProvider, time.Now())
+
+	// Admin-blocked campgrounds aren't polled; their schniffs resume once unblocked
+	blocked, err := m.store.ListBlockedCampgrounds(ctx)
+	if err != nil {
+		m.logger.Warn("failed to load campground blocklist", logctx.Attr(ctx), slog.Any("err", err))
 	}
+	filteredRequests = withoutBlocked(filteredRequests, blocked)
 
 	if len(filteredRequests) == 0 {
 		return nil
 	}
 
 	// dedupe by provider+campground, then provider decides how to bucket dates
-	datesByPC, _ := collectDatesByPC(filteredRequests)
-	for k, datesSet := range datesByPC {
-		prov, ok := m.reg.Get(k.prov)
-		if !ok {
-			continue
-		}
-		// to sorted slice
-		dates := datesFromSet(datesSet)
-		// provider decides minimal set of requests
-		buckets := prov.PlanBuckets(dates)
-		// collect all states for this provider+campground across buckets to enable bundled notifications
-		var collectedStates []providers.CampsiteAvailability
-		for _, b := range buckets {
-			states, err := prov.FetchAvailability(ctx, k.cg, b.Start, b.End)
-			if err != nil {
-				// return an error straight away at first sign of api failing
-				return fmt.Errorf("failed to fetch availability: %w", err)
-			}
-
-			// record lookup if no error
-			err = m.store.RecordLookup(ctx, db.LookupLog{
-				Provider:      k.prov,
-				CampgroundID:  k.cg,
-				StartDate:     b.Start,
-				EndDate:       b.End,
-				CheckedAt:     time.Now(),
-				Success:       true,
-				CampsiteCount: len(states),
-			})
-			if err != nil {
-				m.logger.Warn("record lookup failed", slog.Any("err", err))
-			}
-
-			if len(states) == 0 {
-				m.logger.Info("no states returned", slog.String("provider", k.prov), slog.String("campground", k.cg), slog.Time("start", b.Start), slog.Time("end", b.End))
-			}
-			// collect for later bundled change detection and notification
-			collectedStates = append(collectedStates, states...)
-		}
-
-		// Process all collected states for this provider+campground at once
-		if len(collectedStates) == 0 {
-			continue
-		}
-
-		// Convert to db format
-		batch := make([]db.CampsiteAvailability, 0, len(collectedStates))
-		now := time.Now()
-		for _, s := range collectedStates {
-			batch = append(batch, db.CampsiteAvailability{
-				Provider:     k.prov,
-				CampgroundID: k.cg,
-				CampsiteID:   s.ID,
-				Date:         s.Date,
-				Available:    s.Available,
-				LastChecked:  now,
-			})
-		}
-
-		// Upsert states
-		start := time.Now()
-		err := m.executeDBOperation(func() error {
-			return m.store.UpsertCampsiteAvailabilityBatch(ctx, batch)
-		})
-		if err != nil {
-			// only http errors need to fail the function.
-			m.logger.Error("upsert states failed", slog.Any("err", err))
-		} else {
-			m.logger.Info("persisted campsite states",
-				slog.String("provider", k.prov),
-				slog.String("campground", k.cg),
-				slog.Int("count", len(batch)),
-				slog.Duration("duration_ms", time.Since(start)),
-			)
-		}
-
+	// only campgrounds whose checkin-based interval has elapsed are fetched this cycle
+	// campgrounds that keep failing are only retried once their cooldown passes
+	datesByPC, reqsByPC := collectDatesByPC(filteredRequests)
+	failures := m.scrapeFailures(ctx, targetProvider)
+	datesByPC = withoutFailingCampgrounds(datesByPC, failures, time.Now())
+	datesByPC = m.dueCampgrounds(targetProvider, datesByPC, reqsByPC, time.Now())
+	if prov, ok := m.reg.Get(targetProvider); ok {
+		datesByPC = withinLookahead(datesByPC, lookaheadEnd(prov, time.Now()))
+	}
+	if len(datesByPC) == 0 {
+		return nil
+	}
+	pollErr := m.pollCampgrounds(ctx, datesByPC, failures)
+	if ctx.Err() != nil {
+		return pollErr
 	}
 
-	// After processing all states, check for notifications
-	if len(filteredRequests) > 0 {
-		err := m.ProcessNotificationsWithBatches(ctx, filteredRequests)
-		if err != nil {
-			m.logger.Warn("process notifications failed", slog.String("provider", targetProvider), slog.Any("err", err))
-		}
+	// After processing all states, check for notifications. Campgrounds that fetched fine are
+	// notified even if others failed this cycle.
+	err = m.ProcessNotificationsWithBatches(ctx, filteredRequests)
+	if err != nil {
+		m.logger.Warn("process notifications failed", logctx.Attr(ctx), slog.String("provider", targetProvider), slog.Any("err", err))
 	}
+	m.notifyPriceDrops(ctx, targetProvider, filteredRequests)
 
-	return nil
+	return pollErr
 }
 
 // normalizeDay returns t truncated to 00:00:00 UTC.
@@ -247,14 +350,39 @@ func normalizeDay(t time.Time) time.Time {
 	return time.Date(tt.Year(), tt.Month(), tt.Day(), 0, 0, 0, 0, time.UTC)
 }
 
-// generateNights returns the UTC days in [checkin, checkout) at day granularity.
-func generateNights(checkin, checkout time.Time) []time.Time {
+// lookaheadEnd returns the first day past prov's booking window, counting from now.
+func lookaheadEnd(prov providers.Provider, now time.Time) time.Time {
+	return normalizeDay(now).AddDate(0, 0, prov.MaxLookaheadDays())
+}
+
+// withinLookahead drops dates on or after end, which the provider hasn't published yet, and any
+// campground left with nothing to fetch.
+func withinLookahead(datesByPC map[pc]map[time.Time]struct{}, end time.Time) map[pc]map[time.Time]struct{} {
+	for k, dates := range datesByPC {
+		for d := range dates {
+			if !d.Before(end) {
+				delete(dates, d)
+			}
+		}
+		if len(dates) == 0 {
+			delete(datesByPC, k)
+		}
+	}
+	return datesByPC
+}
+
+// generateNights returns the UTC days in [checkin, checkout) at day granularity that pass the
+// night filter (see db.NightMatches), with the window widened by flexDays on each side.
+func generateNights(checkin, checkout time.Time, nightFilter string, flexDays int) []time.Time {
 	if !checkin.Before(checkout) {
 		return nil
 	}
 	out := []time.Time{}
-	for d := normalizeDay(checkin); d.Before(normalizeDay(checkout)); d = d.AddDate(0, 0, 1) {
-		out = append(out, d)
+	start, end := normalizeDay(checkin).AddDate(0, 0, -flexDays), normalizeDay(checkout).AddDate(0, 0, flexDays)
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		if db.NightMatches(nightFilter, d) {
+			out = append(out, d)
+		}
 	}
 	return out
 }
@@ -281,19 +409,203 @@ func datesFromSet(set map[time.Time]struct{}) []time.Time {
 
 type pc struct{ prov, cg string }
 
+// pollCampgrounds fetches every campground in datesByPC using up to pollConcurrency workers.
+// A failing campground doesn't stop the others; every failure is returned joined together.
+// failures holds the campgrounds with failed fetches on record, so a success can clear them.
+// Cancelling ctx stops further fetches from being started.
+func (m *Manager) pollCampgrounds(ctx context.Context, datesByPC map[pc]map[time.Time]struct{}, failures map[pc]db.ScrapeFailure) error {
+	workers := m.pollConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	var (
+		wg    sync.WaitGroup
+		errMu sync.Mutex
+		errs  []error
+		sem   = make(chan struct{}, workers)
+	)
+	for k, datesSet := range datesByPC {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(k pc, datesSet map[time.Time]struct{}) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			_, hadFailures := failures[k]
+			if err := m.pollCampground(ctx, k, datesSet, hadFailures); err != nil {
+				m.logger.Warn("poll campground failed",
+					logctx.Attr(ctx),
+					slog.String("provider", k.prov),
+					slog.String("campground", k.cg),
+					slog.Any("err", err))
+				m.retryNextCycle(k)
+				errMu.Lock()
+				errs = append(errs, fmt.Errorf("%s/%s: %w", k.prov, k.cg, err))
+				errMu.Unlock()
+			}
+		}(k, datesSet)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// pollCampground fetches, records and persists availability for one provider+campground.
+// DB writes go through executeDBOperation so concurrent workers don't contend for the write lock.
+func (m *Manager) pollCampground(ctx context.Context, k pc, datesSet map[time.Time]struct{}, hadFailures bool) error {
+	prov, ok := m.reg.Get(k.prov)
+	if !ok {
+		return nil
+	}
+	// to sorted slice
+	dates := datesFromSet(datesSet)
+	// provider decides minimal set of requests
+	buckets := prov.PlanBuckets(dates)
+	// collect all states for this provider+campground across buckets to enable bundled notifications
+	var collectedStates []providers.CampsiteAvailability
+	for _, b := range buckets {
+		states, err := prov.FetchAvailability(ctx, k.cg, b.Start, b.End)
+		if err != nil {
+			// return an error straight away at first sign of api failing
+			m.recordScrapeResult(ctx, k, err, hadFailures)
+			return fmt.Errorf("failed to fetch availability: %w", err)
+		}
+
+		// record lookup if no error
+		err = m.executeDBOperation(func() error {
+			return m.store.RecordLookup(ctx, db.LookupLog{
+				Provider:      k.prov,
+				CampgroundID:  k.cg,
+				StartDate:     b.Start,
+				EndDate:       b.End,
+				CheckedAt:     time.Now(),
+				Success:       true,
+				CampsiteCount: len(states),
+			})
+		})
+		if err != nil {
+			m.logger.Warn("record lookup failed", logctx.Attr(ctx), slog.Any("err", err))
+		}
+
+		if len(states) == 0 {
+			m.logger.Info("no states returned", logctx.Attr(ctx), slog.String("provider", k.prov), slog.String("campground", k.cg), slog.Time("start", b.Start), slog.Time("end", b.End))
+		}
+		// collect for later bundled change detection and notification
+		collectedStates = append(collectedStates, states...)
+	}
+	m.recordScrapeResult(ctx, k, nil, hadFailures)
+
+	// Process all collected states for this provider+campground at once
+	if len(collectedStates) == 0 {
+		return nil
+	}
+
+	// Convert to db format
+	batch := make([]db.CampsiteAvailability, 0, len(collectedStates))
+	now := time.Now()
+	for _, s := range collectedStates {
+		batch = append(batch, db.CampsiteAvailability{
+			Provider:     k.prov,
+			CampgroundID: k.cg,
+			CampsiteID:   s.ID,
+			Date:         s.Date,
+			Available:    s.Available,
+			LastChecked:  now,
+			CostPerNight: s.CostPerNight,
+		})
+	}
+
+	// Upsert states
+	start := time.Now()
+	err := m.executeDBOperation(func() error {
+		return m.store.UpsertCampsiteAvailabilityBatch(ctx, batch)
+	})
+	if err != nil {
+		// only http errors need to fail the function.
+		m.logger.Error("upsert states failed", logctx.Attr(ctx), slog.Any("err", err))
+	} else {
+		m.logger.Info("persisted campsite states",
+			logctx.Attr(ctx),
+			slog.String("provider", k.prov),
+			slog.String("campground", k.cg),
+			slog.Int("count", len(batch)),
+			slog.Duration("duration_ms", time.Since(start)),
+		)
+	}
+
+	return nil
+}
+
+// pollableRequests returns the provider's requests that still need availability fetched.
+// The active list is read after DeactivateExpiredRequests, but a request can expire between the
+// two queries (or still be in the list if deactivation raced), so anything just deactivated or
+// with a checkin before today is dropped here. Otherwise a campground whose only request just
+// expired would still be fetched this cycle.
+func pollableRequests(requests, deactivated []db.SchniffRequest, provider string, now time.Time) []db.SchniffRequest {
+	gone := make(map[int64]struct{}, len(deactivated))
+	for _, r := range deactivated {
+		gone[r.ID] = struct{}{}
+	}
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var out []db.SchniffRequest
+	for _, r := range requests {
+		if r.Provider != provider {
+			continue
+		}
+		if _, ok := gone[r.ID]; ok {
+			continue
+		}
+		if r.Checkin.Before(today) || r.Checkout.Before(today) {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// withoutBlocked drops requests for campgrounds on the blocklist.
+func withoutBlocked(requests []db.SchniffRequest, blocked []db.BlockedCampground) []db.SchniffRequest {
+	if len(blocked) == 0 {
+		return requests
+	}
+	skip := make(map[pc]struct{}, len(blocked))
+	for _, b := range blocked {
+		skip[pc{b.Provider, b.CampgroundID}] = struct{}{}
+	}
+	var out []db.SchniffRequest
+	for _, r := range requests {
+		if _, ok := skip[pc{r.Provider, r.CampgroundID}]; ok {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
 // collectDatesByPC groups requests by provider+campground and accumulates unique UTC days.
 func collectDatesByPC(reqs []db.SchniffRequest) (map[pc]map[time.Time]struct{}, map[pc][]db.SchniffRequest) {
 	datesBy := map[pc]map[time.Time]struct{}{}
 	reqsBy := map[pc][]db.SchniffRequest{}
 	for _, r := range reqs {
-		if !r.Checkin.Before(r.Checkout) {
+		nights := generateNights(r.Checkin, r.Checkout, r.NightFilter, r.FlexDays)
+		if len(nights) == 0 {
 			continue
 		}
 		key := pc{prov: r.Provider, cg: r.CampgroundID}
 		if _, ok := datesBy[key]; !ok {
 			datesBy[key] = map[time.Time]struct{}{}
 		}
-		for _, d := range generateNights(r.Checkin, r.Checkout) {
+		for _, d := range nights {
 			datesBy[key][d] = struct{}{}
 		}
 		reqsBy[key] = append(reqsBy[key], r)
@@ -322,7 +634,9 @@ func (m *Manager) RunDailySummary(ctx context.Context) {
 		embed := db.MakeSummaryEmbed(summary)
 
 		m.logger.Info("daily summary generated", slog.Any("summary", summary))
-		m.notifier.ChannelMessageSendEmbed(m.GetSummaryChannel(), embed)
+		if err := m.sendSummaryEmbed(embed); err != nil {
+			m.logger.Warn("failed to send daily summary", slog.Any("err", err))
+		}
 	})
 	cron.Start()
 }
@@ -336,6 +650,23 @@ func (m *Manager) CampsiteURL(provider, campgroundID, campsiteID string) string
 	return p.CampsiteURL(campgroundID, campsiteID)
 }
 
+// ProviderNames returns the registered provider names, sorted.
+func (m *Manager) ProviderNames() []string {
+	names := m.reg.GetProviderNames()
+	sort.Strings(names)
+	return names
+}
+
+// DescribeProvider returns a provider's display label and website. ok is false for unknown providers.
+func (m *Manager) DescribeProvider(name string) (label, baseURL string, ok bool) {
+	p, ok := m.reg.Get(name)
+	if !ok {
+		return "", "", false
+	}
+	label, baseURL = providers.Describe(name, p)
+	return label, baseURL, true
+}
+
 // CampgroundURL exposes provider-specific campground URLs for the bot to build embeds.
 func (m *Manager) CampgroundURL(provider, campgroundID string) string {
 	if m.reg == nil {
@@ -380,7 +711,19 @@ func (m *Manager) processAdhocScrapes(ctx context.Context) {
 	m.logger.Info("processing adhoc scrape requests", slog.Int("count", len(pending)))
 
 	for _, req := range pending {
+		if ctx.Err() != nil {
+			// Shutting down; the rest stay pending for the next run
+			return
+		}
 		err := m.processAdhocScrapeRequest(ctx, req)
+		if err != nil && ctx.Err() != nil {
+			// Interrupted rather than failed, so leave it pending to be picked up after a restart
+			m.logger.Info("adhoc scrape interrupted; left pending",
+				slog.Int("request_id", req.ID),
+				slog.String("provider", req.Provider),
+				slog.String("campground_id", req.CampgroundID))
+			return
+		}
 		if err != nil {
 			m.logger.Error("failed to process adhoc scrape request",
 				slog.Int("request_id", req.ID),
@@ -408,15 +751,19 @@ func (m *Manager) processAdhocScrapeRequest(ctx context.Context, req *db.AdhocSc
 		return fmt.Errorf("provider %s not found", req.Provider)
 	}
 
-	// Calculate date range (next 60 days from now)
+	// Scrape everything the provider publishes from now on
 	startDate := time.Now()
-	endDate := startDate.AddDate(0, 0, 60)
+	endDate := lookaheadEnd(provider, startDate)
 
 	// Execute the scrape using FetchAvailability
 	results, err := provider.FetchAvailability(ctx, req.CampgroundID, startDate, endDate)
 	if err != nil {
 		return fmt.Errorf("failed to scrape availability: %w", err)
 	}
+	// Don't write results once shutdown has started; the request is retried after a restart
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("adhoc scrape interrupted: %w", err)
+	}
 
 	// Convert provider results to database format
 	var availabilityStates []db.CampsiteAvailability
@@ -429,6 +776,7 @@ func (m *Manager) processAdhocScrapeRequest(ctx context.Context, req *db.AdhocSc
 			Date:         result.Date,
 			Available:    result.Available,
 			LastChecked:  now,
+			CostPerNight: result.CostPerNight,
 		})
 	}
 
@@ -477,6 +825,7 @@ func (m *Manager) notifyUsersOfDeactivatedRequests(ctx context.Context, deactiva
 		channel, err := m.notifier.UserChannelCreate(userID)
 		if err != nil {
 			m.logger.Warn("failed to create DM channel for user",
+				logctx.Attr(ctx),
 				slog.String("user_id", userID),
 				slog.Any("err", err))
 			continue
@@ -489,10 +838,12 @@ func (m *Manager) notifyUsersOfDeactivatedRequests(ctx context.Context, deactiva
 		_, err = m.notifier.ChannelMessageSendEmbed(channel.ID, embed)
 		if err != nil {
 			m.logger.Warn("failed to send deactivation notification",
+				logctx.Attr(ctx),
 				slog.String("user_id", userID),
 				slog.Any("err", err))
 		} else {
 			m.logger.Info("sent deactivation notification",
+				logctx.Attr(ctx),
 				slog.String("user_id", userID),
 				slog.Int("requests_count", len(requests)))
 		}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/logctx"
 	"github.com/brensch/schniffer/internal/nonsense"
 	"github.com/brensch/schniffer/internal/providers"
 	"github.com/bwmarrin/discordgo"
@@ -20,59 +22,126 @@ import (
 // ProcessNotificationsWithBatches handles the state-change-based notification system.
 // DB access, logging, and notifier usage live here (methods on Manager).
 func (m *Manager) ProcessNotificationsWithBatches(ctx context.Context, requests []db.SchniffRequest) error {
-	m.logger.Info("processing notifications", slog.Int("request_count", len(requests)))
+	m.logger.Info("processing notifications", logctx.Attr(ctx), slog.Int("request_count", len(requests)))
 
 	// Get unnotified state changes for all requests
 	stateChanges, err := m.store.GetUnnotifiedStateChanges(ctx, requests)
 	if err != nil {
-		m.logger.Warn("get unnotified state changes failed", slog.Any("err", err))
+		m.logger.Warn("get unnotified state changes failed", logctx.Attr(ctx), slog.Any("err", err))
 		return err
 	}
-	m.logger.Info("found unnotified state changes", slog.Int("count", len(stateChanges)))
+	// New requests also hear about sites that were already open without a state change on record
+	firstAvailable, err := m.store.GetFirstAvailable(ctx, requests)
+	if err != nil {
+		m.logger.Warn("get first available failed", logctx.Attr(ctx), slog.Any("err", err))
+	}
+	stateChanges = append(stateChanges, firstAvailable...)
+	m.logger.Info("found unnotified state changes", logctx.Attr(ctx), slog.Int("count", len(stateChanges)))
 	if len(stateChanges) == 0 {
 		return nil
 	}
 
 	// Group changes per request (pure helper)
 	changesByRequest := groupStateChangesByRequest(stateChanges)
-	m.logger.Info("grouped state changes by request", slog.Int("requests", len(changesByRequest)))
+	m.logger.Info("grouped state changes by request", logctx.Attr(ctx), slog.Int("requests", len(changesByRequest)))
 
 	// Batch ID for recording notifications
 	batchID := uuid.New().String()
 	var notificationsToRecord []db.Notification
 	now := time.Now()
 
-	// Process each request independently
+	// Process each request independently, in ID order so overlapping requests dedupe predictably
 	reqIndex := indexRequestsByID(requests)
-	for requestID, changes := range changesByRequest {
+	requestIDs := sortedRequestIDs(changesByRequest)
+	deliveredOpenings := make(map[string]map[string]bool) // openings DMed to each user this round
+	digestUsers := make(map[string]bool)
+	for _, requestID := range requestIDs {
+		changes := changesByRequest[requestID]
 		req, ok := reqIndex[requestID]
 		if !ok {
-			m.logger.Warn("request not found for state changes", slog.Int64("requestID", requestID))
+			m.logger.Warn("request not found for state changes", logctx.Attr(ctx), slog.Int64("requestID", requestID))
 			continue
 		}
 
 		m.logger.Info("processing request",
+			logctx.Attr(ctx),
 			slog.Int64("requestID", requestID),
 			slog.String("provider", req.Provider),
 			slog.String("campgroundID", req.CampgroundID),
 			slog.Int("changes", len(changes)),
 		)
 
-		err := m.sendStateChangeNotification(ctx, req)
-		if err != nil {
-			m.logger.Warn("send state change notification failed",
+		dmSent := false
+		if allOpeningsDelivered(changes, deliveredOpenings[req.UserID]) {
+			// Another of the user's requests already DMed every opening; its message is enough
+			m.logger.Info("openings already notified via an overlapping request; not notifying",
+				logctx.Attr(ctx),
+				slog.Int64("requestID", requestID),
+				slog.String("userID", req.UserID))
+		} else if m.withinRenotifyCooldown(ctx, req, changes) {
+			// Only flapping sites reopened; record them below without telling the user again
+			m.logger.Info("reopenings within renotify cooldown; not notifying",
+				logctx.Attr(ctx),
+				slog.Int64("requestID", requestID),
+				slog.String("userID", req.UserID))
+		} else if m.wantsDigest(ctx, req.UserID, digestUsers) {
+			// Openings wait for the user's next digest instead of a DM per request; webhooks aren't batched
+			stats, campground, skipped := m.requestCampsiteStats(ctx, req)
+			if !skipped {
+				markChanges(stats, changes)
+				provider, _ := m.reg.Get(req.Provider)
+				m.webhookNotification(ctx, req, campground, m.CampgroundURL(req.Provider, req.CampgroundID), stats, provider)
+			}
+			if err := m.store.AddDigestItems(ctx, req.UserID, digestChanges(changes, stats)); err != nil {
+				m.logger.Warn("queue digest items failed",
+					logctx.Attr(ctx),
+					slog.String("userID", req.UserID),
+					slog.Any("err", err))
+			}
+		} else if throttled, notice, limit := m.notificationThrottle(ctx, req); throttled {
+			// The request has notified too often today; record its changes below without a DM
+			m.logger.Info("request hit its daily notification cap; not notifying",
+				logctx.Attr(ctx),
+				slog.Int64("requestID", requestID),
 				slog.String("userID", req.UserID),
-				slog.Any("err", err))
+				slog.Int("limit", limit))
+			if notice {
+				if err := m.sendThrottledNotice(ctx, req, limit); err != nil {
+					m.logger.Warn("send throttled notice failed",
+						logctx.Attr(ctx),
+						slog.String("userID", req.UserID),
+						slog.Any("err", err))
+				}
+			}
+		} else {
+			stats, sent, err := m.sendStateChangeNotification(ctx, req, changes)
+			dmSent = sent
+			if err != nil {
+				m.logger.Warn("send state change notification failed",
+					logctx.Attr(ctx),
+					slog.String("userID", req.UserID),
+					slog.Any("err", err))
+			}
+			if sent {
+				if deliveredOpenings[req.UserID] == nil {
+					deliveredOpenings[req.UserID] = make(map[string]bool)
+				}
+				addDeliveredOpenings(deliveredOpenings[req.UserID], req.Provider, req.CampgroundID, stats)
+				m.broadcastFound(ctx, req.UserID)
+			}
 		}
 
-		m.notifier.ChannelMessageSend(m.summaryChannelID, nonsense.RandomSillyBroadcast(req.UserID))
-
 		// Record outgoing notifications for each change
 		for _, c := range changes {
 			state := "available"
 			if !c.NewAvailable {
 				state = "unavailable"
 			}
+			// First-available openings have no state change behind them
+			var stateChangeID *int64
+			if c.ID != 0 {
+				stateChangeID = &c.ID
+			}
 			notificationsToRecord = append(notificationsToRecord, db.Notification{
 				RequestID:     req.ID,
 				UserID:        req.UserID,
@@ -81,8 +150,9 @@ func (m *Manager) ProcessNotificationsWithBatches(ctx context.Context, requests
 				CampsiteID:    c.CampsiteID,
 				Date:          c.Date,
 				State:         state,
-				StateChangeID: &c.ID,
+				StateChangeID: stateChangeID,
 				SentAt:        now,
+				Delivered:     dmSent,
 			})
 		}
 	}
@@ -90,9 +160,10 @@ func (m *Manager) ProcessNotificationsWithBatches(ctx context.Context, requests
 	// Record all notifications (single DB call)
 	if len(notificationsToRecord) > 0 {
 		if err := m.store.InsertNotificationsBatch(ctx, notificationsToRecord, batchID); err != nil {
-			m.logger.Warn("record notification batch failed", slog.Any("err", err))
+			m.logger.Warn("record notification batch failed", logctx.Attr(ctx), slog.Any("err", err))
 		} else {
 			m.logger.Info("recorded state change notification batch",
+				logctx.Attr(ctx),
 				slog.String("batchID", batchID),
 				slog.Int("count", len(notificationsToRecord)))
 		}
@@ -101,57 +172,219 @@ func (m *Manager) ProcessNotificationsWithBatches(ctx context.Context, requests
 	return nil
 }
 
-// sendStateChangeNotification fetches context data, builds the embed(s) via pure helpers, and sends them.
-func (m *Manager) sendStateChangeNotification(
-	ctx context.Context,
-	req db.SchniffRequest,
-) error {
-	// Create DM channel
+// broadcastFound tells the summary channel that the user was just sent openings.
+func (m *Manager) broadcastFound(ctx context.Context, userID string) {
+	broadcast := nonsense.RandomSillyBroadcast(userID)
+	retryDiscord(ctx, func() error {
+		_, err := m.notifier.ChannelMessageSend(m.GetSummaryChannel(), broadcast)
+		return err
+	})
+}
+
+// wantsDigest reports whether the user opted into digests, caching the answer in seen for the batch.
+func (m *Manager) wantsDigest(ctx context.Context, userID string, seen map[string]bool) bool {
+	if digest, ok := seen[userID]; ok {
+		return digest
+	}
+	prefs, err := m.store.GetNotificationPrefs(ctx, userID)
+	if err != nil {
+		m.logger.Warn("get notification prefs failed; sending directly", logctx.Attr(ctx), slog.String("userID", userID), slog.Any("err", err))
+	}
+	seen[userID] = err == nil && prefs.Digest
+	return seen[userID]
+}
+
+// notificationThrottle reports whether the request has DMed the user up to their daily notification
+// cap, and whether to tell them it's been muted, which happens at most once a day per request.
+func (m *Manager) notificationThrottle(ctx context.Context, req db.SchniffRequest) (throttled, notice bool, limit int) {
+	prefs, err := m.store.GetNotificationPrefs(ctx, req.UserID)
+	if err != nil {
+		return false, false, 0
+	}
+	sent, err := m.store.CountNotificationsLast24hByRequest(ctx, req.ID)
+	if err != nil {
+		m.logger.Warn("count recent notifications failed", logctx.Attr(ctx), slog.Int64("requestID", req.ID), slog.Any("err", err))
+		return false, false, 0
+	}
+	limit = prefs.DailyLimit()
+	if sent < int64(limit) {
+		return false, false, limit
+	}
+	notice, err = m.store.ClaimThrottleNotice(ctx, req.ID, time.Now())
+	if err != nil {
+		m.logger.Warn("claim throttled notice failed", logctx.Attr(ctx), slog.Int64("requestID", req.ID), slog.Any("err", err))
+	}
+	return true, notice, limit
+}
+
+// sendThrottledNotice tells the user a request has stopped notifying for the day.
+func (m *Manager) sendThrottledNotice(ctx context.Context, req db.SchniffRequest, limit int) error {
+	name := req.CampgroundID
+	if campground, ok, _ := m.store.GetCampgroundByID(ctx, req.Provider, req.CampgroundID); ok && campground.Name != "" {
+		name = campground.Name
+	}
 	channel, err := m.notifier.UserChannelCreate(req.UserID)
 	if err != nil {
 		return err
 	}
+	_, err = m.notifier.ChannelMessageSend(channel.ID, fmt.Sprintf(
+		"🔇 Your schniff for %s (%s to %s) has notified you %d times in the last 24 hours, so it's muted until that drops. Change the limit with /schniff prefs max_per_day.",
+		name, req.Checkin.Format("Jan 2"), req.Checkout.Format("Jan 2"), limit))
+	return err
+}
 
-	// Currently available items for the user's window
-	allAvailable, err := m.store.GetCurrentlyAvailableCampsites(ctx, req.Provider, req.CampgroundID, req.Checkin, req.Checkout)
+// withinRenotifyCooldown reports whether every opening in changes reopens a campsite/night that was
+// closed less than the user's renotify_after ago.
+func (m *Manager) withinRenotifyCooldown(ctx context.Context, req db.SchniffRequest, changes []db.StateChangeForRequest) bool {
+	prefs, err := m.store.GetNotificationPrefs(ctx, req.UserID)
+	if err != nil || prefs.RenotifyAfter <= 0 {
+		return false
+	}
+	closed, err := m.store.RecentlyClosedCampsites(ctx, req.UserID, req.Provider, req.CampgroundID, time.Now().Add(-prefs.RenotifyAfter))
 	if err != nil {
-		m.logger.Warn("get currently available campsites failed", slog.Any("err", err))
-		// We can still continue with only the change lists, but the experience is better with context.
+		m.logger.Warn("get recently closed campsites failed", logctx.Attr(ctx), slog.String("userID", req.UserID), slog.Any("err", err))
+		return false
 	}
+	return onlyFlappingReopens(changes, closed)
+}
 
-	// Group by campsite; collect IDs to enrich details
-	byCampsite := groupAvailabilityByCampsite(allAvailable)
-	campsiteIDs := collectMapKeys(byCampsite)
-
-	// Try to fetch enhanced details in batch; if it fails, fall back to empty map
-	detailsMap, derr := m.store.GetCampsiteDetailsBatch(ctx, req.Provider, req.CampgroundID, campsiteIDs)
-	if derr != nil {
-		m.logger.Warn("GetCampsiteDetailsBatch failed; using basic details", slog.Any("err", derr))
-		detailsMap = map[string]db.CampsiteDetails{} // empty — pure helpers will handle defaults
+// onlyFlappingReopens reports whether changes contain at least one opening and every opening is in
+// recentlyClosed (keyed by db.CampsiteNightKey).
+func onlyFlappingReopens(changes []db.StateChangeForRequest, recentlyClosed map[string]bool) bool {
+	openings := 0
+	for _, c := range changes {
+		if !c.NewAvailable {
+			continue
+		}
+		openings++
+		if !recentlyClosed[db.CampsiteNightKey(c.CampsiteID, c.Date)] {
+			return false
+		}
 	}
+	return openings > 0
+}
 
-	// Build stats (pure).
-	stats := buildCampsiteStats(byCampsite, req.Checkin, req.Checkout, detailsMap)
+// sendStateChangeNotification fetches context data, builds the embed(s) via pure helpers, and sends them.
+// During the user's quiet hours the notification is queued instead and sent by FlushPendingNotifications.
+// changes are the request's state changes this round, used for the embed's change markers. stats and
+// sent are as for deliverNotification; sent is false when the notification was queued.
+func (m *Manager) sendStateChangeNotification(
+	ctx context.Context,
+	req db.SchniffRequest,
+	changes []db.StateChangeForRequest,
+) (stats []CampsiteStats, sent bool, err error) {
+	prefs, err := m.store.GetNotificationPrefs(ctx, req.UserID)
+	if err != nil {
+		m.logger.Warn("get notification prefs failed; sending anyway", logctx.Attr(ctx), slog.String("userID", req.UserID), slog.Any("err", err))
+	} else if prefs.InQuietHours(time.Now()) {
+		m.logger.Info("quiet hours; queuing notification", logctx.Attr(ctx), slog.String("userID", req.UserID), slog.Int64("requestID", req.ID))
+		return nil, false, m.store.QueuePendingNotification(ctx, req.UserID, req.ID)
+	}
+	stats, sent, err = m.deliverNotification(ctx, req, changes, false, false)
+	if err != nil {
+		return nil, false, m.handleFailedNotification(ctx, req, err)
+	}
+	return stats, sent, nil
+}
 
-	// Get campground presentation info
-	campground, _, err := m.store.GetCampgroundByID(ctx, req.Provider, req.CampgroundID)
+// deliverNotification builds and DMs the request's notification, returning the campsites it covered.
+// sent is false when no campsite matched the request's filters, or none is available and
+// requireAvailable is set. changes may be nil when the notification isn't for a particular round of
+// state changes. dmOnly skips the webhook and email, for retrying a DM that failed after they went out.
+func (m *Manager) deliverNotification(ctx context.Context, req db.SchniffRequest, changes []db.StateChangeForRequest, requireAvailable, dmOnly bool) (stats []CampsiteStats, sent bool, err error) {
+	stats, campground, skipped := m.requestCampsiteStats(ctx, req)
+	markChanges(stats, changes)
+	if requireAvailable && len(stats) == 0 {
+		return nil, false, nil
+	}
+	if skipped {
+		m.logger.Info("no campsites match the request's filters; skipping notification",
+			logctx.Attr(ctx),
+			slog.Int64("requestID", req.ID),
+			slog.Float64("minRating", req.MinRating),
+			slog.Bool("includeDayUse", req.IncludeDayUse))
+		return nil, false, nil
+	}
 	campgroundURL := m.CampgroundURL(req.Provider, req.CampgroundID)
 
 	// missing the provider is irrelevant, checked in
 	provider, _ := m.reg.Get(req.Provider)
 
-	// Build a single embed showing only the top 3 campsites with up to 20 dates each.
-	embeds := BuildNotificationEmbeds(
+	// The webhook gets every matching campsite, not just the embed's top 3, and doesn't depend on Discord
+	if !dmOnly {
+		m.webhookNotification(ctx, req, campground, campgroundURL, stats, provider)
+	}
+
+	// Build a single embed showing the top campsites, by default 3 with up to 20 dates each.
+	embeds := BuildNotificationEmbedsWithOptions(
+		m.notificationTemplate(),
+		m.embedOptions,
 		req.Checkin, req.Checkout, req.UserID,
 		campground.Name, campgroundURL, campground.ID,
 		stats,
 		provider,
 	)
 
-	for _, e := range embeds {
-		_, err = m.notifier.ChannelMessageSendEmbed(channel.ID, e)
+	err = m.sendDM(ctx, req.UserID, embeds)
+	if !dmOnly {
+		m.emailNotification(ctx, req.UserID, embeds)
 	}
-	return err
+	if err != nil {
+		return nil, false, err
+	}
+	return stats, true, nil
+}
+
+// requestCampsiteStats gathers the campsites currently available in the request's window with their
+// details, filtered by the request's preferences. skipped is true when campsites were available but
+// none of them passed the filters.
+func (m *Manager) requestCampsiteStats(ctx context.Context, req db.SchniffRequest) (stats []CampsiteStats, campground db.Campground, skipped bool) {
+	// Currently available items for the user's window, including any flex days
+	start, end := req.Window()
+	allAvailable, err := m.store.GetCurrentlyAvailableCampsites(ctx, req.Provider, req.CampgroundID, start, end)
+	if err != nil {
+		m.logger.Warn("get currently available campsites failed", logctx.Attr(ctx), slog.Any("err", err))
+		// We can still continue with only the change lists, but the experience is better with context.
+	}
+
+	// Group by campsite, keeping only the nights the request cares about; collect IDs to enrich details
+	byCampsite := groupAvailabilityByCampsite(filterNights(allAvailable, req.NightFilter))
+	campsiteIDs := collectMapKeys(byCampsite)
+
+	// Try to fetch enhanced details in batch; if it fails, fall back to empty map
+	detailsMap, derr := m.store.GetCampsiteDetailsBatch(ctx, req.Provider, req.CampgroundID, campsiteIDs)
+	if derr != nil {
+		m.logger.Warn("GetCampsiteDetailsBatch failed; using basic details", logctx.Attr(ctx), slog.Any("err", derr))
+		detailsMap = map[string]db.CampsiteDetails{} // empty — pure helpers will handle defaults
+	}
+
+	// Get campground presentation info
+	campground, _, _ = m.store.GetCampgroundByID(ctx, req.Provider, req.CampgroundID)
+
+	// Build stats (pure), then apply the request's filters
+	stats = buildCampsiteStats(byCampsite, start, end, detailsMap)
+	if req.NightFilter != "" && req.NightFilter != db.NightsAny {
+		totalNights := len(generateNights(req.Checkin, req.Checkout, req.NightFilter, req.FlexDays))
+		for i := range stats {
+			stats[i].TotalDays = totalNights
+		}
+	}
+	applyNightlyCosts(stats, allAvailable)
+	stats = filterStatsByRating(stats, campground.Rating, req.MinRating, req.RequireRating)
+	if !req.IncludeDayUse {
+		stats = filterDayUse(stats)
+	}
+	if req.SpecialtyOnly {
+		stats = filterSpecialty(stats)
+	}
+	if req.ReservableOnly {
+		stats = filterReservable(stats)
+	}
+	stats = filterSiteKind(stats, req.Equipment, req.CampsiteType)
+	if req.MinNights > 1 {
+		stats = filterMinNights(stats, req.MinNights)
+	}
+	return stats, campground, len(stats) == 0 && len(allAvailable) > 0
 }
 
 // ------- Data structures used by pure functions -------
@@ -163,10 +396,62 @@ type CampsiteStats struct {
 	TotalDays     int
 	Dates         []time.Time
 	Details       db.CampsiteDetails // Optional/enhanced details from DB
+	CostPerNight  float64            // Highest nightly price across the available dates, 0 if unknown
+	Runs          []DateRun          // Qualifying stays when the request has a minimum length; shown instead of Dates
+	NewDates      map[time.Time]bool // Dates that opened in this round of changes, marked "(new)"
+	MissedDates   []time.Time        // Nights booked again in this round of changes, marked "(missed it!)"
+}
+
+// DateRun is a stretch of consecutive available nights. End is the last night, so the stay checks
+// out the day after.
+type DateRun struct {
+	Start, End time.Time
+}
+
+// Nights returns how many nights the run covers.
+func (r DateRun) Nights() int {
+	return int(r.End.Sub(r.Start).Hours()/24) + 1
 }
 
 // ------- Pure helpers (easy to unit test) -------
 
+// outsideWindow reports whether the night falls outside [checkin, checkout), i.e. on one of the
+// request's flex days.
+func outsideWindow(night, checkin, checkout time.Time) bool {
+	day := normalizeDay(night)
+	return day.Before(normalizeDay(checkin)) || !day.Before(normalizeDay(checkout))
+}
+
+// markChanges records which of each campsite's dates just opened, and which of its nights were just
+// booked, from the round's state changes.
+func markChanges(stats []CampsiteStats, changes []db.StateChangeForRequest) {
+	if len(changes) == 0 {
+		return
+	}
+	index := make(map[string]int, len(stats))
+	for i, s := range stats {
+		index[s.CampsiteID] = i
+	}
+	for _, c := range changes {
+		i, ok := index[c.CampsiteID]
+		if !ok {
+			continue
+		}
+		day := normalizeDay(c.Date)
+		if !c.NewAvailable {
+			stats[i].MissedDates = append(stats[i].MissedDates, day)
+			continue
+		}
+		if stats[i].NewDates == nil {
+			stats[i].NewDates = make(map[time.Time]bool)
+		}
+		stats[i].NewDates[day] = true
+	}
+	for i := range stats {
+		sort.Slice(stats[i].MissedDates, func(a, b int) bool { return stats[i].MissedDates[a].Before(stats[i].MissedDates[b]) })
+	}
+}
+
 // groupStateChangesByRequest groups state changes by RequestID.
 func groupStateChangesByRequest(changes []db.StateChangeForRequest) map[int64][]db.StateChangeForRequest {
 	out := make(map[int64][]db.StateChangeForRequest, len(changes))
@@ -176,6 +461,48 @@ func groupStateChangesByRequest(changes []db.StateChangeForRequest) map[int64][]
 	return out
 }
 
+// sortedRequestIDs returns the request IDs in changesByRequest in ascending order.
+func sortedRequestIDs(changesByRequest map[int64][]db.StateChangeForRequest) []int64 {
+	requestIDs := make([]int64, 0, len(changesByRequest))
+	for requestID := range changesByRequest {
+		requestIDs = append(requestIDs, requestID)
+	}
+	slices.Sort(requestIDs)
+	return requestIDs
+}
+
+// openingKey identifies a campsite/night opening across a user's requests.
+func openingKey(provider, campgroundID, campsiteID string, date time.Time) string {
+	return provider + "|" + campgroundID + "|" + db.CampsiteNightKey(campsiteID, date)
+}
+
+// allOpeningsDelivered reports whether changes contain at least one opening and every opening was
+// already DMed to the user this round (keyed by openingKey), so overlapping requests don't DM the
+// same opening twice. Their notifications are still recorded per request.
+func allOpeningsDelivered(changes []db.StateChangeForRequest, delivered map[string]bool) bool {
+	openings := 0
+	for _, c := range changes {
+		if !c.NewAvailable {
+			continue
+		}
+		openings++
+		if !delivered[openingKey(c.Provider, c.CampgroundID, c.CampsiteID, c.Date)] {
+			return false
+		}
+	}
+	return openings > 0
+}
+
+// addDeliveredOpenings adds the openings a sent notification showed, i.e. those that survived the
+// request's filters, to delivered.
+func addDeliveredOpenings(delivered map[string]bool, provider, campgroundID string, stats []CampsiteStats) {
+	for _, st := range stats {
+		for day := range st.NewDates {
+			delivered[openingKey(provider, campgroundID, st.CampsiteID, day)] = true
+		}
+	}
+}
+
 // indexRequestsByID makes a quick lookup map for SchniffRequest by ID.
 func indexRequestsByID(requests []db.SchniffRequest) map[int64]db.SchniffRequest {
 	idx := make(map[int64]db.SchniffRequest, len(requests))
@@ -201,6 +528,20 @@ func separateChanges(changes []db.StateChangeForRequest) (newlyAvailable []db.Av
 	return
 }
 
+// filterNights drops availability on nights the request's night filter excludes.
+func filterNights(items []db.AvailabilityItem, nightFilter string) []db.AvailabilityItem {
+	if nightFilter == "" || nightFilter == db.NightsAny {
+		return items
+	}
+	out := make([]db.AvailabilityItem, 0, len(items))
+	for _, it := range items {
+		if db.NightMatches(nightFilter, it.Date) {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
 // groupAvailabilityByCampsite groups raw availability items by campsite ID.
 func groupAvailabilityByCampsite(items []db.AvailabilityItem) map[string][]time.Time {
 	by := make(map[string][]time.Time)
@@ -248,6 +589,150 @@ func buildCampsiteStats(
 	return stats
 }
 
+// applyNightlyCosts sets each campsite's CostPerNight to the highest price recorded for its
+// available nights, falling back to the campsite's listed cost.
+func applyNightlyCosts(stats []CampsiteStats, items []db.AvailabilityItem) {
+	highest := make(map[string]float64)
+	for _, it := range items {
+		if it.CostPerNight > highest[it.CampsiteID] {
+			highest[it.CampsiteID] = it.CostPerNight
+		}
+	}
+	for i := range stats {
+		stats[i].CostPerNight = highest[stats[i].CampsiteID]
+		if stats[i].CostPerNight <= 0 {
+			stats[i].CostPerNight = stats[i].Details.CostPerNight
+		}
+	}
+}
+
+// ConsecutiveRuns splits sorted, de-duplicated UTC days into runs of consecutive days and returns
+// those at least minNights long.
+func ConsecutiveRuns(dates []time.Time, minNights int) []DateRun {
+	var out []DateRun
+	for i := 0; i < len(dates); {
+		j := i
+		for j+1 < len(dates) && normalizeDay(dates[j+1]).Equal(normalizeDay(dates[j]).AddDate(0, 0, 1)) {
+			j++
+		}
+		if run := (DateRun{Start: normalizeDay(dates[i]), End: normalizeDay(dates[j])}); run.Nights() >= minNights {
+			out = append(out, run)
+		}
+		i = j + 1
+	}
+	return out
+}
+
+// filterMinNights keeps campsites with at least one run of minNights consecutive available nights,
+// recording the qualifying runs for the embed.
+func filterMinNights(stats []CampsiteStats, minNights int) []CampsiteStats {
+	out := make([]CampsiteStats, 0, len(stats))
+	for _, st := range stats {
+		if runs := ConsecutiveRuns(st.Dates, minNights); len(runs) > 0 {
+			st.Runs = runs
+			out = append(out, st)
+		}
+	}
+	return out
+}
+
+// filterStatsByRating keeps campsites rated at least minRating, using the campsite's own rating
+// and falling back to the campground's. Unrated sites are kept unless requireRating is set.
+// A zero minRating with requireRating unset keeps everything.
+func filterStatsByRating(stats []CampsiteStats, campgroundRating, minRating float64, requireRating bool) []CampsiteStats {
+	if minRating <= 0 && !requireRating {
+		return stats
+	}
+	out := make([]CampsiteStats, 0, len(stats))
+	for _, st := range stats {
+		rating := st.Details.Rating
+		if rating <= 0 {
+			rating = campgroundRating
+		}
+		if rating <= 0 {
+			if !requireRating {
+				out = append(out, st)
+			}
+			continue
+		}
+		if rating >= minRating {
+			out = append(out, st)
+		}
+	}
+	return out
+}
+
+// filterDayUse drops day-use-only campsites, which nobody can sleep at.
+func filterDayUse(stats []CampsiteStats) []CampsiteStats {
+	out := make([]CampsiteStats, 0, len(stats))
+	for _, st := range stats {
+		if !st.Details.IsDayUse() {
+			out = append(out, st)
+		}
+	}
+	return out
+}
+
+// filterReservable drops walk-up and other campsites that can't be reserved ahead by site.
+func filterReservable(stats []CampsiteStats) []CampsiteStats {
+	out := make([]CampsiteStats, 0, len(stats))
+	for _, st := range stats {
+		if st.Details.IsReservable() {
+			out = append(out, st)
+		}
+	}
+	return out
+}
+
+// filterSpecialty keeps only specialty lodging, judged by campsite type and falling back to its name.
+func filterSpecialty(stats []CampsiteStats) []CampsiteStats {
+	out := make([]CampsiteStats, 0, len(stats))
+	for _, st := range stats {
+		if providers.IsSpecialtyType(st.Details.Type, st.Details.Name) {
+			out = append(out, st)
+		}
+	}
+	return out
+}
+
+// filterSiteKind keeps campsites that allow equipment and are of campsiteType, compared
+// case-insensitively. Empty values don't filter; sites with no equipment data never match an
+// equipment filter.
+func filterSiteKind(stats []CampsiteStats, equipment, campsiteType string) []CampsiteStats {
+	if equipment == "" && campsiteType == "" {
+		return stats
+	}
+	out := make([]CampsiteStats, 0, len(stats))
+	for _, st := range stats {
+		if campsiteType != "" && !strings.EqualFold(strings.TrimSpace(st.Details.Type), campsiteType) {
+			continue
+		}
+		if equipment != "" && !slices.ContainsFunc(st.Details.Equipment, func(e string) bool {
+			return strings.EqualFold(strings.TrimSpace(e), equipment)
+		}) {
+			continue
+		}
+		out = append(out, st)
+	}
+	return out
+}
+
+// EmbedOptions controls how much of each notification BuildNotificationEmbedsWithOptions shows.
+type EmbedOptions struct {
+	MaxCampsites int // campsites listed, most available first
+	MaxDates     int // dates (or stays) listed per campsite before "…and N more"
+	// ChangeMarkers marks dates that just opened "(new)" and lists nights just booked as
+	// "(missed it!)", from CampsiteStats.NewDates and MissedDates.
+	ChangeMarkers bool
+}
+
+// DefaultEmbedOptions is the compact layout: the top 3 campsites with up to 20 dates each.
+var DefaultEmbedOptions = EmbedOptions{MaxCampsites: 3, MaxDates: 20}
+
+// DetailedEmbedOptions is the older layout: the top 5 campsites with up to 8 dates each, marked
+// with what changed.
+var DetailedEmbedOptions = EmbedOptions{MaxCampsites: 5, MaxDates: 8, ChangeMarkers: true}
+
 // BuildNotificationEmbeds creates a single embed that lists ONLY the top 3 campsites by days available.
 // Each campsite shows at most 20 dates. No chunking or secondary embeds.
 func BuildNotificationEmbeds(
@@ -258,10 +743,50 @@ func BuildNotificationEmbeds(
 	campgroundID string,
 	campsiteStats []CampsiteStats,
 	provider providers.Provider,
+) []*discordgo.MessageEmbed {
+	return BuildNotificationEmbedsWithOptions(defaultNotificationTemplate, DefaultEmbedOptions, checkin, checkout, userID,
+		campgroundName, campgroundURL, campgroundID, campsiteStats, provider)
+}
+
+var defaultNotificationTemplate = DefaultNotificationTemplate()
+
+// BuildNotificationEmbedsWithTemplate is BuildNotificationEmbeds with operator-supplied wording.
+func BuildNotificationEmbedsWithTemplate(
+	tmpl *NotificationTemplate,
+	checkin, checkout time.Time,
+	userID string,
+	campgroundName string,
+	campgroundURL string,
+	campgroundID string,
+	campsiteStats []CampsiteStats,
+	provider providers.Provider,
+) []*discordgo.MessageEmbed {
+	return BuildNotificationEmbedsWithOptions(tmpl, DefaultEmbedOptions, checkin, checkout, userID,
+		campgroundName, campgroundURL, campgroundID, campsiteStats, provider)
+}
+
+// BuildNotificationEmbedsWithOptions is BuildNotificationEmbedsWithTemplate showing as much as opts
+// allows. Zero limits fall back to DefaultEmbedOptions'.
+func BuildNotificationEmbedsWithOptions(
+	tmpl *NotificationTemplate,
+	opts EmbedOptions,
+	checkin, checkout time.Time,
+	userID string,
+	campgroundName string,
+	campgroundURL string,
+	campgroundID string,
+	campsiteStats []CampsiteStats,
+	provider providers.Provider,
 ) []*discordgo.MessageEmbed {
 	if len(campsiteStats) == 0 {
 		return nil
 	}
+	if opts.MaxCampsites <= 0 {
+		opts.MaxCampsites = DefaultEmbedOptions.MaxCampsites
+	}
+	if opts.MaxDates <= 0 {
+		opts.MaxDates = DefaultEmbedOptions.MaxDates
+	}
 
 	const dateFmtISO = "Monday 2006-01-02"
 
@@ -273,25 +798,22 @@ func BuildNotificationEmbeds(
 		return campsiteStats[i].CampsiteID < campsiteStats[j].CampsiteID
 	})
 
-	// Keep only top 3.
-	if len(campsiteStats) > 3 {
-		campsiteStats = campsiteStats[:3]
+	// Keep only the top few.
+	if len(campsiteStats) > opts.MaxCampsites {
+		campsiteStats = campsiteStats[:opts.MaxCampsites]
 	}
 
-	title := nonsense.RandomSillyHeader()
-	title = fmt.Sprintf("%s\n%s", title, campgroundName)
-
-	desc := fmt.Sprintf("[%s ➡️ %s](%s)",
-		checkin.Format(dateFmtISO), checkout.Format(dateFmtISO),
-		campgroundURL,
-	)
+	data := newNotificationTemplateData(nonsense.RandomSillyHeader(), campgroundName, campgroundURL, checkin, checkout)
 
 	embed := &discordgo.MessageEmbed{
-		Title:       title,
-		Description: desc,
+		Title:       render(tmpl.title, data, embedTitleLimit),
+		Description: render(tmpl.description, data, embedDescriptionLimit),
 		Color:       0x00ff00, // green
 		Fields:      []*discordgo.MessageEmbedField{},
 	}
+	if footer := render(tmpl.footer, data, embedFooterLimit); footer != "" {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: footer}
+	}
 
 	for _, s := range campsiteStats {
 		var b strings.Builder
@@ -303,6 +825,9 @@ func BuildNotificationEmbeds(
 		if len(s.Details.Equipment) > 0 {
 			b.WriteString(fmt.Sprintf("🛖 %s\n", strings.Join(s.Details.Equipment, ", ")))
 		}
+		if s.CostPerNight > 0 {
+			b.WriteString(fmt.Sprintf("💲 $%.2f/night\n", s.CostPerNight))
+		}
 
 		// Availability summary w/ link if provider present.
 		if provider != nil {
@@ -312,19 +837,43 @@ func BuildNotificationEmbeds(
 			b.WriteString(fmt.Sprintf("%d of %d days available\n", s.DaysAvailable, s.TotalDays))
 		}
 
-		// Up to 20 dates.
-		maxDates := 20
-		limit := len(s.Dates)
-		if limit > maxDates {
-			limit = maxDates
+		// Up to MaxDates dates, or qualifying stays when the request has a minimum length.
+		maxDates := opts.MaxDates
+		lines := make([]string, 0, len(s.Dates))
+		// Nights outside checkin/checkout come from the request's flex days and are labeled as such.
+		if len(s.Runs) > 0 {
+			for _, r := range s.Runs {
+				line := fmt.Sprintf("%s → %s (%d nights)",
+					r.Start.Format(dateFmtISO), r.End.AddDate(0, 0, 1).Format(dateFmtISO), r.Nights())
+				if outsideWindow(r.Start, checkin, checkout) || outsideWindow(r.End, checkin, checkout) {
+					line += " (partly outside your dates)"
+				}
+				lines = append(lines, line)
+			}
+		} else {
+			for _, d := range s.Dates {
+				line := d.Format(dateFmtISO)
+				if outsideWindow(d, checkin, checkout) {
+					line += " (outside your dates)"
+				}
+				if opts.ChangeMarkers && s.NewDates[normalizeDay(d)] {
+					line += " (new)"
+				}
+				lines = append(lines, line)
+			}
 		}
-		for i := 0; i < limit; i++ {
-			b.WriteString(s.Dates[i].Format(dateFmtISO))
+		if opts.ChangeMarkers {
+			for _, d := range s.MissedDates {
+				lines = append(lines, d.Format(dateFmtISO)+" (missed it!)")
+			}
+		}
+		for i := 0; i < len(lines) && i < maxDates; i++ {
+			b.WriteString(lines[i])
 			b.WriteByte('\n')
 		}
-		// If there are more dates beyond 20, note it (no extra truncation other than this limit).
-		if len(s.Dates) > maxDates {
-			b.WriteString(fmt.Sprintf("…and %d more\n", len(s.Dates)-maxDates))
+		// If there are more beyond the limit, note it (no extra truncation other than this limit).
+		if len(lines) > maxDates {
+			b.WriteString(fmt.Sprintf("…and %d more\n", len(lines)-maxDates))
 		}
 
 		displayName := s.Details.Name
@@ -339,16 +888,13 @@ func BuildNotificationEmbeds(
 		})
 	}
 
-	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-		Name: "Important Information",
-		Value: strings.Join([]string{
-			"🔗 Links go to booking pages",
-			"🏃‍♂️ Campsites at Yosemite book out in 2 minutes",
-			"⚠️ Opening links in mobile app goes to your last open page",
-			"\nWith 💖 from 🐽",
-		}, "\n"),
-		Inline: false,
-	})
+	if tmpl.InfoHeading != "" && len(tmpl.InfoLines) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   clip(tmpl.InfoHeading, embedFieldNameLimit),
+			Value:  tmpl.info(),
+			Inline: false,
+		})
+	}
 
 	return []*discordgo.MessageEmbed{embed}
 }
@@ -19,7 +19,9 @@ import (
 // ------------------ Mocks & Helpers ------------------
 
 // mockProvider implements providers.Provider for testing
-type mockProvider struct{}
+type mockProvider struct {
+	providers.BaseProvider
+}
 
 func (m *mockProvider) CampsiteURL(campgroundID, campsiteID string) string {
 	return "https://example.com/campsite/" + campsiteID
@@ -558,3 +560,129 @@ func TestBuildNotificationEmbed_Suite_Smoke(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildNotificationEmbeds_ShowsNightlyCost(t *testing.T) {
+	checkin := mustDate(2025, 8, 18)
+	checkout := checkin.AddDate(0, 0, 2)
+	priced := makeStats(2, "cs1", genDates(checkin, 2), false)
+	priced.CostPerNight = 35
+	unpriced := makeStats(2, "cs2", genDates(checkin, 1), false)
+
+	embeds := manager.BuildNotificationEmbeds(checkin, checkout, "u1", "Camp", "https://example.com/cg", "cgid",
+		[]manager.CampsiteStats{priced, unpriced}, &mockProvider{})
+	if len(embeds) != 1 || len(embeds[0].Fields) < 2 {
+		t.Fatalf("expected one embed with a field per campsite, got %+v", embeds)
+	}
+	if !strings.Contains(embeds[0].Fields[0].Value, "$35.00/night") {
+		t.Errorf("expected nightly cost on the priced campsite, got %q", embeds[0].Fields[0].Value)
+	}
+	if strings.Contains(embeds[0].Fields[1].Value, "/night") {
+		t.Errorf("expected no cost line when the price is unknown, got %q", embeds[0].Fields[1].Value)
+	}
+}
+
+func TestBuildNotificationEmbeds_ShowsRunsInsteadOfDates(t *testing.T) {
+	checkin := mustDate(2025, 8, 18)
+	checkout := checkin.AddDate(0, 0, 5)
+	st := makeStats(5, "cs1", genDates(checkin, 3), false)
+	st.Runs = []manager.DateRun{{Start: checkin, End: checkin.AddDate(0, 0, 2)}}
+
+	embeds := manager.BuildNotificationEmbeds(checkin, checkout, "u1", "Camp", "https://example.com/cg", "cgid",
+		[]manager.CampsiteStats{st}, &mockProvider{})
+	if len(embeds) != 1 || len(embeds[0].Fields) == 0 {
+		t.Fatalf("expected one embed with fields, got %+v", embeds)
+	}
+	value := embeds[0].Fields[0].Value
+	if !strings.Contains(value, "Monday 2025-08-18 → Thursday 2025-08-21 (3 nights)") {
+		t.Errorf("expected the stay range, got %q", value)
+	}
+	if strings.Contains(value, "Tuesday 2025-08-19\n") {
+		t.Errorf("expected runs instead of a flat date list, got %q", value)
+	}
+}
+
+func TestBuildNotificationEmbedsWithOptions_HistoricalLayouts(t *testing.T) {
+	checkin := mustDate(2025, 8, 18)
+	checkout := checkin.AddDate(0, 0, 30)
+	build := func(opts manager.EmbedOptions) []*discordgo.MessageEmbedField {
+		t.Helper()
+		var stats []manager.CampsiteStats
+		for i := 0; i < 6; i++ {
+			st := makeStats(30, fmt.Sprintf("cs%d", i), genDates(checkin, 25-i), false)
+			st.NewDates = map[time.Time]bool{checkin: true}
+			st.MissedDates = []time.Time{checkin.AddDate(0, 0, 28)}
+			stats = append(stats, st)
+		}
+		embeds := manager.BuildNotificationEmbedsWithOptions(manager.DefaultNotificationTemplate(), opts,
+			checkin, checkout, "u1", "Camp", "https://example.com/cg", "cgid", stats, &mockProvider{})
+		if len(embeds) != 1 {
+			t.Fatalf("expected one embed, got %d", len(embeds))
+		}
+		// the last field is the info footer, not a campsite
+		return embeds[0].Fields[:len(embeds[0].Fields)-1]
+	}
+
+	compact := build(manager.DefaultEmbedOptions)
+	if len(compact) != 3 {
+		t.Fatalf("default layout: expected 3 campsites, got %d", len(compact))
+	}
+	if v := compact[0].Value; !strings.Contains(v, "…and 5 more") || strings.Contains(v, "(new)") || strings.Contains(v, "(missed it!)") {
+		t.Errorf("default layout: expected 20 unmarked dates, got %q", v)
+	}
+
+	detailed := build(manager.DetailedEmbedOptions)
+	if len(detailed) != 5 {
+		t.Fatalf("detailed layout: expected 5 campsites, got %d", len(detailed))
+	}
+	v := detailed[0].Value
+	if !strings.Contains(v, "Monday 2025-08-18 (new)\n") {
+		t.Errorf("detailed layout: expected the first date marked new, got %q", v)
+	}
+	// 25 open dates plus one missed night, 8 shown
+	if !strings.Contains(v, "…and 18 more") {
+		t.Errorf("detailed layout: expected 8 dates shown, got %q", v)
+	}
+
+	// the missed night shows once the list is short enough to reach it
+	few := makeStats(30, "few", genDates(checkin, 2), false)
+	few.MissedDates = []time.Time{checkin.AddDate(0, 0, 5)}
+	embeds := manager.BuildNotificationEmbedsWithOptions(manager.DefaultNotificationTemplate(), manager.DetailedEmbedOptions,
+		checkin, checkout, "u1", "Camp", "https://example.com/cg", "cgid", []manager.CampsiteStats{few}, &mockProvider{})
+	if v := embeds[0].Fields[0].Value; !strings.Contains(v, "Saturday 2025-08-23 (missed it!)") {
+		t.Errorf("detailed layout: expected the missed night listed, got %q", v)
+	}
+}
+
+func TestBuildNotificationEmbeds_LabelsFlexDates(t *testing.T) {
+	checkin := mustDate(2025, 8, 18)
+	checkout := checkin.AddDate(0, 0, 2)
+
+	// two flex days either side: Aug 16-17 before, Aug 20-21 after
+	st := makeStats(6, "cs1", genDates(checkin.AddDate(0, 0, -2), 6), false)
+	embeds := manager.BuildNotificationEmbeds(checkin, checkout, "u1", "Camp", "https://example.com/cg", "cgid",
+		[]manager.CampsiteStats{st}, &mockProvider{})
+	v := embeds[0].Fields[0].Value
+	for _, want := range []string{
+		"Saturday 2025-08-16 (outside your dates)\n",
+		"Monday 2025-08-18\n",
+		"Tuesday 2025-08-19\n",
+		"Wednesday 2025-08-20 (outside your dates)\n",
+	} {
+		if !strings.Contains(v, want) {
+			t.Errorf("expected %q in %q", want, v)
+		}
+	}
+
+	// stays reaching past the requested dates are labeled too
+	runs := makeStats(6, "cs2", nil, false)
+	runs.Runs = []manager.DateRun{
+		{Start: checkin, End: checkin.AddDate(0, 0, 1)},
+		{Start: checkin.AddDate(0, 0, 1), End: checkin.AddDate(0, 0, 3)},
+	}
+	embeds = manager.BuildNotificationEmbeds(checkin, checkout, "u1", "Camp", "https://example.com/cg", "cgid",
+		[]manager.CampsiteStats{runs}, &mockProvider{})
+	v = embeds[0].Fields[0].Value
+	if !strings.Contains(v, "(2 nights)\n") || !strings.Contains(v, "(3 nights) (partly outside your dates)\n") {
+		t.Errorf("expected only the second stay labeled, got %q", v)
+	}
+}
@@ -0,0 +1,54 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/bwmarrin/discordgo"
+)
+
+// AvailableNow renders what can be booked right now across the user's active, unpaused schniffs,
+// filtered by each schniff's preferences the same way its notifications are. It returns no embeds
+// when nothing is open.
+func (m *Manager) AvailableNow(ctx context.Context, userID string) ([]*discordgo.MessageEmbed, error) {
+	reqs, err := m.store.ListUserActiveRequestsDetailed(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var openings []db.StateChangeForRequest
+	campgrounds := make(map[string]db.Campground)
+	seen := make(map[string]bool)
+	for _, req := range reqs {
+		if req.Paused {
+			continue
+		}
+		stats, campground, _ := m.requestCampsiteStats(ctx, req.SchniffRequest)
+		if campground.Name == "" {
+			campground.Name = req.CampgroundName
+		}
+		campgrounds[req.Provider+"/"+req.CampgroundID] = campground
+		for _, st := range stats {
+			for _, night := range st.Dates {
+				key := openingKey(req.Provider, req.CampgroundID, st.CampsiteID, night)
+				if seen[key] || !statsIncludeNight(st, night) {
+					continue
+				}
+				seen[key] = true
+				openings = append(openings, db.StateChangeForRequest{
+					Provider: req.Provider, CampgroundID: req.CampgroundID, CampsiteID: st.CampsiteID,
+					Date: night, NewAvailable: true,
+				})
+			}
+		}
+	}
+
+	embeds, nights, places := buildOpeningsEmbeds(openings, campgrounds, m.CampgroundURL)
+	if embeds == nil {
+		return nil, nil
+	}
+	embeds[0].Title = clip(fmt.Sprintf("🏕️ Open right now: %d nights at %d campgrounds", nights, places), embedTitleLimit)
+	embeds[0].Description = "Campsite/nights you can book right now across your schniffs. Book fast, they may not last."
+	return embeds, nil
+}
@@ -0,0 +1,75 @@
+package manager
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+func TestAvailableNowAppliesRequestFilters(t *testing.T) {
+	ctx := context.Background()
+	store, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+	m := NewManager(store, providers.NewRegistry(), nil, "summary")
+
+	if embeds, err := m.AvailableNow(ctx, "user1"); err != nil || len(embeds) != 0 {
+		t.Fatalf("Expected no embeds without schniffs, got %v, %v", embeds, err)
+	}
+
+	checkin := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 10)
+	add := func(campgroundID string, minNights int) int64 {
+		t.Helper()
+		id, err := store.AddRequest(ctx, db.SchniffRequest{
+			UserID: "user1", Provider: "p", CampgroundID: campgroundID,
+			Checkin: checkin, Checkout: checkin.AddDate(0, 0, 3), MinNights: minNights, IncludeDayUse: true,
+		})
+		if err != nil {
+			t.Fatalf("AddRequest failed: %v", err)
+		}
+		return id
+	}
+	add("cg1", 2)
+	paused := add("cg2", 0)
+	if err := store.SetRequestActive(ctx, paused, "user1", false); err != nil {
+		t.Fatalf("SetRequestActive failed: %v", err)
+	}
+
+	var states []db.CampsiteAvailability
+	open := func(campgroundID, campsiteID string, nights ...int) {
+		for _, n := range nights {
+			states = append(states, db.CampsiteAvailability{
+				Provider: "p", CampgroundID: campgroundID, CampsiteID: campsiteID,
+				Date: checkin.AddDate(0, 0, n), Available: true, LastChecked: time.Now(),
+			})
+		}
+	}
+	open("cg1", "long", 0, 1)
+	open("cg1", "short", 2) // a single night is under the request's minimum stay
+	open("cg2", "paused", 0)
+	if err := store.UpsertCampsiteAvailabilityBatch(ctx, states); err != nil {
+		t.Fatalf("UpsertCampsiteAvailabilityBatch failed: %v", err)
+	}
+
+	embeds, err := m.AvailableNow(ctx, "user1")
+	if err != nil {
+		t.Fatalf("AvailableNow failed: %v", err)
+	}
+	if len(embeds) != 1 || len(embeds[0].Fields) != 1 {
+		t.Fatalf("Expected one field for the unpaused schniff, got %+v", embeds)
+	}
+	if !strings.Contains(embeds[0].Title, "2 nights at 1 campgrounds") {
+		t.Errorf("Unexpected title %q", embeds[0].Title)
+	}
+	value := embeds[0].Fields[0].Value
+	if !strings.Contains(value, "`long`") || strings.Contains(value, "`short`") {
+		t.Errorf("Expected only the campsite passing the schniff's filters, got %q", value)
+	}
+}
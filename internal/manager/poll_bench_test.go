@@ -0,0 +1,163 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+// slowProvider simulates provider latency so the benefit of concurrent fetches is visible.
+// It records the most fetches seen in flight at once, and campgrounds in fail return an error.
+type slowProvider struct {
+	providers.BaseProvider
+	latency     time.Duration
+	calls       atomic.Int64
+	inFlight    atomic.Int64
+	maxInFlight atomic.Int64
+	fail        map[string]bool
+}
+
+func (p *slowProvider) Name() string { return "slow" }
+func (p *slowProvider) FetchAvailability(ctx context.Context, campgroundID string, start, end time.Time) ([]providers.CampsiteAvailability, error) {
+	p.calls.Add(1)
+	n := p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+	for {
+		max := p.maxInFlight.Load()
+		if n <= max || p.maxInFlight.CompareAndSwap(max, n) {
+			break
+		}
+	}
+	if p.fail[campgroundID] {
+		return nil, fmt.Errorf("campground %s unavailable", campgroundID)
+	}
+	select {
+	case <-time.After(p.latency):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return []providers.CampsiteAvailability{{ID: "site1", Date: start, Available: false}}, nil
+}
+func (p *slowProvider) FetchAllCampgrounds(ctx context.Context) ([]providers.CampgroundInfo, error) {
+	return nil, nil
+}
+func (p *slowProvider) FetchCampsites(ctx context.Context, campgroundID string) ([]providers.CampsiteInfo, error) {
+	return nil, nil
+}
+func (p *slowProvider) CampsiteURL(campgroundID, campsiteID string) string { return "" }
+func (p *slowProvider) CampgroundURL(campgroundID string) string           { return "" }
+func (p *slowProvider) PlanBuckets(dates []time.Time) []providers.DateRange {
+	return []providers.DateRange{{Start: dates[0], End: dates[len(dates)-1]}}
+}
+
+func newPollTestManager(tb testing.TB, campgrounds int) (*Manager, *slowProvider) {
+	tb.Helper()
+	store, err := db.Open(filepath.Join(tb.TempDir(), "test.db"))
+	if err != nil {
+		tb.Fatalf("Failed to open store: %v", err)
+	}
+	tb.Cleanup(func() { store.Close() })
+
+	checkin := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 10)
+	for i := 0; i < campgrounds; i++ {
+		_, err := store.AddRequest(context.Background(), db.SchniffRequest{
+			UserID: "user1", Provider: "slow", CampgroundID: fmt.Sprintf("cg%d", i),
+			Checkin: checkin, Checkout: checkin.AddDate(0, 0, 2),
+		})
+		if err != nil {
+			tb.Fatalf("AddRequest failed: %v", err)
+		}
+	}
+
+	prov := &slowProvider{latency: 5 * time.Millisecond}
+	reg := providers.NewRegistry()
+	reg.Register("slow", prov)
+	return NewManager(store, reg, nil, ""), prov
+}
+
+func TestPollProvider_Concurrent(t *testing.T) {
+	m, prov := newPollTestManager(t, 12)
+	m.SetPollConcurrency(4)
+	if err := m.PollProvider(context.Background(), "slow"); err != nil {
+		t.Fatalf("PollProvider failed: %v", err)
+	}
+	if got := prov.calls.Load(); got != 12 {
+		t.Errorf("Expected 12 fetches, got %d", got)
+	}
+	if got := prov.maxInFlight.Load(); got < 2 || got > 4 {
+		t.Errorf("Expected between 2 and 4 fetches in flight, got %d", got)
+	}
+}
+
+func TestPollProvider_SerialByDefault(t *testing.T) {
+	m, prov := newPollTestManager(t, 5)
+	if err := m.PollProvider(context.Background(), "slow"); err != nil {
+		t.Fatalf("PollProvider failed: %v", err)
+	}
+	if got := prov.maxInFlight.Load(); got != 1 {
+		t.Errorf("Expected one fetch at a time, got %d", got)
+	}
+}
+
+func TestPollProvider_FailureDoesNotAbortOthers(t *testing.T) {
+	m, prov := newPollTestManager(t, 6)
+	m.SetPollConcurrency(2)
+	prov.fail = map[string]bool{"cg1": true, "cg4": true}
+
+	err := m.PollProvider(context.Background(), "slow")
+	if err == nil {
+		t.Fatal("Expected an error for the failing campgrounds")
+	}
+	for _, cg := range []string{"slow/cg1", "slow/cg4"} {
+		if !strings.Contains(err.Error(), cg) {
+			t.Errorf("Expected error to mention %s, got %v", cg, err)
+		}
+	}
+	if got := prov.calls.Load(); got != 6 {
+		t.Errorf("Expected every campground to be fetched, got %d fetches", got)
+	}
+
+	var checked int
+	if err := m.store.DB.QueryRow(`SELECT count(DISTINCT campground_id) FROM campsite_availability`).Scan(&checked); err != nil {
+		t.Fatalf("count availability: %v", err)
+	}
+	if checked != 4 {
+		t.Errorf("Expected availability stored for the 4 healthy campgrounds, got %d", checked)
+	}
+}
+
+func TestPollProvider_CancelledContext(t *testing.T) {
+	m, prov := newPollTestManager(t, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := m.PollProvider(ctx, "slow"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if got := prov.calls.Load(); got != 0 {
+		t.Errorf("Expected no fetches after cancellation, got %d", got)
+	}
+}
+
+func benchmarkPollProvider(b *testing.B, concurrency int) {
+	m, _ := newPollTestManager(b, 20)
+	m.SetPollConcurrency(concurrency)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.polled = nil // every campground is due each iteration
+		if err := m.PollProvider(context.Background(), "slow"); err != nil {
+			b.Fatalf("PollProvider failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkPollProvider_Serial(b *testing.B)      { benchmarkPollProvider(b, 1) }
+func BenchmarkPollProvider_Concurrent4(b *testing.B) { benchmarkPollProvider(b, 4) }
+func BenchmarkPollProvider_Concurrent8(b *testing.B) { benchmarkPollProvider(b, 8) }
@@ -0,0 +1,204 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/logctx"
+)
+
+func TestPollableRequests_AfterExpiry(t *testing.T) {
+	store, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	add := func(provider, cg string, checkin time.Time) {
+		t.Helper()
+		_, err := store.AddRequest(ctx, db.SchniffRequest{
+			UserID: "user1", Provider: provider, CampgroundID: cg,
+			Checkin: checkin, Checkout: checkin.AddDate(0, 0, 2),
+		})
+		if err != nil {
+			t.Fatalf("AddRequest failed: %v", err)
+		}
+	}
+
+	add("prov", "expired", today.AddDate(0, 0, -1))
+	add("prov", "watched", today.AddDate(0, 0, 5))
+	add("other", "other-provider", today.AddDate(0, 0, 5))
+
+	// expiry runs first, then the active list is read, as in PollProvider
+	deactivated, err := store.DeactivateExpiredRequests(ctx)
+	if err != nil {
+		t.Fatalf("DeactivateExpiredRequests failed: %v", err)
+	}
+	if len(deactivated) != 1 || deactivated[0].CampgroundID != "expired" {
+		t.Fatalf("Expected the expired request to be deactivated, got %+v", deactivated)
+	}
+	active, err := store.ListActiveRequests(ctx)
+	if err != nil {
+		t.Fatalf("ListActiveRequests failed: %v", err)
+	}
+
+	got := pollableRequests(active, deactivated, "prov", time.Now())
+	if len(got) != 1 || got[0].CampgroundID != "watched" {
+		t.Fatalf("Expected only the watched campground to be polled, got %+v", got)
+	}
+
+	// a request that expired after deactivation ran, or was deactivated but still listed, is skipped too
+	stale := db.SchniffRequest{ID: 99, Provider: "prov", CampgroundID: "stale", Checkin: today.AddDate(0, 0, -1), Checkout: today.AddDate(0, 0, 1)}
+	raced := db.SchniffRequest{ID: 100, Provider: "prov", CampgroundID: "raced", Checkin: today.AddDate(0, 0, 3), Checkout: today.AddDate(0, 0, 4)}
+	got = pollableRequests(append(active, stale, raced), []db.SchniffRequest{raced}, "prov", time.Now())
+	if len(got) != 1 || got[0].CampgroundID != "watched" {
+		t.Errorf("Expected stale and raced requests to be skipped, got %+v", got)
+	}
+
+	datesByPC, _ := collectDatesByPC(got)
+	if len(datesByPC) != 1 {
+		t.Errorf("Expected one campground to fetch, got %d", len(datesByPC))
+	}
+}
+
+func TestWithoutBlocked(t *testing.T) {
+	reqs := []db.SchniffRequest{
+		{ID: 1, Provider: "prov", CampgroundID: "bad"},
+		{ID: 2, Provider: "prov", CampgroundID: "good"},
+		{ID: 3, Provider: "other", CampgroundID: "bad"},
+	}
+	got := withoutBlocked(reqs, []db.BlockedCampground{{Provider: "prov", CampgroundID: "bad"}})
+	if len(got) != 2 || got[0].ID != 2 || got[1].ID != 3 {
+		t.Errorf("Expected only the blocked campground to be dropped, got %+v", got)
+	}
+	if got := withoutBlocked(reqs, nil); len(got) != 3 {
+		t.Errorf("Expected no filtering without a blocklist, got %d", len(got))
+	}
+}
+
+func TestGenerateNightsFilters(t *testing.T) {
+	// Wed 2025-01-22 to Wed 2025-02-12: three weeks across the January/February boundary
+	checkin := time.Date(2025, 1, 22, 0, 0, 0, 0, time.UTC)
+	checkout := time.Date(2025, 2, 12, 0, 0, 0, 0, time.UTC)
+
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC) }
+	weekends := []time.Time{day(1, 24), day(1, 25), day(1, 31), day(2, 1), day(2, 7), day(2, 8)}
+
+	tests := []struct {
+		filter string
+		want   int
+		check  func(time.Time) bool
+	}{
+		{"", 21, func(time.Time) bool { return true }},
+		{db.NightsAny, 21, func(time.Time) bool { return true }},
+		{db.NightsWeekends, 6, func(d time.Time) bool { return d.Weekday() == time.Friday || d.Weekday() == time.Saturday }},
+		{db.NightsWeekdays, 15, func(d time.Time) bool { return d.Weekday() != time.Friday && d.Weekday() != time.Saturday }},
+	}
+	for _, tt := range tests {
+		got := generateNights(checkin, checkout, tt.filter, 0)
+		if len(got) != tt.want {
+			t.Errorf("%q: expected %d nights, got %d: %v", tt.filter, tt.want, len(got), got)
+			continue
+		}
+		for _, d := range got {
+			if !tt.check(d) || d.Before(checkin) || !d.Before(checkout) {
+				t.Errorf("%q: unexpected night %s", tt.filter, d.Format("Mon 2006-01-02"))
+			}
+		}
+	}
+
+	got := generateNights(checkin, checkout, db.NightsWeekends, 0)
+	for i, want := range weekends {
+		if !got[i].Equal(want) {
+			t.Errorf("weekend night %d: expected %s, got %s", i, want.Format("2006-01-02"), got[i].Format("2006-01-02"))
+		}
+	}
+
+	// a window with no matching nights produces nothing to poll
+	monToWed := []db.SchniffRequest{{Provider: "p", CampgroundID: "cg", Checkin: day(2, 3), Checkout: day(2, 5), NightFilter: db.NightsWeekends}}
+	if datesByPC, _ := collectDatesByPC(monToWed); len(datesByPC) != 0 {
+		t.Errorf("Expected no campgrounds to poll for a weekday-only window, got %v", datesByPC)
+	}
+}
+
+func TestGenerateNightsFlexDays(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 7, d, 0, 0, 0, 0, time.UTC) } // Jul 4 2025 is a Friday
+
+	got := generateNights(day(10), day(12), db.NightsAny, 2)
+	if len(got) != 6 || !got[0].Equal(day(8)) || !got[5].Equal(day(13)) {
+		t.Errorf("Expected Jul 8-13 with 2 flex days, got %v", got)
+	}
+
+	// the night filter still applies to flex days
+	got = generateNights(day(7), day(10), db.NightsWeekends, 2)
+	if len(got) != 2 || !got[0].Equal(day(5)) || !got[1].Equal(day(11)) {
+		t.Errorf("Expected only the flex weekend nights Jul 5 and Jul 11, got %v", got)
+	}
+
+	// flex days widen the dates polled for the campground
+	reqs := []db.SchniffRequest{{Provider: "p", CampgroundID: "cg", Checkin: day(10), Checkout: day(11), FlexDays: 1}}
+	datesByPC, _ := collectDatesByPC(reqs)
+	dates := datesByPC[pc{prov: "p", cg: "cg"}]
+	for _, d := range []time.Time{day(9), day(10), day(11)} {
+		if _, ok := dates[d]; !ok {
+			t.Errorf("Expected %s to be polled, got %v", d.Format("2006-01-02"), dates)
+		}
+	}
+}
+
+func TestPollProvider_LogsCarryPollID(t *testing.T) {
+	// poll runs one cycle on a fresh manager and returns the pollID of every log line, by message
+	poll := func(ctx context.Context) map[string][]string {
+		t.Helper()
+		m, _ := newPollTestManager(t, 3)
+		var buf bytes.Buffer
+		m.logger = slog.New(slog.NewJSONHandler(&buf, nil))
+		if err := m.PollProvider(ctx, "slow"); err != nil {
+			t.Fatalf("PollProvider failed: %v", err)
+		}
+		out := map[string][]string{}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var rec struct {
+				Msg    string `json:"msg"`
+				PollID string `json:"pollID"`
+			}
+			if err := json.Unmarshal([]byte(line), &rec); err != nil {
+				t.Fatalf("parse log line %q: %v", line, err)
+			}
+			out[rec.Msg] = append(out[rec.Msg], rec.PollID)
+		}
+		if len(out["persisted campsite states"]) != 3 || len(out["processing notifications"]) != 1 {
+			t.Fatalf("Expected fetch and notification log lines from the cycle, got %v", out)
+		}
+		return out
+	}
+	expectPollID := func(lines map[string][]string, want string) {
+		t.Helper()
+		for msg, ids := range lines {
+			for _, got := range ids {
+				if got != want {
+					t.Errorf("%q: Expected pollID %q, got %q", msg, want, got)
+				}
+			}
+		}
+	}
+
+	lines := poll(context.Background())
+	id := lines["persisted campsite states"][0]
+	if id == "" {
+		t.Fatal("Expected a generated pollID on poll log lines")
+	}
+	expectPollID(lines, id)
+
+	// a caller's poll ID is kept rather than replaced
+	expectPollID(poll(logctx.WithPollID(context.Background(), "given")), "given")
+}
@@ -0,0 +1,164 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/logctx"
+)
+
+// maxPriceDropLines caps how many drops one price alert DM lists, keeping it under Discord's limit.
+const maxPriceDropLines = 10
+
+// notifyPriceDrops DMs users with a price alert about campsite/nights in their schniffs whose price
+// just crossed under their threshold, as long as the night is still available and passes the
+// request's filters. Users in quiet hours (only when enabled) get no alert for these changes. Every
+// pending change for the provider is then marked processed, except those whose alert couldn't be
+// sent, which are tried again next poll.
+func (m *Manager) notifyPriceDrops(ctx context.Context, provider string, requests []db.SchniffRequest) {
+	changes, err := m.store.ListUnprocessedPriceChanges(ctx, provider)
+	if err != nil {
+		m.logger.Warn("list price changes failed", logctx.Attr(ctx), slog.String("provider", provider), slog.Any("err", err))
+		return
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	allPrefs := make(map[string]db.NotificationPrefs)
+	prefsFor := func(userID string) db.NotificationPrefs {
+		if p, ok := allPrefs[userID]; ok {
+			return p
+		}
+		prefs, err := m.store.GetNotificationPrefs(ctx, userID)
+		if err != nil {
+			m.logger.Warn("get notification prefs failed; skipping price alert", logctx.Attr(ctx), slog.String("userID", userID), slog.Any("err", err))
+		}
+		allPrefs[userID] = prefs
+		return prefs
+	}
+	threshold := func(userID string) float64 { return prefsFor(userID).PriceAlertBelow }
+
+	now := time.Now()
+	byUser := make(map[string][]db.PriceChange)
+	seen := make(map[string]map[int64]bool)
+	for _, req := range requests {
+		drops := priceDropsForRequest(changes, req, threshold)
+		if len(drops) == 0 || prefsFor(req.UserID).InQuietHours(now) {
+			continue
+		}
+		stats, _, _ := m.requestCampsiteStats(ctx, req)
+		if seen[req.UserID] == nil {
+			seen[req.UserID] = make(map[int64]bool)
+		}
+		byUser[req.UserID] = append(byUser[req.UserID], availableDrops(drops, stats, seen[req.UserID])...)
+	}
+
+	var unsent []int64
+	for userID, drops := range byUser {
+		if len(drops) == 0 {
+			continue
+		}
+		if err := m.sendPriceDropAlert(ctx, userID, drops); err != nil {
+			m.logger.Warn("send price drop alert failed", logctx.Attr(ctx), slog.String("userID", userID), slog.Any("err", err))
+			for _, d := range drops {
+				unsent = append(unsent, d.ID)
+			}
+			continue
+		}
+		m.logger.Info("sent price drop alert", logctx.Attr(ctx), slog.String("userID", userID), slog.Int("drops", len(drops)))
+	}
+
+	if err := m.store.MarkPriceChangesProcessed(ctx, provider, changes[len(changes)-1].ID, unsent...); err != nil {
+		m.logger.Warn("mark price changes processed failed", logctx.Attr(ctx), slog.String("provider", provider), slog.Any("err", err))
+	}
+}
+
+// priceDropsForRequest returns the changes that took a price from at or above threshold(user) to
+// under it on nights the request covers. threshold returns 0 for users without a price alert.
+func priceDropsForRequest(changes []db.PriceChange, req db.SchniffRequest, threshold func(userID string) float64) []db.PriceChange {
+	var out []db.PriceChange
+	start, end := req.Window()
+	for _, c := range changes {
+		if !c.IsDrop() || req.Provider != c.Provider || req.CampgroundID != c.CampgroundID {
+			continue
+		}
+		day := normalizeDay(c.Date)
+		if day.Before(normalizeDay(start)) || !day.Before(normalizeDay(end)) || !db.NightMatches(req.NightFilter, day) {
+			continue
+		}
+		if limit := threshold(req.UserID); limit <= 0 || c.OldCost < limit || c.NewCost >= limit {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// availableDrops keeps the drops on nights the request's notification would show for that
+// campsite, i.e. still available and passing its filters, skipping any already in seen. Kept drops
+// are added to seen.
+func availableDrops(drops []db.PriceChange, stats []CampsiteStats, seen map[int64]bool) []db.PriceChange {
+	byCampsite := make(map[string]CampsiteStats, len(stats))
+	for _, st := range stats {
+		byCampsite[st.CampsiteID] = st
+	}
+	var out []db.PriceChange
+	for _, d := range drops {
+		if st, ok := byCampsite[d.CampsiteID]; ok && !seen[d.ID] && statsIncludeNight(st, d.Date) {
+			seen[d.ID] = true
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// sendPriceDropAlert DMs the user one line per drop, grouped by campground and ordered by date.
+func (m *Manager) sendPriceDropAlert(ctx context.Context, userID string, drops []db.PriceChange) error {
+	sort.Slice(drops, func(i, j int) bool {
+		if drops[i].CampgroundID != drops[j].CampgroundID {
+			return drops[i].CampgroundID < drops[j].CampgroundID
+		}
+		if !drops[i].Date.Equal(drops[j].Date) {
+			return drops[i].Date.Before(drops[j].Date)
+		}
+		return drops[i].CampsiteID < drops[j].CampsiteID
+	})
+
+	var b strings.Builder
+	b.WriteString("💸 Prices dropped on nights you're schniffing:")
+	lastCampground := ""
+	for i, d := range drops {
+		if i == maxPriceDropLines {
+			fmt.Fprintf(&b, "\n…and %d more", len(drops)-i)
+			break
+		}
+		if d.CampgroundID != lastCampground {
+			name := d.CampgroundID
+			if campground, ok, _ := m.store.GetCampgroundByID(ctx, d.Provider, d.CampgroundID); ok && campground.Name != "" {
+				name = campground.Name
+			}
+			fmt.Fprintf(&b, "\n**%s**", name)
+			lastCampground = d.CampgroundID
+		}
+		line := fmt.Sprintf("\n- %s, site %s: $%.2f/night (was $%.2f)", d.Date.Format("Mon Jan 2"), d.CampsiteID, d.NewCost, d.OldCost)
+		if prov, ok := m.reg.Get(d.Provider); ok {
+			if url := prov.CampsiteURL(d.CampgroundID, d.CampsiteID); url != "" {
+				line += " " + url
+			}
+		}
+		b.WriteString(line)
+	}
+
+	channel, err := m.notifier.UserChannelCreate(userID)
+	if err != nil {
+		return err
+	}
+	_, err = m.notifier.ChannelMessageSend(channel.ID, b.String())
+	return err
+}
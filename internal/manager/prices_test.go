@@ -0,0 +1,170 @@
+package manager
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/providers"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestPriceDropsForRequest(t *testing.T) {
+	checkin := time.Date(2026, 7, 10, 0, 0, 0, 0, time.UTC)
+	req := db.SchniffRequest{ID: 1, UserID: "alert", Provider: "p", CampgroundID: "cg1", Checkin: checkin, Checkout: checkin.AddDate(0, 0, 3)}
+	thresholds := map[string]float64{"alert": 30}
+	threshold := func(userID string) float64 { return thresholds[userID] }
+	change := func(id int64, campground string, day int, oldCost, newCost float64) db.PriceChange {
+		return db.PriceChange{ID: id, Provider: "p", CampgroundID: campground, CampsiteID: "s1", Date: checkin.AddDate(0, 0, day), OldCost: oldCost, NewCost: newCost}
+	}
+	changes := []db.PriceChange{
+		change(1, "cg1", 0, 40, 25), // crosses under the threshold
+		change(2, "cg1", 1, 40, 35), // drop, but not under the threshold
+		change(3, "cg1", 1, 20, 25), // rise
+		change(4, "cg1", 2, 0, 10),  // first known price
+		change(5, "cg1", 3, 40, 10), // checkout night isn't covered
+		change(6, "cg2", 0, 40, 10), // campground the request doesn't watch
+		change(7, "cg1", 2, 30, 29), // crosses from exactly the threshold
+		change(8, "cg1", 1, 28, 25), // already under the threshold
+	}
+
+	got := priceDropsForRequest(changes, req, threshold)
+	if len(got) != 2 || got[0].ID != 1 || got[1].ID != 7 {
+		t.Errorf("Expected drops 1 and 7, got %+v", got)
+	}
+
+	req.NightFilter = db.NightsWeekends // Jul 10 2026 is a Friday, Jul 12 a Sunday
+	if got := priceDropsForRequest(changes, req, threshold); len(got) != 1 || got[0].ID != 1 {
+		t.Errorf("Expected only the weekend drop 1, got %+v", got)
+	}
+
+	req.UserID = "no-alert"
+	if got := priceDropsForRequest(changes, req, threshold); len(got) != 0 {
+		t.Errorf("Expected no drops for a user without a price alert, got %+v", got)
+	}
+}
+
+func TestAvailableDrops(t *testing.T) {
+	night := time.Date(2026, 7, 10, 0, 0, 0, 0, time.UTC)
+	drops := []db.PriceChange{
+		{ID: 1, CampsiteID: "s1", Date: night},
+		{ID: 2, CampsiteID: "s1", Date: night.AddDate(0, 0, 1)}, // night no longer available
+		{ID: 3, CampsiteID: "s2", Date: night},                  // campsite filtered out
+	}
+	stats := []CampsiteStats{{CampsiteID: "s1", Dates: []time.Time{night}}}
+
+	seen := make(map[int64]bool)
+	if got := availableDrops(drops, stats, seen); len(got) != 1 || got[0].ID != 1 {
+		t.Fatalf("Expected only drop 1, got %+v", got)
+	}
+	// another of the user's requests covering the same drop doesn't list it again
+	if got := availableDrops(drops, stats, seen); len(got) != 0 {
+		t.Errorf("Expected drop 1 to be listed once, got %+v", got)
+	}
+}
+
+// failingDiscord rejects every Discord API call.
+type failingDiscord struct{}
+
+func (failingDiscord) RoundTrip(r *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusForbidden,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"message":"Cannot send messages to this user","code":50007}`)),
+		Request:    r,
+	}, nil
+}
+
+func TestNotifyPriceDrops(t *testing.T) {
+	ctx := context.Background()
+	store, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+
+	checkin := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 10)
+	req := db.SchniffRequest{
+		UserID: "user1", Provider: "p", CampgroundID: "cg1",
+		Checkin: checkin, Checkout: checkin.AddDate(0, 0, 1), IncludeDayUse: true,
+	}
+	id, err := store.AddRequest(ctx, req)
+	if err != nil {
+		t.Fatalf("AddRequest failed: %v", err)
+	}
+	req.ID = id
+	if err := store.UpsertNotificationPrefs(ctx, db.NotificationPrefs{UserID: "user1", PriceAlertBelow: 30}); err != nil {
+		t.Fatalf("UpsertNotificationPrefs failed: %v", err)
+	}
+	price := func(campsiteID string, available bool, cost float64) {
+		t.Helper()
+		err := store.UpsertCampsiteAvailabilityBatch(ctx, []db.CampsiteAvailability{{
+			Provider: "p", CampgroundID: "cg1", CampsiteID: campsiteID,
+			Date: checkin, Available: available, LastChecked: time.Now(), CostPerNight: cost,
+		}})
+		if err != nil {
+			t.Fatalf("UpsertCampsiteAvailabilityBatch failed: %v", err)
+		}
+	}
+	pending := func() int {
+		t.Helper()
+		changes, err := store.ListUnprocessedPriceChanges(ctx, "p")
+		if err != nil {
+			t.Fatalf("ListUnprocessedPriceChanges failed: %v", err)
+		}
+		return len(changes)
+	}
+
+	session, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("discordgo.New failed: %v", err)
+	}
+	m := NewManager(store, providers.NewRegistry(), session, "summary")
+
+	price("open", true, 40)
+	price("booked", false, 40)
+	price("open", true, 25)
+	price("booked", false, 25)
+
+	// a failed DM leaves its drop to be tried again; the booked site's drop is done with
+	session.Client = &http.Client{Transport: failingDiscord{}}
+	m.notifyPriceDrops(ctx, "p", []db.SchniffRequest{req})
+	if n := pending(); n != 1 {
+		t.Fatalf("Expected the unsent drop to stay pending, got %d pending", n)
+	}
+
+	discord := &fakeDiscord{}
+	session.Client = &http.Client{Transport: discord}
+	m.notifyPriceDrops(ctx, "p", []db.SchniffRequest{req})
+	dms := discord.sent()
+	if len(dms) != 1 || !strings.Contains(dms[0], "site open") || strings.Contains(dms[0], "site booked") {
+		t.Fatalf("Expected one alert for the available site only, got %q", dms)
+	}
+	if n := pending(); n != 0 {
+		t.Errorf("Expected no pending price changes after alerting, got %d", n)
+	}
+
+	// during quiet hours the drop is marked processed without an alert
+	now := time.Now().UTC()
+	err = store.UpsertNotificationPrefs(ctx, db.NotificationPrefs{
+		UserID: "user1", PriceAlertBelow: 30, Enabled: true,
+		QuietStart: now.Add(-time.Hour).Format("15:04"), QuietEnd: now.Add(time.Hour).Format("15:04"),
+	})
+	if err != nil {
+		t.Fatalf("UpsertNotificationPrefs failed: %v", err)
+	}
+	price("open", true, 40)
+	price("open", true, 20)
+	m.notifyPriceDrops(ctx, "p", []db.SchniffRequest{req})
+	if len(discord.sent()) != 1 {
+		t.Errorf("Expected no alert during quiet hours, got %q", discord.sent())
+	}
+	if n := pending(); n != 0 {
+		t.Errorf("Expected quiet-hours drops to be marked processed, got %d pending", n)
+	}
+}
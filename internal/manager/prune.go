@@ -0,0 +1,75 @@
+package manager
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// defaultHistoryRetention keeps past nights' availability and state changes for a month so
+	// recent history views still have data.
+	defaultHistoryRetention = 30 * 24 * time.Hour
+	// defaultNotificationRetention keeps sent notifications for three months.
+	defaultNotificationRetention = 90 * 24 * time.Hour
+)
+
+// SetHistoryRetention overrides how long past availability/state changes and sent notifications
+// are kept. A non-positive value keeps the default.
+func (m *Manager) SetHistoryRetention(history, notifications time.Duration) {
+	m.historyRetention = history
+	m.notificationRetention = notifications
+}
+
+// pruneCutoffs returns the night before which history is deleted and the send time before which
+// notifications are deleted.
+func (m *Manager) pruneCutoffs(now time.Time) (history, notifications time.Time) {
+	historyRetention := m.historyRetention
+	if historyRetention <= 0 {
+		historyRetention = defaultHistoryRetention
+	}
+	notificationRetention := m.notificationRetention
+	if notificationRetention <= 0 {
+		notificationRetention = defaultNotificationRetention
+	}
+	return normalizeDay(now.Add(-historyRetention)), now.Add(-notificationRetention)
+}
+
+// PruneHistory deletes history older than the retention windows and logs what was removed.
+func (m *Manager) PruneHistory(ctx context.Context) {
+	historyCutoff, notificationCutoff := m.pruneCutoffs(time.Now())
+	stats, err := m.store.PruneHistory(ctx, historyCutoff, notificationCutoff)
+	if err != nil {
+		m.logger.Error("failed to prune history", slog.Any("err", err))
+		return
+	}
+	m.logger.Info("pruned history",
+		slog.Time("history_before", historyCutoff),
+		slog.Time("notifications_before", notificationCutoff),
+		slog.Int64("availability", stats.Availability),
+		slog.Int64("state_changes", stats.StateChanges),
+		slog.Int64("price_changes", stats.PriceChanges),
+		slog.Int64("notifications", stats.Notifications))
+}
+
+// RunHistoryPruning prunes old history at 3 AM San Francisco time every night, away from the
+// evening summary.
+func (m *Manager) RunHistoryPruning(ctx context.Context) {
+	sfLocation, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		m.logger.Error("failed to load San Francisco timezone", slog.Any("err", err))
+		return
+	}
+
+	c := cron.New(cron.WithLocation(sfLocation))
+	c.AddFunc("0 3 * * *", func() {
+		m.PruneHistory(ctx)
+	})
+	c.Start()
+	go func() {
+		<-ctx.Done()
+		c.Stop()
+	}()
+}
@@ -0,0 +1,89 @@
+package manager
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+)
+
+// pendingFlushInterval is how often held notifications are checked against quiet hours.
+const pendingFlushInterval = time.Minute
+
+// StartPendingNotificationFlusher periodically sends notifications held during quiet hours.
+func (m *Manager) StartPendingNotificationFlusher(ctx context.Context) {
+	ticker := time.NewTicker(pendingFlushInterval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.FlushPendingNotifications(ctx)
+			}
+		}
+	}()
+}
+
+// FlushPendingNotifications sends held notifications for users whose quiet hours are over. They
+// are rebuilt from current availability, so sites booked overnight aren't announced; requests
+// that ended or were paused in the meantime are dropped.
+func (m *Manager) FlushPendingNotifications(ctx context.Context) {
+	pending, err := m.store.ListPendingNotifications(ctx)
+	if err != nil {
+		m.logger.Error("failed to list pending notifications", slog.Any("err", err))
+		return
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	active, err := m.store.ListActiveRequests(ctx)
+	if err != nil {
+		m.logger.Error("failed to list active requests", slog.Any("err", err))
+		return
+	}
+	requests := indexRequestsByID(active)
+
+	now := time.Now()
+	prefsByUser := make(map[string]db.NotificationPrefs)
+	for _, p := range pending {
+		prefs, ok := prefsByUser[p.UserID]
+		if !ok {
+			prefs, err = m.store.GetNotificationPrefs(ctx, p.UserID)
+			if err != nil {
+				m.logger.Warn("get notification prefs failed", slog.String("userID", p.UserID), slog.Any("err", err))
+				continue
+			}
+			prefsByUser[p.UserID] = prefs
+		}
+		if prefs.InQuietHours(now) {
+			continue
+		}
+
+		if req, ok := requests[p.RequestID]; ok && req.UserID == p.UserID {
+			_, sent, err := m.deliverNotification(ctx, req, nil, true, false)
+			if discordErrorUndeliverable(err) {
+				m.markUndeliverable(ctx, req, err)
+			} else if err != nil {
+				// keep it queued and try again next tick
+				m.logger.Warn("send held notification failed", slog.String("userID", p.UserID), slog.Any("err", err))
+				continue
+			} else {
+				if sent {
+					m.broadcastFound(ctx, req.UserID)
+				}
+				m.logger.Info("flushed held notification",
+					slog.String("userID", p.UserID),
+					slog.Int64("requestID", p.RequestID),
+					slog.Bool("sent", sent))
+			}
+		}
+		if err := m.store.DeletePendingNotification(ctx, p.UserID, p.RequestID); err != nil {
+			m.logger.Warn("delete pending notification failed", slog.Any("err", err))
+		}
+	}
+}
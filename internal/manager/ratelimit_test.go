@@ -0,0 +1,89 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+// throttledProvider reports a slower rate limit than the default.
+type throttledProvider struct {
+	slowProvider
+}
+
+func (p *throttledProvider) Name() string { return "throttled" }
+func (p *throttledProvider) RateLimit() (min, increment time.Duration) {
+	return 30 * time.Second, 5 * time.Second
+}
+
+func TestProviderRateLimitFloor(t *testing.T) {
+	reg := providers.NewRegistry()
+	reg.Register("throttled", &throttledProvider{})
+	reg.Register("default", &slowProvider{})
+	m := NewManager(nil, reg, nil, "")
+
+	min, increment := m.providerRateLimit("throttled")
+	if min != 30*time.Second || increment != 5*time.Second {
+		t.Fatalf("Expected the provider's own limits, got %v / %v", min, increment)
+	}
+	if min, _ := m.providerRateLimit("default"); min != providers.DefaultMinPollInterval {
+		t.Errorf("Expected default min interval, got %v", min)
+	}
+
+	// however polls go, the interval never drops below the provider's minimum
+	interval := min
+	for i, pollErr := range []error{providers.ErrRateLimited, providers.ErrRateLimited, nil, providers.ErrRateLimited, nil, nil, providers.ErrRateLimited} {
+		interval = nextPollInterval(interval, min, increment, pollErr)
+		if interval < min {
+			t.Fatalf("Poll %d: interval %v is faster than the 30s minimum", i, interval)
+		}
+	}
+	if got := nextPollInterval(time.Second, min, increment, providers.ErrRateLimited); got != min+increment {
+		t.Errorf("Expected backoff to start from the minimum, got %v", got)
+	}
+
+	// the loop itself starts at the provider's minimum rather than the default
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		m.runProviderLoop(ctx, "throttled")
+		close(done)
+	}()
+	deadline := time.Now().Add(time.Second)
+	for m.PollIntervals()["throttled"] == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+	if got := m.PollIntervals()["throttled"]; got != 30*time.Second {
+		t.Errorf("Expected the loop to start at 30s, got %v", got)
+	}
+}
+
+func TestNextPollIntervalByErrorKind(t *testing.T) {
+	min, increment := 10*time.Second, 5*time.Second
+	current := 20 * time.Second
+	wrapped := fmt.Errorf("slow/cg1: %w", fmt.Errorf("%w: availability status 429", providers.ErrRateLimited))
+
+	tests := []struct {
+		name string
+		err  error
+		want time.Duration
+	}{
+		{"success", nil, min},
+		{"rate limited", providers.ErrRateLimited, current + increment},
+		{"rate limited among other failures", errors.Join(errors.New("slow/cg2: bad json"), wrapped), current + increment},
+		{"not found", fmt.Errorf("slow/cg1: %w", providers.ErrNotFound), min},
+		{"transient", fmt.Errorf("slow/cg1: %w", providers.ErrTransient), min},
+		{"decode error", errors.New("availability JSON decode failed"), min},
+	}
+	for _, tt := range tests {
+		if got := nextPollInterval(current, min, increment, tt.err); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package manager
+
+import (
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+)
+
+// campgroundPollInterval is how often a campground is polled when its nearest checkin is lead
+// away. Close checkins churn the most, so they are polled the most. The provider loop still never
+// ticks faster than the provider's rate limit.
+func campgroundPollInterval(lead time.Duration) time.Duration {
+	switch {
+	case lead < 48*time.Hour:
+		return 10 * time.Second
+	case lead < 7*24*time.Hour:
+		return time.Minute
+	case lead <= 30*24*time.Hour:
+		return 10 * time.Minute
+	default:
+		return time.Hour
+	}
+}
+
+// nearestCheckin returns the earliest checkin among reqs.
+func nearestCheckin(reqs []db.SchniffRequest) time.Time {
+	var nearest time.Time
+	for _, r := range reqs {
+		if nearest.IsZero() || r.Checkin.Before(nearest) {
+			nearest = r.Checkin
+		}
+	}
+	return nearest
+}
+
+// campgroundPoll is when a campground was last polled and the requests it was polled for.
+type campgroundPoll struct {
+	at       time.Time
+	requests map[int64]bool
+}
+
+// dueCampgrounds returns the campgrounds in datesByPC that are due a poll and records the poll. A
+// campground is due once the interval for its nearest checkin in reqsByPC has passed since its last
+// poll, worked out afresh each cycle so a nearer checkin brings it forward, or straight away when it
+// has a request it wasn't last polled for. Campgrounds that are no longer requested are forgotten so
+// they start fresh if requested again.
+func (m *Manager) dueCampgrounds(provider string, datesByPC map[pc]map[time.Time]struct{}, reqsByPC map[pc][]db.SchniffRequest, now time.Time) map[pc]map[time.Time]struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.polled == nil {
+		m.polled = make(map[pc]campgroundPoll)
+	}
+	for k := range m.polled {
+		if _, ok := datesByPC[k]; !ok && k.prov == provider {
+			delete(m.polled, k)
+		}
+	}
+
+	due := make(map[pc]map[time.Time]struct{})
+	for k, dates := range datesByPC {
+		reqs := reqsByPC[k]
+		if last, ok := m.polled[k]; ok && !hasNewRequest(last, reqs) &&
+			now.Before(last.at.Add(campgroundPollInterval(nearestCheckin(reqs).Sub(now)))) {
+			continue
+		}
+		due[k] = dates
+		poll := campgroundPoll{at: now, requests: make(map[int64]bool, len(reqs))}
+		for _, r := range reqs {
+			poll.requests[r.ID] = true
+		}
+		m.polled[k] = poll
+	}
+	return due
+}
+
+// hasNewRequest reports whether any of reqs wasn't part of the last poll.
+func hasNewRequest(last campgroundPoll, reqs []db.SchniffRequest) bool {
+	for _, r := range reqs {
+		if !last.requests[r.ID] {
+			return true
+		}
+	}
+	return false
+}
+
+// retryNextCycle makes a campground due again straight away, e.g. after its fetch failed.
+func (m *Manager) retryNextCycle(k pc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.polled, k)
+}
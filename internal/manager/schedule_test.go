@@ -0,0 +1,92 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+)
+
+func TestCampgroundPollInterval(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		lead time.Duration
+		want time.Duration
+	}{
+		{-time.Hour, 10 * time.Second},
+		{0, 10 * time.Second},
+		{12 * time.Hour, 10 * time.Second},
+		{48*time.Hour - time.Second, 10 * time.Second},
+		{48 * time.Hour, time.Minute},
+		{5 * day, time.Minute},
+		{7 * day, 10 * time.Minute},
+		{30 * day, 10 * time.Minute},
+		{30*day + time.Second, time.Hour},
+		{180 * day, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := campgroundPollInterval(tt.lead); got != tt.want {
+			t.Errorf("campgroundPollInterval(%v) = %v, want %v", tt.lead, got, tt.want)
+		}
+	}
+}
+
+func TestDueCampgrounds(t *testing.T) {
+	m := &Manager{}
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	soon, later := pc{"p", "soon"}, pc{"p", "later"}
+	dates := map[pc]map[time.Time]struct{}{soon: {}, later: {}}
+	reqs := map[pc][]db.SchniffRequest{
+		soon:  {{Checkin: now.Add(24 * time.Hour)}, {Checkin: now.Add(90 * 24 * time.Hour)}},
+		later: {{Checkin: now.Add(90 * 24 * time.Hour)}},
+	}
+
+	if due := m.dueCampgrounds("p", dates, reqs, now); len(due) != 2 {
+		t.Fatalf("first cycle should poll everything, got %v", due)
+	}
+	if due := m.dueCampgrounds("p", dates, reqs, now.Add(5*time.Second)); len(due) != 0 {
+		t.Fatalf("nothing should be due after 5s, got %v", due)
+	}
+	due := m.dueCampgrounds("p", dates, reqs, now.Add(10*time.Second))
+	if _, ok := due[soon]; !ok || len(due) != 1 {
+		t.Fatalf("only the campground with a checkin tomorrow should be due after 10s, got %v", due)
+	}
+
+	m.retryNextCycle(later)
+	if due := m.dueCampgrounds("p", dates, reqs, now.Add(11*time.Second)); len(due) != 1 {
+		t.Fatalf("a failed campground should be due again, got %v", due)
+	}
+
+	delete(dates, later)
+	m.dueCampgrounds("p", dates, reqs, now.Add(12*time.Second))
+	if _, ok := m.polled[later]; ok {
+		t.Fatal("campgrounds no longer requested should be forgotten")
+	}
+}
+
+func TestDueCampgroundsFollowsRequestChanges(t *testing.T) {
+	m := &Manager{}
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	k := pc{"p", "cg"}
+	dates := map[pc]map[time.Time]struct{}{k: {}}
+	reqs := map[pc][]db.SchniffRequest{k: {{ID: 1, Checkin: now.Add(90 * 24 * time.Hour)}}}
+
+	if due := m.dueCampgrounds("p", dates, reqs, now); len(due) != 1 {
+		t.Fatalf("first cycle should poll the campground, got %v", due)
+	}
+
+	// a new request is polled for straight away, even with a far checkin
+	reqs[k] = append(reqs[k], db.SchniffRequest{ID: 2, Checkin: now.Add(60 * 24 * time.Hour)})
+	if due := m.dueCampgrounds("p", dates, reqs, now.Add(time.Second)); len(due) != 1 {
+		t.Fatalf("a new request should make the campground due, got %v", due)
+	}
+	if due := m.dueCampgrounds("p", dates, reqs, now.Add(2*time.Second)); len(due) != 0 {
+		t.Fatalf("nothing should be due right after polling, got %v", due)
+	}
+
+	// an existing request moving to a checkin tomorrow (e.g. reactivated) shortens the interval
+	reqs[k][0].Checkin = now.Add(24 * time.Hour)
+	if due := m.dueCampgrounds("p", dates, reqs, now.Add(11*time.Second)); len(due) != 1 {
+		t.Fatalf("a nearer checkin should bring the next poll forward, got %v", due)
+	}
+}
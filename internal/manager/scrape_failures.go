@@ -0,0 +1,74 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/logctx"
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+// scrapeFailureCooldown is how long a campground past db.ScrapeFailureThreshold is left alone
+// before it gets another try. Each failed try restarts the cooldown.
+const scrapeFailureCooldown = 6 * time.Hour
+
+// scrapeFailures returns the provider's campgrounds with failed fetches on record.
+func (m *Manager) scrapeFailures(ctx context.Context, provider string) map[pc]db.ScrapeFailure {
+	failures, err := m.store.ListScrapeFailures(ctx, 1)
+	if err != nil {
+		m.logger.Warn("failed to load scrape failures", logctx.Attr(ctx), slog.Any("err", err))
+		return nil
+	}
+	out := make(map[pc]db.ScrapeFailure)
+	for _, f := range failures {
+		if f.Provider == provider {
+			out[pc{prov: f.Provider, cg: f.CampgroundID}] = f
+		}
+	}
+	return out
+}
+
+// withoutFailingCampgrounds drops campgrounds that have failed too many fetches in a row, until
+// their cooldown since the last failure has passed.
+func withoutFailingCampgrounds(datesByPC map[pc]map[time.Time]struct{}, failures map[pc]db.ScrapeFailure, now time.Time) map[pc]map[time.Time]struct{} {
+	if len(failures) == 0 {
+		return datesByPC
+	}
+	out := make(map[pc]map[time.Time]struct{}, len(datesByPC))
+	for k, dates := range datesByPC {
+		f, ok := failures[k]
+		if ok && f.ConsecutiveFailures >= db.ScrapeFailureThreshold && now.Sub(f.LastFailedAt) < scrapeFailureCooldown {
+			continue
+		}
+		out[k] = dates
+	}
+	return out
+}
+
+// recordScrapeResult counts a failed fetch against the campground, or clears its failures after a
+// successful one. Rate limiting and cancellation say nothing about the campground, so they aren't
+// counted.
+func (m *Manager) recordScrapeResult(ctx context.Context, k pc, fetchErr error, hadFailures bool) {
+	var err error
+	switch {
+	case fetchErr == nil:
+		if !hadFailures {
+			return
+		}
+		err = m.executeDBOperation(func() error {
+			return m.store.ClearScrapeFailures(ctx, k.prov, k.cg)
+		})
+	case errors.Is(fetchErr, providers.ErrRateLimited), ctx.Err() != nil:
+		return
+	default:
+		err = m.executeDBOperation(func() error {
+			return m.store.RecordScrapeFailure(ctx, k.prov, k.cg, fetchErr.Error(), time.Now())
+		})
+	}
+	if err != nil {
+		m.logger.Warn("failed to record scrape result", logctx.Attr(ctx), slog.String("provider", k.prov), slog.String("campground", k.cg), slog.Any("err", err))
+	}
+}
@@ -0,0 +1,84 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+)
+
+func TestWithoutFailingCampgrounds(t *testing.T) {
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	ok, flaky, dead, cooled := pc{"p", "ok"}, pc{"p", "flaky"}, pc{"p", "dead"}, pc{"p", "cooled"}
+	dates := map[pc]map[time.Time]struct{}{ok: {}, flaky: {}, dead: {}, cooled: {}}
+	failures := map[pc]db.ScrapeFailure{
+		flaky:  {ConsecutiveFailures: db.ScrapeFailureThreshold - 1, LastFailedAt: now},
+		dead:   {ConsecutiveFailures: db.ScrapeFailureThreshold, LastFailedAt: now.Add(-time.Hour)},
+		cooled: {ConsecutiveFailures: db.ScrapeFailureThreshold + 5, LastFailedAt: now.Add(-scrapeFailureCooldown)},
+	}
+
+	got := withoutFailingCampgrounds(dates, failures, now)
+	for k, want := range map[pc]bool{ok: true, flaky: true, dead: false, cooled: true} {
+		if _, polled := got[k]; polled != want {
+			t.Errorf("%s polled = %v, want %v", k.cg, polled, want)
+		}
+	}
+}
+
+func TestPollProvider_SkipsPersistentlyFailingCampgrounds(t *testing.T) {
+	m, prov := newPollTestManager(t, 2)
+	ctx := context.Background()
+	prov.fail = map[string]bool{"cg0": true}
+
+	poll := func() {
+		t.Helper()
+		m.polled = nil // ignore the adaptive schedule, every campground is due
+		m.PollProvider(ctx, "slow")
+	}
+	failuresFor := func(cg string) int {
+		t.Helper()
+		failures, err := m.store.ListScrapeFailures(ctx, 1)
+		if err != nil {
+			t.Fatalf("ListScrapeFailures: %v", err)
+		}
+		for _, f := range failures {
+			if f.CampgroundID == cg {
+				return f.ConsecutiveFailures
+			}
+		}
+		return 0
+	}
+
+	for i := 0; i < db.ScrapeFailureThreshold; i++ {
+		poll()
+	}
+	if got := failuresFor("cg0"); got != db.ScrapeFailureThreshold {
+		t.Fatalf("Expected %d failures recorded for cg0, got %d", db.ScrapeFailureThreshold, got)
+	}
+	if got := failuresFor("cg1"); got != 0 {
+		t.Errorf("Expected no failures for the healthy campground, got %d", got)
+	}
+
+	// past the threshold only the healthy campground is fetched
+	before := prov.calls.Load()
+	poll()
+	if got := prov.calls.Load() - before; got != 1 {
+		t.Errorf("Expected the failing campground to be skipped, got %d fetches", got)
+	}
+
+	// once the cooldown passes it's tried again, and a success clears its record
+	_, err := m.store.DB.Exec(`UPDATE scrape_failures SET last_failed_at = ?`, time.Now().Add(-scrapeFailureCooldown-time.Minute).UTC())
+	if err != nil {
+		t.Fatalf("backdate failure: %v", err)
+	}
+	prov.fail = nil
+	before = prov.calls.Load()
+	poll()
+	if got := prov.calls.Load() - before; got != 2 {
+		t.Errorf("Expected both campgrounds fetched after the cooldown, got %d fetches", got)
+	}
+	if got := failuresFor("cg0"); got != 0 {
+		t.Errorf("Expected a successful fetch to clear the failures, got %d", got)
+	}
+}
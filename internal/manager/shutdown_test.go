@@ -0,0 +1,86 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShutdown_FlushesQueuedWrites(t *testing.T) {
+	m := NewManager(nil, nil, nil, "")
+
+	// The first write holds the writer so the rest pile up in the queue
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var ran atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		m.executeDBOperation(func() error {
+			close(started)
+			<-release
+			ran.Add(1)
+			return nil
+		})
+	}()
+	<-started
+
+	const queued = 10
+	for i := 0; i < queued; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := m.executeDBOperation(func() error { ran.Add(1); return nil }); err != nil {
+				t.Errorf("queued write failed: %v", err)
+			}
+		}()
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(m.dbWriteChan) < queued {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d writes queued, got %d", queued, len(m.dbWriteChan))
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- m.Shutdown(context.Background()) }()
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	wg.Wait()
+	if got := ran.Load(); got != queued+1 {
+		t.Errorf("Expected all %d writes to run before Shutdown returned, got %d", queued+1, got)
+	}
+
+	if err := m.executeDBOperation(func() error { return nil }); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("Expected ErrShuttingDown after Shutdown, got %v", err)
+	}
+	if err := m.Shutdown(context.Background()); err != nil {
+		t.Errorf("Expected a second Shutdown to be a no-op, got %v", err)
+	}
+}
+
+func TestShutdown_GivesUpAtDeadline(t *testing.T) {
+	m := NewManager(nil, nil, nil, "")
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	go m.executeDBOperation(func() error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := m.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline error while a write is stuck, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// SetSummaryChannel posts summaries and alerts to channelID instead of the channel given to
+// NewManager. An empty ID keeps that channel.
+func (m *Manager) SetSummaryChannel(channelID string) {
+	m.summaryOverride = channelID
+}
+
+// SetSummaryFallbackUser DMs userID (e.g. the guild owner) whatever can't be posted to the summary
+// channel, such as when the bot lacks permission to post there.
+func (m *Manager) SetSummaryFallbackUser(userID string) {
+	m.summaryFallback = userID
+}
+
+// GetSummaryChannel returns the channel summaries are posted to.
+func (m *Manager) GetSummaryChannel() string {
+	return summaryChannel(m.summaryOverride, m.summaryChannelID)
+}
+
+// summaryChannel picks the configured override over the guild's default channel.
+func summaryChannel(override, guildDefault string) string {
+	if override != "" {
+		return override
+	}
+	return guildDefault
+}
+
+// sendSummary posts content to the summary channel, falling back to the fallback user's DMs.
+func (m *Manager) sendSummary(content string) error {
+	return m.postSummary(func(channelID string) error {
+		_, err := m.notifier.ChannelMessageSend(channelID, content)
+		return err
+	})
+}
+
+// sendSummaryEmbed posts embed to the summary channel, falling back to the fallback user's DMs.
+func (m *Manager) sendSummaryEmbed(embed *discordgo.MessageEmbed) error {
+	return m.postSummary(func(channelID string) error {
+		_, err := m.notifier.ChannelMessageSendEmbed(channelID, embed)
+		return err
+	})
+}
+
+// postSummary runs send against the summary channel. If that fails the failure is logged and, when
+// a fallback user is set, send is retried against their DMs.
+func (m *Manager) postSummary(send func(channelID string) error) error {
+	channelID := m.GetSummaryChannel()
+	err := retryDiscord(context.Background(), func() error { return send(channelID) })
+	if err == nil {
+		return nil
+	}
+	m.logger.Warn("summary channel send failed", slog.String("channelID", channelID), slog.Any("err", err))
+	if m.summaryFallback == "" {
+		return err
+	}
+	dm, dmErr := m.notifier.UserChannelCreate(m.summaryFallback)
+	if dmErr == nil {
+		dmErr = send(dm.ID)
+	}
+	if dmErr != nil {
+		return fmt.Errorf("summary channel: %w; fallback DM: %v", err, dmErr)
+	}
+	m.logger.Info("sent summary to fallback user instead", slog.String("userID", m.summaryFallback))
+	return nil
+}
@@ -0,0 +1,89 @@
+package manager
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestSummaryChannelPrecedence(t *testing.T) {
+	tests := []struct {
+		override, guildDefault, want string
+	}{
+		{"", "general", "general"},
+		{"alerts", "general", "alerts"},
+		{"alerts", "", "alerts"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		if got := summaryChannel(tt.override, tt.guildDefault); got != tt.want {
+			t.Errorf("summaryChannel(%q, %q) = %q, want %q", tt.override, tt.guildDefault, got, tt.want)
+		}
+	}
+
+	m := NewManager(nil, nil, nil, "general")
+	if got := m.GetSummaryChannel(); got != "general" {
+		t.Errorf("Expected the guild channel by default, got %q", got)
+	}
+	m.SetSummaryChannel("alerts")
+	if got := m.GetSummaryChannel(); got != "alerts" {
+		t.Errorf("Expected the override, got %q", got)
+	}
+	m.SetSummaryChannel("")
+	if got := m.GetSummaryChannel(); got != "general" {
+		t.Errorf("Expected clearing the override to restore the guild channel, got %q", got)
+	}
+}
+
+// forbiddenChannel refuses posts to one channel and records the paths of the posts it accepts.
+type forbiddenChannel struct {
+	channelID string
+	mu        sync.Mutex
+	posted    []string
+}
+
+func (f *forbiddenChannel) RoundTrip(r *http.Request) (*http.Response, error) {
+	status, body := http.StatusOK, `{"id":"1"}`
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/channels/"+f.channelID+"/messages"):
+		status, body = http.StatusForbidden, `{"code":50013,"message":"Missing Permissions"}`
+	case strings.HasSuffix(r.URL.Path, "/users/@me/channels"):
+		body = `{"id":"owner-dm"}`
+	default:
+		f.mu.Lock()
+		f.posted = append(f.posted, r.URL.Path)
+		f.mu.Unlock()
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    r,
+	}, nil
+}
+
+func TestSendSummary_FallsBackToDM(t *testing.T) {
+	discord := &forbiddenChannel{channelID: "locked"}
+	session, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("discordgo.New failed: %v", err)
+	}
+	session.Client = &http.Client{Transport: discord}
+	m := NewManager(nil, nil, session, "locked")
+
+	if err := m.sendSummary("hello"); err == nil {
+		t.Errorf("Expected an error without a fallback user")
+	}
+
+	m.SetSummaryFallbackUser("owner")
+	if err := m.sendSummary("hello"); err != nil {
+		t.Fatalf("Expected the fallback DM to succeed, got %v", err)
+	}
+	if len(discord.posted) != 1 || !strings.HasSuffix(discord.posted[0], "/channels/owner-dm/messages") {
+		t.Errorf("Expected one post to the owner's DMs, got %v", discord.posted)
+	}
+}
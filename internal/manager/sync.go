@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/providers"
+	"github.com/bwmarrin/discordgo"
 	"github.com/robfig/cron/v3"
 	"golang.org/x/time/rate"
 )
@@ -17,6 +20,7 @@ func (m *Manager) SyncCampgrounds(ctx context.Context, providerName string) (int
 	if !ok {
 		return 0, fmt.Errorf("unknown provider: %s", providerName)
 	}
+	defer m.notifySynced()
 
 	started := time.Now()
 
@@ -25,14 +29,18 @@ func (m *Manager) SyncCampgrounds(ctx context.Context, providerName string) (int
 	if err != nil {
 		return 0, err
 	}
-	count := 0
-	for _, cg := range all {
-		err := m.store.UpsertCampground(ctx, providerName, cg.ID, cg.Name, cg.Lat, cg.Lon, cg.Rating, cg.Amenities, cg.ImageURL, cg.PriceMin, cg.PriceMax, cg.PriceUnit)
-		if err != nil {
-			return count, err
-		}
-		count++
+	// Snapshot what we had before upserting, so campgrounds the provider stopped returning can be flagged
+	existing, err := m.store.GetCampgroundsByProvider(ctx, providerName)
+	if err != nil {
+		return 0, err
+	}
+
+	// Upserting replaces the rows, which also clears delisted_at if a campground came back
+	if err := m.store.UpsertCampgroundsBatch(ctx, providerName, all); err != nil {
+		return 0, err
 	}
+	count := len(all)
+	m.flagDelistedCampgrounds(ctx, providerName, existing, all)
 	err = m.store.RecordMetadataSync(ctx,
 		db.MetadataSyncLog{
 			SyncType:     db.MetadataSyncTypeAllCampgrounds,
@@ -59,6 +67,7 @@ func (m *Manager) SyncCampsites(ctx context.Context, providerName string) (int,
 	if !ok {
 		return 0, fmt.Errorf("unknown provider: %s", providerName)
 	}
+	defer m.notifySynced()
 
 	started := time.Now()
 
@@ -114,16 +123,10 @@ func (m *Manager) SyncCampsites(ctx context.Context, providerName string) (int,
 		}
 
 		// Fetch campsite metadata for this campground
-		campsiteInfos, err := prov.FetchCampsites(ctx, campground.ID)
+		campsiteInfos, err := m.fetchCampgroundCampsites(ctx, prov, campground)
 		if err != nil {
-			m.logger.Warn("failed to fetch campsite metadata",
-				slog.String("provider", providerName),
-				slog.String("campground", campground.ID),
-				slog.Any("err", err))
-
 			msg := fmt.Sprintf("⚠️ %s error while syncing campsite metadata for campground %s. Slowing down requests.", providerName, campground.ID)
-			_, err = m.notifier.ChannelMessageSend(m.GetSummaryChannel(), msg)
-			if err != nil {
+			if err := m.sendSummary(msg); err != nil {
 				m.logger.Warn("failed to send rate limit notification", slog.Any("err", err))
 			}
 
@@ -135,74 +138,8 @@ func (m *Manager) SyncCampsites(ctx context.Context, providerName string) (int,
 
 		}
 
-		// Store each campsite metadata
-		campgroundID := campground.ID
-		err = m.store.UpsertCampsiteMetadataBatch(ctx, providerName, campgroundID, campsiteInfos)
-		if err != nil {
-			m.logger.Warn("failed to store campsite metadata",
-				slog.String("provider", providerName),
-				slog.String("campground", campground.ID),
-				slog.Any("err", err))
-			return processed, fmt.Errorf("failed to store campsite metadata: %w", err)
-		}
-
-		// Extract unique campsite types and equipment from the fetched data
-		campsiteTypesSet := make(map[string]struct{})
-		equipmentSet := make(map[string]struct{})
-
-		for _, campsite := range campsiteInfos {
-			if campsite.Type != "" {
-				campsiteTypesSet[campsite.Type] = struct{}{}
-			}
-			for _, eq := range campsite.Equipment {
-				if eq != "" {
-					equipmentSet[eq] = struct{}{}
-				}
-			}
-		}
-
-		// Convert sets to slices
-		var campsiteTypes []string
-		for t := range campsiteTypesSet {
-			campsiteTypes = append(campsiteTypes, t)
-		}
-
-		var equipment []string
-		for e := range equipmentSet {
-			equipment = append(equipment, e)
-		}
-
-		// get max min price from campsites
-		var minPrice, maxPrice float64
-		for _, campsite := range campsiteInfos {
-			if campsite.CostPerNight < minPrice || minPrice == 0 {
-				minPrice = campsite.CostPerNight
-			}
-			if campsite.CostPerNight > maxPrice {
-				maxPrice = campsite.CostPerNight
-			}
-		}
-
-		// Update campground with aggregated campsite types and equipment
-		err = m.store.UpdateCampgroundBasedOnCampsites(ctx, providerName, campground.ID, campsiteTypes, equipment, minPrice, maxPrice)
-		if err != nil {
-			m.logger.Warn("failed to update campground with campsite data",
-				slog.String("provider", providerName),
-				slog.String("campground", campground.ID),
-				slog.Any("err", err))
-			// Don't skip - this is not critical
-		}
-
-		// Record successful sync for this campground
-		if err := m.store.RecordMetadataSync(ctx, db.MetadataSyncLog{
-			SyncType:     db.MetadataSyncTypeCampgroundMetadata,
-			Provider:     providerName,
-			CampgroundID: &campgroundID,
-			StartedAt:    time.Now(),
-			FinishedAt:   time.Now(),
-			Count:        len(campsiteInfos),
-		}); err != nil {
-			m.logger.Warn("record campground sync failed", slog.Any("err", err))
+		if err := m.storeCampgroundCampsites(ctx, prov, campground, campsiteInfos, rateLimiter); err != nil {
+			return processed, err
 		}
 
 		count++
@@ -221,6 +158,13 @@ func (m *Manager) SyncCampsites(ctx context.Context, providerName string) (int,
 		m.logger.Warn("record campsite sync failed", slog.Any("err", err))
 	}
 
+	// Some campsites come through without a type; infer one so they still show up in type filters
+	if backfilled, err := m.store.BackfillCampsiteTypes(ctx); err != nil {
+		m.logger.Warn("backfill campsite types failed", slog.Any("err", err))
+	} else if backfilled > 0 {
+		m.logger.Info("backfilled campsite types", slog.String("provider", providerName), slog.Int("count", backfilled))
+	}
+
 	m.logger.Info("campsite sync completed",
 		slog.String("provider", providerName),
 		slog.Int("campgrounds_processed", processed),
@@ -229,6 +173,138 @@ func (m *Manager) SyncCampsites(ctx context.Context, providerName string) (int,
 	return count, nil
 }
 
+// SyncCampsitesForCampground refreshes a single campground's campsite metadata straight away,
+// regardless of when it was last synced. It returns how many campsites the provider listed.
+func (m *Manager) SyncCampsitesForCampground(ctx context.Context, providerName, campgroundID string) (int, error) {
+	prov, ok := m.reg.Get(providerName)
+	if !ok {
+		return 0, fmt.Errorf("unknown provider: %s", providerName)
+	}
+	campground, found, err := m.store.GetCampgroundByID(ctx, providerName, campgroundID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get campground: %w", err)
+	}
+	if !found {
+		return 0, fmt.Errorf("unknown campground: %s/%s", providerName, campgroundID)
+	}
+	defer m.notifySynced()
+
+	campsiteInfos, err := m.fetchCampgroundCampsites(ctx, prov, campground)
+	if err != nil {
+		return 0, err
+	}
+	if err := m.storeCampgroundCampsites(ctx, prov, campground, campsiteInfos, rate.NewLimiter(rate.Inf, 1)); err != nil {
+		return 0, err
+	}
+	return len(campsiteInfos), nil
+}
+
+// fetchCampgroundCampsites fetches one campground's campsite metadata from its provider, logging
+// failures.
+func (m *Manager) fetchCampgroundCampsites(ctx context.Context, prov providers.Provider, campground db.Campground) ([]providers.CampsiteInfo, error) {
+	campsiteInfos, err := prov.FetchCampsites(ctx, campground.ID)
+	if err != nil {
+		m.logger.Warn("failed to fetch campsite metadata",
+			slog.String("provider", campground.Provider),
+			slog.String("campground", campground.ID),
+			slog.Any("err", err))
+	}
+	return campsiteInfos, err
+}
+
+// storeCampgroundCampsites stores one campground's fetched campsite metadata, updates the
+// campground's aggregated types, equipment and prices, and records the sync. rateLimiter paces the
+// pricing fallback fetch.
+func (m *Manager) storeCampgroundCampsites(ctx context.Context, prov providers.Provider, campground db.Campground, campsiteInfos []providers.CampsiteInfo, rateLimiter *rate.Limiter) error {
+	providerName := campground.Provider
+
+	// Store each campsite metadata
+	campgroundID := campground.ID
+	newCampsites, err := m.store.UpsertCampsiteMetadataBatch(ctx, providerName, campgroundID, campsiteInfos)
+	if err != nil {
+		m.logger.Warn("failed to store campsite metadata",
+			slog.String("provider", providerName),
+			slog.String("campground", campground.ID),
+			slog.Any("err", err))
+		return fmt.Errorf("failed to store campsite metadata: %w", err)
+	}
+	if len(newCampsites) > 0 {
+		m.logger.Info("new campsites detected",
+			slog.String("provider", providerName),
+			slog.String("campground", campground.ID),
+			slog.Any("campsites", newCampsites))
+		if m.announceNewCampsites {
+			m.announceCampsites(providerName, campground, newCampsites, campsiteInfos)
+		}
+	}
+
+	// Extract unique campsite types and equipment from the fetched data
+	campsiteTypesSet := make(map[string]struct{})
+	equipmentSet := make(map[string]struct{})
+
+	for _, campsite := range campsiteInfos {
+		if campsite.Type != "" {
+			campsiteTypesSet[campsite.Type] = struct{}{}
+		}
+		for _, eq := range campsite.Equipment {
+			if eq != "" {
+				equipmentSet[eq] = struct{}{}
+			}
+		}
+	}
+
+	// Convert sets to slices
+	var campsiteTypes []string
+	for t := range campsiteTypesSet {
+		campsiteTypes = append(campsiteTypes, t)
+	}
+
+	var equipment []string
+	for e := range equipmentSet {
+		equipment = append(equipment, e)
+	}
+
+	// Price range from campsite rates, falling back to the provider's campground-level pricing
+	// when campsites aren't priced (e.g. recreation.gov)
+	minPrice, maxPrice := providers.CampsitePriceRange(campsiteInfos)
+	priceUnit := ""
+	if maxPrice == 0 {
+		if err := rateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+		minPrice, maxPrice, priceUnit, err = providers.FetchPricing(ctx, prov, campground.ID, campsiteInfos)
+		if err != nil {
+			m.logger.Warn("failed to fetch campground pricing",
+				slog.String("provider", providerName),
+				slog.String("campground", campground.ID),
+				slog.Any("err", err))
+		}
+	}
+
+	// Update campground with aggregated campsite types and equipment
+	err = m.store.UpdateCampgroundBasedOnCampsites(ctx, providerName, campground.ID, campsiteTypes, equipment, minPrice, maxPrice, priceUnit)
+	if err != nil {
+		m.logger.Warn("failed to update campground with campsite data",
+			slog.String("provider", providerName),
+			slog.String("campground", campground.ID),
+			slog.Any("err", err))
+		// Don't skip - this is not critical
+	}
+
+	// Record successful sync for this campground
+	if err := m.store.RecordMetadataSync(ctx, db.MetadataSyncLog{
+		SyncType:     db.MetadataSyncTypeCampgroundMetadata,
+		Provider:     providerName,
+		CampgroundID: &campgroundID,
+		StartedAt:    time.Now(),
+		FinishedAt:   time.Now(),
+		Count:        len(campsiteInfos),
+	}); err != nil {
+		m.logger.Warn("record campground sync failed", slog.Any("err", err))
+	}
+	return nil
+}
+
 const (
 	metadataSyncCron = "0 4 1 * *" // 4am on 1st of the month
 )
@@ -247,7 +323,7 @@ func (m *Manager) RunCampgroundSync(ctx context.Context, provider string) {
 		m.logger.Info("no successful campground metadata sync found, running full sync", slog.String("provider", provider))
 		campgroundCount, err := m.SyncCampgrounds(ctx, provider)
 		if err != nil {
-			m.notifier.ChannelMessageSend(m.GetSummaryChannel(), fmt.Sprintf("⚠️ %s campground sync failed: %s", provider, err))
+			m.sendSummary(fmt.Sprintf("⚠️ %s campground sync failed: %s", provider, err))
 			// don't return because we should still attempt
 		}
 
@@ -265,7 +341,7 @@ func (m *Manager) RunCampgroundSync(ctx context.Context, provider string) {
 		m.logger.Info("no successful campsite metadata sync found, running full sync", slog.String("provider", provider))
 		campsiteCount, err := m.SyncCampsites(ctx, provider)
 		if err != nil {
-			m.notifier.ChannelMessageSend(m.GetSummaryChannel(), fmt.Sprintf("⚠️ %s campsite sync failed: %s", provider, err))
+			m.sendSummary(fmt.Sprintf("⚠️ %s campsite sync failed: %s", provider, err))
 			// don't return because we should still attempt
 		}
 
@@ -291,3 +367,135 @@ func (m *Manager) RunCampgroundSync(ctx context.Context, provider string) {
 	}
 
 }
+
+// maxDelistFraction caps how much of a provider's catalog a single sync may delist. A bigger drop is far
+// more likely a partial or broken upstream response than real delistings, so it's skipped.
+const maxDelistFraction = 0.2
+
+// delistedCampgrounds returns the previously listed campgrounds missing from this sync's results.
+func delistedCampgrounds(existing []db.Campground, listed []providers.CampgroundInfo) []db.Campground {
+	seen := make(map[string]bool, len(listed))
+	for _, cg := range listed {
+		seen[cg.ID] = true
+	}
+	var out []db.Campground
+	for _, cg := range existing {
+		if !seen[cg.ID] && cg.DelistedAt == nil {
+			out = append(out, cg)
+		}
+	}
+	return out
+}
+
+// flagDelistedCampgrounds marks campgrounds the provider no longer returns as delisted and lets users
+// with active schniffs on them know, since those schniffs will never fire.
+func (m *Manager) flagDelistedCampgrounds(ctx context.Context, providerName string, existing []db.Campground, listed []providers.CampgroundInfo) {
+	missing := delistedCampgrounds(existing, listed)
+	if len(missing) == 0 {
+		return
+	}
+	if len(listed) == 0 || float64(len(missing)) > maxDelistFraction*float64(len(existing)) {
+		m.logger.Warn("too many campgrounds missing from sync; not delisting",
+			slog.String("provider", providerName),
+			slog.Int("missing", len(missing)),
+			slog.Int("existing", len(existing)))
+		return
+	}
+
+	requests, err := m.store.ListActiveRequests(ctx)
+	if err != nil {
+		m.logger.Warn("list active requests failed", slog.Any("err", err))
+	}
+
+	for _, cg := range missing {
+		if err := m.store.SetCampgroundDelisted(ctx, providerName, cg.ID, true); err != nil {
+			m.logger.Warn("mark campground delisted failed",
+				slog.String("provider", providerName),
+				slog.String("campground", cg.ID),
+				slog.Any("err", err))
+			continue
+		}
+		m.logger.Info("campground delisted",
+			slog.String("provider", providerName),
+			slog.String("campground", cg.ID),
+			slog.String("name", cg.Name))
+
+		byUser := make(map[string][]int64)
+		for _, r := range requests {
+			if r.Provider == providerName && r.CampgroundID == cg.ID {
+				byUser[r.UserID] = append(byUser[r.UserID], r.ID)
+			}
+		}
+		for userID, ids := range byUser {
+			m.notifyDelisted(userID, cg, ids)
+		}
+	}
+}
+
+// notifyDelisted DMs a user that a campground they're schniffing is no longer listed.
+func (m *Manager) notifyDelisted(userID string, cg db.Campground, requestIDs []int64) {
+	channel, err := m.notifier.UserChannelCreate(userID)
+	if err != nil {
+		m.logger.Warn("failed to create DM channel", slog.String("userID", userID), slog.Any("err", err))
+		return
+	}
+	idStrs := make([]string, len(requestIDs))
+	for i, id := range requestIDs {
+		idStrs[i] = fmt.Sprintf("#%d", id)
+	}
+	msg := fmt.Sprintf("⚠️ %s is no longer listed by %s, so your schniffs on it (%s) probably won't find anything. "+
+		"They'll stay active in case it comes back; use `/schniff remove` if you want to drop them.",
+		cg.Name, cg.Provider, strings.Join(idStrs, ", "))
+	if _, err := m.notifier.ChannelMessageSend(channel.ID, msg); err != nil {
+		m.logger.Warn("failed to send delisted notice", slog.String("userID", userID), slog.Any("err", err))
+	}
+}
+
+// announceCampsites posts a broadcast note about campsites a provider has just added.
+func (m *Manager) announceCampsites(providerName string, campground db.Campground, newCampsites []string, infos []providers.CampsiteInfo) {
+	names := make(map[string]string, len(infos))
+	for _, info := range infos {
+		names[info.ID] = info.Name
+	}
+
+	const maxListed = 10
+	var lines []string
+	for i, id := range newCampsites {
+		if i == maxListed {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(newCampsites)-maxListed))
+			break
+		}
+		name := names[id]
+		if name == "" {
+			name = "Campsite " + id
+		}
+		lines = append(lines, fmt.Sprintf("[%s](%s)", name, m.CampsiteURL(providerName, campground.ID, id)))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("🆕 %d new campsites at %s", len(newCampsites), campground.Name),
+		URL:         m.CampgroundURL(providerName, campground.ID),
+		Description: strings.Join(lines, "\n"),
+		Color:       0xc47331,
+	}
+	if err := m.sendSummaryEmbed(embed); err != nil {
+		m.logger.Warn("failed to announce new campsites", slog.Any("err", err))
+	}
+}
+
+// OnSync registers fn to be called whenever a campground or campsite sync finishes, so callers
+// holding derived data (like the web server's filter options) can drop it.
+func (m *Manager) OnSync(fn func()) {
+	m.mu.Lock()
+	m.syncHooks = append(m.syncHooks, fn)
+	m.mu.Unlock()
+}
+
+func (m *Manager) notifySynced() {
+	m.mu.Lock()
+	hooks := append([]func(){}, m.syncHooks...)
+	m.mu.Unlock()
+	for _, fn := range hooks {
+		fn()
+	}
+}
@@ -0,0 +1,72 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+// campsiteProvider returns fixed campsites from FetchCampsites, or err if set.
+type campsiteProvider struct {
+	slowProvider
+	campsites []providers.CampsiteInfo
+	err       error
+}
+
+func (p *campsiteProvider) FetchCampsites(ctx context.Context, campgroundID string) ([]providers.CampsiteInfo, error) {
+	return p.campsites, p.err
+}
+
+func TestSyncCampsitesForCampground(t *testing.T) {
+	m, _ := newPollTestManager(t, 0)
+	ctx := context.Background()
+	prov := &campsiteProvider{campsites: []providers.CampsiteInfo{
+		{ID: "s1", Name: "Site 1", Type: "STANDARD NONELECTRIC", CostPerNight: 30, Equipment: []string{"Tent"}},
+		{ID: "s2", Name: "Site 2", Type: "RV ELECTRIC", CostPerNight: 45, Equipment: []string{"RV"}},
+	}}
+	m.reg.Register("mock", prov)
+	if err := m.store.UpsertCampground(ctx, "mock", "cg1", "Campground 1", 45, -120, 0, nil, "", 0, 0, ""); err != nil {
+		t.Fatalf("UpsertCampground failed: %v", err)
+	}
+
+	count, err := m.SyncCampsitesForCampground(ctx, "mock", "cg1")
+	if err != nil {
+		t.Fatalf("SyncCampsitesForCampground failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 campsites synced, got %d", count)
+	}
+
+	types, err := m.store.GetCampsiteTypes(ctx, "mock", "cg1")
+	if err != nil {
+		t.Fatalf("GetCampsiteTypes failed: %v", err)
+	}
+	if len(types) != 2 {
+		t.Errorf("Expected both campsite types stored, got %v", types)
+	}
+	cg, _, err := m.store.GetCampgroundByID(ctx, "mock", "cg1")
+	if err != nil {
+		t.Fatalf("GetCampgroundByID failed: %v", err)
+	}
+	if cg.PriceMin != 30 || cg.PriceMax != 45 {
+		t.Errorf("Expected campground priced 30-45, got %v-%v", cg.PriceMin, cg.PriceMax)
+	}
+
+	cgID := "cg1"
+	if _, ok, err := m.store.GetLastSuccessfulMetadataSync(ctx, db.MetadataSyncTypeCampgroundMetadata, "mock", &cgID); err != nil || !ok {
+		t.Errorf("Expected a metadata sync recorded for the campground, got ok=%v err=%v", ok, err)
+	}
+
+	if _, err := m.SyncCampsitesForCampground(ctx, "mock", "missing"); err == nil {
+		t.Error("Expected an error syncing an unknown campground")
+	}
+
+	upstream := errors.New("upstream down")
+	prov.err = upstream
+	if _, err := m.SyncCampsitesForCampground(ctx, "mock", "cg1"); !errors.Is(err, upstream) {
+		t.Errorf("Expected the provider error, got %v", err)
+	}
+}
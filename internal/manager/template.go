@@ -0,0 +1,145 @@
+package manager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Discord embed limits, enforced after templating so custom wording can't break a send.
+const (
+	embedTitleLimit       = 256
+	embedDescriptionLimit = 4096
+	embedFieldNameLimit   = 256
+	embedFieldValueLimit  = 1024
+	embedFooterLimit      = 2048
+	// embedTotalLimit caps the characters across all of one message's embeds.
+	embedTotalLimit = 6000
+)
+
+// NotificationTemplate holds the operator-editable wording of availability notifications.
+// Title, Description and Footer are text/template strings executed with NotificationTemplateData.
+type NotificationTemplate struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	InfoHeading string   `json:"info_heading"`
+	InfoLines   []string `json:"info_lines"`
+	Footer      string   `json:"footer"`
+
+	title, description, footer *template.Template
+}
+
+// NotificationTemplateData is what notification templates can reference.
+type NotificationTemplateData struct {
+	Header         string // random silly header
+	CampgroundName string
+	CampgroundURL  string
+	Checkin        string // formatted "Monday 2006-01-02"
+	Checkout       string
+	Nights         int
+}
+
+// DefaultNotificationTemplate reproduces the built-in notification wording.
+func DefaultNotificationTemplate() *NotificationTemplate {
+	t := &NotificationTemplate{
+		Title:       "{{.Header}}\n{{.CampgroundName}}",
+		Description: "[{{.Checkin}} ➡️ {{.Checkout}}]({{.CampgroundURL}})",
+		InfoHeading: "Important Information",
+		InfoLines: []string{
+			"🔗 Links go to booking pages",
+			"🏃‍♂️ Campsites at Yosemite book out in 2 minutes",
+			"⚠️ Opening links in mobile app goes to your last open page",
+			"\nWith 💖 from 🐽",
+		},
+	}
+	if err := t.compile(); err != nil {
+		panic(err) // defaults are constant, so this only fires on a programming error
+	}
+	return t
+}
+
+// LoadNotificationTemplate reads a JSON template file. Fields left empty keep their defaults,
+// and templates are test-rendered so a bad file fails at startup rather than on first send.
+func LoadNotificationTemplate(path string) (*NotificationTemplate, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notification template: %w", err)
+	}
+	t := DefaultNotificationTemplate()
+	if err := json.Unmarshal(raw, t); err != nil {
+		return nil, fmt.Errorf("failed to parse notification template: %w", err)
+	}
+	if err := t.compile(); err != nil {
+		return nil, err
+	}
+	sample := NotificationTemplateData{Header: "header", CampgroundName: "name", CampgroundURL: "https://example.com", Checkin: "in", Checkout: "out", Nights: 1}
+	for _, tmpl := range []*template.Template{t.title, t.description, t.footer} {
+		if tmpl == nil {
+			continue
+		}
+		if err := tmpl.Execute(&bytes.Buffer{}, sample); err != nil {
+			return nil, fmt.Errorf("notification template %q failed to render: %w", tmpl.Name(), err)
+		}
+	}
+	return t, nil
+}
+
+func (t *NotificationTemplate) compile() error {
+	var err error
+	if t.title, err = template.New("title").Parse(t.Title); err != nil {
+		return fmt.Errorf("invalid title template: %w", err)
+	}
+	if t.description, err = template.New("description").Parse(t.Description); err != nil {
+		return fmt.Errorf("invalid description template: %w", err)
+	}
+	// the footer is optional (and off by default)
+	t.footer = nil
+	if t.Footer != "" {
+		if t.footer, err = template.New("footer").Parse(t.Footer); err != nil {
+			return fmt.Errorf("invalid footer template: %w", err)
+		}
+	}
+	return nil
+}
+
+func newNotificationTemplateData(header, campgroundName, campgroundURL string, checkin, checkout time.Time) NotificationTemplateData {
+	const dateFmtISO = "Monday 2006-01-02"
+	return NotificationTemplateData{
+		Header:         header,
+		CampgroundName: campgroundName,
+		CampgroundURL:  campgroundURL,
+		Checkin:        checkin.Format(dateFmtISO),
+		Checkout:       checkout.Format(dateFmtISO),
+		Nights:         int(checkout.Sub(checkin).Hours() / 24),
+	}
+}
+
+// render executes tmpl and clips the result to limit runes. Templates are test-rendered on load,
+// so an execution error here renders empty rather than dropping the notification.
+func render(tmpl *template.Template, data NotificationTemplateData, limit int) string {
+	if tmpl == nil {
+		return ""
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return ""
+	}
+	return clip(b.String(), limit)
+}
+
+// info renders the information field value within Discord's field limit.
+func (t *NotificationTemplate) info() string {
+	return clip(strings.Join(t.InfoLines, "\n"), embedFieldValueLimit)
+}
+
+func clip(s string, limit int) string {
+	r := []rune(s)
+	if len(r) <= limit {
+		return s
+	}
+	return string(r[:limit-1]) + "…"
+}
@@ -0,0 +1,77 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestDefaultNotificationTemplateMatchesBuiltInWording(t *testing.T) {
+	checkin := time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)
+	stats := []CampsiteStats{{CampsiteID: "cs1", DaysAvailable: 1, TotalDays: 2, Dates: []time.Time{checkin}}}
+
+	embeds := BuildNotificationEmbeds(checkin, checkin.AddDate(0, 0, 2), "u", "Lakeside", "https://example.com/cg", "cg1", stats, nil)
+	if len(embeds) != 1 {
+		t.Fatalf("expected one embed, got %d", len(embeds))
+	}
+	e := embeds[0]
+	if !strings.HasSuffix(e.Title, "\nLakeside") {
+		t.Errorf("unexpected title: %q", e.Title)
+	}
+	if e.Description != "[Monday 2025-08-18 ➡️ Wednesday 2025-08-20](https://example.com/cg)" {
+		t.Errorf("unexpected description: %q", e.Description)
+	}
+	if e.Footer != nil {
+		t.Errorf("expected no footer by default, got %+v", e.Footer)
+	}
+	last := e.Fields[len(e.Fields)-1]
+	if last.Name != "Important Information" || !strings.Contains(last.Value, "With 💖 from 🐽") {
+		t.Errorf("unexpected info field: %+v", last)
+	}
+}
+
+func TestLoadNotificationTemplate(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write template: %v", err)
+		}
+		return path
+	}
+
+	long := strings.Repeat("x", 5000)
+	path := write("ok.json", `{"title": "Sites at {{.CampgroundName}} for {{.Nights}} nights", "footer": "`+long+`", "info_lines": ["book fast"]}`)
+	tmpl, err := LoadNotificationTemplate(path)
+	if err != nil {
+		t.Fatalf("LoadNotificationTemplate failed: %v", err)
+	}
+
+	checkin := time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)
+	stats := []CampsiteStats{{CampsiteID: "cs1", DaysAvailable: 1, TotalDays: 2, Dates: []time.Time{checkin}}}
+	e := BuildNotificationEmbedsWithTemplate(tmpl, checkin, checkin.AddDate(0, 0, 2), "u", "Lakeside", "https://example.com/cg", "cg1", stats, nil)[0]
+	if e.Title != "Sites at Lakeside for 2 nights" {
+		t.Errorf("unexpected title: %q", e.Title)
+	}
+	// unset fields keep their defaults
+	if !strings.HasPrefix(e.Description, "[Monday 2025-08-18") {
+		t.Errorf("expected default description, got %q", e.Description)
+	}
+	if e.Footer == nil || utf8.RuneCountInString(e.Footer.Text) != embedFooterLimit {
+		t.Errorf("expected footer clipped to %d runes", embedFooterLimit)
+	}
+	if last := e.Fields[len(e.Fields)-1]; last.Value != "book fast" {
+		t.Errorf("unexpected info field: %+v", last)
+	}
+
+	if _, err := LoadNotificationTemplate(write("syntax.json", `{"title": "{{.CampgroundName"}`)); err == nil {
+		t.Error("expected error for unparseable template")
+	}
+	if _, err := LoadNotificationTemplate(write("field.json", `{"title": "{{.Nope}}"}`)); err == nil {
+		t.Error("expected error for unknown template field")
+	}
+}
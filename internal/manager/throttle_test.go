@@ -0,0 +1,151 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/providers"
+	"github.com/bwmarrin/discordgo"
+)
+
+// fakeDiscord answers Discord API calls locally and remembers the DMs sent.
+type fakeDiscord struct {
+	mu  sync.Mutex
+	dms []string // request bodies posted to the DM channel
+}
+
+func (f *fakeDiscord) RoundTrip(r *http.Request) (*http.Response, error) {
+	body := `{"id":"1"}`
+	if r.Body != nil {
+		b, _ := io.ReadAll(r.Body)
+		if strings.Contains(r.URL.Path, "/channels/dm/messages") {
+			f.mu.Lock()
+			f.dms = append(f.dms, string(b))
+			f.mu.Unlock()
+		}
+	}
+	if strings.HasSuffix(r.URL.Path, "/users/@me/channels") {
+		body = `{"id":"dm"}`
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    r,
+	}, nil
+}
+
+func (f *fakeDiscord) sent() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.dms...)
+}
+
+func TestProcessNotifications_ThrottlesAfterDailyLimit(t *testing.T) {
+	ctx := context.Background()
+	store, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+
+	checkin := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 10)
+	reqID, err := store.AddRequest(ctx, db.SchniffRequest{
+		UserID: "user1", Provider: "p", CampgroundID: "cg1",
+		Checkin: checkin, Checkout: checkin.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		t.Fatalf("AddRequest failed: %v", err)
+	}
+	const limit = 2
+	if err := store.UpsertNotificationPrefs(ctx, db.NotificationPrefs{UserID: "user1", MaxPerDay: limit}); err != nil {
+		t.Fatalf("UpsertNotificationPrefs failed: %v", err)
+	}
+
+	discord := &fakeDiscord{}
+	session, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("discordgo.New failed: %v", err)
+	}
+	session.Client = &http.Client{Transport: discord}
+	m := NewManager(store, providers.NewRegistry(), session, "summary")
+
+	recorded := func() int {
+		t.Helper()
+		var n int
+		if err := store.DB.QueryRow(`SELECT count(*) FROM notifications WHERE request_id = ?`, reqID).Scan(&n); err != nil {
+			t.Fatalf("count notifications: %v", err)
+		}
+		return n
+	}
+
+	// Each round another site opens, producing one state change and one notification round.
+	// Flipping a single site would collide within a second on state_changes' changed_at. That
+	// limit isn't test-only: in production a site that flips back within the same second also
+	// loses the second state change to the UNIQUE constraint.
+	site := 0
+	flap := func(rounds int) {
+		t.Helper()
+		for i := 0; i < rounds; i++ {
+			err := store.UpsertCampsiteAvailabilityBatch(ctx, []db.CampsiteAvailability{{
+				Provider: "p", CampgroundID: "cg1", CampsiteID: fmt.Sprintf("s%d", site),
+				Date: checkin, Available: true, LastChecked: time.Now(),
+			}})
+			if err != nil {
+				t.Fatalf("UpsertCampsiteAvailabilityBatch failed: %v", err)
+			}
+			site++
+			reqs, err := store.ListActiveRequests(ctx)
+			if err != nil {
+				t.Fatalf("ListActiveRequests failed: %v", err)
+			}
+			if err := m.ProcessNotificationsWithBatches(ctx, reqs); err != nil {
+				t.Fatalf("ProcessNotificationsWithBatches failed: %v", err)
+			}
+			if got := recorded(); got != site {
+				t.Errorf("site %d: Expected %d state changes recorded, got %d", site, site, got)
+			}
+		}
+	}
+
+	// Keep flapping well past the cap: throttled rounds are recorded but don't count towards it
+	flap(limit + 4)
+
+	// limit notifications, then one throttled notice, then silence
+	dms := discord.sent()
+	if len(dms) != limit+1 {
+		t.Fatalf("Expected %d DMs, got %d: %q", limit+1, len(dms), dms)
+	}
+	for i, dm := range dms[:limit] {
+		if strings.Contains(dm, "muted") {
+			t.Errorf("DM %d: Expected a notification, got the throttled notice", i)
+		}
+	}
+	if !strings.Contains(dms[limit], "muted") {
+		t.Errorf("Expected the last DM to be the throttled notice, got %q", dms[limit])
+	}
+
+	// A day later the delivered rounds have aged out, so the request notifies again
+	if _, err := store.DB.Exec(`UPDATE notifications SET sent_at = datetime('now', '-25 hours')`); err != nil {
+		t.Fatalf("age notifications: %v", err)
+	}
+	if _, err := store.DB.Exec(`UPDATE schniff_requests SET throttle_notice_at = datetime('now', '-25 hours')`); err != nil {
+		t.Fatalf("age throttled notice: %v", err)
+	}
+	flap(limit + 1)
+	dms = discord.sent()[limit+1:]
+	if len(dms) != limit+1 {
+		t.Fatalf("Expected %d more DMs after the cap reset, got %d: %q", limit+1, len(dms), dms)
+	}
+	if strings.Contains(dms[0], "muted") || !strings.Contains(dms[limit], "muted") {
+		t.Errorf("Expected notifications then one throttled notice after the reset, got %q", dms)
+	}
+}
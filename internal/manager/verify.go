@@ -0,0 +1,125 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+// maxVerifySamples caps how many discrepancies a VerifyReport lists.
+const maxVerifySamples = 10
+
+// AvailabilityMismatch is a campsite/date where what we stored differs from a fresh fetch.
+// Stored or Live is nil when that side has no row for the campsite/date.
+type AvailabilityMismatch struct {
+	CampsiteID string
+	Date       time.Time
+	Stored     *bool
+	Live       *bool
+}
+
+// VerifyReport summarises how stored availability compares to a live fetch.
+type VerifyReport struct {
+	Provider     string
+	CampgroundID string
+	Start, End   time.Time
+	Compared     int // campsite/dates present on both sides
+	Differing    int // present on both sides with different availability
+	MissingLocal int // returned live but never stored
+	MissingLive  int // stored but not returned live
+	Samples      []AvailabilityMismatch
+}
+
+// Mismatches is the total number of discrepancies of any kind.
+func (r VerifyReport) Mismatches() int {
+	return r.Differing + r.MissingLocal + r.MissingLive
+}
+
+// VerifyCampground fetches live availability for a campground over [start, end] and compares it to
+// campsite_availability. It's a diagnostic only: nothing is written, not even the lookup log.
+func (m *Manager) VerifyCampground(ctx context.Context, provider, campgroundID string, start, end time.Time) (VerifyReport, error) {
+	prov, ok := m.reg.Get(provider)
+	if !ok {
+		return VerifyReport{}, fmt.Errorf("unknown provider %q", provider)
+	}
+	start, end = normalizeDay(start), normalizeDay(end)
+
+	var live []providers.CampsiteAvailability
+	for _, b := range prov.PlanBuckets(generateNights(start, end.AddDate(0, 0, 1), db.NightsAny, 0)) {
+		states, err := prov.FetchAvailability(ctx, campgroundID, b.Start, b.End)
+		if err != nil {
+			return VerifyReport{}, fmt.Errorf("failed to fetch availability: %w", err)
+		}
+		live = append(live, states...)
+	}
+
+	stored, err := m.store.GetStoredAvailability(ctx, provider, campgroundID, start, end)
+	if err != nil {
+		return VerifyReport{}, err
+	}
+
+	report := compareAvailability(stored, live, start, end)
+	report.Provider, report.CampgroundID = provider, campgroundID
+	return report, nil
+}
+
+// compareAvailability diffs stored and live states for dates in [start, end].
+// Providers return whole buckets, so live states outside the window are ignored.
+func compareAvailability(stored []db.CampsiteAvailability, live []providers.CampsiteAvailability, start, end time.Time) VerifyReport {
+	type key struct {
+		site string
+		date time.Time
+	}
+	storedByKey := make(map[key]bool, len(stored))
+	for _, s := range stored {
+		storedByKey[key{s.CampsiteID, normalizeDay(s.Date)}] = s.Available
+	}
+
+	report := VerifyReport{Start: start, End: end}
+	var mismatches []AvailabilityMismatch
+	seen := make(map[key]bool, len(live))
+	for _, l := range live {
+		k := key{l.ID, normalizeDay(l.Date)}
+		if k.date.Before(start) || k.date.After(end) || seen[k] {
+			continue
+		}
+		seen[k] = true
+		liveAvailable := l.Available
+		storedAvailable, ok := storedByKey[k]
+		switch {
+		case !ok:
+			report.MissingLocal++
+			mismatches = append(mismatches, AvailabilityMismatch{CampsiteID: k.site, Date: k.date, Live: &liveAvailable})
+		case storedAvailable != liveAvailable:
+			report.Compared++
+			report.Differing++
+			mismatches = append(mismatches, AvailabilityMismatch{CampsiteID: k.site, Date: k.date, Stored: &storedAvailable, Live: &liveAvailable})
+		default:
+			report.Compared++
+		}
+	}
+	for k, available := range storedByKey {
+		if seen[k] {
+			continue
+		}
+		storedAvailable := available
+		report.MissingLive++
+		mismatches = append(mismatches, AvailabilityMismatch{CampsiteID: k.site, Date: k.date, Stored: &storedAvailable})
+	}
+
+	sort.Slice(mismatches, func(i, j int) bool {
+		if !mismatches[i].Date.Equal(mismatches[j].Date) {
+			return mismatches[i].Date.Before(mismatches[j].Date)
+		}
+		return mismatches[i].CampsiteID < mismatches[j].CampsiteID
+	})
+	if len(mismatches) > maxVerifySamples {
+		mismatches = mismatches[:maxVerifySamples]
+	}
+	report.Samples = mismatches
+	return report
+}
@@ -0,0 +1,57 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+func TestCompareAvailability(t *testing.T) {
+	day := time.Date(2030, 7, 1, 0, 0, 0, 0, time.UTC)
+	next := day.AddDate(0, 0, 1)
+
+	stored := []db.CampsiteAvailability{
+		{CampsiteID: "a", Date: day, Available: true},
+		{CampsiteID: "b", Date: day, Available: true},
+		{CampsiteID: "c", Date: next, Available: false},
+	}
+	live := []providers.CampsiteAvailability{
+		{ID: "a", Date: day, Available: true},
+		{ID: "b", Date: day, Available: false},
+		{ID: "d", Date: next, Available: true},
+		// outside the window, ignored
+		{ID: "a", Date: day.AddDate(0, 0, 5), Available: true},
+	}
+
+	report := compareAvailability(stored, live, day, next)
+	if report.Compared != 2 || report.Differing != 1 || report.MissingLocal != 1 || report.MissingLive != 1 {
+		t.Fatalf("Unexpected counts: %+v", report)
+	}
+	if report.Mismatches() != 3 || len(report.Samples) != 3 {
+		t.Fatalf("Expected 3 mismatches, got %d with %d samples", report.Mismatches(), len(report.Samples))
+	}
+	first := report.Samples[0]
+	if first.CampsiteID != "b" || first.Stored == nil || !*first.Stored || first.Live == nil || *first.Live {
+		t.Errorf("Expected site b stored open / live booked first, got %+v", first)
+	}
+	if s := report.Samples[1]; s.CampsiteID != "c" || s.Live != nil {
+		t.Errorf("Expected site c missing live, got %+v", s)
+	}
+	if s := report.Samples[2]; s.CampsiteID != "d" || s.Stored != nil {
+		t.Errorf("Expected site d missing locally, got %+v", s)
+	}
+}
+
+func TestCompareAvailabilityCapsSamples(t *testing.T) {
+	day := time.Date(2030, 7, 1, 0, 0, 0, 0, time.UTC)
+	var live []providers.CampsiteAvailability
+	for i := 0; i < maxVerifySamples+5; i++ {
+		live = append(live, providers.CampsiteAvailability{ID: string(rune('a' + i)), Date: day, Available: true})
+	}
+	report := compareAvailability(nil, live, day, day)
+	if report.MissingLocal != maxVerifySamples+5 || len(report.Samples) != maxVerifySamples {
+		t.Errorf("Expected all mismatches counted but samples capped, got %d / %d", report.MissingLocal, len(report.Samples))
+	}
+}
@@ -0,0 +1,129 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/httpx"
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+// WebhookPayload is the JSON body POSTed to a user's webhook for each notification.
+type WebhookPayload struct {
+	RequestID      int64             `json:"request_id"`
+	Provider       string            `json:"provider"`
+	CampgroundID   string            `json:"campground_id"`
+	CampgroundName string            `json:"campground_name"`
+	CampgroundURL  string            `json:"campground_url,omitempty"`
+	Checkin        string            `json:"checkin"`
+	Checkout       string            `json:"checkout"`
+	Campsites      []WebhookCampsite `json:"campsites"`
+	SentAt         time.Time         `json:"sent_at"`
+}
+
+// WebhookCampsite is one available campsite in a WebhookPayload. Dates are the available nights.
+type WebhookCampsite struct {
+	CampsiteID   string   `json:"campsite_id"`
+	Name         string   `json:"name,omitempty"`
+	URL          string   `json:"url,omitempty"`
+	CostPerNight float64  `json:"cost_per_night,omitempty"`
+	Dates        []string `json:"dates"`
+}
+
+// webhookDeadline bounds a webhook post including retries. Posts run in the background, so a slow
+// endpoint doesn't hold up the user's DM.
+const webhookDeadline = 30 * time.Second
+
+// webhookClient retries throttled and failing endpoints like the provider clients do. Webhook URLs
+// come from users, so it only connects to public addresses, and gives up on slow ones quickly.
+var webhookClient = httpx.WithRetry(httpx.NewClient(
+	httpx.WithPublicAddressesOnly(),
+	httpx.WithTimeout(10*time.Second),
+	httpx.WithDialTimeout(5*time.Second),
+	httpx.WithTLSHandshakeTimeout(5*time.Second),
+), httpx.DefaultRetryPolicy)
+
+// buildWebhookPayload lists every matching campsite, unlike the Discord embed which shows the top 3.
+func buildWebhookPayload(req db.SchniffRequest, campground db.Campground, campgroundURL string, stats []CampsiteStats, provider providers.Provider, now time.Time) WebhookPayload {
+	p := WebhookPayload{
+		RequestID:      req.ID,
+		Provider:       req.Provider,
+		CampgroundID:   req.CampgroundID,
+		CampgroundName: campground.Name,
+		CampgroundURL:  campgroundURL,
+		Checkin:        req.Checkin.Format("2006-01-02"),
+		Checkout:       req.Checkout.Format("2006-01-02"),
+		Campsites:      make([]WebhookCampsite, 0, len(stats)),
+		SentAt:         now.UTC(),
+	}
+	for _, s := range stats {
+		site := WebhookCampsite{
+			CampsiteID:   s.CampsiteID,
+			Name:         s.Details.Name,
+			CostPerNight: s.CostPerNight,
+			Dates:        make([]string, len(s.Dates)),
+		}
+		if provider != nil {
+			site.URL = provider.CampsiteURL(req.CampgroundID, s.CampsiteID)
+		}
+		for i, d := range s.Dates {
+			site.Dates[i] = d.Format("2006-01-02")
+		}
+		p.Campsites = append(p.Campsites, site)
+	}
+	sort.Slice(p.Campsites, func(i, j int) bool { return p.Campsites[i].CampsiteID < p.Campsites[j].CampsiteID })
+	return p
+}
+
+// postWebhook sends the payload as JSON; any non-2xx response after retries is an error.
+func postWebhook(ctx context.Context, client *http.Client, url string, payload WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "schniffer-webhook")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// webhookNotification POSTs the notification to the user's webhook in the background, if they set
+// one. Like email, it is best-effort alongside the Discord DM.
+func (m *Manager) webhookNotification(ctx context.Context, req db.SchniffRequest, campground db.Campground, campgroundURL string, stats []CampsiteStats, provider providers.Provider) {
+	prefs, err := m.store.GetNotificationPrefs(ctx, req.UserID)
+	if err != nil {
+		m.logger.Warn("get notification prefs failed", slog.String("userID", req.UserID), slog.Any("err", err))
+		return
+	}
+	if prefs.WebhookURL == "" {
+		return
+	}
+	payload := buildWebhookPayload(req, campground, campgroundURL, stats, provider, time.Now())
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), webhookDeadline)
+		defer cancel()
+		if err := postWebhook(ctx, webhookClient, prefs.WebhookURL, payload); err != nil {
+			m.logger.Warn("webhook notification failed", slog.String("userID", req.UserID), slog.Any("err", err))
+		}
+	}()
+}
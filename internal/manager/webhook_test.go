@@ -0,0 +1,93 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/httpx"
+)
+
+func TestPostWebhookPayloadShape(t *testing.T) {
+	var got map[string]any
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("payload is not JSON: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	day := func(d int) time.Time { return time.Date(2025, 7, d, 0, 0, 0, 0, time.UTC) }
+	req := db.SchniffRequest{ID: 42, Provider: "recreation_gov", CampgroundID: "232447", Checkin: day(4), Checkout: day(7)}
+	stats := []CampsiteStats{
+		{CampsiteID: "012", Dates: []time.Time{day(4), day(5)}, Details: db.CampsiteDetails{Name: "Site 12"}, CostPerNight: 36},
+		{CampsiteID: "003", Dates: []time.Time{day(6)}},
+	}
+	payload := buildWebhookPayload(req, db.Campground{Name: "Upper Pines"}, "https://example.com/cg", stats, nil, day(1))
+
+	if err := postWebhook(context.Background(), srv.Client(), srv.URL, payload); err != nil {
+		t.Fatalf("postWebhook: %v", err)
+	}
+	if contentType != "application/json" {
+		t.Errorf("Content-Type = %q", contentType)
+	}
+
+	for key, want := range map[string]any{
+		"request_id":      float64(42),
+		"provider":        "recreation_gov",
+		"campground_id":   "232447",
+		"campground_name": "Upper Pines",
+		"campground_url":  "https://example.com/cg",
+		"checkin":         "2025-07-04",
+		"checkout":        "2025-07-07",
+	} {
+		if got[key] != want {
+			t.Errorf("%s = %v, want %v", key, got[key], want)
+		}
+	}
+	sites, ok := got["campsites"].([]any)
+	if !ok || len(sites) != 2 {
+		t.Fatalf("expected 2 campsites, got %v", got["campsites"])
+	}
+	first := sites[0].(map[string]any)
+	if first["campsite_id"] != "003" {
+		t.Errorf("campsites should be sorted by ID, first is %v", first["campsite_id"])
+	}
+	second := sites[1].(map[string]any)
+	dates, _ := second["dates"].([]any)
+	if second["name"] != "Site 12" || second["cost_per_night"] != float64(36) || len(dates) != 2 || dates[0] != "2025-07-04" {
+		t.Errorf("unexpected campsite entry: %v", second)
+	}
+}
+
+func TestPostWebhookRejectsErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	if err := postWebhook(context.Background(), srv.Client(), srv.URL, WebhookPayload{}); err == nil {
+		t.Fatal("expected an error for a 400 response")
+	}
+}
+
+func TestWebhookClientRefusesInternalAddresses(t *testing.T) {
+	reached := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { reached = true }))
+	defer srv.Close()
+
+	err := postWebhook(context.Background(), webhookClient, srv.URL, WebhookPayload{})
+	if !errors.Is(err, httpx.ErrNonPublicAddress) || reached {
+		t.Fatalf("expected the loopback webhook to be refused before connecting, got %v (reached %v)", err, reached)
+	}
+}
@@ -0,0 +1,46 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Fetch methods wrap these so callers can tell upstream throttling from other failures with
+// errors.Is. Errors wrapping none of them (e.g. a bad response body) are plain failures.
+var (
+	// ErrRateLimited means the provider is throttling us (HTTP 429), even after the client's retries.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrNotFound means the provider doesn't know the campground or campsite (HTTP 404 or 410).
+	ErrNotFound = errors.New("not found")
+	// ErrTransient means a network failure or server error (5xx) that may clear up on its own.
+	ErrTransient = errors.New("transient failure")
+)
+
+// statusError describes a non-200 response to what, wrapping the error kind its status maps to.
+func statusError(what string, code int, body []byte) error {
+	var kind error
+	switch {
+	case code == http.StatusTooManyRequests:
+		kind = ErrRateLimited
+	case code == http.StatusNotFound || code == http.StatusGone:
+		kind = ErrNotFound
+	case code >= 500:
+		kind = ErrTransient
+	}
+	msg := fmt.Sprintf("%s status %d; body: %s", what, code, clipBody(body))
+	if kind == nil {
+		return errors.New(msg)
+	}
+	return fmt.Errorf("%w: %s", kind, msg)
+}
+
+// requestError wraps a failed round trip to what as ErrTransient, leaving cancellation alone so
+// callers still see context.Canceled.
+func requestError(what string, err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%s failed: %w", what, err)
+	}
+	return fmt.Errorf("%w: %s failed: %w", ErrTransient, what, err)
+}
@@ -6,9 +6,10 @@ import (
 )
 
 type CampsiteAvailability struct {
-	ID        string
-	Date      time.Time
-	Available bool
+	ID           string
+	Date         time.Time
+	Available    bool
+	CostPerNight float64 // Price for this night in USD, 0 if the provider doesn't report it
 }
 
 type CampsiteInfo struct {
@@ -20,6 +21,8 @@ type CampsiteInfo struct {
 	Equipment       []string // Equipment types supported at this campsite
 	Amenities       []string // Individual campsite amenities
 	PreviewImageURL string   // Preview image URL
+	TypeOfUse       string   // "Overnight" or "Day" where the provider reports it
+	ReserveType     string   // e.g. "Site-Specific" or "Walk-Up" where the provider reports it
 }
 
 // type CampsiteMetadataProvider interface {
@@ -44,6 +47,84 @@ type Provider interface {
 	// the minimal set of upstream requests (inclusive day ranges) for this provider.
 	// The input dates are unique and normalized to YYYY-MM-DD UTC.
 	PlanBuckets(dates []time.Time) []DateRange
+	// RateLimit returns the fastest this provider should be polled and how much to back off
+	// after each failed poll. Embed BaseProvider for the defaults.
+	RateLimit() (min, increment time.Duration)
+	// MaxLookaheadDays returns how many days ahead of today the provider publishes availability.
+	// Nothing past it is scraped. Embed BaseProvider for DefaultMaxLookaheadDays.
+	MaxLookaheadDays() int
+}
+
+// Default polling limits, suitable for providers that tolerate frequent polling.
+const (
+	DefaultMinPollInterval = 10 * time.Second
+	DefaultPollIncrement   = 10 * time.Second
+)
+
+// DefaultMaxLookaheadDays is roughly the six month booking window most providers publish.
+const DefaultMaxLookaheadDays = 180
+
+// BaseProvider supplies default implementations of Provider methods that most providers don't need to customise.
+type BaseProvider struct{}
+
+// RateLimit returns DefaultMinPollInterval and DefaultPollIncrement.
+func (BaseProvider) RateLimit() (min, increment time.Duration) {
+	return DefaultMinPollInterval, DefaultPollIncrement
+}
+
+// MaxLookaheadDays returns DefaultMaxLookaheadDays.
+func (BaseProvider) MaxLookaheadDays() int {
+	return DefaultMaxLookaheadDays
+}
+
+// PricingProvider is implemented by providers that can report a campground's current nightly rates.
+type PricingProvider interface {
+	// FetchPricing returns the campground's current min and max price and the unit they're per (e.g. "night").
+	// campsites are the campground's campsites as already fetched, for providers that price from them.
+	FetchPricing(ctx context.Context, campgroundID string, campsites []CampsiteInfo) (min, max float64, unit string, err error)
+}
+
+// FetchPricing asks p for campground pricing, returning zeros if p doesn't implement PricingProvider.
+func FetchPricing(ctx context.Context, p Provider, campgroundID string, campsites []CampsiteInfo) (min, max float64, unit string, err error) {
+	pp, ok := p.(PricingProvider)
+	if !ok {
+		return 0, 0, "", nil
+	}
+	return pp.FetchPricing(ctx, campgroundID, campsites)
+}
+
+// CampsitePriceRange returns the min and max non-zero CostPerNight across campsites, or zeros if none are priced.
+func CampsitePriceRange(campsites []CampsiteInfo) (min, max float64) {
+	for _, c := range campsites {
+		if c.CostPerNight <= 0 {
+			continue
+		}
+		if min == 0 || c.CostPerNight < min {
+			min = c.CostPerNight
+		}
+		if c.CostPerNight > max {
+			max = c.CostPerNight
+		}
+	}
+	return min, max
+}
+
+// DescribedProvider is implemented by providers with a human-friendly name and website.
+type DescribedProvider interface {
+	// Label returns the provider's display name, e.g. "Recreation.gov".
+	Label() string
+	// BaseURL returns the provider's public website.
+	BaseURL() string
+}
+
+// Describe returns p's label and website, falling back to name and no website if p doesn't
+// implement DescribedProvider.
+func Describe(name string, p Provider) (label, baseURL string) {
+	dp, ok := p.(DescribedProvider)
+	if !ok {
+		return name, ""
+	}
+	return dp.Label(), dp.BaseURL()
 }
 
 // DateRange represents an inclusive date span [Start..End] at day granularity.
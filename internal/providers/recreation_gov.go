@@ -16,15 +16,23 @@ import (
 )
 
 type RecreationGov struct {
-	client *http.Client
+	BaseProvider
+	client    *http.Client
+	userAgent string // pinned User-Agent, empty to randomize
 }
 
 func NewRecreationGov() *RecreationGov {
-	return &RecreationGov{client: httpx.Default()}
+	return &RecreationGov{client: httpx.WithProxyRotation(), userAgent: httpx.PinnedUserAgent("recreation_gov")}
 }
 
 func (r *RecreationGov) Name() string { return "recreation_gov" }
 
+// Label implements providers.DescribedProvider
+func (r *RecreationGov) Label() string { return "Recreation.gov" }
+
+// BaseURL implements providers.DescribedProvider
+func (r *RecreationGov) BaseURL() string { return "https://www.recreation.gov" }
+
 // CampsiteURL implements providers.Provider
 func (r *RecreationGov) CampsiteURL(_ string, campsiteID string) string {
 	if campsiteID == "" {
@@ -49,9 +57,14 @@ type recGovResp struct {
 	} `json:"campsites"`
 }
 
-// FetchAvailability fetches monthly availability pages between start and end (inclusive by month).
+// FetchAvailability fetches the monthly availability pages covering start and end, returning
+// only the days in [start, end] (inclusive, by UTC day). Each campsite/day appears once.
 func (r *RecreationGov) FetchAvailability(ctx context.Context, campgroundID string, start, end time.Time) ([]CampsiteAvailability, error) {
 	var out []CampsiteAvailability
+	start, end = start.UTC(), end.UTC()
+	startDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	endDay := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	seen := make(map[string]struct{})
 	cur := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
 	endMonth := time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, time.UTC)
 	for !cur.After(endMonth) {
@@ -66,11 +79,11 @@ func (r *RecreationGov) FetchAvailability(ctx context.Context, campgroundID stri
 		u.RawQuery = q.Encode()
 		slog.Info("Fetching availability", slog.String("url", u.String()))
 		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-		httpx.SpoofChromeHeaders(req)
+		httpx.SpoofChromeHeaders(req, r.userAgent)
 		resp, err := r.client.Do(req)
 		if err != nil {
 			slog.Error("availability GET failed", slog.Any("err", err))
-			return nil, fmt.Errorf("availability GET failed: %w", err)
+			return nil, requestError("availability GET", err)
 		}
 		body, err := io.ReadAll(resp.Body)
 		resp.Body.Close()
@@ -80,7 +93,7 @@ func (r *RecreationGov) FetchAvailability(ctx context.Context, campgroundID stri
 		}
 		if resp.StatusCode != http.StatusOK {
 			slog.Error("availability request failed, not ok", slog.Int("status", resp.StatusCode), slog.String("body", clipBody(body)))
-			return nil, fmt.Errorf("recreation.gov availability status %d; body: %s", resp.StatusCode, clipBody(body))
+			return nil, statusError("recreation.gov availability", resp.StatusCode, body)
 		}
 		var parsed recGovResp
 		err = json.Unmarshal(body, &parsed)
@@ -95,6 +108,16 @@ func (r *RecreationGov) FetchAvailability(ctx context.Context, campgroundID stri
 					slog.Error("bad date from rec.gov", slog.String("date", dateStr))
 					continue
 				}
+				// Pages cover whole months; drop days outside the request and any repeated across pages
+				day := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+				if day.Before(startDay) || day.After(endDay) {
+					continue
+				}
+				key := siteID + "|" + day.Format("2006-01-02")
+				if _, dup := seen[key]; dup {
+					continue
+				}
+				seen[key] = struct{}{}
 				out = append(out, CampsiteAvailability{
 					ID:        siteID,
 					Date:      d,
@@ -146,10 +169,10 @@ func (r *RecreationGov) FetchAllCampgrounds(ctx context.Context) ([]CampgroundIn
 		if err != nil {
 			return nil, err
 		}
-		httpx.SpoofChromeHeaders(req)
+		httpx.SpoofChromeHeaders(req, r.userAgent)
 		resp, err := r.client.Do(req)
 		if err != nil {
-			return nil, fmt.Errorf("search GET failed: %w", err)
+			return nil, requestError("search GET", err)
 		}
 		body, rerr := io.ReadAll(resp.Body)
 		resp.Body.Close()
@@ -157,7 +180,7 @@ func (r *RecreationGov) FetchAllCampgrounds(ctx context.Context) ([]CampgroundIn
 			return nil, fmt.Errorf("search read body failed: %w", rerr)
 		}
 		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("recreation.gov search status %d; body: %s", resp.StatusCode, clipBody(body))
+			return nil, statusError("recreation.gov search", resp.StatusCode, body)
 		}
 
 		var page struct {
@@ -282,16 +305,16 @@ func (r *RecreationGov) FetchCampsites(ctx context.Context, campgroundID string)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create campsite metadata request: %w", err)
 	}
-	httpx.SpoofChromeHeaders(req)
+	httpx.SpoofChromeHeaders(req, r.userAgent)
 
 	resp, err := r.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch campsite metadata: %w", err)
+		return nil, requestError("campsite metadata GET", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("campsite metadata request failed with status %d", resp.StatusCode)
+		return nil, statusError("campsite metadata", resp.StatusCode, nil)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -311,6 +334,8 @@ func (r *RecreationGov) FetchCampsites(ctx context.Context, campgroundID string)
 			} `json:"permitted_equipment"`
 			PreviewImageURL string `json:"preview_image_url"`
 			Reservable      bool   `json:"reservable"`
+			TypeOfUse       string `json:"type_of_use"`
+			ReserveType     string `json:"campsite_reserve_type"`
 		} `json:"campsites"`
 	}
 
@@ -343,6 +368,8 @@ func (r *RecreationGov) FetchCampsites(ctx context.Context, campgroundID string)
 			Equipment:       equipment,
 			Amenities:       []string{}, // No campsite-level amenities available in rec.gov API
 			PreviewImageURL: site.PreviewImageURL,
+			TypeOfUse:       site.TypeOfUse,
+			ReserveType:     site.ReserveType,
 		}
 		campsiteInfos = append(campsiteInfos, campsiteInfo)
 	}
@@ -353,3 +380,46 @@ func (r *RecreationGov) FetchCampsites(ctx context.Context, campgroundID string)
 
 	return campsiteInfos, nil
 }
+
+// FetchPricing implements PricingProvider using the price range the search API reports for a single campground.
+func (r *RecreationGov) FetchPricing(ctx context.Context, campgroundID string, _ []CampsiteInfo) (float64, float64, string, error) {
+	endpoint := fmt.Sprintf("https://www.recreation.gov/api/search?fq=entity_type%%3Acampground&fq=entity_id%%3A%s&size=1", url.QueryEscape(campgroundID))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	httpx.SpoofChromeHeaders(req, r.userAgent)
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return 0, 0, "", requestError("pricing GET", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("pricing read body failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, "", statusError("recreation.gov pricing", resp.StatusCode, body)
+	}
+
+	var page struct {
+		Results []struct {
+			EntityID   string `json:"entity_id"`
+			PriceRange struct {
+				AmountMax float64 `json:"amount_max"`
+				AmountMin float64 `json:"amount_min"`
+				PerUnit   string  `json:"per_unit"`
+			} `json:"price_range"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return 0, 0, "", fmt.Errorf("pricing JSON decode failed: %w; body: %s", err, clipBody(body))
+	}
+	for _, result := range page.Results {
+		if result.EntityID == campgroundID {
+			return result.PriceRange.AmountMin, result.PriceRange.AmountMax, result.PriceRange.PerUnit, nil
+		}
+	}
+	return 0, 0, "", nil
+}
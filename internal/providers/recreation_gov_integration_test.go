@@ -91,3 +91,41 @@ func TestRecreationGov_FetchAllCampgrounds_Paginates(t *testing.T) {
 		t.Fatalf("unexpected pagination calls: %v", calls)
 	}
 }
+
+func TestRecreationGov_FetchPricing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/search" {
+			http.NotFound(w, r)
+			return
+		}
+		fq := r.URL.Query()["fq"]
+		if len(fq) != 2 || fq[1] != "entity_id:232447" {
+			t.Errorf("unexpected fq params: %v", fq)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[{"entity_id":"232447","price_range":{"amount_min":22,"amount_max":36,"per_unit":"night"}}]}`))
+	}))
+	defer srv.Close()
+
+	targetURL, _ := url.Parse(srv.URL)
+	p := NewRecreationGov()
+	p.client = &http.Client{Transport: &rewriteTransport{target: targetURL}}
+
+	min, max, unit, err := FetchPricing(context.Background(), p, "232447", nil)
+	if err != nil {
+		t.Fatalf("FetchPricing error: %v", err)
+	}
+	if min != 22 || max != 36 || unit != "night" {
+		t.Errorf("got %v-%v per %q, want 22-36 per night", min, max, unit)
+	}
+}
+
+func TestCampsitePriceRange_IgnoresUnpriced(t *testing.T) {
+	min, max := CampsitePriceRange([]CampsiteInfo{{CostPerNight: 0}, {CostPerNight: 45}, {CostPerNight: 30}})
+	if min != 30 || max != 45 {
+		t.Errorf("got %v-%v, want 30-45", min, max)
+	}
+	if min, max := CampsitePriceRange(nil); min != 0 || max != 0 {
+		t.Errorf("got %v-%v for no campsites, want zeros", min, max)
+	}
+}
@@ -3,6 +3,7 @@ package providers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -68,3 +69,89 @@ func TestRecreationGov_FetchAvailability_URLQueryEncoded(t *testing.T) {
 		}
 	}
 }
+
+func TestRecreationGov_FetchAvailability_StatusErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusBadGateway, ErrTransient},
+		{http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "nope", tt.status)
+		}))
+		p := newRecreationGovForTest(t, srv)
+		start := time.Date(2024, 12, 15, 0, 0, 0, 0, time.UTC)
+		_, err := p.FetchAvailability(context.Background(), "12345", start, start)
+		srv.Close()
+
+		if err == nil {
+			t.Fatalf("status %d: expected an error", tt.status)
+		}
+		for _, kind := range []error{ErrRateLimited, ErrNotFound, ErrTransient} {
+			if got := errors.Is(err, kind); got != (kind == tt.want) {
+				t.Errorf("status %d: errors.Is(err, %v) = %v, err: %v", tt.status, kind, got, err)
+			}
+		}
+	}
+}
+
+func TestRecreationGov_FetchAvailability_MonthBoundary(t *testing.T) {
+	// Each page holds the whole month; February's also repeats Jan 31, which must not be returned twice.
+	pages := map[string]map[string]string{
+		"2025-01-01T00:00:00.000Z": {},
+		"2025-02-01T00:00:00.000Z": {"2025-01-31T00:00:00Z": "Reserved"},
+	}
+	for d := 1; d <= 31; d++ {
+		pages["2025-01-01T00:00:00.000Z"][time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)] = "Reserved"
+	}
+	for d := 1; d <= 28; d++ {
+		pages["2025-02-01T00:00:00.000Z"][time.Date(2025, 2, d, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)] = "Available"
+	}
+
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startDate := r.URL.Query().Get("start_date")
+		requested = append(requested, startDate)
+		avail, ok := pages[startDate]
+		if !ok {
+			http.Error(w, "unexpected month", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"campsites": map[string]any{
+			"site1": map[string]any{"availabilities": avail},
+		}})
+	}))
+	defer srv.Close()
+
+	p := newRecreationGovForTest(t, srv)
+	got, err := p.FetchAvailability(context.Background(), "12345", time.Date(2025, 1, 30, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("FetchAvailability returned error: %v", err)
+	}
+
+	if len(requested) != 2 || requested[0] != "2025-01-01T00:00:00.000Z" || requested[1] != "2025-02-01T00:00:00.000Z" {
+		t.Errorf("requested months %v, want January then February", requested)
+	}
+	want := map[string]bool{"2025-01-30": false, "2025-01-31": false, "2025-02-01": true, "2025-02-02": true}
+	if len(got) != len(want) {
+		t.Fatalf("got %d rows, want %d: %+v", len(got), len(want), got)
+	}
+	for _, a := range got {
+		day := a.Date.Format("2006-01-02")
+		available, ok := want[day]
+		if !ok {
+			t.Errorf("unexpected or duplicate day %s", day)
+			continue
+		}
+		if a.Available != available {
+			t.Errorf("%s available = %v, want %v", day, a.Available, available)
+		}
+		delete(want, day)
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/brensch/schniffer/internal/httpx"
 )
@@ -18,13 +20,28 @@ import (
 // ReserveCalifornia implements the Provider interface using the UseDirect endpoints.
 // Docs are inferred from examples in reservecalifornia_examples.md.
 type ReserveCalifornia struct {
-	client *http.Client
+	BaseProvider
+	client    *http.Client
+	userAgent string // pinned User-Agent, empty to randomize
 }
 
-func NewReserveCalifornia() *ReserveCalifornia { return &ReserveCalifornia{client: httpx.Default()} }
+// rcAPIHost serves every UseDirect API call. Its limit replaces the old fixed sleep between
+// campsite detail requests.
+const rcAPIHost = "calirdr.usedirect.com"
+
+func NewReserveCalifornia() *ReserveCalifornia {
+	httpx.SetHostLimit(rcAPIHost, 5, 1)
+	return &ReserveCalifornia{client: httpx.WithRetry(httpx.WithProxyRotation(), httpx.DefaultRetryPolicy), userAgent: httpx.PinnedUserAgent("reservecalifornia")}
+}
 
 func (r *ReserveCalifornia) Name() string { return "reservecalifornia" }
 
+// Label implements providers.DescribedProvider
+func (r *ReserveCalifornia) Label() string { return "ReserveCalifornia" }
+
+// BaseURL implements providers.DescribedProvider
+func (r *ReserveCalifornia) BaseURL() string { return "https://reservecalifornia.com" }
+
 // CampsiteURL returns a ReserveCalifornia URL for the campground.
 // campgroundID format: "parentID-facilityID" (e.g., "1260-2181")
 func (r *ReserveCalifornia) CampsiteURL(campgroundID string, _ string) string {
@@ -51,7 +68,17 @@ func (r *ReserveCalifornia) CampgroundURL(campgroundID string) string {
 	return fmt.Sprintf("https://reservecalifornia.com/Web/#!park/%s/%s", parentID, facilityID)
 }
 
-// PlanBuckets: ReserveCalifornia can query an arbitrary date range per facility, so collapse to a single [min..max] range.
+// rcMaxBucketDays caps a single grid request; very wide windows are slow or rejected by RC.
+const rcMaxBucketDays = 60
+
+// RateLimit polls UseDirect more gently than the default; it starts refusing requests well
+// before recreation.gov does.
+func (r *ReserveCalifornia) RateLimit() (min, increment time.Duration) {
+	return 30 * time.Second, 30 * time.Second
+}
+
+// PlanBuckets: ReserveCalifornia can query an arbitrary date range per facility, so collapse to a [min..max] range,
+// split into chunks of at most rcMaxBucketDays days.
 func (r *ReserveCalifornia) PlanBuckets(dates []time.Time) []DateRange {
 	if len(dates) == 0 {
 		return nil
@@ -70,7 +97,15 @@ func (r *ReserveCalifornia) PlanBuckets(dates []time.Time) []DateRange {
 			max = dd
 		}
 	}
-	return []DateRange{{Start: min, End: max}}
+	var buckets []DateRange
+	for start := min; !start.After(max); start = start.AddDate(0, 0, rcMaxBucketDays) {
+		end := start.AddDate(0, 0, rcMaxBucketDays-1)
+		if end.After(max) {
+			end = max
+		}
+		buckets = append(buckets, DateRange{Start: start, End: end})
+	}
+	return buckets
 }
 
 // gridRequest is the payload for the search/grid endpoint.
@@ -133,54 +168,16 @@ func (r *ReserveCalifornia) FetchAvailability(ctx context.Context, campgroundID
 	}
 	body, _ := json.Marshal(payload)
 
-	var intErr error
-	var parsed gridResponse
-	for i := 0; i < maxRetries; i++ {
-		req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://calirdr.usedirect.com/RDR/rdr/search/grid", bytes.NewReader(body))
-		if err != nil {
-			return nil, err
-		}
-
-		httpx.SpoofChromeHeaders(req)
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Origin", "https://reservecalifornia.com")
-		req.Header.Set("Referer", "https://reservecalifornia.com/")
-
-		time.Sleep(time.Duration(i) * 100 * time.Millisecond) // Exponential backoff
-
-		slog.Info("Fetching RC grid", slog.String("facility", facilityID), slog.String("start", payload.StartDate), slog.String("end", payload.EndDate))
-		resp, err := r.client.Do(req)
-		if err != nil {
-			slog.Warn("grid POST failed", slog.Any("err", err), slog.String("facility", campgroundID))
-			intErr = err
-			continue
-		}
-		b, rerr := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if rerr != nil {
-			slog.Warn("grid read body failed", slog.Any("err", rerr), slog.String("facility", campgroundID))
-			intErr = fmt.Errorf("grid read body failed: %w", rerr)
-			continue
-		}
-		if resp.StatusCode != http.StatusOK {
-			slog.Warn("grid status not OK", slog.Int("status", resp.StatusCode), slog.String("facility", campgroundID), slog.String("body", string(b)))
-			intErr = fmt.Errorf("grid status %d; body: %s", resp.StatusCode, clipBody(b))
-			continue
-		}
-
-		err = json.Unmarshal(b, &parsed)
-		if err != nil {
-			slog.Warn("grid JSON decode failed", slog.Any("err", err), slog.String("body", string(b)))
-			intErr = fmt.Errorf("grid JSON decode failed: %w; body: %s", err, clipBody(b))
-			continue
-		}
-
-		intErr = nil
-		break
+	slog.Info("Fetching RC grid", slog.String("facility", facilityID), slog.String("start", payload.StartDate), slog.String("end", payload.EndDate))
+	b, err := r.postUseDirect(ctx, "grid", "https://calirdr.usedirect.com/RDR/rdr/search/grid", body)
+	if err != nil {
+		slog.Warn("grid request failed", slog.Any("err", err), slog.String("facility", campgroundID))
+		return nil, err
 	}
-
-	if intErr != nil {
-		return nil, intErr
+	var parsed gridResponse
+	if err := json.Unmarshal(b, &parsed); err != nil {
+		slog.Warn("grid JSON decode failed", slog.Any("err", err), slog.String("body", clipBody(b)))
+		return nil, fmt.Errorf("grid JSON decode failed: %w; body: %s", err, clipBody(b))
 	}
 
 	var out []CampsiteAvailability
@@ -202,6 +199,33 @@ func (r *ReserveCalifornia) FetchAvailability(ctx context.Context, campgroundID
 	return out, nil
 }
 
+// postUseDirect POSTs a JSON body to a UseDirect endpoint and returns the response body. The client
+// already retries throttling and server errors with backoff, so a failure here is final.
+func (r *ReserveCalifornia) postUseDirect(ctx context.Context, what, url string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpx.SpoofChromeHeaders(req, r.userAgent)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Origin", "https://reservecalifornia.com")
+	req.Header.Set("Referer", "https://reservecalifornia.com/")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, requestError(what+" POST", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s read body failed: %w", what, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(what, resp.StatusCode, b)
+	}
+	return b, nil
+}
+
 // FetchAllCampgrounds enumerates city parks, then places and facilities to build a list of campgrounds keyed by FacilityId.
 func (r *ReserveCalifornia) FetchAllCampgrounds(ctx context.Context) ([]CampgroundInfo, error) {
 	// 1) Fetch all city parks
@@ -209,10 +233,10 @@ func (r *ReserveCalifornia) FetchAllCampgrounds(ctx context.Context) ([]Campgrou
 	if err != nil {
 		return nil, err
 	}
-	httpx.SpoofChromeHeaders(req)
+	httpx.SpoofChromeHeaders(req, r.userAgent)
 	resp, err := r.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("citypark GET failed: %w", err)
+		return nil, requestError("citypark GET", err)
 	}
 	body, rerr := io.ReadAll(resp.Body)
 	resp.Body.Close()
@@ -220,7 +244,7 @@ func (r *ReserveCalifornia) FetchAllCampgrounds(ctx context.Context) ([]Campgrou
 		return nil, fmt.Errorf("citypark read body failed: %w", rerr)
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("citypark status %d; body: %s", resp.StatusCode, clipBody(body))
+		return nil, statusError("citypark", resp.StatusCode, body)
 	}
 	var parks map[string]struct {
 		CityParkId int     `json:"CityParkId"`
@@ -268,42 +292,12 @@ func (r *ReserveCalifornia) FetchAllCampgrounds(ctx context.Context) ([]Campgrou
 			continue
 		}
 
-		var b2 []byte
-		success := false
-		for i := 0; i < 100; i++ {
-			pr := map[string]string{"PlaceId": strconv.Itoa(p.PlaceId)}
-			pb, _ := json.Marshal(pr)
-			req2, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://calirdr.usedirect.com/RDR/rdr/search/place", bytes.NewReader(pb))
-			if err != nil {
-				slog.Warn("build place request failed", slog.Any("err", err))
-				continue
-			}
-			httpx.SpoofChromeHeaders(req2)
-			req2.Header.Set("Content-Type", "application/json")
-			req2.Header.Set("Origin", "https://reservecalifornia.com")
-			req2.Header.Set("Referer", "https://reservecalifornia.com/")
-
-			time.Sleep(time.Duration(i) * time.Second)
-
-			slog.Info("checking park", slog.Int("id", p.CityParkId), slog.String("name", p.Name), slog.Int("placeId", p.PlaceId), slog.Int("retry", i))
-			resp2, err := r.client.Do(req2)
-			if err != nil {
-				slog.Warn("place POST failed", slog.Any("err", err), slog.Int("placeId", p.PlaceId))
-				continue
-			}
-			b2, _ = io.ReadAll(resp2.Body)
-			resp2.Body.Close()
-			if resp2.StatusCode != http.StatusOK {
-				slog.Warn("place status not OK", slog.Int("status", resp2.StatusCode), slog.Int("placeId", p.PlaceId))
-				continue
-			}
-			// exit if we got the value
-			success = true
-			break
-		}
-		if !success {
-			slog.Warn("place request failed after retries", slog.Int("placeId", p.PlaceId))
-			return nil, fmt.Errorf("place request failed after retries for PlaceId %d", p.PlaceId)
+		pb, _ := json.Marshal(map[string]string{"PlaceId": strconv.Itoa(p.PlaceId)})
+		slog.Info("checking park", slog.Int("id", p.CityParkId), slog.String("name", p.Name), slog.Int("placeId", p.PlaceId))
+		b2, err := r.postUseDirect(ctx, "place", "https://calirdr.usedirect.com/RDR/rdr/search/place", pb)
+		if err != nil {
+			slog.Warn("place request failed", slog.Any("err", err), slog.Int("placeId", p.PlaceId))
+			return nil, fmt.Errorf("place request for PlaceId %d: %w", p.PlaceId, err)
 		}
 		var prParsed placeResp
 		err = json.Unmarshal(b2, &prParsed)
@@ -382,8 +376,6 @@ func (r *ReserveCalifornia) FetchAllCampgrounds(ctx context.Context) ([]Campgrou
 	return out, nil
 }
 
-const maxRetries = 100
-
 // FetchCampsites returns detailed campsite metadata for storage in the database
 func (r *ReserveCalifornia) FetchCampsites(ctx context.Context, campgroundID string) ([]CampsiteInfo, error) {
 	// Extract facility ID from composite ID format "parentID-facilityID"
@@ -413,50 +405,11 @@ func (r *ReserveCalifornia) FetchCampsites(ctx context.Context, campgroundID str
 	}
 
 	body, _ := json.Marshal(payload)
-	success := false
-	var respBody []byte
-	for i := 0; i < maxRetries; i++ {
-		req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://calirdr.usedirect.com/RDR/rdr/search/grid", bytes.NewReader(body))
-		if err != nil {
-			return nil, err
-		}
-		httpx.SpoofChromeHeaders(req)
-
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Origin", "https://reservecalifornia.com")
-		req.Header.Set("Referer", "https://reservecalifornia.com/")
-
-		time.Sleep(time.Duration(i) * 100 * time.Millisecond) // Exponential backoff
-
-		slog.Info("Sending campsite metadata grid request",
-			slog.Int("attempt", i+1))
-		resp, err := r.client.Do(req)
-		if err != nil {
-			slog.Warn("campsite metadata grid request failed", slog.Any("error", err), slog.Int("attempt", i+1))
-			continue
-		}
-		defer resp.Body.Close()
-
-		respBody, err = io.ReadAll(resp.Body)
-		if err != nil {
-			slog.Warn("failed to read campsite metadata response",
-				slog.Any("error", err))
-			continue
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			slog.Warn("campsite metadata request failed with status",
-				slog.Int("status", resp.StatusCode),
-				slog.String("body", clipBody(respBody)))
-			continue
-		}
-		success = true
-		break
-	}
-	if !success {
-		slog.Warn("campsite metadata grid request failed after max retries",
-			slog.String("facilityId", facilityID))
-		return nil, fmt.Errorf("campsite metadata grid request failed after max retries")
+	slog.Info("Sending campsite metadata grid request", slog.String("facilityId", facilityID))
+	respBody, err := r.postUseDirect(ctx, "campsite metadata grid", "https://calirdr.usedirect.com/RDR/rdr/search/grid", body)
+	if err != nil {
+		slog.Warn("campsite metadata grid request failed", slog.Any("error", err), slog.String("facilityId", facilityID))
+		return nil, err
 	}
 
 	// Parse using expanded structure to get unit details
@@ -484,8 +437,7 @@ func (r *ReserveCalifornia) FetchCampsites(ctx context.Context, campgroundID str
 
 	var campsiteInfos []CampsiteInfo
 	for _, unit := range gridResp.Facility.Units {
-		// Get detailed campsite information with retries
-		// ids are
+		// Get detailed campsite information
 		detailsURL := fmt.Sprintf("https://calirdr.usedirect.com/RDR/rdr/search/details/%d/startdate/%s",
 			unit.UnitId, start.Format("2006-01-02"))
 
@@ -493,7 +445,6 @@ func (r *ReserveCalifornia) FetchCampsites(ctx context.Context, campgroundID str
 			slog.String("unitId", fmt.Sprintf("%d", unit.UnitId)),
 			slog.String("url", detailsURL))
 
-		// Try to get details with exponential backoff
 		var detailsResp struct {
 			Unit struct {
 				UnitId          int    `json:"UnitId"`
@@ -523,62 +474,33 @@ func (r *ReserveCalifornia) FetchCampsites(ctx context.Context, campgroundID str
 			} `json:"Amenities"`
 		}
 
-		success := false
-		for attempt := 0; attempt < maxRetries; attempt++ {
-			detailReq, err := http.NewRequestWithContext(ctx, http.MethodGet, detailsURL, nil)
-			if err != nil {
-				break
-			}
-			httpx.SpoofChromeHeaders(detailReq)
-			detailReq.Header.Set("Origin", "https://reservecalifornia.com")
-			detailReq.Header.Set("Referer", "https://reservecalifornia.com/")
-
-			time.Sleep(time.Duration(attempt) * 100 * time.Millisecond) // Exponential backoff
-			detailResp, err := r.client.Do(detailReq)
-			if err != nil {
-				slog.Warn("failed to fetch campsite details",
-					slog.Int("unitId", unit.UnitId),
-					slog.String("error", err.Error()))
-				continue
-			}
-
-			detailBody, err := io.ReadAll(detailResp.Body)
-			detailResp.Body.Close()
-			if err != nil {
-				slog.Warn("failed to read campsite details response",
-					slog.Int("unitId", unit.UnitId),
-					slog.String("error", err.Error()))
-				continue
-			}
-
-			if detailResp.StatusCode == http.StatusTooManyRequests || detailResp.StatusCode >= 500 {
-				slog.Warn("server error for campsite details",
-					slog.Int("unitId", unit.UnitId),
-					slog.Int("status", detailResp.StatusCode),
-					slog.Int("attempt", attempt+1),
-					slog.String("response", clipBody(detailBody)))
-				continue
-			}
-
-			if detailResp.StatusCode != http.StatusOK {
-				slog.Warn("non-200 status for campsite details",
-					slog.Int("unitId", unit.UnitId),
-					slog.Int("status", detailResp.StatusCode),
-					slog.String("response", clipBody(detailBody)))
-				break
-			}
-
-			if err := json.Unmarshal(detailBody, &detailsResp); err != nil {
-				break
-			}
-
-			// Success!
-			success = true
-			break
+		// 429s and 5xx are retried with backoff by the client
+		detailReq, err := http.NewRequestWithContext(ctx, http.MethodGet, detailsURL, nil)
+		if err != nil {
+			return nil, err
 		}
+		httpx.SpoofChromeHeaders(detailReq, r.userAgent)
+		detailReq.Header.Set("Origin", "https://reservecalifornia.com")
+		detailReq.Header.Set("Referer", "https://reservecalifornia.com/")
 
-		if !success {
-			return nil, fmt.Errorf("failed to fetch details for unit %d after %d attempts", unit.UnitId, maxRetries)
+		detailResp, err := r.client.Do(detailReq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch details for unit %d: %w", unit.UnitId, err)
+		}
+		detailBody, err := io.ReadAll(detailResp.Body)
+		detailResp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read details for unit %d: %w", unit.UnitId, err)
+		}
+		if detailResp.StatusCode != http.StatusOK {
+			slog.Warn("non-200 status for campsite details",
+				slog.Int("unitId", unit.UnitId),
+				slog.Int("status", detailResp.StatusCode),
+				slog.String("response", clipBody(detailBody)))
+			return nil, statusError(fmt.Sprintf("details for unit %d", unit.UnitId), detailResp.StatusCode, detailBody)
+		}
+		if err := json.Unmarshal(detailBody, &detailsResp); err != nil {
+			return nil, fmt.Errorf("failed to parse details for unit %d: %w", unit.UnitId, err)
 		}
 
 		// Determine equipment types based on site characteristics
@@ -607,24 +529,8 @@ func (r *ReserveCalifornia) FetchCampsites(ctx context.Context, campgroundID str
 		// Determine campsite type from unit type name or characteristics (convert to lowercase)
 		campsiteType := strings.ToLower(detailsResp.UnitType.Name)
 		if campsiteType == "" {
-			// Inline campsite type detection (returning lowercase)
-			unitLower := strings.ToLower(detailsResp.Unit.Name)
-			switch {
-			case strings.Contains(unitLower, "tent"):
-				campsiteType = "tent"
-			case strings.Contains(unitLower, "rv"):
-				campsiteType = "rv"
-			case strings.Contains(unitLower, "cabin"):
-				campsiteType = "cabin"
-			case strings.Contains(unitLower, "group"):
-				campsiteType = "group"
-			case strings.Contains(unitLower, "primitive"):
-				campsiteType = "primitive"
-			case strings.Contains(unitLower, "yurt"):
-				campsiteType = "yurt"
-			case strings.Contains(unitLower, "camp"):
-				campsiteType = "campsite"
-			default:
+			campsiteType = InferCampsiteType(detailsResp.Unit.Name)
+			if campsiteType == "" {
 				campsiteType = "standard"
 			}
 		}
@@ -649,9 +555,6 @@ func (r *ReserveCalifornia) FetchCampsites(ctx context.Context, campgroundID str
 			Amenities:       amenities,
 			PreviewImageURL: detailsResp.UnitImage,
 		})
-
-		// Add progressive delay to be respectful to the API
-		time.Sleep(200 * time.Millisecond)
 	}
 
 	slog.Info("Completed campsite metadata fetch",
@@ -661,3 +564,86 @@ func (r *ReserveCalifornia) FetchCampsites(ctx context.Context, campgroundID str
 
 	return campsiteInfos, nil
 }
+
+// campsiteTypeKeywords maps words in campsite names, types and equipment to a campsite type, in the
+// order InferCampsiteType tries them. Specialty types are lodging (cabins, yurts, lookouts and
+// similar) that books differently to regular sites and is often wanted specifically.
+var campsiteTypeKeywords = []struct {
+	keyword, campsiteType string
+	specialty             bool
+}{
+	{"tent", "tent", false},
+	{"rv", "rv", false},
+	{"cabin", "cabin", true},
+	{"group", "group", false},
+	{"primitive", "primitive", false},
+	{"yurt", "yurt", true},
+	{"lookout", "lookout", true},
+	{"hut", "hut", true},
+	{"cottage", "cottage", true},
+	{"tipi", "tipi", true},
+	{"teepee", "tipi", true},
+	{"camp", "campsite", false},
+}
+
+// SpecialtyKeywords are the campsiteTypeKeywords that identify specialty lodging.
+var SpecialtyKeywords = func() []string {
+	var out []string
+	for _, k := range campsiteTypeKeywords {
+		if k.specialty {
+			out = append(out, k.keyword)
+		}
+	}
+	return out
+}()
+
+// hasKeyword reports whether a word in text starts with keyword, ignoring case, so "cabin" matches
+// "Cabins" but "hut" doesn't match "shuttle".
+func hasKeyword(text, keyword string) bool {
+	lower := strings.ToLower(text)
+	for i := 0; i < len(lower); {
+		j := strings.Index(lower[i:], keyword)
+		if j < 0 {
+			return false
+		}
+		j += i
+		prev, _ := utf8.DecodeLastRuneInString(lower[:j])
+		if j == 0 || !(unicode.IsLetter(prev) || unicode.IsDigit(prev)) {
+			return true
+		}
+		i = j + 1
+	}
+	return false
+}
+
+// InferCampsiteType guesses a lowercase campsite type from free text such as a unit name or
+// equipment list. Texts are checked in order and the first keyword match wins; "" means no match.
+func InferCampsiteType(texts ...string) string {
+	for _, text := range texts {
+		for _, k := range campsiteTypeKeywords {
+			if hasKeyword(text, k.keyword) {
+				return k.campsiteType
+			}
+		}
+	}
+	return ""
+}
+
+// FetchPricing implements PricingProvider by summarising the per-unit rates of the campsites the
+// caller already fetched, so nothing is requested from UseDirect.
+func (r *ReserveCalifornia) FetchPricing(ctx context.Context, campgroundID string, campsites []CampsiteInfo) (float64, float64, string, error) {
+	min, max := CampsitePriceRange(campsites)
+	return min, max, "night", nil
+}
+
+// IsSpecialtyType reports whether any of the texts (a campsite type or name) mention specialty lodging.
+func IsSpecialtyType(texts ...string) bool {
+	for _, text := range texts {
+		for _, kw := range SpecialtyKeywords {
+			if hasKeyword(text, kw) {
+				return true
+			}
+		}
+	}
+	return false
+}
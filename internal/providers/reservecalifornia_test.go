@@ -1,10 +1,19 @@
 package providers
 
 import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 )
 
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
 func TestReserveCaliforniaPlanBuckets(t *testing.T) {
 	r := NewReserveCalifornia()
 	d1 := time.Date(2025, 8, 12, 13, 0, 0, 0, time.UTC)
@@ -20,3 +29,96 @@ func TestReserveCaliforniaPlanBuckets(t *testing.T) {
 		t.Fatalf("unexpected end: %v", b[0].End)
 	}
 }
+
+func TestReserveCaliforniaPlanBucketsChunksWideSpans(t *testing.T) {
+	r := NewReserveCalifornia()
+	start := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	var dates []time.Time
+	for i := 0; i < 120; i++ {
+		dates = append(dates, start.AddDate(0, 0, i))
+	}
+	b := r.PlanBuckets(dates)
+	if len(b) != 2 {
+		t.Fatalf("expected two buckets, got %d: %+v", len(b), b)
+	}
+	if !b[0].Start.Equal(start) || !b[0].End.Equal(start.AddDate(0, 0, 59)) {
+		t.Fatalf("unexpected first bucket: %+v", b[0])
+	}
+	if !b[1].Start.Equal(start.AddDate(0, 0, 60)) || !b[1].End.Equal(start.AddDate(0, 0, 119)) {
+		t.Fatalf("unexpected second bucket: %+v", b[1])
+	}
+}
+
+func TestReserveCaliforniaFetchAvailabilityDoesNotRetryItself(t *testing.T) {
+	calls := 0
+	r := &ReserveCalifornia{client: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(strings.NewReader("down")), Request: req}, nil
+	})}}
+
+	// Retries are the client's job; the provider reports the failure once
+	start := time.Now()
+	_, err := r.FetchAvailability(context.Background(), "1-2", time.Now(), time.Now().AddDate(0, 0, 1))
+	if !errors.Is(err, ErrTransient) {
+		t.Errorf("expected ErrTransient, got %v", err)
+	}
+	if calls != 1 || time.Since(start) > time.Second {
+		t.Errorf("expected a single request without sleeping, got %d in %v", calls, time.Since(start))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.client = &http.Client{}
+	if _, err := r.FetchCampsites(ctx, "1-2"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled fetch to stop with context.Canceled, got %v", err)
+	}
+}
+
+func TestInferCampsiteType(t *testing.T) {
+	tests := []struct {
+		texts []string
+		want  string
+	}{
+		{[]string{"Tent Cabin 12"}, "tent"},
+		{[]string{"Cabins"}, "cabin"},
+		{[]string{"Shuttle Stop Site"}, ""}, // "hut" only matches at the start of a word
+		{[]string{"Reserved", "RV/Trailer"}, "rv"},
+		{[]string{"Fire Lookout"}, "lookout"},
+		{[]string{"Family Campsite"}, "campsite"},
+	}
+	for _, tt := range tests {
+		if got := InferCampsiteType(tt.texts...); got != tt.want {
+			t.Errorf("InferCampsiteType(%q) = %q, want %q", tt.texts, got, tt.want)
+		}
+	}
+}
+
+func TestIsSpecialtyType(t *testing.T) {
+	for _, text := range []string{"cabin", "Tent Cabin", "YURTS", "mountain hut", "tipi-style"} {
+		if !IsSpecialtyType(text) {
+			t.Errorf("Expected %q to be specialty", text)
+		}
+	}
+	for _, text := range []string{"shuttle parking", "standard nonelectric", "chute"} {
+		if IsSpecialtyType(text) {
+			t.Errorf("Expected %q not to be specialty", text)
+		}
+	}
+}
+
+func TestReserveCaliforniaFetchPricingUsesGivenCampsites(t *testing.T) {
+	r := NewReserveCalifornia()
+	r.client = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		t.Errorf("unexpected request to %s", req.URL)
+		return nil, errors.New("no requests expected")
+	})}
+
+	campsites := []CampsiteInfo{{ID: "1", CostPerNight: 35}, {ID: "2", CostPerNight: 0}, {ID: "3", CostPerNight: 50}}
+	min, max, unit, err := FetchPricing(context.Background(), r, "1-2", campsites)
+	if err != nil {
+		t.Fatalf("FetchPricing failed: %v", err)
+	}
+	if min != 35 || max != 50 || unit != "night" {
+		t.Errorf("got %v-%v per %q, want 35-50 per night", min, max, unit)
+	}
+}
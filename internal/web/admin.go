@@ -0,0 +1,129 @@
+package web
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SetAdminToken enables the /api/admin endpoints, authenticated with "Authorization: Bearer <token>".
+func (s *Server) SetAdminToken(token string) {
+	s.adminToken = token
+}
+
+// requireAdmin writes an error and returns false unless the request carries the admin token.
+// Admin endpoints 404 when no token is configured.
+func (s *Server) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if s.adminToken == "" {
+		http.NotFound(w, r)
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) != 1 {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+type availabilityAsOfItem struct {
+	CampsiteID string    `json:"campsite_id"`
+	Date       string    `json:"date"`
+	Available  bool      `json:"available"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+// handleAvailabilityAsOf serves /api/admin/availability_as_of?provider=&campground_id=&at=<RFC3339>,
+// reconstructing a campground's availability at a past time from state changes.
+func (s *Server) handleAvailabilityAsOf(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	provider, campgroundID := q.Get("provider"), q.Get("campground_id")
+	if provider == "" || campgroundID == "" {
+		http.Error(w, "provider and campground_id are required", http.StatusBadRequest)
+		return
+	}
+	at := time.Now()
+	if raw := q.Get("at"); raw != "" {
+		var err error
+		at, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			http.Error(w, "at must be RFC3339", http.StatusBadRequest)
+			return
+		}
+	}
+
+	avail, err := s.store.GetAvailabilityAsOf(r.Context(), provider, campgroundID, at)
+	if err != nil {
+		slog.Error("failed to get availability as of", slog.Any("err", err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	items := make([]availabilityAsOfItem, 0, len(avail))
+	for _, a := range avail {
+		items = append(items, availabilityAsOfItem{
+			CampsiteID: a.CampsiteID,
+			Date:       a.Date.Format("2006-01-02"),
+			Available:  a.Available,
+			ChangedAt:  a.LastChecked,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"provider":      provider,
+		"campground_id": campgroundID,
+		"at":            at,
+		"availability":  items,
+	})
+}
+
+// handleSyncCampground serves POST /api/admin/sync_campground?provider=&campground_id=, refreshing
+// one campground's campsite metadata without waiting for the monthly sync.
+func (s *Server) handleSyncCampground(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	provider, campgroundID := r.FormValue("provider"), r.FormValue("campground_id")
+	if provider == "" || campgroundID == "" {
+		http.Error(w, "provider and campground_id are required", http.StatusBadRequest)
+		return
+	}
+	if _, found, err := s.store.GetCampgroundByID(r.Context(), provider, campgroundID); err != nil {
+		slog.Error("failed to get campground", slog.Any("err", err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	} else if !found {
+		http.Error(w, "Campground not found", http.StatusNotFound)
+		return
+	}
+
+	started := time.Now()
+	count, err := s.mgr.SyncCampsitesForCampground(r.Context(), provider, campgroundID)
+	if err != nil {
+		slog.Error("manual campground sync failed",
+			slog.String("provider", provider),
+			slog.String("campground_id", campgroundID),
+			slog.Any("err", err))
+		http.Error(w, "Sync failed: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"provider":      provider,
+		"campground_id": campgroundID,
+		"campsites":     count,
+		"duration_ms":   time.Since(started).Milliseconds(),
+	})
+}
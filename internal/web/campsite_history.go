@@ -0,0 +1,101 @@
+package web
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	campsiteHistoryDefaultDays  = 30
+	campsiteHistoryMaxDays      = 365
+	campsiteHistoryDefaultLimit = 100
+	campsiteHistoryMaxLimit     = 500
+)
+
+type campsiteHistoryItem struct {
+	Date        string    `json:"date"`
+	Available   bool      `json:"available"`
+	LastChecked time.Time `json:"last_checked"`
+}
+
+type campsiteHistoryResponse struct {
+	Provider     string                `json:"provider"`
+	CampgroundID string                `json:"campground_id"`
+	CampsiteID   string                `json:"campsite_id"`
+	Since        string                `json:"since"`
+	Items        []campsiteHistoryItem `json:"items"`
+	NextSince    string                `json:"next_since,omitempty"` // pass as since for the next page
+}
+
+// handleCampsiteHistory serves a campsite's stored availability by date, so users can see how
+// often it opens up. since is clamped to the last year; pages hold up to limit dates.
+// Path: /api/campsite_history/{provider}/{campgroundID}/{campsiteID}?since=YYYY-MM-DD&limit=100
+func (s *Server) handleCampsiteHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/campsite_history/"), "/"), "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		http.Error(w, "expected /api/campsite_history/{provider}/{campgroundID}/{campsiteID}", http.StatusBadRequest)
+		return
+	}
+	provider, campgroundID, campsiteID := parts[0], parts[1], parts[2]
+
+	q := r.URL.Query()
+	today := normalizeDay(time.Now())
+	since := today.AddDate(0, 0, -campsiteHistoryDefaultDays)
+	if raw := q.Get("since"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			http.Error(w, "since must be YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		since = parsed
+	}
+	if earliest := today.AddDate(0, 0, -campsiteHistoryMaxDays); since.Before(earliest) {
+		since = earliest
+	}
+	limit := campsiteHistoryDefaultLimit
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = min(n, campsiteHistoryMaxLimit)
+	}
+
+	history, err := s.store.GetCampsiteAvailabilityHistory(r.Context(), provider, campgroundID, campsiteID, since)
+	if err != nil {
+		slog.Error("failed to get campsite history", slog.Any("err", err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	resp := campsiteHistoryResponse{
+		Provider:     provider,
+		CampgroundID: campgroundID,
+		CampsiteID:   campsiteID,
+		Since:        since.Format("2006-01-02"),
+		Items:        make([]campsiteHistoryItem, 0, min(len(history), limit)),
+	}
+	if len(history) > limit {
+		resp.NextSince = history[limit].Date.Format("2006-01-02")
+		history = history[:limit]
+	}
+	for _, a := range history {
+		resp.Items = append(resp.Items, campsiteHistoryItem{
+			Date:        a.Date.Format("2006-01-02"),
+			Available:   a.Available,
+			LastChecked: a.LastChecked,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
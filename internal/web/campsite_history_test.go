@@ -0,0 +1,69 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+)
+
+func TestCampsiteHistoryPaginates(t *testing.T) {
+	store, err := db.Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	s := &Server{store: store}
+
+	start := normalizeDay(time.Now().AddDate(0, 0, -3))
+	var states []db.CampsiteAvailability
+	for d := 0; d < 5; d++ {
+		states = append(states, db.CampsiteAvailability{
+			Provider: "p", CampgroundID: "cg1", CampsiteID: "s1",
+			Date: start.AddDate(0, 0, d), Available: d == 2, LastChecked: time.Now(),
+		})
+	}
+	if err := store.UpsertCampsiteAvailabilityBatch(context.Background(), states); err != nil {
+		t.Fatalf("UpsertCampsiteAvailabilityBatch: %v", err)
+	}
+
+	get := func(query string) (int, campsiteHistoryResponse) {
+		rec := httptest.NewRecorder()
+		s.handleCampsiteHistory(rec, httptest.NewRequest(http.MethodGet, "/api/campsite_history/p/cg1/s1?"+query, nil))
+		var resp campsiteHistoryResponse
+		if rec.Code == http.StatusOK {
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+		}
+		return rec.Code, resp
+	}
+
+	code, page := get("limit=3&since=" + start.Format("2006-01-02"))
+	if code != http.StatusOK || len(page.Items) != 3 || page.NextSince != start.AddDate(0, 0, 3).Format("2006-01-02") {
+		t.Fatalf("first page: got %d %+v, want 3 items and a next page", code, page)
+	}
+	if !page.Items[2].Available || page.Items[0].Available {
+		t.Errorf("first page availability wrong: %+v", page.Items)
+	}
+	code, page = get("limit=3&since=" + page.NextSince)
+	if code != http.StatusOK || len(page.Items) != 2 || page.NextSince != "" {
+		t.Errorf("last page: got %d %+v, want the remaining 2 items", code, page)
+	}
+
+	// A since older than a year is clamped
+	if _, page := get("since=2000-01-01"); page.Since != normalizeDay(time.Now()).AddDate(0, 0, -campsiteHistoryMaxDays).Format("2006-01-02") {
+		t.Errorf("since not clamped: %s", page.Since)
+	}
+
+	rec := httptest.NewRecorder()
+	s.handleCampsiteHistory(rec, httptest.NewRequest(http.MethodGet, "/api/campsite_history/p/cg1", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("missing campsite: got %d, want 400", rec.Code)
+	}
+}
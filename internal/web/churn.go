@@ -0,0 +1,69 @@
+package web
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	churnDefaultDays = 7
+	churnMaxDays     = 90
+)
+
+type churnResponse struct {
+	Provider     string    `json:"provider"`
+	CampgroundID string    `json:"campground_id"`
+	Name         string    `json:"name"`
+	Since        time.Time `json:"since"`
+	Opens        int       `json:"opens"`
+	Closes       int       `json:"closes"`
+	Changes      int       `json:"changes"`
+	PerDay       float64   `json:"per_day"`
+}
+
+// handleCampgroundChurn serves how often a campground's availability changed recently, a hint at
+// whether cancellations actually turn up there.
+// Path: /api/campground_churn/{provider}/{campgroundID}?days=7
+func (s *Server) handleCampgroundChurn(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	provider, campgroundID, ok := parseCampgroundPath(r.URL.Path, "/api/campground_churn/")
+	if !ok {
+		http.Error(w, "expected /api/campground_churn/{provider}/{campgroundID}", http.StatusBadRequest)
+		return
+	}
+	days := churnDefaultDays
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			http.Error(w, "days must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		days = min(n, churnMaxDays)
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -days)
+	stats, err := s.store.GetChurnStats(r.Context(), provider, campgroundID, since)
+	if err != nil {
+		slog.Error("failed to get campground churn", slog.Any("err", err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(churnResponse{
+		Provider:     provider,
+		CampgroundID: campgroundID,
+		Name:         stats.CampgroundName,
+		Since:        since,
+		Opens:        stats.Opens,
+		Closes:       stats.Closes,
+		Changes:      stats.Changes(),
+		PerDay:       float64(stats.Changes()) / float64(days),
+	})
+}
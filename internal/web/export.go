@@ -0,0 +1,170 @@
+package web
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+)
+
+// exportDefaultDays is how much notification history an export covers when no since is given.
+const exportDefaultDays = 90
+
+type exportRequest struct {
+	ID           int64     `json:"id"`
+	Provider     string    `json:"provider"`
+	CampgroundID string    `json:"campground_id"`
+	Checkin      string    `json:"checkin"`
+	Checkout     string    `json:"checkout"`
+	CreatedAt    time.Time `json:"created_at"`
+	Paused       bool      `json:"paused"`
+}
+
+type exportNotification struct {
+	RequestID    int64     `json:"request_id"`
+	Provider     string    `json:"provider"`
+	CampgroundID string    `json:"campground_id"`
+	CampsiteID   string    `json:"campsite_id"`
+	Date         string    `json:"date"`
+	State        string    `json:"state"`
+	SentAt       time.Time `json:"sent_at"`
+}
+
+type exportResponse struct {
+	User          string               `json:"user"`
+	Since         string               `json:"since"`
+	Requests      []exportRequest      `json:"requests"`
+	Notifications []exportNotification `json:"notifications"`
+}
+
+// exportCSVHeader is shared by both record kinds so the export loads as a single table.
+var exportCSVHeader = []string{"record", "request_id", "provider", "campground_id", "campsite_id",
+	"checkin", "checkout", "date", "state", "paused", "time"}
+
+// handleExport serves GET /api/export?user=&format=csv|json&since=YYYY-MM-DD with the user's
+// active requests and the notifications sent to them since (default the last 90 days).
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	q := r.URL.Query()
+	userID := requestUserID(r)
+	format := q.Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		http.Error(w, "format must be csv or json", http.StatusBadRequest)
+		return
+	}
+	since := normalizeDay(time.Now()).AddDate(0, 0, -exportDefaultDays)
+	if raw := q.Get("since"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			http.Error(w, "since must be YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		since = parsed
+	}
+
+	ctx := r.Context()
+	requests, err := s.store.ListUserActiveRequests(ctx, userID)
+	if err != nil {
+		slog.Error("failed to list requests for export", slog.Any("err", err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if format == "csv" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="schniffer-%s.csv"`, sanitizeFilename(userID)))
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		each := func(fn func(db.Notification) error) error {
+			return s.store.EachUserNotification(ctx, userID, since, fn)
+		}
+		// The header is sent with the first rows, so a failure part way only cuts the file short
+		if err := writeExportCSV(w, requests, each); err != nil {
+			slog.Warn("failed to write export", slog.Any("err", err))
+		}
+		return
+	}
+
+	notifications, err := s.store.GetUserNotifications(ctx, userID, since)
+	if err != nil {
+		slog.Error("failed to list notifications for export", slog.Any("err", err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="schniffer-%s.json"`, sanitizeFilename(userID)))
+
+	out := exportResponse{
+		User:          userID,
+		Since:         since.Format("2006-01-02"),
+		Requests:      make([]exportRequest, 0, len(requests)),
+		Notifications: make([]exportNotification, 0, len(notifications)),
+	}
+	for _, req := range requests {
+		out.Requests = append(out.Requests, exportRequest{
+			ID:           req.ID,
+			Provider:     req.Provider,
+			CampgroundID: req.CampgroundID,
+			Checkin:      req.Checkin.Format("2006-01-02"),
+			Checkout:     req.Checkout.Format("2006-01-02"),
+			CreatedAt:    req.CreatedAt,
+			Paused:       req.Paused,
+		})
+	}
+	for _, n := range notifications {
+		out.Notifications = append(out.Notifications, exportNotification{
+			RequestID:    n.RequestID,
+			Provider:     n.Provider,
+			CampgroundID: n.CampgroundID,
+			CampsiteID:   n.CampsiteID,
+			Date:         n.Date.Format("2006-01-02"),
+			State:        n.State,
+			SentAt:       n.SentAt,
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
+
+// writeExportCSV writes one row per request then one per notification straight to w as each
+// yields them, flushing as it goes so long histories are never held in memory.
+func writeExportCSV(w http.ResponseWriter, requests []db.SchniffRequest, each func(func(db.Notification) error) error) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportCSVHeader); err != nil {
+		return err
+	}
+	for _, req := range requests {
+		err := cw.Write([]string{"request", strconv.FormatInt(req.ID, 10), req.Provider, req.CampgroundID, "",
+			req.Checkin.Format("2006-01-02"), req.Checkout.Format("2006-01-02"), "", "",
+			strconv.FormatBool(req.Paused), req.CreatedAt.UTC().Format(time.RFC3339)})
+		if err != nil {
+			return err
+		}
+	}
+	written := 0
+	err := each(func(n db.Notification) error {
+		err := cw.Write([]string{"notification", strconv.FormatInt(n.RequestID, 10), n.Provider, n.CampgroundID,
+			n.CampsiteID, "", "", n.Date.Format("2006-01-02"), n.State, "", n.SentAt.UTC().Format(time.RFC3339)})
+		if err != nil {
+			return err
+		}
+		if written++; written%1000 == 0 {
+			cw.Flush()
+			return cw.Error()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
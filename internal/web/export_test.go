@@ -0,0 +1,97 @@
+package web
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+)
+
+func TestExport(t *testing.T) {
+	store, err := db.Open(filepath.Join(t.TempDir(), "export.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	s := &Server{store: store}
+	s.SetUserTokenSecret("secret")
+	ctx := context.Background()
+
+	checkin := normalizeDay(time.Now()).AddDate(0, 0, 10)
+	reqID, err := store.AddRequest(ctx, db.SchniffRequest{UserID: "u1", Provider: "p", CampgroundID: "cg", Checkin: checkin, Checkout: checkin.AddDate(0, 0, 2)})
+	if err != nil {
+		t.Fatalf("AddRequest: %v", err)
+	}
+	now := time.Now()
+	err = store.InsertNotificationsBatch(ctx, []db.Notification{
+		{RequestID: reqID, UserID: "u1", Provider: "p", CampgroundID: "cg", CampsiteID: "7", Date: checkin, State: "available", SentAt: now.Add(-time.Hour)},
+		{RequestID: reqID, UserID: "u1", Provider: "p", CampgroundID: "cg", CampsiteID: "7", Date: checkin, State: "unavailable", SentAt: now},
+		{RequestID: reqID, UserID: "u1", Provider: "p", CampgroundID: "cg", CampsiteID: "9", Date: checkin, State: "available", SentAt: now.AddDate(0, 0, -200)},
+	}, "batch")
+	if err != nil {
+		t.Fatalf("InsertNotificationsBatch: %v", err)
+	}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		query = strings.NewReplacer("user=u1", "user="+s.IssueUserToken("u1"), "user=nobody", "user="+s.IssueUserToken("nobody")).Replace(query)
+		s.auth(authUser, s.handleExport)(rec, httptest.NewRequest(http.MethodGet, "/api/export?"+query, nil))
+		return rec
+	}
+
+	rec := get("user=u1&format=json")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("json: got %d: %s", rec.Code, rec.Body)
+	}
+	var out exportResponse
+	if err := json.NewDecoder(rec.Body).Decode(&out); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+	if len(out.Requests) != 1 || out.Requests[0].ID != reqID {
+		t.Errorf("json requests: got %+v", out.Requests)
+	}
+	if len(out.Notifications) != 2 || out.Notifications[0].State != "available" || out.Notifications[1].State != "unavailable" {
+		t.Errorf("json notifications: want the 2 recent ones oldest first, got %+v", out.Notifications)
+	}
+
+	rec = get("user=u1&format=csv&since=2000-01-01")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("csv: got %d: %s", rec.Code, rec.Body)
+	}
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if len(rows) != 5 || rows[0][0] != "record" || rows[1][0] != "request" || rows[4][0] != "notification" {
+		t.Errorf("csv: want a header, 1 request and 3 notifications, got %v", rows)
+	}
+
+	rec = get("user=nobody&format=json")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("empty json: got %d", rec.Code)
+	}
+	var empty map[string]json.RawMessage
+	if err := json.NewDecoder(rec.Body).Decode(&empty); err != nil {
+		t.Fatalf("decode empty json: %v", err)
+	}
+	if string(empty["requests"]) != "[]" || string(empty["notifications"]) != "[]" {
+		t.Errorf("empty json should have empty arrays, got %s and %s", empty["requests"], empty["notifications"])
+	}
+	rows, err = csv.NewReader(get("user=nobody&format=csv").Body).ReadAll()
+	if err != nil || len(rows) != 1 {
+		t.Errorf("empty csv should be just the header, got %v (%v)", rows, err)
+	}
+
+	for _, query := range []string{"format=csv", "user=u1&format=xml", "user=u1&since=yesterday"} {
+		if rec := get(query); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d, want 400", query, rec.Code)
+		}
+	}
+}
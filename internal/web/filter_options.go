@@ -0,0 +1,48 @@
+package web
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// filterOptionsTTL bounds how stale filter options can get if a sync never invalidates them.
+const filterOptionsTTL = 10 * time.Minute
+
+// filterOptionsCache holds the encoded filter options response. The zero value is ready to use.
+type filterOptionsCache struct {
+	mu        sync.Mutex
+	body      []byte
+	fetchedAt time.Time
+	now       func() time.Time // nil uses time.Now
+}
+
+// get returns the cached body while it's fresh, otherwise it calls load and caches the result.
+// The lock is held across load so concurrent misses only query the database once.
+func (c *filterOptionsCache) get(ctx context.Context, load func(context.Context) ([]byte, error)) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	if c.body != nil && now().Sub(c.fetchedAt) < filterOptionsTTL {
+		return c.body, nil
+	}
+
+	body, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.body = body
+	c.fetchedAt = now()
+	return body, nil
+}
+
+// invalidate drops the cached body so the next request reloads it.
+func (c *filterOptionsCache) invalidate() {
+	c.mu.Lock()
+	c.body = nil
+	c.mu.Unlock()
+}
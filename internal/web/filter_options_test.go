@@ -0,0 +1,92 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+)
+
+func TestFilterOptionsCache(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	c := &filterOptionsCache{now: func() time.Time { return now }}
+
+	loads := 0
+	load := func(context.Context) ([]byte, error) {
+		loads++
+		return []byte("{}"), nil
+	}
+	get := func() {
+		t.Helper()
+		if _, err := c.get(context.Background(), load); err != nil {
+			t.Fatalf("get: %v", err)
+		}
+	}
+
+	get()
+	get()
+	if loads != 1 {
+		t.Fatalf("second call within the TTL reloaded: %d loads", loads)
+	}
+
+	c.invalidate()
+	get()
+	if loads != 2 {
+		t.Fatalf("call after invalidate used the cache: %d loads", loads)
+	}
+
+	now = now.Add(filterOptionsTTL)
+	get()
+	if loads != 3 {
+		t.Fatalf("call after the TTL used the cache: %d loads", loads)
+	}
+}
+
+func TestHandleFilterOptionsAPI_Cached(t *testing.T) {
+	store, err := db.Open(filepath.Join(t.TempDir(), "filters.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	s := &Server{store: store}
+
+	amenities := func() []string {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		s.handleFilterOptionsAPI(rec, httptest.NewRequest(http.MethodGet, "/api/filter-options", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+		}
+		var opts FilterOptions
+		if err := json.NewDecoder(rec.Body).Decode(&opts); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		return opts.Amenities
+	}
+
+	if err := store.UpsertCampground(ctx, "p", "cg1", "One", 45, -120, 0, []string{"water"}, "", 0, 0, ""); err != nil {
+		t.Fatalf("UpsertCampground: %v", err)
+	}
+	if got := amenities(); !slices.Equal(got, []string{"water"}) {
+		t.Fatalf("amenities = %v, want [water]", got)
+	}
+
+	if err := store.UpsertCampground(ctx, "p", "cg2", "Two", 45, -120, 0, []string{"showers"}, "", 0, 0, ""); err != nil {
+		t.Fatalf("UpsertCampground: %v", err)
+	}
+	if got := amenities(); !slices.Equal(got, []string{"water"}) {
+		t.Errorf("amenities before invalidation = %v, want cached [water]", got)
+	}
+
+	s.filterOptions.invalidate()
+	if got := amenities(); !slices.Equal(got, []string{"showers", "water"}) {
+		t.Errorf("amenities after invalidation = %v, want [showers water]", got)
+	}
+}
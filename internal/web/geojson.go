@@ -0,0 +1,58 @@
+package web
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONPoint           `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"` // [lon, lat] per RFC 7946
+}
+
+// campgroundsToGeoJSON converts viewport campgrounds to a FeatureCollection of points.
+func campgroundsToGeoJSON(campgrounds []CampgroundMapData) geoJSONFeatureCollection {
+	fc := geoJSONFeatureCollection{Type: "FeatureCollection", Features: make([]geoJSONFeature, 0, len(campgrounds))}
+	for _, c := range campgrounds {
+		fc.Features = append(fc.Features, geoJSONFeature{
+			Type:     "Feature",
+			Geometry: geoJSONPoint{Type: "Point", Coordinates: [2]float64{c.Lon, c.Lat}},
+			Properties: map[string]interface{}{
+				"id":         c.ID,
+				"provider":   c.Provider,
+				"name":       c.Name,
+				"url":        c.URL,
+				"rating":     c.Rating,
+				"has_rating": c.HasRating,
+				"price_min":  c.PriceMin,
+				"price_max":  c.PriceMax,
+				"price_unit": c.PriceUnit,
+			},
+		})
+	}
+	return fc
+}
+
+// writeViewportGeoJSON serves unclustered viewport results as GeoJSON for GIS tools.
+func (s *Server) writeViewportGeoJSON(w http.ResponseWriter, r *http.Request, req ViewportRequest) {
+	campgrounds, err := s.getCampgroundsInViewport(r.Context(), req, true)
+	if err != nil {
+		slog.Error("failed to get campgrounds for geojson", slog.Any("err", err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/geo+json")
+	json.NewEncoder(w).Encode(campgroundsToGeoJSON(campgrounds))
+}
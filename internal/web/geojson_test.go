@@ -0,0 +1,81 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/manager"
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+func TestViewportGeoJSON(t *testing.T) {
+	store, err := db.Open(filepath.Join(t.TempDir(), "geojson.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	s := &Server{store: store, mgr: manager.NewManager(store, providers.NewRegistry(), nil, "")}
+	ctx := context.Background()
+
+	if err := store.UpsertCampground(ctx, "p", "inside", "Inside Camp", 45, -120, 0, nil, "", 20, 35, "night"); err != nil {
+		t.Fatalf("UpsertCampground: %v", err)
+	}
+	if err := store.UpsertCampground(ctx, "p", "outside", "Outside Camp", 10, 10, 0, nil, "", 0, 0, ""); err != nil {
+		t.Fatalf("UpsertCampground: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	body := `{"north":46,"south":44,"east":-119,"west":-121,"zoom":3}`
+	s.handleViewportAPI(rec, httptest.NewRequest(http.MethodPost, "/api/viewport?format=geojson", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/geo+json" {
+		t.Errorf("Expected application/geo+json, got %q", ct)
+	}
+
+	var fc struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Type     string `json:"type"`
+			Geometry struct {
+				Type        string     `json:"type"`
+				Coordinates [2]float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&fc); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	// a zoomed-out viewport would normally cluster; GeoJSON always returns points
+	if fc.Type != "FeatureCollection" || len(fc.Features) != 1 {
+		t.Fatalf("Expected one feature in a FeatureCollection, got %q with %d", fc.Type, len(fc.Features))
+	}
+	f := fc.Features[0]
+	if f.Type != "Feature" || f.Geometry.Type != "Point" || f.Geometry.Coordinates != [2]float64{-120, 45} {
+		t.Errorf("Expected a point at [lon, lat] = [-120, 45], got %s %s %v", f.Type, f.Geometry.Type, f.Geometry.Coordinates)
+	}
+	if f.Properties["id"] != "inside" || f.Properties["provider"] != "p" || f.Properties["name"] != "Inside Camp" ||
+		f.Properties["price_min"] != 20.0 || f.Properties["price_max"] != 35.0 || f.Properties["price_unit"] != "night" {
+		t.Errorf("Unexpected properties: %v", f.Properties)
+	}
+}
+
+func TestViewportGeoJSONEmpty(t *testing.T) {
+	fc := campgroundsToGeoJSON(nil)
+	b, err := json.Marshal(fc)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	// GIS tools reject a null features member
+	if got := string(b); got != `{"type":"FeatureCollection","features":[]}` {
+		t.Errorf("Expected an empty feature list, got %s", got)
+	}
+}
@@ -0,0 +1,71 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/brensch/schniffer/internal/db"
+)
+
+func TestGroupUpdateAndDelete(t *testing.T) {
+	store, err := db.Open(filepath.Join(t.TempDir(), "groups.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	s := &Server{store: store}
+	s.SetUserTokenSecret("secret")
+
+	group, err := store.CreateGroup(context.Background(), "owner", "coast", []db.CampgroundRef{{Provider: "p", CampgroundID: "cg1"}})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	path := "/api/groups/" + strconv.FormatInt(group.ID, 10)
+	do := func(method, user, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path+"?user="+s.IssueUserToken(user), strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		s.auth(authUser, s.handleGroup)(rec, req)
+		return rec
+	}
+
+	update := `{"name":"north coast","campgrounds":[{"provider":"p","campground_id":"cg2"}]}`
+	if rec := do(http.MethodPut, "someone-else", update); rec.Code != http.StatusNotFound {
+		t.Errorf("PUT by non-owner: got %d, want 404", rec.Code)
+	}
+	if rec := do(http.MethodDelete, "someone-else", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("DELETE by non-owner: got %d, want 404", rec.Code)
+	}
+
+	var refs []string
+	for i := 0; i <= db.MaxGroupCampgrounds; i++ {
+		refs = append(refs, `{"provider":"p","campground_id":"cg`+strconv.Itoa(i)+`"}`)
+	}
+	if rec := do(http.MethodPut, "owner", `{"name":"big","campgrounds":[`+strings.Join(refs, ",")+`]}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("PUT past the cap: got %d, want 400", rec.Code)
+	}
+
+	rec := do(http.MethodPut, "owner", update)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT by owner: got %d: %s", rec.Code, rec.Body.String())
+	}
+	var updated db.Group
+	if err := json.NewDecoder(rec.Body).Decode(&updated); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if updated.Name != "north coast" || len(updated.Campgrounds) != 1 || updated.Campgrounds[0].CampgroundID != "cg2" {
+		t.Errorf("unexpected updated group: %+v", updated)
+	}
+
+	if rec := do(http.MethodDelete, "owner", ""); rec.Code != http.StatusNoContent {
+		t.Errorf("DELETE by owner: got %d, want 204", rec.Code)
+	}
+	if rec := do(http.MethodDelete, "owner", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("DELETE again: got %d, want 404", rec.Code)
+	}
+}
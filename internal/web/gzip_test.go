@@ -0,0 +1,91 @@
+package web
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGzipMiddleware(t *testing.T) {
+	payload := []byte(`{"type":"clusters","data":[` + strings.Repeat(`{"lat":37.1,"lon":-122.2,"count":3},`, 200) + `{}]}`)
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 512)...)
+	handler := gzipMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(payload)
+		case "/sniffed":
+			w.Write(payload[:64])
+			w.Write(payload[64:])
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(png)
+		case "/encoded":
+			w.Header().Set("Content-Encoding", "br")
+			w.Write([]byte("already compressed"))
+		}
+	}))
+	get := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	gunzip := func(t *testing.T, rec *httptest.ResponseRecorder) []byte {
+		t.Helper()
+		if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+			t.Fatalf("Content-Encoding = %q, want gzip", got)
+		}
+		zr, err := gzip.NewReader(rec.Body)
+		if err != nil {
+			t.Fatalf("gzip reader: %v", err)
+		}
+		body, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("gunzip: %v", err)
+		}
+		return body
+	}
+
+	rec := get("/json", "br, gzip;q=0.8")
+	compressedLen := rec.Body.Len()
+	if body := gunzip(t, rec); !bytes.Equal(body, payload) {
+		t.Error("decompressed body differs from the original")
+	}
+	if compressedLen >= len(payload) {
+		t.Errorf("expected compression, got %d bytes for %d", compressedLen, len(payload))
+	}
+	if rec.Header().Get("Vary") != "Accept-Encoding" {
+		t.Errorf("Vary = %q, want Accept-Encoding", rec.Header().Get("Vary"))
+	}
+
+	rec = get("/sniffed", "gzip")
+	if body := gunzip(t, rec); !bytes.Equal(body, payload) {
+		t.Error("decompressed body differs for a multi-write response")
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type should be sniffed from the plain body, got %q", ct)
+	}
+
+	for _, tt := range []struct {
+		path, acceptEncoding string
+		want                 []byte
+	}{
+		{"/json", "", payload},
+		{"/json", "gzip;q=0", payload},
+		{"/image", "gzip", png},
+		{"/encoded", "gzip", []byte("already compressed")},
+	} {
+		rec := get(tt.path, tt.acceptEncoding)
+		if rec.Header().Get("Content-Encoding") == "gzip" || !bytes.Equal(rec.Body.Bytes(), tt.want) {
+			t.Errorf("%s with Accept-Encoding %q should pass through uncompressed", tt.path, tt.acceptEncoding)
+		}
+	}
+}
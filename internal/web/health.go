@@ -0,0 +1,56 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// readyzPingTimeout bounds the database ping so a wedged database fails readiness rather than
+// hanging the probe.
+const readyzPingTimeout = 2 * time.Second
+
+type healthResponse struct {
+	Status   string               `json:"status"`
+	DBPingMS *float64             `json:"db_ping_ms,omitempty"`
+	DBError  string               `json:"db_error,omitempty"`
+	LastPoll map[string]time.Time `json:"last_poll,omitempty"`
+}
+
+// handleHealthz serves GET /healthz. It only says the process is up and serving requests.
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, healthResponse{Status: "ok"})
+}
+
+// handleReadyz serves GET /readyz, 200 when the database answers a ping and 503 when it doesn't.
+// The body also carries the ping latency and when each provider last polled successfully.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readyzPingTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := s.store.Ping(ctx)
+	pingMS := float64(time.Since(start).Microseconds()) / 1000
+
+	resp := healthResponse{Status: "ok", DBPingMS: &pingMS}
+	if s.mgr != nil {
+		resp.LastPoll = s.mgr.LastSuccessfulPolls()
+	}
+	status := http.StatusOK
+	if err != nil {
+		slog.Warn("readiness check failed", slog.Any("err", err))
+		resp.Status = "unavailable"
+		resp.DBError = err.Error()
+		status = http.StatusServiceUnavailable
+	}
+	writeHealth(w, status, resp)
+}
+
+func writeHealth(w http.ResponseWriter, status int, resp healthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
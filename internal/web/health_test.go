@@ -0,0 +1,47 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/brensch/schniffer/internal/db"
+)
+
+func TestHealthAndReadiness(t *testing.T) {
+	store, err := db.Open(filepath.Join(t.TempDir(), "health.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	s := &Server{store: store}
+
+	get := func(h http.HandlerFunc, path string) (int, healthResponse) {
+		rec := httptest.NewRecorder()
+		h(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var resp healthResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("%s: decode body: %v", path, err)
+		}
+		return rec.Code, resp
+	}
+
+	if code, resp := get(s.handleHealthz, "/healthz"); code != http.StatusOK || resp.Status != "ok" {
+		t.Errorf("/healthz: got %d %+v, want 200 ok", code, resp)
+	}
+	code, resp := get(s.handleReadyz, "/readyz")
+	if code != http.StatusOK || resp.Status != "ok" || resp.DBPingMS == nil {
+		t.Errorf("/readyz: got %d %+v, want 200 ok with a ping latency", code, resp)
+	}
+
+	store.Close()
+	code, resp = get(s.handleReadyz, "/readyz")
+	if code != http.StatusServiceUnavailable || resp.DBError == "" {
+		t.Errorf("/readyz with closed store: got %d %+v, want 503 with a db error", code, resp)
+	}
+	if code, _ := get(s.handleHealthz, "/healthz"); code != http.StatusOK {
+		t.Errorf("/healthz should not depend on the database, got %d", code)
+	}
+}
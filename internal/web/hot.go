@@ -0,0 +1,67 @@
+package web
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+const (
+	hotDefaultLimit = 20
+	hotMaxLimit     = 100
+	hotWindowDays   = 30
+)
+
+type hotCampground struct {
+	Provider      string  `json:"provider"`
+	CampgroundID  string  `json:"campground_id"`
+	Name          string  `json:"name"`
+	URL           string  `json:"url"`
+	Scarcity      float64 `json:"scarcity"`
+	TrackedNights int     `json:"tracked_nights"`
+	BookedNights  int     `json:"booked_nights"`
+	Openings      int     `json:"openings"`
+}
+
+// handleHotAPI serves GET /api/hot?limit=20, the hardest campgrounds to book over the next month.
+func (s *Server) handleHotAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := hotDefaultLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = min(n, hotMaxLimit)
+	}
+
+	ranked, err := s.store.TopCampgroundsByScarcity(r.Context(), hotWindowDays, limit)
+	if err != nil {
+		slog.Error("failed to rank campgrounds by scarcity", slog.Any("err", err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	out := make([]hotCampground, 0, len(ranked))
+	for _, c := range ranked {
+		out = append(out, hotCampground{
+			Provider:      c.Provider,
+			CampgroundID:  c.CampgroundID,
+			Name:          c.CampgroundName,
+			URL:           s.mgr.CampgroundURL(c.Provider, c.CampgroundID),
+			Scarcity:      c.Scarcity,
+			TrackedNights: c.TrackedNights,
+			BookedNights:  c.BookedNights,
+			Openings:      c.Openings,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
@@ -0,0 +1,118 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/manager"
+)
+
+// icsMaxLineOctets is the RFC 5545 content line limit before folding.
+const icsMaxLineOctets = 75
+
+// handleCampgroundICS returns a text/calendar file with one all-day event per run of consecutive
+// available nights for each campsite.
+// Path: /api/campground_ics/{provider}/{campgroundID}?from=YYYY-MM-DD&to=YYYY-MM-DD
+func (s *Server) handleCampgroundICS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	provider, campgroundID, ok := parseCampgroundPath(r.URL.Path, "/api/campground_ics/")
+	if !ok {
+		http.Error(w, "expected /api/campground_ics/{provider}/{campgroundID}", http.StatusBadRequest)
+		return
+	}
+	startDate, endDate := parseStateRange(r)
+
+	ctx := r.Context()
+	campground, found, err := s.store.GetCampgroundByID(ctx, provider, campgroundID)
+	if err != nil {
+		slog.Error("failed to load campground", slog.Any("err", err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "campground not found", http.StatusNotFound)
+		return
+	}
+
+	matrix, err := s.store.GetAvailabilityMatrix(ctx, provider, campgroundID, startDate, endDate)
+	if err != nil {
+		slog.Error("failed to load availability", slog.Any("err", err))
+		http.Error(w, "failed to load availability", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.ics"`, provider, sanitizeFilename(campgroundID)))
+	w.Write(renderAvailabilityICS(campground, s.mgr.CampgroundURL(provider, campgroundID), matrix, time.Now()))
+}
+
+// renderAvailabilityICS builds the calendar. Event UIDs depend only on the campsite and the run's
+// first night, so re-importing an updated export replaces events instead of duplicating them.
+func renderAvailabilityICS(cg db.Campground, url string, matrix *db.AvailabilityMatrix, now time.Time) []byte {
+	var b bytes.Buffer
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//schniffer//campground availability//EN")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "X-WR-CALNAME:"+icsEscape(cg.Name+" availability"))
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, site := range matrix.CampsiteIDs {
+		for _, run := range manager.ConsecutiveRuns(matrix.AvailableNights(site), 1) {
+			writeICSLine(&b, "BEGIN:VEVENT")
+			writeICSLine(&b, fmt.Sprintf("UID:%s-%s-%s-%s@schniffer", cg.Provider, cg.ID, site, run.Start.Format("20060102")))
+			writeICSLine(&b, "DTSTAMP:"+stamp)
+			writeICSLine(&b, "DTSTART;VALUE=DATE:"+run.Start.Format("20060102"))
+			writeICSLine(&b, "DTEND;VALUE=DATE:"+run.End.AddDate(0, 0, 1).Format("20060102"))
+			writeICSLine(&b, "SUMMARY:"+icsEscape(fmt.Sprintf("Site %s free at %s (%d nights)", site, cg.Name, run.Nights())))
+			if url != "" {
+				writeICSLine(&b, "URL:"+url)
+			}
+			writeICSLine(&b, "TRANSP:TRANSPARENT")
+			writeICSLine(&b, "END:VEVENT")
+		}
+	}
+	writeICSLine(&b, "END:VCALENDAR")
+	return b.Bytes()
+}
+
+// writeICSLine writes a CRLF-terminated content line, folding it at 75 octets without
+// splitting a UTF-8 sequence.
+func writeICSLine(b *bytes.Buffer, line string) {
+	limit := icsMaxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// continuation lines start with a space, which counts towards the limit
+		limit = icsMaxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+// icsEscape escapes TEXT property values per RFC 5545.
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// sanitizeFilename keeps campground IDs that contain slashes or quotes safe in a header.
+func sanitizeFilename(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '"' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, s)
+}
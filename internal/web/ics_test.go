@@ -0,0 +1,125 @@
+package web
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+)
+
+// parseICS is a minimal RFC 5545 reader: it checks line endings and folding, unfolds content lines
+// and returns the properties of each VEVENT.
+func parseICS(t *testing.T, raw string) []map[string]string {
+	t.Helper()
+	if !strings.HasSuffix(raw, "\r\n") {
+		t.Fatalf("calendar must end with CRLF")
+	}
+	physical := strings.Split(strings.TrimSuffix(raw, "\r\n"), "\r\n")
+	var lines []string
+	for _, l := range physical {
+		if strings.Contains(l, "\n") {
+			t.Fatalf("bare LF in line %q", l)
+		}
+		if len(l) > icsMaxLineOctets {
+			t.Fatalf("line longer than %d octets: %q", icsMaxLineOctets, l)
+		}
+		if strings.HasPrefix(l, " ") {
+			if len(lines) == 0 {
+				t.Fatalf("continuation line without a preceding line")
+			}
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		lines = append(lines, l)
+	}
+
+	var stack []string
+	var events []map[string]string
+	var current map[string]string
+	for _, l := range lines {
+		name, value, ok := strings.Cut(l, ":")
+		if !ok {
+			t.Fatalf("content line without a colon: %q", l)
+		}
+		switch name {
+		case "BEGIN":
+			stack = append(stack, value)
+			if value == "VEVENT" {
+				current = make(map[string]string)
+			}
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1] != value {
+				t.Fatalf("END:%s does not match open components %v", value, stack)
+			}
+			stack = stack[:len(stack)-1]
+			if value == "VEVENT" {
+				events = append(events, current)
+				current = nil
+			}
+		default:
+			if current != nil {
+				current[name] = value
+			}
+		}
+	}
+	if len(stack) != 0 {
+		t.Fatalf("unclosed components: %v", stack)
+	}
+	if lines[0] != "BEGIN:VCALENDAR" {
+		t.Fatalf("calendar must start with BEGIN:VCALENDAR, got %q", lines[0])
+	}
+	return events
+}
+
+func TestRenderAvailabilityICS(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 7, d, 0, 0, 0, 0, time.UTC) }
+	cg := db.Campground{
+		Provider: "recreation_gov",
+		ID:       "232447",
+		Name:     "Upper Pines, Yosemite National Park; a very long name that needs folding across lines",
+	}
+	states := []db.CampsiteAvailability{
+		{CampsiteID: "A1", Date: day(1), Available: true},
+		{CampsiteID: "A1", Date: day(2), Available: true},
+		{CampsiteID: "A1", Date: day(3), Available: false},
+		{CampsiteID: "A1", Date: day(4), Available: true},
+		{CampsiteID: "B2", Date: day(1), Available: false},
+	}
+
+	matrix := db.NewAvailabilityMatrix(nil, day(1), day(4), states)
+	raw := string(renderAvailabilityICS(cg, "https://example.com/cg", matrix, day(1)))
+	events := parseICS(t, raw)
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events (one per run), got %d:\n%s", len(events), raw)
+	}
+
+	uids := make(map[string]bool)
+	for _, ev := range events {
+		for _, prop := range []string{"UID", "DTSTAMP", "DTSTART;VALUE=DATE", "DTEND;VALUE=DATE", "SUMMARY"} {
+			if ev[prop] == "" {
+				t.Errorf("event missing %s: %v", prop, ev)
+			}
+		}
+		if uids[ev["UID"]] {
+			t.Errorf("duplicate UID %s", ev["UID"])
+		}
+		uids[ev["UID"]] = true
+	}
+
+	first := events[0]
+	if first["DTSTART;VALUE=DATE"] != "20250701" || first["DTEND;VALUE=DATE"] != "20250703" {
+		t.Errorf("first run should cover the nights of Jul 1-2, got %s to %s", first["DTSTART;VALUE=DATE"], first["DTEND;VALUE=DATE"])
+	}
+	if !strings.Contains(first["SUMMARY"], `Upper Pines\, Yosemite National Park\; a very long name`) {
+		t.Errorf("summary not escaped: %q", first["SUMMARY"])
+	}
+
+	// Re-rendering later must keep the same UIDs so calendar apps update rather than duplicate.
+	again := parseICS(t, string(renderAvailabilityICS(cg, "", matrix, day(5))))
+	for i, ev := range again {
+		if ev["UID"] != events[i]["UID"] {
+			t.Errorf("UID changed between exports: %s vs %s", events[i]["UID"], ev["UID"])
+		}
+	}
+}
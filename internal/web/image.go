@@ -0,0 +1,163 @@
+package web
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// placeholderImage is served by /api/image when there is no stored image or it can't be fetched.
+const placeholderImage = `<svg xmlns="http://www.w3.org/2000/svg" width="320" height="200" viewBox="0 0 320 200">` +
+	`<rect width="320" height="200" fill="#e8efe6"/>` +
+	`<path d="M110 150 L160 70 L210 150 Z" fill="#9bb59a"/>` +
+	`<text x="160" y="180" font-family="sans-serif" font-size="14" text-anchor="middle" fill="#5f7a5e">no image</text>` +
+	`</svg>`
+
+// SetImageCacheDir makes /api/image cache fetched images as files in dir, so they survive
+// restarts. Without it images are cached in memory alongside /img's.
+func (s *Server) SetImageCacheDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create image cache dir: %w", err)
+	}
+	s.imageCacheDir = dir
+	return nil
+}
+
+// handleImageAPI serves /api/image?provider=&campground=&campsite= with the stored image of a
+// campsite, or of the campground when campsite is blank. Images come from the cache or are fetched
+// from the provider; a placeholder is served when there's nothing to show.
+func (s *Server) handleImageAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.images == nil {
+		http.NotFound(w, r)
+		return
+	}
+	q := r.URL.Query()
+	provider, campgroundID, campsiteID := q.Get("provider"), q.Get("campground"), q.Get("campsite")
+	if provider == "" || campgroundID == "" {
+		http.Error(w, "provider and campground parameters required", http.StatusBadRequest)
+		return
+	}
+
+	raw, err := s.storedImageURL(r.Context(), provider, campgroundID, campsiteID)
+	if err != nil {
+		slog.Warn("image lookup failed", slog.String("provider", provider), slog.String("campground", campgroundID), slog.Any("err", err))
+	}
+	u, perr := url.Parse(raw)
+	if raw == "" || perr != nil || s.images.validate(u) != nil {
+		servePlaceholderImage(w)
+		return
+	}
+
+	img, err := s.cachedImage(r, u)
+	if err != nil {
+		slog.Warn("image fetch failed", slog.String("url", raw), slog.Any("err", err))
+		servePlaceholderImage(w)
+		return
+	}
+	w.Header().Set("Content-Type", img.contentType)
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write(img.body)
+}
+
+// storedImageURL returns the image URL synced for the campsite, or for the campground when
+// campsiteID is blank. It's "" when none is stored.
+func (s *Server) storedImageURL(ctx context.Context, provider, campgroundID, campsiteID string) (string, error) {
+	if campsiteID != "" {
+		details, err := s.store.GetCampsiteDetails(ctx, provider, campgroundID, campsiteID)
+		return details.ImageURL, err
+	}
+	cg, _, err := s.store.GetCampgroundByID(ctx, provider, campgroundID)
+	return cg.ImageURL, err
+}
+
+// cachedImage returns the image at u from the disk cache (or memory, without one), fetching it if
+// it's missing or older than imageProxyTTL. A stale copy is served if the refetch fails.
+func (s *Server) cachedImage(r *http.Request, u *url.URL) (cachedImage, error) {
+	key := u.String()
+	if s.imageCacheDir == "" {
+		if img, ok := s.images.get(key); ok {
+			return img, nil
+		}
+		img, err := s.images.fetch(r, u)
+		if err != nil {
+			return cachedImage{}, err
+		}
+		s.images.put(key, img)
+		return img, nil
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	path := filepath.Join(s.imageCacheDir, hex.EncodeToString(sum[:]))
+	stale, found := readCachedImage(path)
+	if found && time.Since(stale.fetchedAt) <= imageProxyTTL {
+		return stale, nil
+	}
+	img, err := s.images.fetch(r, u)
+	if err != nil {
+		if found {
+			return stale, nil
+		}
+		return cachedImage{}, err
+	}
+	if err := writeCachedImage(path, img); err != nil {
+		slog.Warn("image cache write failed", slog.String("path", path), slog.Any("err", err))
+	}
+	return img, nil
+}
+
+// readCachedImage loads a cached image file, sniffing its content type. fetchedAt is the file's
+// modification time.
+func readCachedImage(path string) (cachedImage, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return cachedImage{}, false
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return cachedImage{}, false
+	}
+	contentType := http.DetectContentType(body)
+	if !strings.HasPrefix(contentType, "image/") {
+		return cachedImage{}, false
+	}
+	return cachedImage{contentType: contentType, body: body, fetchedAt: info.ModTime()}, true
+}
+
+// writeCachedImage writes the image through a temp file so readers never see a partial one.
+func writeCachedImage(path string, img cachedImage) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".image-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(img.body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// servePlaceholderImage writes the placeholder, uncached so the real image shows once it's available.
+func servePlaceholderImage(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write([]byte(placeholderImage))
+}
@@ -0,0 +1,206 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brensch/schniffer/internal/httpx"
+)
+
+const (
+	imageProxyMaxBytes   = 5 << 20 // 5MB per image
+	imageProxyCacheBytes = 64 << 20
+	imageProxyTTL        = 24 * time.Hour
+)
+
+// DefaultImageProxyHosts are the provider image hosts the proxy will fetch from.
+// Subdomains of these hosts are allowed too.
+var DefaultImageProxyHosts = []string{
+	"recreation.gov",
+	"usedirect.com",
+	"reservecalifornia.com",
+}
+
+type cachedImage struct {
+	contentType string
+	body        []byte
+	fetchedAt   time.Time
+}
+
+// imageProxy fetches provider images on behalf of the map and embeds, caching them in memory.
+// Only allowlisted hosts are fetched (including across redirects) to avoid SSRF.
+type imageProxy struct {
+	client  *http.Client
+	allowed []string
+
+	mu    sync.Mutex
+	cache map[string]cachedImage
+	size  int
+}
+
+func newImageProxy(allowed []string) *imageProxy {
+	p := &imageProxy{
+		allowed: allowed,
+		cache:   make(map[string]cachedImage),
+	}
+	client := *httpx.Default()
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 5 {
+			return errors.New("too many redirects")
+		}
+		return p.validate(req.URL)
+	}
+	p.client = &client
+	return p
+}
+
+// EnableImageProxy turns on the /img endpoint for the given host allowlist.
+// Passing no hosts uses DefaultImageProxyHosts.
+func (s *Server) EnableImageProxy(hosts ...string) {
+	var allowed []string
+	for _, h := range hosts {
+		if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+			allowed = append(allowed, h)
+		}
+	}
+	if len(allowed) == 0 {
+		allowed = DefaultImageProxyHosts
+	}
+	s.images = newImageProxy(allowed)
+}
+
+// validate checks that the URL is https and points at an allowlisted host.
+func (p *imageProxy) validate(u *url.URL) error {
+	if u.Scheme != "https" {
+		return fmt.Errorf("scheme %q not allowed", u.Scheme)
+	}
+	if u.User != nil || u.Port() != "" {
+		return errors.New("credentials and ports not allowed")
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range p.allowed {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return nil
+		}
+	}
+	return fmt.Errorf("host %q not allowed", host)
+}
+
+func (p *imageProxy) get(key string) (cachedImage, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	img, ok := p.cache[key]
+	if !ok {
+		return cachedImage{}, false
+	}
+	if time.Since(img.fetchedAt) > imageProxyTTL {
+		delete(p.cache, key)
+		p.size -= len(img.body)
+		return cachedImage{}, false
+	}
+	return img, true
+}
+
+func (p *imageProxy) put(key string, img cachedImage) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// Simple eviction: drop arbitrary entries until the new image fits
+	for k, v := range p.cache {
+		if p.size+len(img.body) <= imageProxyCacheBytes {
+			break
+		}
+		delete(p.cache, k)
+		p.size -= len(v.body)
+	}
+	p.cache[key] = img
+	p.size += len(img.body)
+}
+
+// fetch downloads an image, enforcing the size limit and an image/* content type.
+func (p *imageProxy) fetch(r *http.Request, u *url.URL) (cachedImage, error) {
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, u.String(), nil)
+	if err != nil {
+		return cachedImage{}, err
+	}
+	httpx.SpoofChromeHeaders(req)
+	req.Header.Set("Accept", "image/avif,image/webp,image/apng,image/*,*/*;q=0.8")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return cachedImage{}, fmt.Errorf("image GET failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return cachedImage{}, fmt.Errorf("image status %d", resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "image/") {
+		return cachedImage{}, fmt.Errorf("content type %q is not an image", contentType)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, imageProxyMaxBytes+1))
+	if err != nil {
+		return cachedImage{}, fmt.Errorf("image read body failed: %w", err)
+	}
+	if len(body) > imageProxyMaxBytes {
+		return cachedImage{}, fmt.Errorf("image larger than %d bytes", imageProxyMaxBytes)
+	}
+
+	return cachedImage{contentType: contentType, body: body, fetchedAt: time.Now()}, nil
+}
+
+// handleImageProxy serves /img?url=... from cache or by fetching from an allowlisted provider host.
+func (s *Server) handleImageProxy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.images == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	raw := r.URL.Query().Get("url")
+	u, err := url.Parse(raw)
+	if raw == "" || err != nil {
+		http.Error(w, "url parameter required", http.StatusBadRequest)
+		return
+	}
+	if err := s.images.validate(u); err != nil {
+		http.Error(w, "url not allowed", http.StatusForbidden)
+		return
+	}
+
+	key := u.String()
+	img, ok := s.images.get(key)
+	if !ok {
+		img, err = s.images.fetch(r, u)
+		if err != nil {
+			slog.Warn("image proxy fetch failed", slog.String("url", key), slog.Any("err", err))
+			http.Error(w, "failed to fetch image", http.StatusBadGateway)
+			return
+		}
+		s.images.put(key, img)
+	}
+
+	w.Header().Set("Content-Type", img.contentType)
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write(img.body)
+}
+
+// ImageURL rewrites a provider image URL to go through the proxy when it is enabled.
+func (s *Server) ImageURL(raw string) string {
+	if s.images == nil || raw == "" {
+		return raw
+	}
+	return "/img?url=" + url.QueryEscape(raw)
+}
@@ -0,0 +1,96 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestImageProxyValidate(t *testing.T) {
+	p := newImageProxy(DefaultImageProxyHosts)
+	tests := []struct {
+		url     string
+		allowed bool
+	}{
+		{"https://recreation.gov/img.jpg", true},
+		{"https://cdn.recreation.gov/img.jpg", true},
+		{"https://RESERVECALIFORNIA.com/img.jpg", true},
+		{"http://recreation.gov/img.jpg", false},           // not https
+		{"https://evilrecreation.gov/img.jpg", false},      // not a subdomain
+		{"https://recreation.gov.evil.com/img.jpg", false}, // allowlisted host as a prefix
+		{"https://recreation.gov:8443/img.jpg", false},     // explicit port
+		{"https://user:pw@recreation.gov/img.jpg", false},  // credentials
+		{"https://169.254.169.254/latest/meta-data", false},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tt.url, err)
+		}
+		if err := p.validate(u); (err == nil) != tt.allowed {
+			t.Errorf("validate(%q) = %v, want allowed=%v", tt.url, err, tt.allowed)
+		}
+	}
+}
+
+func TestHandleImageProxy(t *testing.T) {
+	get := func(s *Server, raw string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.handleImageProxy(rec, httptest.NewRequest(http.MethodGet, "/img?url="+url.QueryEscape(raw), nil))
+		return rec
+	}
+
+	if rec := get(&Server{}, "https://recreation.gov/a.jpg"); rec.Code != http.StatusNotFound {
+		t.Fatalf("Expected 404 while the proxy is disabled, got %d", rec.Code)
+	}
+
+	s := &Server{}
+	s.EnableImageProxy()
+	var fetches atomic.Int32
+	s.images.client.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		fetches.Add(1)
+		resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Request: r}
+		switch r.URL.Path {
+		case "/a.jpg":
+			resp.Header.Set("Content-Type", "image/jpeg")
+			resp.Body = io.NopCloser(strings.NewReader("jpeg bytes"))
+		case "/page.html":
+			resp.Header.Set("Content-Type", "text/html")
+			resp.Body = io.NopCloser(strings.NewReader("<html>"))
+		case "/huge.jpg":
+			resp.Header.Set("Content-Type", "image/jpeg")
+			resp.Body = io.NopCloser(strings.NewReader(strings.Repeat("x", imageProxyMaxBytes+1)))
+		case "/redirect.jpg":
+			resp.StatusCode = http.StatusFound
+			resp.Header.Set("Location", "https://internal.example/secret.jpg")
+			resp.Body = io.NopCloser(strings.NewReader(""))
+		default:
+			resp.StatusCode = http.StatusNotFound
+			resp.Body = io.NopCloser(strings.NewReader(""))
+		}
+		return resp, nil
+	})
+
+	for i := 0; i < 2; i++ {
+		rec := get(s, "https://recreation.gov/a.jpg")
+		if rec.Code != http.StatusOK || rec.Body.String() != "jpeg bytes" || rec.Header().Get("Content-Type") != "image/jpeg" {
+			t.Fatalf("Expected the image to be served, got %d %q %q", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("Expected the second request to be served from cache, got %d fetches", n)
+	}
+
+	if rec := get(s, "https://internal.example/secret.jpg"); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected a host off the allowlist to be refused, got %d", rec.Code)
+	}
+	for _, path := range []string{"/page.html", "/huge.jpg", "/redirect.jpg"} {
+		if rec := get(s, "https://recreation.gov"+path); rec.Code != http.StatusBadGateway {
+			t.Errorf("Expected %s to be rejected, got %d", path, rec.Code)
+		}
+	}
+}
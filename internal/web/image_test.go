@@ -0,0 +1,111 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestImageAPI(t *testing.T) {
+	store, err := db.Open(filepath.Join(t.TempDir(), "image.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	const campgroundImage = "https://cdn.recreation.gov/cg.png"
+	const campsiteImage = "https://cdn.recreation.gov/site.png"
+	if err := store.UpsertCampground(ctx, "p", "cg1", "Camp", 0, 0, 0, nil, campgroundImage, 0, 0, ""); err != nil {
+		t.Fatalf("UpsertCampground: %v", err)
+	}
+	if _, err := store.UpsertCampsiteMetadataBatch(ctx, "p", "cg1", []providers.CampsiteInfo{
+		{ID: "s1", PreviewImageURL: campsiteImage},
+		{ID: "s2", PreviewImageURL: "https://cdn.recreation.gov/missing.png"},
+	}); err != nil {
+		t.Fatalf("UpsertCampsiteMetadataBatch: %v", err)
+	}
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 32)...)
+	var fetches atomic.Int64
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if r.URL.Path == "/missing.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(png)
+	}))
+	defer upstream.Close()
+
+	s := &Server{store: store}
+	s.EnableImageProxy()
+	if err := s.SetImageCacheDir(filepath.Join(t.TempDir(), "images")); err != nil {
+		t.Fatalf("SetImageCacheDir: %v", err)
+	}
+	// route the provider's https image URLs to the stub server
+	target, _ := url.Parse(upstream.URL)
+	s.images.client.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme, r.URL.Host = target.Scheme, target.Host
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	get := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.handleImageAPI(rec, httptest.NewRequest(http.MethodGet, "/api/image?"+query, nil))
+		return rec
+	}
+	isImage := func(rec *httptest.ResponseRecorder) bool {
+		return rec.Code == http.StatusOK && rec.Header().Get("Content-Type") == "image/png" && bytes.Equal(rec.Body.Bytes(), png)
+	}
+
+	// a miss fetches from the provider and writes the cache
+	if rec := get("provider=p&campground=cg1&campsite=s1"); !isImage(rec) || rec.Header().Get("Cache-Control") != "public, max-age=86400" {
+		t.Fatalf("Expected the campsite image, got %d %q %v", rec.Code, rec.Header().Get("Content-Type"), rec.Header())
+	}
+	if fetches.Load() != 1 {
+		t.Fatalf("Expected one upstream fetch, got %d", fetches.Load())
+	}
+	sum := sha256.Sum256([]byte(campsiteImage))
+	if _, err := os.Stat(filepath.Join(s.imageCacheDir, hex.EncodeToString(sum[:]))); err != nil {
+		t.Errorf("Expected the image cached on disk: %v", err)
+	}
+
+	// a cached image is served without going upstream
+	if rec := get("provider=p&campground=cg1&campsite=s1"); !isImage(rec) || fetches.Load() != 1 {
+		t.Errorf("Expected the cached image without a fetch, got %d (fetches %d)", rec.Code, fetches.Load())
+	}
+
+	// campground images are used when no campsite is given
+	if rec := get("provider=p&campground=cg1"); !isImage(rec) || fetches.Load() != 2 {
+		t.Errorf("Expected the campground image, got %d (fetches %d)", rec.Code, fetches.Load())
+	}
+
+	// an upstream failure or no stored image falls back to the placeholder
+	for _, query := range []string{"provider=p&campground=cg1&campsite=s2", "provider=p&campground=unknown"} {
+		rec := get(query)
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/svg+xml" || rec.Body.String() != placeholderImage {
+			t.Errorf("%s: expected the placeholder, got %d %q", query, rec.Code, rec.Header().Get("Content-Type"))
+		}
+	}
+
+	if rec := get("provider=p"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without a campground, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,99 @@
+package web
+
+import (
+	"encoding/json"
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+)
+
+const (
+	earthRadiusKm   = 6371.0
+	maxNearRadiusKm = 500.0
+)
+
+// NearRequest asks for campgrounds within RadiusKm of a point. The embedded viewport filters
+// apply as they do on the map; its bounds are ignored and derived from the radius instead.
+type NearRequest struct {
+	Lat      float64 `json:"lat"`
+	Lon      float64 `json:"lon"`
+	RadiusKm float64 `json:"radius_km"`
+	ViewportRequest
+}
+
+// haversineKm is the great-circle distance between two points in kilometres.
+func haversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := math.Pi / 180
+	dLat := (lat2 - lat1) * toRad
+	dLon := (lon2 - lon1) * toRad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*toRad)*math.Cos(lat2*toRad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// radiusBounds returns a box that contains every point within radiusKm of (lat, lon), so the
+// indexed latitude/longitude range query can do the coarse cut before the exact distance check.
+// Near the poles or across the antimeridian it widens to every longitude rather than wrapping.
+func radiusBounds(lat, lon, radiusKm float64) (north, south, east, west float64) {
+	dLat := radiusKm / earthRadiusKm * 180 / math.Pi
+	north, south = math.Min(90, lat+dLat), math.Max(-90, lat-dLat)
+	if north >= 90 || south <= -90 {
+		return north, south, 180, -180
+	}
+	dLon := dLat / math.Cos(math.Max(math.Abs(north), math.Abs(south))*math.Pi/180)
+	east, west = lon+dLon, lon-dLon
+	if east > 180 || west < -180 {
+		return north, south, 180, -180
+	}
+	return north, south, east, west
+}
+
+// handleNearAPI serves POST /api/near with the campgrounds within radius_km of lat/lon, nearest
+// first, each with its distance_km.
+func (s *Server) handleNearAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req NearRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Lat < -90 || req.Lat > 90 || req.Lon < -180 || req.Lon > 180 {
+		http.Error(w, "lat must be within ±90 and lon within ±180", http.StatusBadRequest)
+		return
+	}
+	if req.RadiusKm <= 0 || req.RadiusKm > maxNearRadiusKm {
+		http.Error(w, "radius_km must be between 0 and 500", http.StatusBadRequest)
+		return
+	}
+	if req.OnlyAvailable {
+		if _, _, err := req.availabilityRange(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	req.North, req.South, req.East, req.West = radiusBounds(req.Lat, req.Lon, req.RadiusKm)
+	campgrounds, err := s.getCampgroundsInViewport(r.Context(), req.ViewportRequest, true)
+	if err != nil {
+		slog.Error("failed to get campgrounds near point", slog.Any("err", err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	near := make([]CampgroundMapData, 0, len(campgrounds))
+	for _, c := range campgrounds {
+		c.DistanceKm = haversineKm(req.Lat, req.Lon, c.Lat, c.Lon)
+		if c.DistanceKm <= req.RadiusKm {
+			c.DistanceKm = math.Round(c.DistanceKm*10) / 10
+			near = append(near, c)
+		}
+	}
+	sort.SliceStable(near, func(i, j int) bool { return near[i].DistanceKm < near[j].DistanceKm })
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(near)
+}
@@ -0,0 +1,114 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/manager"
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+func TestHaversineKm(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		want                   float64
+	}{
+		{"same point", 37.77, -122.42, 37.77, -122.42, 0},
+		{"san francisco to los angeles", 37.7749, -122.4194, 34.0522, -118.2437, 559.1},
+		{"one degree of latitude", 0, 0, 1, 0, 111.2},
+		{"across the antimeridian", 0, 179.5, 0, -179.5, 111.2},
+	}
+	for _, tt := range tests {
+		if got := haversineKm(tt.lat1, tt.lon1, tt.lat2, tt.lon2); math.Abs(got-tt.want) > 0.5 {
+			t.Errorf("%s: got %.1f km, want %.1f", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRadiusBounds(t *testing.T) {
+	north, south, east, west := radiusBounds(45, -120, 100)
+	// points due north/south and due east/west at exactly the radius must be inside the box
+	for _, p := range [][2]float64{{45 + 100/111.195, -120}, {45 - 100/111.195, -120}} {
+		if p[0] > north || p[0] < south {
+			t.Errorf("latitude %.4f outside %.4f..%.4f", p[0], south, north)
+		}
+	}
+	if d := haversineKm(45, -120, 45, east); d < 100 {
+		t.Errorf("east edge only %.1f km away, box too narrow", d)
+	}
+	if d := haversineKm(45, -120, 45, west); d < 100 {
+		t.Errorf("west edge only %.1f km away, box too narrow", d)
+	}
+
+	if _, _, east, west := radiusBounds(0, 179.9, 50); east != 180 || west != -180 {
+		t.Errorf("antimeridian crossing should cover every longitude, got %v..%v", west, east)
+	}
+	if _, _, east, west := radiusBounds(89.9, 0, 50); east != 180 || west != -180 {
+		t.Errorf("polar radius should cover every longitude, got %v..%v", west, east)
+	}
+}
+
+func TestNearAPIOrdersByDistance(t *testing.T) {
+	store, err := db.Open(filepath.Join(t.TempDir(), "near.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	s := &Server{store: store, mgr: manager.NewManager(store, providers.NewRegistry(), nil, "")}
+	ctx := context.Background()
+
+	// roughly 5, 20 and 80 km north of the origin, plus one in the corner of the bounding box
+	// that is outside the true radius
+	for _, cg := range []struct {
+		id       string
+		lat, lon float64
+	}{
+		{"far", 45.72, -120},
+		{"near", 45.045, -120},
+		{"mid", 45.18, -120},
+		{"corner", 45.8, -118.9},
+	} {
+		if err := store.UpsertCampground(ctx, "p", cg.id, cg.id, cg.lat, cg.lon, 0, nil, "", 0, 0, ""); err != nil {
+			t.Fatalf("UpsertCampground: %v", err)
+		}
+	}
+
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.handleNearAPI(rec, httptest.NewRequest(http.MethodPost, "/api/near", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := post(`{"lat":45,"lon":-120,"radius_km":100}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body)
+	}
+	var got []CampgroundMapData
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	var ids []string
+	for _, c := range got {
+		ids = append(ids, c.ID)
+	}
+	if strings.Join(ids, ",") != "near,mid,far" {
+		t.Fatalf("want near,mid,far, got %v", ids)
+	}
+	if math.Abs(got[0].DistanceKm-5) > 0.5 || math.Abs(got[2].DistanceKm-80) > 0.5 {
+		t.Errorf("unexpected distances %+v", got)
+	}
+
+	for _, body := range []string{`{"lat":45,"lon":-120}`, `{"lat":45,"lon":-120,"radius_km":1000}`, `{"lat":95,"lon":0,"radius_km":10}`} {
+		if rec := post(body); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d, want 400", body, rec.Code)
+		}
+	}
+}
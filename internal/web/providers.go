@@ -0,0 +1,33 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type providerInfo struct {
+	Name    string `json:"name"`
+	Label   string `json:"label"`
+	BaseURL string `json:"base_url,omitempty"`
+}
+
+// handleProvidersAPI serves GET /api/providers, the registered providers for building filters.
+func (s *Server) handleProvidersAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	names := s.mgr.ProviderNames()
+	out := make([]providerInfo, 0, len(names))
+	for _, name := range names {
+		label, baseURL, ok := s.mgr.DescribeProvider(name)
+		if !ok {
+			continue
+		}
+		out = append(out, providerInfo{Name: name, Label: label, BaseURL: baseURL})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
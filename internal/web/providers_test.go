@@ -0,0 +1,41 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/brensch/schniffer/internal/manager"
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+func TestProvidersAPI(t *testing.T) {
+	reg := providers.NewRegistry()
+	reg.Register("recreation_gov", providers.NewRecreationGov())
+	reg.Register("reservecalifornia", providers.NewReserveCalifornia())
+	s := &Server{mgr: manager.NewManager(nil, reg, nil, "")}
+
+	rec := httptest.NewRecorder()
+	s.handleProvidersAPI(rec, httptest.NewRequest(http.MethodGet, "/api/providers", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+	}
+	var got []providerInfo
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+
+	want := []providerInfo{
+		{Name: "recreation_gov", Label: "Recreation.gov", BaseURL: "https://www.recreation.gov"},
+		{Name: "reservecalifornia", Label: "ReserveCalifornia", BaseURL: "https://reservecalifornia.com"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("provider %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -4,24 +4,46 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math"
 	"net/http"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/brensch/schniffer/internal/db"
 	"github.com/brensch/schniffer/internal/manager"
+	"github.com/brensch/schniffer/internal/providers"
 )
 
 type Server struct {
-	store *db.Store
-	mgr   *manager.Manager
-	addr  string
+	store  *db.Store
+	mgr    *manager.Manager
+	addr   string
+	images *imageProxy // nil unless EnableImageProxy was called
+
+	imageCacheDir string // /api/image caches on disk here when set (see SetImageCacheDir)
+
+	adminToken  string // admin endpoints are disabled when empty
+	disableGzip bool   // responses are gzipped for clients that accept it unless set
+
+	userTokenSecret []byte // signs the user tokens in web links; user routes are refused when empty
+
+	filterOptions filterOptionsCache
+
+	// baseCtx parents background work started by handlers so it stops when the server does;
+	// background tracks that work so shutdown can wait for it.
+	baseCtx    context.Context
+	background sync.WaitGroup
 }
 
+// shutdownTimeout bounds how long Run waits for in-flight requests and background scrapes on shutdown.
+const shutdownTimeout = 10 * time.Second
+
 type CampgroundMapData struct {
 	ID            string   `json:"id"`
 	Name          string   `json:"name"`
@@ -30,6 +52,7 @@ type CampgroundMapData struct {
 	Lon           float64  `json:"lon"`
 	URL           string   `json:"url"`
 	Rating        float64  `json:"rating"`
+	HasRating     bool     `json:"has_rating"` // false for providers without ratings (e.g. reservecalifornia), where rating is 0
 	Amenities     []string `json:"amenities"`
 	CampsiteTypes []string `json:"campsite_types"`
 	Equipment     []string `json:"equipment"`
@@ -37,6 +60,7 @@ type CampgroundMapData struct {
 	PriceMin      float64  `json:"price_min"`
 	PriceMax      float64  `json:"price_max"`
 	PriceUnit     string   `json:"price_unit"`
+	DistanceKm    float64  `json:"distance_km,omitempty"` // only set by /api/near
 }
 
 type ClusterData struct {
@@ -44,6 +68,10 @@ type ClusterData struct {
 	Lon   float64  `json:"lon"`
 	Count int      `json:"count"`
 	Names []string `json:"names,omitempty"`
+	// Only set for small clusters at high zoom (see clusterDetailMinZoom), and only from
+	// campgrounds that have a rating or price respectively.
+	AvgRating float64 `json:"avg_rating,omitempty"`
+	MinPrice  float64 `json:"min_price,omitempty"`
 }
 
 type ViewportRequest struct {
@@ -57,18 +85,134 @@ type ViewportRequest struct {
 	CampsiteTypes []string `json:"campsite_types,omitempty"`
 	Equipment     []string `json:"equipment,omitempty"`
 	MinRating     float64  `json:"min_rating,omitempty"`
-	MinPrice      float64  `json:"min_price,omitempty"`
-	MaxPrice      float64  `json:"max_price,omitempty"`
+	// IncludeUnrated keeps campgrounds without a rating when MinRating is set
+	IncludeUnrated bool `json:"include_unrated,omitempty"`
+	// IncludeDayUse keeps campgrounds whose campsites are all day-use only
+	IncludeDayUse bool `json:"include_day_use,omitempty"`
+	// ReservableOnly hides campgrounds whose campsites are all walk-up or otherwise can't be
+	// reserved ahead by site
+	ReservableOnly bool `json:"reservable_only,omitempty"`
+	// Specialty limits results to campgrounds with cabins, yurts, lookouts and similar
+	Specialty bool     `json:"specialty,omitempty"`
+	MinPrice  float64  `json:"min_price,omitempty"`
+	MaxPrice  float64  `json:"max_price,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	// OnlyAvailable keeps campgrounds with a campsite available on some night from DateFrom to
+	// DateTo (YYYY-MM-DD, inclusive). The range defaults to the next 30 days.
+	OnlyAvailable bool   `json:"only_available,omitempty"`
+	DateFrom      string `json:"date_from,omitempty"`
+	DateTo        string `json:"date_to,omitempty"`
+}
+
+// availabilityDefaultDays is the only_available range when date_to isn't given.
+const availabilityDefaultDays = 30
+
+// availabilityRange parses the only_available date range, filling in the defaults.
+func (req ViewportRequest) availabilityRange() (time.Time, time.Time, error) {
+	from := normalizeDay(time.Now())
+	if req.DateFrom != "" {
+		parsed, err := time.Parse("2006-01-02", req.DateFrom)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("date_from must be YYYY-MM-DD")
+		}
+		from = parsed
+	}
+	to := from.AddDate(0, 0, availabilityDefaultDays)
+	if req.DateTo != "" {
+		parsed, err := time.Parse("2006-01-02", req.DateTo)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("date_to must be YYYY-MM-DD")
+		}
+		to = parsed
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New("date_to must not be before date_from")
+	}
+	return from, to, nil
+}
+
+// NewServer creates a web server. Background work started by handlers is cancelled when ctx is done.
+func NewServer(ctx context.Context, store *db.Store, mgr *manager.Manager, addr string) *Server {
+	s := &Server{
+		store:   store,
+		mgr:     mgr,
+		addr:    addr,
+		baseCtx: ctx,
+	}
+	if mgr != nil {
+		mgr.OnSync(s.filterOptions.invalidate)
+	}
+	return s
 }
 
-func NewServer(store *db.Store, mgr *manager.Manager, addr string) *Server {
-	return &Server{
-		store: store,
-		mgr:   mgr,
-		addr:  addr,
+// goBackground runs fn in a goroutine tracked by the server, with a context derived from the
+// server's base context and limited to timeout.
+func (s *Server) goBackground(timeout time.Duration, fn func(ctx context.Context)) {
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+		ctx, cancel := context.WithTimeout(s.baseCtx, timeout)
+		defer cancel()
+		fn(ctx)
+	}()
+}
+
+// waitBackground waits for tracked background work to finish or ctx to expire.
+func (s *Server) waitBackground(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		slog.Warn("timed out waiting for background work to finish")
 	}
 }
 
+// authKind is the credential a route requires.
+type authKind int
+
+const (
+	authAdmin authKind = iota // the admin bearer token (see SetAdminToken)
+	authUser                  // a user token in ?user= (see IssueUserToken)
+)
+
+type userIDKey struct{}
+
+// auth only runs h for requests carrying the credential kind requires. For user routes the
+// verified user ID is available to h through requestUserID. Read-only map endpoints stay public.
+func (s *Server) auth(kind authKind, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch kind {
+		case authAdmin:
+			if !s.requireAdmin(w, r) {
+				return
+			}
+		case authUser:
+			token := r.URL.Query().Get("user")
+			if token == "" {
+				http.Error(w, "user parameter required", http.StatusBadRequest)
+				return
+			}
+			userID, err := s.VerifyUserToken(token)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			r = r.WithContext(context.WithValue(r.Context(), userIDKey{}, userID))
+		}
+		h(w, r)
+	}
+}
+
+// requestUserID returns the user ID verified by the auth middleware.
+func requestUserID(r *http.Request) string {
+	userID, _ := r.Context().Value(userIDKey{}).(string)
+	return userID
+}
+
 func (s *Server) Run(ctx context.Context) error {
 	mux := http.NewServeMux()
 
@@ -85,6 +229,12 @@ func (s *Server) Run(ctx context.Context) error {
 	// API endpoint to get campgrounds in viewport with clustering
 	mux.HandleFunc("/api/viewport", s.handleViewportAPI)
 
+	// API endpoint for campgrounds within a radius of a point
+	mux.HandleFunc("/api/near", s.handleNearAPI)
+
+	// API endpoint listing the registered providers
+	mux.HandleFunc("/api/providers", s.handleProvidersAPI)
+
 	// API endpoint to get filter options
 	mux.HandleFunc("/api/filter-options", s.handleFilterOptionsAPI)
 
@@ -94,24 +244,70 @@ func (s *Server) Run(ctx context.Context) error {
 	// API endpoint to get campground ASCII state (availability grid)
 	mux.HandleFunc("/api/campground_state/", s.handleCampgroundState)
 
+	// API endpoint exporting campground availability as an iCalendar file
+	mux.HandleFunc("/api/campground_ics/", s.handleCampgroundICS)
+
+	// API endpoint listing a campsite's availability by date
+	mux.HandleFunc("/api/campsite_history/", s.handleCampsiteHistory)
+
+	// API endpoint counting a campground's recent availability changes
+	mux.HandleFunc("/api/campground_churn/", s.handleCampgroundChurn)
+
+	// API endpoint ranking the hardest campgrounds to book
+	mux.HandleFunc("/api/hot", s.handleHotAPI)
+
+	// Image proxy for provider images (404s unless enabled)
+	mux.HandleFunc("/img", s.handleImageProxy)
+
+	// Stored campground/campsite images by ID, cached (404s unless the image proxy is enabled)
+	mux.HandleFunc("/api/image", s.handleImageAPI)
+
+	// Admin endpoints (404 unless an admin token is set)
+	mux.HandleFunc("/api/admin/availability_as_of", s.auth(authAdmin, s.handleAvailabilityAsOf))
+	mux.HandleFunc("/api/admin/sync_campground", s.auth(authAdmin, s.handleSyncCampground))
+
+	// Export of a user's requests and notification history
+	mux.HandleFunc("/api/export", s.auth(authUser, s.handleExport))
+
+	// Liveness and readiness probes
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/readyz", s.handleReadyz)
+
 	// Group API endpoints
-	mux.HandleFunc("/api/groups", s.handleGroups)
-	mux.HandleFunc("/api/groups/create", s.handleCreateGroup)
+	mux.HandleFunc("/api/groups", s.auth(authUser, s.handleGroups))
+	mux.HandleFunc("/api/groups/create", s.auth(authUser, s.handleCreateGroup))
+	mux.HandleFunc("/api/groups/from_viewport", s.auth(authUser, s.handleCreateGroupFromViewport))
+	mux.HandleFunc("/api/groups/", s.auth(authUser, s.handleGroup))
+
+	var handler http.Handler = mux
+	if !s.disableGzip {
+		handler = gzipMiddleware(mux)
+	}
 
 	server := &http.Server{
 		Addr:    s.addr,
-		Handler: mux,
+		Handler: handler,
 	}
 
-	// Graceful shutdown
+	slog.Info("starting web server", slog.String("addr", s.addr))
+	errCh := make(chan error, 1)
 	go func() {
-		<-ctx.Done()
-		slog.Info("shutting down web server")
-		server.Shutdown(context.Background())
+		errCh <- server.ListenAndServe()
 	}()
 
-	slog.Info("starting web server", slog.String("addr", s.addr))
-	return server.ListenAndServe()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	// Graceful shutdown: stop accepting requests, then give in-flight scrapes a moment to wind down
+	slog.Info("shutting down web server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err := server.Shutdown(shutdownCtx)
+	s.waitBackground(shutdownCtx)
+	return err
 }
 
 func (s *Server) handleCampgroundsAPI(w http.ResponseWriter, r *http.Request) {
@@ -120,12 +316,20 @@ func (s *Server) handleCampgroundsAPI(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	campgrounds, err := s.store.GetAllCampgrounds(r.Context())
+	// limit/offset are optional; without them every campground is returned as before
+	limit, offset, err := parsePaging(r, -1, maxCampgroundsPageSize)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	campgrounds, total, err := s.store.GetAllCampgroundsPaged(r.Context(), limit, offset)
 	if err != nil {
 		slog.Error("failed to list campgrounds", slog.Any("err", err))
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
 
 	var result []CampgroundMapData
 	for _, c := range campgrounds {
@@ -147,6 +351,29 @@ func (s *Server) handleCampgroundsAPI(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// maxCampgroundsPageSize caps a single page of /api/campgrounds.
+const maxCampgroundsPageSize = 1000
+
+// parsePaging reads the optional limit and offset query parameters. limit falls back to
+// defaultLimit when absent and is clamped to maxLimit.
+func parsePaging(r *http.Request, defaultLimit, maxLimit int) (limit, offset int, err error) {
+	limit = defaultLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return 0, 0, errors.New("limit must be a positive integer")
+		}
+		limit = min(limit, maxLimit)
+	}
+	if raw := r.URL.Query().Get("offset"); raw != "" {
+		offset, err = strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+	}
+	return limit, offset, nil
+}
+
 func (s *Server) handleViewportAPI(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -159,6 +386,18 @@ func (s *Server) handleViewportAPI(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
+	if req.OnlyAvailable {
+		if _, _, err := req.availabilityRange(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// GeoJSON export skips clustering and returns every campground as a point
+	if r.URL.Query().Get("format") == "geojson" {
+		s.writeViewportGeoJSON(w, r, req)
+		return
+	}
 
 	// Get campgrounds in viewport
 	start := time.Now()
@@ -205,51 +444,9 @@ func (s *Server) shouldClusterViewport(ctx context.Context, req ViewportRequest)
 
 	args = []interface{}{req.South, req.North, req.West, req.East}
 
-	// Add campsite types filter - OR within category, exact JSON matching
-	if len(req.CampsiteTypes) > 0 {
-		var conditions []string
-		for _, campsiteType := range req.CampsiteTypes {
-			conditions = append(conditions, "EXISTS (SELECT 1 FROM json_each(c.campsite_types) WHERE value = ?)")
-			args = append(args, campsiteType)
-		}
-		query += ` AND (` + strings.Join(conditions, " OR ") + `)`
-	}
-
-	// Add equipment filter - OR within category, exact JSON matching
-	if len(req.Equipment) > 0 {
-		var conditions []string
-		for _, equipment := range req.Equipment {
-			conditions = append(conditions, "EXISTS (SELECT 1 FROM json_each(c.equipment) WHERE value = ?)")
-			args = append(args, equipment)
-		}
-		query += ` AND (` + strings.Join(conditions, " OR ") + `)`
-	}
-
-	// Add amenities filter - OR within category, exact JSON matching
-	if len(req.Amenities) > 0 {
-		var conditions []string
-		for _, amenity := range req.Amenities {
-			conditions = append(conditions, "EXISTS (SELECT 1 FROM json_each(c.amenities) WHERE value = ?)")
-			args = append(args, amenity)
-		}
-		query += ` AND (` + strings.Join(conditions, " OR ") + `)`
-	}
-
-	// Add rating filter - apply to all campgrounds when user sets a minimum rating
-	if req.MinRating > 0 {
-		query += ` AND c.rating >= ?`
-		args = append(args, req.MinRating)
-	}
-
-	// Add price filter - only apply if campground has price data
-	if req.MinPrice > 0 {
-		query += ` AND (c.price_max = 0 OR c.price_max >= ?)`
-		args = append(args, req.MinPrice)
-	}
-	if req.MaxPrice > 0 {
-		query += ` AND (c.price_min = 0 OR c.price_min <= ?)`
-		args = append(args, req.MaxPrice)
-	}
+	filters, filterArgs := viewportFilters(req)
+	query += filters
+	args = append(args, filterArgs...)
 
 	var count int
 	err := s.store.DB.QueryRowContext(ctx, query, args...).Scan(&count)
@@ -274,7 +471,7 @@ func (s *Server) getCampgroundsInViewport(ctx context.Context, req ViewportReque
 			c.name, 
 			c.latitude, 
 			c.longitude, 
-			c.rating, 
+			COALESCE(c.rating, 0), 
 			c.amenities, 
 			c.image_url, 
 			c.price_min, 
@@ -290,10 +487,10 @@ func (s *Server) getCampgroundsInViewport(ctx context.Context, req ViewportReque
 			c.name, 
 			c.latitude, 
 			c.longitude, 
-			0 as rating,
+			COALESCE(c.rating, 0), 
 			'[]' as amenities,
 			'' as image_url,
-			0 as price_min,
+			c.price_min, 
 			0 as price_max,
 			'' as price_unit,
 			'[]' as campsite_types,
@@ -309,6 +506,65 @@ func (s *Server) getCampgroundsInViewport(ctx context.Context, req ViewportReque
 
 	args := []interface{}{req.South, req.North, req.West, req.East}
 
+	filters, filterArgs := viewportFilters(req)
+	query += filters
+	args = append(args, filterArgs...)
+
+	rows, err := s.store.QueryReadContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	slog.Debug("fetched campgrounds in viewport", "duration", time.Since(start))
+
+	var campgrounds []CampgroundMapData
+
+	for rows.Next() {
+		var c CampgroundMapData
+		var amenitiesJSON, campsiteTypesJSON, equipmentJSON string
+		err := rows.Scan(&c.Provider, &c.ID, &c.Name, &c.Lat, &c.Lon, &c.Rating, &amenitiesJSON, &c.ImageURL, &c.PriceMin, &c.PriceMax, &c.PriceUnit, &campsiteTypesJSON, &equipmentJSON)
+		if err != nil {
+			return nil, err
+		}
+
+		// Only parse JSON if we need detailed data (not clustering)
+		if includeDetailedData {
+			// Parse amenities JSON
+			if amenitiesJSON != "" && amenitiesJSON != "[]" {
+				json.Unmarshal([]byte(amenitiesJSON), &c.Amenities)
+			}
+
+			// Parse campsite types JSON
+			if campsiteTypesJSON != "" && campsiteTypesJSON != "[]" {
+				json.Unmarshal([]byte(campsiteTypesJSON), &c.CampsiteTypes)
+			}
+
+			// Parse equipment JSON
+			if equipmentJSON != "" && equipmentJSON != "[]" {
+				json.Unmarshal([]byte(equipmentJSON), &c.Equipment)
+			}
+
+			c.HasRating = c.Rating > 0
+			c.Rating = math.Round(c.Rating*10) / 10
+			c.URL = s.mgr.CampgroundURL(c.Provider, c.ID)
+			c.ImageURL = s.ImageURL(c.ImageURL)
+		}
+
+		campgrounds = append(campgrounds, c)
+	}
+
+	slog.Debug("fetched campgrounds after processing", "count", len(campgrounds), "includeDetailedData", includeDetailedData, "duration", time.Since(start))
+
+	return campgrounds, rows.Err()
+}
+
+// viewportFilters builds the filter clauses shared by the viewport count and fetch queries.
+// The campgrounds table must be aliased as c.
+func viewportFilters(req ViewportRequest) (string, []interface{}) {
+	var query string
+	var args []interface{}
+
 	// Add campsite types filter - OR within category, exact JSON matching
 	if len(req.CampsiteTypes) > 0 {
 		var conditions []string
@@ -339,9 +595,15 @@ func (s *Server) getCampgroundsInViewport(ctx context.Context, req ViewportReque
 		query += ` AND (` + strings.Join(conditions, " OR ") + `)`
 	}
 
-	// Add rating filter - apply to all campgrounds when user sets a minimum rating
+	// Add rating filter - unrated campgrounds store 0, so they're excluded rather than treated as
+	// low rated unless explicitly included. Compare at display precision so a 3.96 shown as 4.0
+	// passes a 4+ filter.
 	if req.MinRating > 0 {
-		query += ` AND c.rating >= ?`
+		if req.IncludeUnrated {
+			query += ` AND (COALESCE(c.rating, 0) = 0 OR ROUND(c.rating, 1) >= ROUND(?, 1))`
+		} else {
+			query += ` AND COALESCE(c.rating, 0) > 0 AND ROUND(c.rating, 1) >= ROUND(?, 1)`
+		}
 		args = append(args, req.MinRating)
 	}
 
@@ -355,50 +617,74 @@ func (s *Server) getCampgroundsInViewport(ctx context.Context, req ViewportReque
 		args = append(args, req.MaxPrice)
 	}
 
-	rows, err := s.store.DB.QueryContext(ctx, query, args...)
-	if err != nil {
-		return nil, err
+	// Specialty lodging category - any campsite type with a word starting with a specialty keyword,
+	// matching providers.IsSpecialtyType for the usual space, hyphen and slash separated types
+	if req.Specialty {
+		var conditions []string
+		for _, kw := range providers.SpecialtyKeywords {
+			conditions = append(conditions, `(' ' || replace(replace(lower(value), '-', ' '), '/', ' ')) LIKE ?`)
+			args = append(args, "% "+kw+"%")
+		}
+		query += ` AND EXISTS (SELECT 1 FROM json_each(c.campsite_types) WHERE ` + strings.Join(conditions, " OR ") + `)`
 	}
-	defer rows.Close()
 
-	slog.Debug("fetched campgrounds in viewport", "duration", time.Since(start))
+	// Hide day-use-only areas unless asked for. Campgrounds without campsite metadata are kept.
+	if !req.IncludeDayUse {
+		query += ` AND NOT (
+			EXISTS (SELECT 1 FROM campsite_metadata m WHERE m.provider = c.provider AND m.campground_id = c.campground_id)
+			AND NOT EXISTS (SELECT 1 FROM campsite_metadata m WHERE m.provider = c.provider AND m.campground_id = c.campground_id AND lower(coalesce(m.type_of_use, '')) != 'day')
+		)`
+	}
 
-	var campgrounds []CampgroundMapData
+	// Hide campgrounds with no campsite reservable ahead by site. Campsites without a reserve type,
+	// and campgrounds without campsite metadata, are kept.
+	if req.ReservableOnly {
+		query += ` AND NOT (
+			EXISTS (SELECT 1 FROM campsite_metadata m WHERE m.provider = c.provider AND m.campground_id = c.campground_id)
+			AND NOT EXISTS (SELECT 1 FROM campsite_metadata m WHERE m.provider = c.provider AND m.campground_id = c.campground_id AND lower(coalesce(m.reserve_type, '')) IN ('', 'site-specific'))
+		)`
+	}
 
-	for rows.Next() {
-		var c CampgroundMapData
-		var amenitiesJSON, campsiteTypesJSON, equipmentJSON string
-		err := rows.Scan(&c.Provider, &c.ID, &c.Name, &c.Lat, &c.Lon, &c.Rating, &amenitiesJSON, &c.ImageURL, &c.PriceMin, &c.PriceMax, &c.PriceUnit, &campsiteTypesJSON, &equipmentJSON)
-		if err != nil {
-			return nil, err
+	// Add tags filter - OR within category
+	if len(req.Tags) > 0 {
+		var conditions []string
+		for _, tag := range req.Tags {
+			conditions = append(conditions, "EXISTS (SELECT 1 FROM campground_tags t WHERE t.provider = c.provider AND t.campground_id = c.campground_id AND t.tag = ?)")
+			args = append(args, db.NormalizeTag(tag))
 		}
+		query += ` AND (` + strings.Join(conditions, " OR ") + `)`
+	}
 
-		// Only parse JSON if we need detailed data (not clustering)
-		if includeDetailedData {
-			// Parse amenities JSON
-			if amenitiesJSON != "" && amenitiesJSON != "[]" {
-				json.Unmarshal([]byte(amenitiesJSON), &c.Amenities)
-			}
-
-			// Parse campsite types JSON
-			if campsiteTypesJSON != "" && campsiteTypesJSON != "[]" {
-				json.Unmarshal([]byte(campsiteTypesJSON), &c.CampsiteTypes)
-			}
-
-			// Parse equipment JSON
-			if equipmentJSON != "" && equipmentJSON != "[]" {
-				json.Unmarshal([]byte(equipmentJSON), &c.Equipment)
-			}
-
-			c.URL = s.mgr.CampgroundURL(c.Provider, c.ID)
+	// Only campgrounds with an open campsite in the range. Availability is the biggest table, so
+	// this is a correlated EXISTS that seeks the partial available=1 index on (provider,
+	// campground_id, available, date) per campground rather than a join. The literal
+	// available = 1 is what lets SQLite use that partial index. Handlers validate the range up front.
+	if req.OnlyAvailable {
+		if from, to, err := req.availabilityRange(); err == nil {
+			query += ` AND EXISTS (
+				SELECT 1 FROM campsite_availability a
+				WHERE a.provider = c.provider AND a.campground_id = c.campground_id
+				AND a.date BETWEEN ? AND ? AND a.available = 1
+			)`
+			args = append(args, from, to)
 		}
-
-		campgrounds = append(campgrounds, c)
 	}
 
-	slog.Debug("fetched campgrounds after processing", "count", len(campgrounds), "includeDetailedData", includeDetailedData, "duration", time.Since(start))
+	return query, args
+}
 
-	return campgrounds, rows.Err()
+// Clusters at or above this zoom with at most clusterDetailMaxCount campgrounds carry an
+// average rating and minimum price so the frontend can tint their markers.
+const (
+	clusterDetailMinZoom  = 10
+	clusterDetailMaxCount = 25
+)
+
+// clusterAccumulator is a cluster plus the running totals its rating average needs.
+type clusterAccumulator struct {
+	ClusterData
+	ratingSum float64
+	rated     int
 }
 
 func (s *Server) clusterCampgrounds(campgrounds []CampgroundMapData, zoom int) []ClusterData {
@@ -421,7 +707,7 @@ func (s *Server) clusterCampgrounds(campgrounds []CampgroundMapData, zoom int) [
 		gridSize = 0.5 // Fine clusters for detailed view
 	}
 
-	clusters := make(map[string]*ClusterData)
+	clusters := make(map[string]*clusterAccumulator)
 
 	for _, camp := range campgrounds {
 		// Create grid cell coordinates
@@ -429,7 +715,8 @@ func (s *Server) clusterCampgrounds(campgrounds []CampgroundMapData, zoom int) [
 		gridLon := math.Floor(camp.Lon/gridSize) * gridSize
 		key := fmt.Sprintf("%.4f,%.4f", gridLat, gridLon)
 
-		if cluster, exists := clusters[key]; exists {
+		cluster, exists := clusters[key]
+		if exists {
 			cluster.Count++
 			cluster.Lat = (cluster.Lat*float64(cluster.Count-1) + camp.Lat) / float64(cluster.Count)
 			cluster.Lon = (cluster.Lon*float64(cluster.Count-1) + camp.Lon) / float64(cluster.Count)
@@ -437,18 +724,34 @@ func (s *Server) clusterCampgrounds(campgrounds []CampgroundMapData, zoom int) [
 				cluster.Names = append(cluster.Names, camp.Name)
 			}
 		} else {
-			clusters[key] = &ClusterData{
+			cluster = &clusterAccumulator{ClusterData: ClusterData{
 				Lat:   camp.Lat,
 				Lon:   camp.Lon,
 				Count: 1,
 				Names: []string{camp.Name},
-			}
+			}}
+			clusters[key] = cluster
+		}
+		if camp.Rating > 0 {
+			cluster.ratingSum += camp.Rating
+			cluster.rated++
+		}
+		if camp.PriceMin > 0 && (cluster.MinPrice == 0 || camp.PriceMin < cluster.MinPrice) {
+			cluster.MinPrice = camp.PriceMin
 		}
 	}
 
+	detailed := zoom >= clusterDetailMinZoom
 	var result []ClusterData
 	for _, cluster := range clusters {
-		result = append(result, *cluster)
+		if detailed && cluster.Count <= clusterDetailMaxCount {
+			if cluster.rated > 0 {
+				cluster.AvgRating = cluster.ratingSum / float64(cluster.rated)
+			}
+		} else {
+			cluster.MinPrice = 0
+		}
+		result = append(result, cluster.ClusterData)
 	}
 	return result
 }
@@ -459,29 +762,107 @@ func (s *Server) handleCampgroundDetail(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Extract provider and ID from URL path
-	// Expected format: /api/campground/{provider}/{id}
-	path := r.URL.Path[len("/api/campground/"):]
-	if path == "" {
-		http.Error(w, "Missing campground identifier", http.StatusBadRequest)
+	provider, campgroundID, ok := parseCampgroundPath(r.URL.Path, "/api/campground/")
+	if !ok {
+		http.Error(w, "Expected /api/campground/{provider}/{id}", http.StatusBadRequest)
 		return
 	}
 
-	// For now, just return a simple response
-	// This could be expanded to show availability, campsites, etc.
+	ctx := r.Context()
+	cg, found, err := s.store.GetCampgroundByID(ctx, provider, campgroundID)
+	if err != nil {
+		slog.Error("failed to get campground", slog.Any("err", err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "Campground not found", http.StatusNotFound)
+		return
+	}
+
+	campsiteTypes, err := s.store.GetCampsiteTypes(ctx, provider, campgroundID)
+	if err != nil {
+		slog.Error("failed to get campsite types", slog.Any("err", err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	equipment, err := s.store.GetCampsiteEquipmentTypes(ctx, provider, campgroundID)
+	if err != nil {
+		slog.Error("failed to get equipment types", slog.Any("err", err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	features, err := s.store.FeatureDistribution(ctx, provider, campgroundID)
+	if err != nil {
+		slog.Error("failed to get feature distribution", slog.Any("err", err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if cg.Amenities == nil {
+		cg.Amenities = []string{}
+	}
+	if campsiteTypes == nil {
+		campsiteTypes = []string{}
+	}
+	if equipment == nil {
+		equipment = []string{}
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"message": "Campground detail endpoint - to be implemented",
-		"path":    path,
+	json.NewEncoder(w).Encode(CampgroundDetail{
+		Provider:            cg.Provider,
+		ID:                  cg.ID,
+		Name:                cg.Name,
+		Lat:                 cg.Lat,
+		Lon:                 cg.Lon,
+		Rating:              cg.Rating,
+		Amenities:           cg.Amenities,
+		ImageURL:            s.ImageURL(cg.ImageURL),
+		PriceMin:            cg.PriceMin,
+		PriceMax:            cg.PriceMax,
+		PriceUnit:           cg.PriceUnit,
+		CampsiteTypes:       campsiteTypes,
+		Equipment:           equipment,
+		Delisted:            cg.DelistedAt != nil,
+		FeatureDistribution: features,
 	})
 }
 
-func (s *Server) handleGroups(w http.ResponseWriter, r *http.Request) {
-	userID := r.URL.Query().Get("user")
-	if userID == "" {
-		http.Error(w, "user parameter required", http.StatusBadRequest)
-		return
+// CampgroundDetail is the /api/campground/{provider}/{id} response.
+type CampgroundDetail struct {
+	Provider            string            `json:"provider"`
+	ID                  string            `json:"campground_id"`
+	Name                string            `json:"name"`
+	Lat                 float64           `json:"lat"`
+	Lon                 float64           `json:"lon"`
+	Rating              float64           `json:"rating"`
+	Amenities           []string          `json:"amenities"`
+	ImageURL            string            `json:"image_url"`
+	PriceMin            float64           `json:"price_min"`
+	PriceMax            float64           `json:"price_max"`
+	PriceUnit           string            `json:"price_unit"`
+	CampsiteTypes       []string          `json:"campsite_types"`
+	Equipment           []string          `json:"equipment"`
+	Delisted            bool              `json:"delisted"`
+	FeatureDistribution []db.FeatureCount `json:"feature_distribution"`
+}
+
+// parseCampgroundPath splits "{prefix}{provider}/{id}" into provider and campground ID. Only the first
+// segment is the provider; campground IDs may themselves contain slashes (e.g. "1260/2181").
+func parseCampgroundPath(path, prefix string) (provider, campgroundID string, ok bool) {
+	rest, found := strings.CutPrefix(path, prefix)
+	if !found {
+		return "", "", false
+	}
+	provider, campgroundID, found = strings.Cut(strings.Trim(rest, "/"), "/")
+	if !found || provider == "" || strings.Trim(campgroundID, "/") == "" {
+		return "", "", false
 	}
+	return provider, strings.Trim(campgroundID, "/"), true
+}
+
+func (s *Server) handleGroups(w http.ResponseWriter, r *http.Request) {
+	userID := requestUserID(r)
 
 	switch r.Method {
 	case http.MethodGet:
@@ -511,11 +892,7 @@ func (s *Server) handleCreateGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userID := r.URL.Query().Get("user")
-	if userID == "" {
-		http.Error(w, "user parameter required", http.StatusBadRequest)
-		return
-	}
+	userID := requestUserID(r)
 
 	var req CreateGroupRequest
 	err := json.NewDecoder(r.Body).Decode(&req)
@@ -524,18 +901,8 @@ func (s *Server) handleCreateGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Name == "" {
-		http.Error(w, "Group name is required", http.StatusBadRequest)
-		return
-	}
-
-	if len(req.Campgrounds) == 0 {
-		http.Error(w, "At least one campground is required", http.StatusBadRequest)
-		return
-	}
-
-	if len(req.Campgrounds) > 10 {
-		http.Error(w, "Maximum 10 campgrounds allowed per group", http.StatusBadRequest)
+	if msg := req.validate(); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
 		return
 	}
 
@@ -550,10 +917,76 @@ func (s *Server) handleCreateGroup(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(group)
 }
 
+// validate returns why the group can't be saved, or "" if it can.
+func (req CreateGroupRequest) validate() string {
+	switch {
+	case req.Name == "":
+		return "Group name is required"
+	case len(req.Campgrounds) == 0:
+		return "At least one campground is required"
+	case len(req.Campgrounds) > db.MaxGroupCampgrounds:
+		return fmt.Sprintf("Maximum %d campgrounds allowed per group", db.MaxGroupCampgrounds)
+	}
+	return ""
+}
+
+// handleGroup updates (PUT) or deletes (DELETE) /api/groups/{id}. Groups belonging to someone
+// else are reported as not found.
+func (s *Server) handleGroup(w http.ResponseWriter, r *http.Request) {
+	userID := requestUserID(r)
+	groupID, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/groups/"), "/"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid group id", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		var req CreateGroupRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if msg := req.validate(); msg != "" {
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		group, err := s.store.UpdateGroup(r.Context(), groupID, userID, req.Name, req.Campgrounds)
+		if errors.Is(err, db.ErrGroupNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			slog.Error("Failed to update group", "error", err)
+			http.Error(w, "Failed to update group", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(group)
+
+	case http.MethodDelete:
+		err := s.store.DeleteGroup(r.Context(), groupID, userID)
+		if errors.Is(err, db.ErrGroupNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			slog.Error("Failed to delete group", "error", err)
+			http.Error(w, "Failed to delete group", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
 type FilterOptions struct {
 	Amenities     []string `json:"amenities"`
 	CampsiteTypes []string `json:"campsite_types"`
 	Equipment     []string `json:"equipment"`
+	Tags          []string `json:"tags"`
 	PriceRange    struct {
 		Min float64 `json:"min"`
 		Max float64 `json:"max"`
@@ -564,23 +997,34 @@ type FilterOptions struct {
 	} `json:"rating_range"`
 }
 
+// handleFilterOptionsAPI serves the values the map's filters offer. They only change when
+// campgrounds or campsites are synced, so they're served from a cache.
 func (s *Server) handleFilterOptionsAPI(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	ctx := r.Context()
+	body, err := s.filterOptions.get(r.Context(), s.loadFilterOptions)
+	if err != nil {
+		slog.Error("failed to load filter options", slog.Any("err", err))
+		http.Error(w, "Failed to fetch filter options", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
 
+// loadFilterOptions queries every filter value the map offers and returns them encoded as JSON.
+func (s *Server) loadFilterOptions(ctx context.Context) ([]byte, error) {
 	// Get all unique amenities
-	amenitiesRows, err := s.store.DB.QueryContext(ctx, `
+	amenitiesRows, err := s.store.QueryReadContext(ctx, `
 		SELECT DISTINCT amenities 
 		FROM campgrounds 
 		WHERE amenities IS NOT NULL AND amenities != '' AND amenities != '{}'
 	`)
 	if err != nil {
-		http.Error(w, "Failed to fetch amenities", http.StatusInternalServerError)
-		return
+		return nil, fmt.Errorf("failed to fetch amenities: %w", err)
 	}
 	defer amenitiesRows.Close()
 
@@ -602,15 +1046,14 @@ func (s *Server) handleFilterOptionsAPI(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Get all unique campsite types from campsite_metadata table
-	campsiteTypesRows, err := s.store.DB.QueryContext(ctx, `
+	campsiteTypesRows, err := s.store.QueryReadContext(ctx, `
 		SELECT DISTINCT campsite_type 
 		FROM campsite_metadata 
 		WHERE campsite_type IS NOT NULL AND campsite_type != ''
 		ORDER BY campsite_type
 	`)
 	if err != nil {
-		http.Error(w, "Failed to fetch campsite types", http.StatusInternalServerError)
-		return
+		return nil, fmt.Errorf("failed to fetch campsite types: %w", err)
 	}
 	defer campsiteTypesRows.Close()
 
@@ -626,15 +1069,14 @@ func (s *Server) handleFilterOptionsAPI(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Get all unique equipment types from campsite_equipment table
-	equipmentTypesRows, err := s.store.DB.QueryContext(ctx, `
+	equipmentTypesRows, err := s.store.QueryReadContext(ctx, `
 		SELECT DISTINCT equipment_type 
 		FROM campsite_equipment 
 		WHERE equipment_type IS NOT NULL AND equipment_type != ''
 		ORDER BY equipment_type
 	`)
 	if err != nil {
-		http.Error(w, "Failed to fetch equipment types", http.StatusInternalServerError)
-		return
+		return nil, fmt.Errorf("failed to fetch equipment types: %w", err)
 	}
 	defer equipmentTypesRows.Close()
 
@@ -649,6 +1091,11 @@ func (s *Server) handleFilterOptionsAPI(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	tags, err := s.store.ListDistinctTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tags: %w", err)
+	}
+
 	// Get price and rating ranges
 	var priceMin, priceMax, ratingMin, ratingMax float64
 	err = s.store.DB.QueryRowContext(ctx, `
@@ -660,8 +1107,7 @@ func (s *Server) handleFilterOptionsAPI(w http.ResponseWriter, r *http.Request)
 		FROM campgrounds
 	`).Scan(&priceMin, &priceMax, &ratingMin, &ratingMax)
 	if err != nil {
-		http.Error(w, "Failed to fetch price/rating ranges", http.StatusInternalServerError)
-		return
+		return nil, fmt.Errorf("failed to fetch price/rating ranges: %w", err)
 	}
 
 	// Convert sets to sorted slices
@@ -669,29 +1115,32 @@ func (s *Server) handleFilterOptionsAPI(w http.ResponseWriter, r *http.Request)
 	for amenity := range amenitiesSet {
 		amenitiesList = append(amenitiesList, amenity)
 	}
+	sort.Strings(amenitiesList)
 
 	var campsiteTypesList []string
 	for campsiteType := range campsiteTypesSet {
 		campsiteTypesList = append(campsiteTypesList, campsiteType)
 	}
+	sort.Strings(campsiteTypesList)
 
 	var equipmentTypesList []string
 	for equipmentType := range equipmentTypesSet {
 		equipmentTypesList = append(equipmentTypesList, equipmentType)
 	}
+	sort.Strings(equipmentTypesList)
 
 	options := FilterOptions{
 		Amenities:     amenitiesList,
 		CampsiteTypes: campsiteTypesList,
 		Equipment:     equipmentTypesList,
+		Tags:          tags,
 	}
 	options.PriceRange.Min = priceMin
 	options.PriceRange.Max = priceMax
 	options.RatingRange.Min = ratingMin
 	options.RatingRange.Max = ratingMax
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(options)
+	return json.Marshal(options)
 }
 
 // handleCampgroundPage serves the static campground HTML page for any /campground/{provider}/{campgroundID}
@@ -707,8 +1156,8 @@ func (s *Server) handleCampgroundPage(w http.ResponseWriter, r *http.Request) {
 	provider := parts[0]
 	campgroundID := parts[1]
 
-	// Extract user parameter from query string
-	userID := r.URL.Query().Get("user")
+	// Extract user parameter from query string; forged tokens are treated as anonymous
+	userID, _ := s.VerifyUserToken(r.URL.Query().Get("user"))
 
 	slog.Info("campground page accessed",
 		slog.String("provider", provider),
@@ -718,14 +1167,12 @@ func (s *Server) handleCampgroundPage(w http.ResponseWriter, r *http.Request) {
 	// Only trigger ad-hoc scrape request if user parameter is present
 	if userID != "" {
 		// Trigger ad-hoc scrape request (with debouncing) in background
-		go func() {
-			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-			defer cancel()
-
-			// Check if we can/should request a scrape (5 minute debouncing)
-			canRequest, err := s.store.CanRequestAdhocScrapeWithTimeout(ctx, provider, campgroundID, 5*time.Minute)
+		s.goBackground(2*time.Minute, func(ctx context.Context) {
+			// Create the request record first. This is debounced atomically in the store, so
+			// concurrent clicks for the same campground only create (and run) one scrape.
+			req, created, err := s.store.RequestAdhocScrape(ctx, provider, campgroundID, "user", userID)
 			if err != nil {
-				slog.Error("failed to check adhoc scrape eligibility",
+				slog.Error("failed to create adhoc scrape request",
 					slog.String("provider", provider),
 					slog.String("campground_id", campgroundID),
 					slog.String("user_id", userID),
@@ -733,7 +1180,7 @@ func (s *Server) handleCampgroundPage(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
-			if !canRequest {
+			if !created {
 				slog.Debug("skipping adhoc scrape - too recent",
 					slog.String("provider", provider),
 					slog.String("campground_id", campgroundID),
@@ -741,17 +1188,6 @@ func (s *Server) handleCampgroundPage(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
-			// Create the request record first
-			req, err := s.store.RequestAdhocScrape(ctx, provider, campgroundID, "user", userID)
-			if err != nil {
-				slog.Error("failed to create adhoc scrape request",
-					slog.String("provider", provider),
-					slog.String("campground_id", campgroundID),
-					slog.String("user_id", userID),
-					slog.Any("error", err))
-				return
-			}
-
 			if req != nil && req.Status == "pending" {
 				slog.Info("executing immediate adhoc scrape",
 					slog.String("provider", provider),
@@ -770,7 +1206,7 @@ func (s *Server) handleCampgroundPage(w http.ResponseWriter, r *http.Request) {
 						slog.Any("error", err))
 				}
 			}
-		}()
+		})
 	} else {
 		slog.Debug("skipping adhoc scrape - no user parameter provided",
 			slog.String("provider", provider),
@@ -780,8 +1216,9 @@ func (s *Server) handleCampgroundPage(w http.ResponseWriter, r *http.Request) {
 	http.ServeFile(w, r, "./static/campground.html")
 }
 
-// handleCampgroundState returns a text/plain ASCII table of campsite availability for a campground.
-// Path: /api/campground_state/{provider}/{campgroundID}?days=14
+// handleCampgroundState returns a text/plain ASCII table of campsite availability for a campground,
+// or a JSON matrix with ?format=json.
+// Path: /api/campground_state/{provider}/{campgroundID}?from=YYYY-MM-DD&to=YYYY-MM-DD
 func (s *Server) handleCampgroundState(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -796,37 +1233,7 @@ func (s *Server) handleCampgroundState(w http.ResponseWriter, r *http.Request) {
 	provider := parts[0]
 	campgroundID := parts[1]
 
-	// Parse date range from query params, default to next 3 weeks
-	var startDate, endDate time.Time
-	now := normalizeDay(time.Now())
-
-	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
-		if parsed, err := time.Parse("2006-01-02", fromStr); err == nil {
-			startDate = normalizeDay(parsed)
-		} else {
-			startDate = now
-		}
-	} else {
-		startDate = now
-	}
-
-	if toStr := r.URL.Query().Get("to"); toStr != "" {
-		if parsed, err := time.Parse("2006-01-02", toStr); err == nil {
-			endDate = normalizeDay(parsed)
-		} else {
-			endDate = normalizeDay(startDate.AddDate(0, 0, 20)) // 3 weeks default
-		}
-	} else {
-		endDate = normalizeDay(startDate.AddDate(0, 0, 20)) // 3 weeks default
-	}
-
-	// Enforce reasonable limits
-	if endDate.Before(startDate) {
-		endDate = startDate.AddDate(0, 0, 1)
-	}
-	if endDate.Sub(startDate) > 60*24*time.Hour { // max 60 days
-		endDate = startDate.AddDate(0, 0, 60)
-	}
+	startDate, endDate := parseStateRange(r)
 
 	ctx := r.Context()
 
@@ -849,57 +1256,110 @@ func (s *Server) handleCampgroundState(w http.ResponseWriter, r *http.Request) {
 
 	isRefreshing := err == nil && pendingCount > 0
 
-	// Collect all campsite ids for campground
-	rows, err := s.store.DB.QueryContext(ctx, `SELECT campsite_id FROM campsite_metadata WHERE provider=? AND campground_id=? ORDER BY campsite_id`, provider, campgroundID)
+	state, err := s.loadCampgroundState(ctx, provider, campgroundID, startDate, endDate)
 	if err != nil {
-		http.Error(w, "failed to load campsites", http.StatusInternalServerError)
+		slog.Error("failed to load campground state", slog.Any("err", err))
+		http.Error(w, "failed to load availability", http.StatusInternalServerError)
 		return
 	}
-	var campsiteIDs []string
-	for rows.Next() {
-		var id string
-		if err := rows.Scan(&id); err == nil {
-			campsiteIDs = append(campsiteIDs, id)
+
+	if r.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(state.toJSON(provider, campgroundID, campgroundName, isRefreshing))
+		if err != nil {
+			slog.Error("failed to encode campground state", slog.Any("err", err))
 		}
+		return
 	}
-	rows.Close()
-	if len(campsiteIDs) == 0 {
+
+	if len(state.CampsiteIDs) == 0 {
 		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 		fmt.Fprintf(w, "No campsites found for %s/%s\n", provider, campgroundID)
 		return
 	}
 
-	// Map availability: campsiteID -> date(YYYY-MM-DD) -> available bool
-	avail := make(map[string]map[string]bool, len(campsiteIDs))
-	for _, id := range campsiteIDs {
-		avail[id] = make(map[string]bool)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Campground-Name", campgroundName)
+	if isRefreshing {
+		w.Header().Set("X-Refresh-In-Progress", "true")
 	}
+	w.Write(state.renderASCII(func(cid string) string {
+		return s.mgr.CampsiteURL(provider, campgroundID, cid)
+	}))
+}
 
-	// Query all availability rows in range
-	arows, err := s.store.DB.QueryContext(ctx, `SELECT campsite_id, date, available FROM campsite_availability WHERE provider=? AND campground_id=? AND date BETWEEN ? AND ?`, provider, campgroundID, startDate, endDate)
+// Values of a cell in the JSON campground state matrix.
+const (
+	stateAvailable   = "available"
+	stateUnavailable = "unavailable"
+	stateUnknown     = "unknown"
+)
+
+// campgroundState renders the availability grid behind campground_state.
+type campgroundState struct {
+	*db.AvailabilityMatrix
+}
+
+// campgroundStateJSON is the ?format=json response. Availability is indexed [campsite][date],
+// matching the order of CampsiteIDs and Dates.
+type campgroundStateJSON struct {
+	Provider     string     `json:"provider"`
+	CampgroundID string     `json:"campground_id"`
+	Name         string     `json:"name"`
+	Refreshing   bool       `json:"refreshing"`
+	CampsiteIDs  []string   `json:"campsite_ids"`
+	Dates        []string   `json:"dates"`
+	Availability [][]string `json:"availability"`
+}
+
+// loadCampgroundState reads the campsites and their stored availability for [startDate, endDate].
+func (s *Server) loadCampgroundState(ctx context.Context, provider, campgroundID string, startDate, endDate time.Time) (*campgroundState, error) {
+	matrix, err := s.store.GetAvailabilityMatrix(ctx, provider, campgroundID, startDate, endDate)
 	if err != nil {
-		http.Error(w, "failed to load availability", http.StatusInternalServerError)
-		return
+		return nil, fmt.Errorf("failed to load availability: %w", err)
 	}
-	for arows.Next() {
-		var cid string
-		var d time.Time
-		var available bool
-		if err := arows.Scan(&cid, &d, &available); err == nil {
-			ds := normalizeDay(d).Format("2006-01-02")
-			if m, ok := avail[cid]; ok {
-				m[ds] = available
-			}
-		}
+	return &campgroundState{matrix}, nil
+}
+
+// status reports a campsite's stored state on a date.
+func (st *campgroundState) status(cid string, d time.Time) string {
+	v, ok := st.Lookup(cid, d)
+	switch {
+	case !ok:
+		return stateUnknown
+	case v:
+		return stateAvailable
+	default:
+		return stateUnavailable
 	}
-	arows.Close()
+}
 
-	// Build ordered list of dates
-	var dates []time.Time
-	for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 1) {
-		dates = append(dates, d)
+func (st *campgroundState) toJSON(provider, campgroundID, name string, refreshing bool) campgroundStateJSON {
+	out := campgroundStateJSON{
+		Provider:     provider,
+		CampgroundID: campgroundID,
+		Name:         name,
+		Refreshing:   refreshing,
+		CampsiteIDs:  append([]string{}, st.CampsiteIDs...),
+		Dates:        make([]string, len(st.Dates)),
+		Availability: make([][]string, len(st.CampsiteIDs)),
+	}
+	for i, d := range st.Dates {
+		out.Dates[i] = d.Format("2006-01-02")
+	}
+	for i, cid := range st.CampsiteIDs {
+		row := make([]string, len(st.Dates))
+		for j, d := range st.Dates {
+			row[j] = st.status(cid, d)
+		}
+		out.Availability[i] = row
 	}
+	return out
+}
 
+// renderASCII draws the grid as text. campsiteURL may return "" for sites without a link.
+func (st *campgroundState) renderASCII(campsiteURL func(cid string) string) []byte {
+	dates := st.Dates
 	// ASCII rendering (compact). Attempt emoji symbols; still keep fixed cell width of 4 chars.
 	const cellWidth = 4
 	var b bytes.Buffer
@@ -930,38 +1390,62 @@ func (s *Server) handleCampgroundState(w http.ResponseWriter, r *http.Request) {
 	// Divider
 	fmt.Fprintf(&b, "%s\n", strings.Repeat("-", 15+len(dates)*cellWidth))
 
-	sort.Strings(campsiteIDs)
-	for _, cid := range campsiteIDs {
+	for _, cid := range st.CampsiteIDs {
 		// Get the campsite URL from the manager
-		campsiteURL := s.mgr.CampsiteURL(provider, campgroundID, cid)
-		if campsiteURL != "" {
+		if u := campsiteURL(cid); u != "" {
 			// Format as clickable link: [campsite_name](url)
-			fmt.Fprintf(&b, "[%-13s](%s)", truncate(cid, 13), campsiteURL)
+			fmt.Fprintf(&b, "[%-13s](%s)", truncate(cid, 13), u)
 		} else {
 			fmt.Fprintf(&b, "%-15s", truncate(cid, 15))
 		}
-		dm := avail[cid]
 		for _, d := range dates {
-			key := d.Format("2006-01-02")
 			sym := "?" // unknown
-			if v, ok := dm[key]; ok {
-				if v {
-					sym = "O"
-				} else {
-					sym = "."
-				}
+			switch st.status(cid, d) {
+			case stateAvailable:
+				sym = "O"
+			case stateUnavailable:
+				sym = "."
 			}
 			fmt.Fprintf(&b, " %s ", sym) // fixed width cell
 		}
 		b.WriteByte('\n')
 	}
+	return b.Bytes()
+}
 
-	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-	w.Header().Set("X-Campground-Name", campgroundName)
-	if isRefreshing {
-		w.Header().Set("X-Refresh-In-Progress", "true")
+// parseStateRange reads the from/to query parameters (YYYY-MM-DD) shared by the campground
+// state endpoints. It defaults to the next 3 weeks and clamps the range to 60 days.
+func parseStateRange(r *http.Request) (startDate, endDate time.Time) {
+	now := normalizeDay(time.Now())
+
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		if parsed, err := time.Parse("2006-01-02", fromStr); err == nil {
+			startDate = normalizeDay(parsed)
+		} else {
+			startDate = now
+		}
+	} else {
+		startDate = now
+	}
+
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		if parsed, err := time.Parse("2006-01-02", toStr); err == nil {
+			endDate = normalizeDay(parsed)
+		} else {
+			endDate = normalizeDay(startDate.AddDate(0, 0, 20)) // 3 weeks default
+		}
+	} else {
+		endDate = normalizeDay(startDate.AddDate(0, 0, 20)) // 3 weeks default
+	}
+
+	// Enforce reasonable limits
+	if endDate.Before(startDate) {
+		endDate = startDate.AddDate(0, 0, 1)
+	}
+	if endDate.Sub(startDate) > 60*24*time.Hour { // max 60 days
+		endDate = startDate.AddDate(0, 0, 60)
 	}
-	w.Write(b.Bytes())
+	return startDate, endDate
 }
 
 // normalizeDay returns time at 00:00 UTC for stable comparison (similar logic exists elsewhere).
@@ -0,0 +1,66 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthUser(t *testing.T) {
+	s := &Server{}
+	s.SetUserTokenSecret("secret")
+
+	var gotUser string
+	h := s.auth(authUser, func(w http.ResponseWriter, r *http.Request) { gotUser = requestUserID(r) })
+	call := func(token string) int {
+		gotUser = ""
+		rec := httptest.NewRecorder()
+		h(rec, httptest.NewRequest(http.MethodGet, "/api/groups?user="+token, nil))
+		return rec.Code
+	}
+
+	if code := call(s.IssueUserToken("u1")); code != http.StatusOK || gotUser != "u1" {
+		t.Errorf("signed token: got %d for user %q, want 200 for u1", code, gotUser)
+	}
+	if code := call("u1"); code != http.StatusUnauthorized || gotUser != "" {
+		t.Errorf("raw user ID: got %d, want 401 without reaching the handler", code)
+	}
+	if code := call(""); code != http.StatusBadRequest {
+		t.Errorf("no token: got %d, want 400", code)
+	}
+
+	// Without a secret user routes are refused rather than trusting raw user IDs
+	s.SetUserTokenSecret("")
+	if code := call("u1"); code != http.StatusUnauthorized || gotUser != "" {
+		t.Errorf("raw user ID without a secret: got %d, want 401 without reaching the handler", code)
+	}
+}
+
+func TestAuthAdmin(t *testing.T) {
+	s := &Server{}
+	h := s.auth(authAdmin, func(w http.ResponseWriter, r *http.Request) {})
+	call := func(header string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/availability_as_of", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		h(rec, req)
+		return rec.Code
+	}
+
+	if code := call("Bearer anything"); code != http.StatusNotFound {
+		t.Errorf("no admin token configured: got %d, want 404", code)
+	}
+	s.SetAdminToken("admin")
+	for header, want := range map[string]int{
+		"Bearer admin": http.StatusOK,
+		"Bearer wrong": http.StatusUnauthorized,
+		"admin":        http.StatusOK, // the Bearer prefix is optional
+		"":             http.StatusUnauthorized,
+	} {
+		if code := call(header); code != want {
+			t.Errorf("Authorization %q: got %d, want %d", header, code, want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+)
+
+// newStateFixture stores two campsites over three days: site 1 is free, booked, then unknown;
+// site 2 is booked on the first day only.
+func newStateFixture(t *testing.T) (*Server, time.Time, time.Time) {
+	t.Helper()
+	store, err := db.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	ctx := context.Background()
+	now := time.Now()
+	for _, site := range []string{"2", "1"} {
+		_, err := store.DB.ExecContext(ctx, `INSERT INTO campsite_metadata(provider, campground_id, campsite_id, name, last_updated) VALUES ('p', 'cg', ?, ?, ?)`, site, "Site "+site, now)
+		if err != nil {
+			t.Fatalf("insert campsite: %v", err)
+		}
+	}
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	rows := []struct {
+		site      string
+		day       int
+		available bool
+	}{{"1", 0, true}, {"1", 1, false}, {"2", 0, false}}
+	for _, r := range rows {
+		_, err := store.DB.ExecContext(ctx, `INSERT INTO campsite_availability(provider, campground_id, campsite_id, date, available, last_checked) VALUES ('p', 'cg', ?, ?, ?, ?)`,
+			r.site, start.AddDate(0, 0, r.day), r.available, now)
+		if err != nil {
+			t.Fatalf("insert availability: %v", err)
+		}
+	}
+	return &Server{store: store}, start, start.AddDate(0, 0, 2)
+}
+
+func TestCampgroundStateFormats(t *testing.T) {
+	s, start, end := newStateFixture(t)
+	state, err := s.loadCampgroundState(context.Background(), "p", "cg", start, end)
+	if err != nil {
+		t.Fatalf("loadCampgroundState: %v", err)
+	}
+
+	t.Run("json", func(t *testing.T) {
+		raw, err := json.Marshal(state.toJSON("p", "cg", "Test Camp", false))
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		var got campgroundStateJSON
+		if err := json.Unmarshal(raw, &got); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if strings.Join(got.CampsiteIDs, ",") != "1,2" {
+			t.Errorf("campsite_ids = %v, want [1 2]", got.CampsiteIDs)
+		}
+		if strings.Join(got.Dates, ",") != "2025-07-01,2025-07-02,2025-07-03" {
+			t.Errorf("dates = %v", got.Dates)
+		}
+		want := [][]string{
+			{stateAvailable, stateUnavailable, stateUnknown},
+			{stateUnavailable, stateUnknown, stateUnknown},
+		}
+		for i := range want {
+			if strings.Join(got.Availability[i], ",") != strings.Join(want[i], ",") {
+				t.Errorf("availability[%d] = %v, want %v", i, got.Availability[i], want[i])
+			}
+		}
+	})
+
+	t.Run("ascii", func(t *testing.T) {
+		lines := strings.Split(strings.TrimRight(string(state.renderASCII(func(string) string { return "" })), "\n"), "\n")
+		if len(lines) != 6 {
+			t.Fatalf("expected 4 header lines and 2 campsite rows, got %d:\n%s", len(lines), strings.Join(lines, "\n"))
+		}
+		for i, want := range []string{" O  .  ? ", " .  ?  ? "} {
+			row := lines[4+i]
+			if !strings.HasSuffix(row, want) {
+				t.Errorf("row %d = %q, want suffix %q", i, row, want)
+			}
+		}
+	})
+}
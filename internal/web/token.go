@@ -0,0 +1,87 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// userTokenTTL is how long a web link from the bot identifies its user.
+const userTokenTTL = 24 * time.Hour
+
+var (
+	errInvalidUserToken = errors.New("invalid user token")
+	errExpiredUserToken = errors.New("user token expired, ask the bot for a new link")
+	errNoUserTokens     = errors.New("user links are disabled on this server")
+)
+
+// SetUserTokenSecret sets the key that signs user tokens. Until it's set no user token verifies, so
+// user endpoints are refused.
+func (s *Server) SetUserTokenSecret(secret string) {
+	s.userTokenSecret = []byte(secret)
+}
+
+// IssueUserToken returns a token identifying userID in web links for the next userTokenTTL:
+// "<user id>.<expiry unix>.<HMAC of both>". Without a secret the raw ID is returned, which won't verify.
+func (s *Server) IssueUserToken(userID string) string {
+	return s.issueUserToken(userID, time.Now())
+}
+
+// VerifyUserToken returns the user ID a token from IssueUserToken was issued for.
+func (s *Server) VerifyUserToken(token string) (string, error) {
+	return s.verifyUserToken(token, time.Now())
+}
+
+func (s *Server) issueUserToken(userID string, now time.Time) string {
+	if len(s.userTokenSecret) == 0 {
+		return userID
+	}
+	payload := userID + "." + strconv.FormatInt(now.Add(userTokenTTL).Unix(), 10)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.userTokenMAC(payload))
+}
+
+func (s *Server) verifyUserToken(token string, now time.Time) (string, error) {
+	if len(s.userTokenSecret) == 0 {
+		return "", errNoUserTokens
+	}
+
+	// Discord IDs are numeric, so the user ID never contains the separator
+	payload, sig, ok := cutLast(token, ".")
+	if !ok {
+		return "", errInvalidUserToken
+	}
+	userID, expiry, ok := strings.Cut(payload, ".")
+	if !ok || userID == "" {
+		return "", errInvalidUserToken
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, s.userTokenMAC(payload)) {
+		return "", errInvalidUserToken
+	}
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return "", errInvalidUserToken
+	}
+	if !now.Before(time.Unix(expiresAt, 0)) {
+		return "", errExpiredUserToken
+	}
+	return userID, nil
+}
+
+func (s *Server) userTokenMAC(payload string) []byte {
+	mac := hmac.New(sha256.New, s.userTokenSecret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
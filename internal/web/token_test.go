@@ -0,0 +1,55 @@
+package web
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUserTokens(t *testing.T) {
+	s := &Server{}
+	s.SetUserTokenSecret("secret")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	token := s.issueUserToken("123", now)
+	if userID, err := s.verifyUserToken(token, now); err != nil || userID != "123" {
+		t.Fatalf("fresh token: got %q, %v; want 123", userID, err)
+	}
+
+	// Tampering with any part of the token invalidates it
+	parts := strings.Split(token, ".")
+	other := &Server{}
+	other.SetUserTokenSecret("other")
+	for name, tampered := range map[string]string{
+		"raw user ID":     "123",
+		"other user":      "456." + parts[1] + "." + parts[2],
+		"later expiry":    parts[0] + ".9999999999." + parts[2],
+		"bad signature":   parts[0] + "." + parts[1] + ".AAAA",
+		"undecodable":     parts[0] + "." + parts[1] + ".!!!",
+		"missing expiry":  parts[0] + "." + parts[2],
+		"missing user ID": "." + parts[1] + "." + parts[2],
+		"other secret":    other.issueUserToken("123", now),
+		"empty":           "",
+	} {
+		if userID, err := s.verifyUserToken(tampered, now); !errors.Is(err, errInvalidUserToken) {
+			t.Errorf("%s: got %q, %v; want errInvalidUserToken", name, userID, err)
+		}
+	}
+
+	if _, err := s.verifyUserToken(token, now.Add(userTokenTTL-time.Second)); err != nil {
+		t.Errorf("token just before expiry: %v", err)
+	}
+	if _, err := s.verifyUserToken(token, now.Add(userTokenTTL)); !errors.Is(err, errExpiredUserToken) {
+		t.Errorf("expired token: got %v, want errExpiredUserToken", err)
+	}
+
+	// Without a secret nothing verifies, not even a raw user ID
+	s.SetUserTokenSecret("")
+	if userID, err := s.verifyUserToken("123", now); !errors.Is(err, errNoUserTokens) {
+		t.Errorf("raw ID without a secret: got %q, %v; want errNoUserTokens", userID, err)
+	}
+	if userID, err := s.verifyUserToken(token, now); !errors.Is(err, errNoUserTokens) {
+		t.Errorf("signed token without a secret: got %q, %v; want errNoUserTokens", userID, err)
+	}
+}
@@ -0,0 +1,90 @@
+package web
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+
+	"github.com/brensch/schniffer/internal/db"
+)
+
+// ViewportGroupRequest asks for a group of the campgrounds matching the map's current bounds
+// and filters. Only the db.MaxGroupCampgrounds nearest the middle of the viewport are kept.
+type ViewportGroupRequest struct {
+	Name string `json:"name"`
+	ViewportRequest
+}
+
+// nearestCampgroundRefs returns up to limit distinct campgrounds ordered by distance from
+// (lat, lon), nearest first.
+func nearestCampgroundRefs(campgrounds []CampgroundMapData, lat, lon float64, limit int) []db.CampgroundRef {
+	sorted := make([]CampgroundMapData, len(campgrounds))
+	copy(sorted, campgrounds)
+	for i := range sorted {
+		sorted[i].DistanceKm = haversineKm(lat, lon, sorted[i].Lat, sorted[i].Lon)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].DistanceKm < sorted[j].DistanceKm })
+
+	seen := make(map[db.CampgroundRef]bool)
+	var refs []db.CampgroundRef
+	for _, c := range sorted {
+		if len(refs) == limit {
+			break
+		}
+		ref := db.CampgroundRef{Provider: c.Provider, CampgroundID: c.ID}
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// handleCreateGroupFromViewport serves POST /api/groups/from_viewport, saving the campgrounds
+// nearest the viewport centre that match its filters as a new group.
+func (s *Server) handleCreateGroupFromViewport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ViewportGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" {
+		http.Error(w, "Group name is required", http.StatusBadRequest)
+		return
+	}
+	if req.OnlyAvailable {
+		if _, _, err := req.availabilityRange(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	campgrounds, err := s.getCampgroundsInViewport(r.Context(), req.ViewportRequest, false)
+	if err != nil {
+		slog.Error("failed to get campgrounds for viewport group", slog.Any("err", err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	refs := nearestCampgroundRefs(campgrounds, (req.North+req.South)/2, (req.East+req.West)/2, db.MaxGroupCampgrounds)
+	if len(refs) == 0 {
+		http.Error(w, "No campgrounds match the current view", http.StatusBadRequest)
+		return
+	}
+
+	group, err := s.store.CreateGroup(r.Context(), requestUserID(r), req.Name, refs)
+	if err != nil {
+		slog.Error("Failed to create group", "error", err)
+		http.Error(w, "Failed to create group", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
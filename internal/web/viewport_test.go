@@ -0,0 +1,281 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brensch/schniffer/internal/db"
+	"github.com/brensch/schniffer/internal/manager"
+	"github.com/brensch/schniffer/internal/providers"
+)
+
+func TestViewportOnlyAvailable(t *testing.T) {
+	store, err := db.Open(filepath.Join(t.TempDir(), "viewport.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	s := &Server{store: store, mgr: manager.NewManager(store, providers.NewRegistry(), nil, "")}
+	ctx := context.Background()
+
+	day := normalizeDay(time.Now()).AddDate(0, 0, 5)
+	for _, cg := range []struct {
+		id    string
+		price float64
+	}{{"open", 30}, {"open-pricey", 90}, {"booked", 30}, {"open-later", 30}} {
+		if err := store.UpsertCampground(ctx, "p", cg.id, cg.id, 45, -120, 0, nil, "", cg.price, cg.price, "night"); err != nil {
+			t.Fatalf("UpsertCampground: %v", err)
+		}
+	}
+	now := time.Now()
+	err = store.UpsertCampsiteAvailabilityBatch(ctx, []db.CampsiteAvailability{
+		{Provider: "p", CampgroundID: "open", CampsiteID: "1", Date: day, Available: true, LastChecked: now},
+		{Provider: "p", CampgroundID: "open-pricey", CampsiteID: "1", Date: day, Available: true, LastChecked: now},
+		{Provider: "p", CampgroundID: "booked", CampsiteID: "1", Date: day, Available: false, LastChecked: now},
+		{Provider: "p", CampgroundID: "open-later", CampsiteID: "1", Date: day.AddDate(0, 0, 60), Available: true, LastChecked: now},
+	})
+	if err != nil {
+		t.Fatalf("UpsertCampsiteAvailabilityBatch: %v", err)
+	}
+
+	post := func(body string) (int, []string) {
+		rec := httptest.NewRecorder()
+		s.handleViewportAPI(rec, httptest.NewRequest(http.MethodPost, "/api/viewport", strings.NewReader(body)))
+		if rec.Code != http.StatusOK {
+			return rec.Code, nil
+		}
+		var resp struct {
+			Data []CampgroundMapData `json:"data"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		var ids []string
+		for _, c := range resp.Data {
+			ids = append(ids, c.ID)
+		}
+		sort.Strings(ids)
+		return rec.Code, ids
+	}
+	bounds := `"north":46,"south":44,"east":-119,"west":-121,"zoom":10`
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"no availability filter", `{` + bounds + `}`, "booked,open,open-later,open-pricey"},
+		{"default range", `{` + bounds + `,"only_available":true}`, "open,open-pricey"},
+		{"with a price filter", `{` + bounds + `,"only_available":true,"max_price":50}`, "open"},
+		{"explicit range", `{` + bounds + `,"only_available":true,"date_from":"` + day.AddDate(0, 0, 50).Format("2006-01-02") + `","date_to":"` + day.AddDate(0, 0, 70).Format("2006-01-02") + `"}`, "open-later"},
+	}
+	for _, tt := range tests {
+		code, ids := post(tt.body)
+		if code != http.StatusOK || strings.Join(ids, ",") != tt.want {
+			t.Errorf("%s: got %d %v, want %s", tt.name, code, ids, tt.want)
+		}
+	}
+
+	for _, body := range []string{
+		`{` + bounds + `,"only_available":true,"date_from":"soon"}`,
+		`{` + bounds + `,"only_available":true,"date_from":"2030-02-01","date_to":"2030-01-01"}`,
+	} {
+		if code, _ := post(body); code != http.StatusBadRequest {
+			t.Errorf("%s: got %d, want 400", body, code)
+		}
+	}
+
+	// the availability check must seek the provider+campground+date index, not scan the table
+	filters, args := viewportFilters(ViewportRequest{OnlyAvailable: true, IncludeDayUse: true})
+	rows, err := store.DB.QueryContext(ctx, `EXPLAIN QUERY PLAN SELECT c.campground_id FROM campgrounds c WHERE 1=1`+filters, args...)
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	defer rows.Close()
+	var plan []string
+	for rows.Next() {
+		var id, parent, notused int
+		var detail string
+		if err := rows.Scan(&id, &parent, &notused, &detail); err != nil {
+			t.Fatalf("scan plan: %v", err)
+		}
+		plan = append(plan, detail)
+	}
+	joined := strings.Join(plan, "\n")
+	if !strings.Contains(joined, "SEARCH a USING") || strings.Contains(joined, "SCAN a") {
+		t.Errorf("availability check should search an index, plan:\n%s", joined)
+	}
+}
+
+func TestViewportReservableOnly(t *testing.T) {
+	store, err := db.Open(filepath.Join(t.TempDir(), "viewport.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	sites := map[string][]string{
+		"reservable":  {"Site-Specific", "Walk-Up"},
+		"walkup":      {"Walk-Up", "Non Site-Specific"},
+		"unlabelled":  {""},
+		"no-metadata": nil,
+	}
+	for id, reserveTypes := range sites {
+		if err := store.UpsertCampground(ctx, "p", id, id, 45, -120, 0, nil, "", 0, 0, ""); err != nil {
+			t.Fatalf("UpsertCampground: %v", err)
+		}
+		var infos []providers.CampsiteInfo
+		for i, rt := range reserveTypes {
+			infos = append(infos, providers.CampsiteInfo{ID: fmt.Sprint(i), ReserveType: rt})
+		}
+		if _, err := store.UpsertCampsiteMetadataBatch(ctx, "p", id, infos); err != nil {
+			t.Fatalf("UpsertCampsiteMetadataBatch: %v", err)
+		}
+	}
+
+	ids := func(req ViewportRequest) string {
+		filters, args := viewportFilters(req)
+		rows, err := store.DB.QueryContext(ctx, `SELECT c.campground_id FROM campgrounds c WHERE 1=1`+filters+` ORDER BY c.campground_id`, args...)
+		if err != nil {
+			t.Fatalf("query: %v", err)
+		}
+		defer rows.Close()
+		var out []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				t.Fatalf("scan: %v", err)
+			}
+			out = append(out, id)
+		}
+		return strings.Join(out, ",")
+	}
+
+	if got := ids(ViewportRequest{}); got != "no-metadata,reservable,unlabelled,walkup" {
+		t.Errorf("without the filter got %s", got)
+	}
+	if got := ids(ViewportRequest{ReservableOnly: true}); got != "no-metadata,reservable,unlabelled" {
+		t.Errorf("reservable only got %s, want the walk-up-only campground hidden", got)
+	}
+}
+
+func TestClusterCampgroundsRatingAndPrice(t *testing.T) {
+	s := &Server{}
+	camps := []CampgroundMapData{
+		{Name: "a", Lat: 45.1, Lon: -120.1, Rating: 4, PriceMin: 30},
+		{Name: "b", Lat: 45.2, Lon: -120.2, Rating: 5, PriceMin: 20},
+		{Name: "c", Lat: 45.3, Lon: -120.3}, // unrated and unpriced, so it doesn't drag the figures down
+	}
+
+	clusters := s.clusterCampgrounds(camps, clusterDetailMinZoom)
+	if len(clusters) != 1 {
+		t.Fatalf("got %d clusters, want 1", len(clusters))
+	}
+	if c := clusters[0]; c.Count != 3 || c.AvgRating != 4.5 || c.MinPrice != 20 {
+		t.Errorf("cluster = %+v, want count 3, avg rating 4.5, min price 20", c)
+	}
+
+	// Zoomed out, clusters only carry counts.
+	for _, c := range s.clusterCampgrounds(camps, clusterDetailMinZoom-1) {
+		if c.AvgRating != 0 || c.MinPrice != 0 {
+			t.Errorf("zoomed-out cluster = %+v, want no rating or price", c)
+		}
+	}
+}
+
+func TestCreateGroupFromViewport(t *testing.T) {
+	store, err := db.Open(filepath.Join(t.TempDir(), "viewport_group.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	s := &Server{store: store}
+	s.SetUserTokenSecret("secret")
+	ctx := context.Background()
+
+	// Campgrounds step out from the viewport centre (45, -120); the cheap ones are the farthest.
+	for i := 0; i < db.MaxGroupCampgrounds+5; i++ {
+		price := 90.0
+		if i >= db.MaxGroupCampgrounds {
+			price = 10
+		}
+		id := fmt.Sprintf("cg%02d", i)
+		if err := store.UpsertCampground(ctx, "p", id, id, 45+float64(i)*0.01, -120, 0, nil, "", price, price, "night"); err != nil {
+			t.Fatalf("UpsertCampground: %v", err)
+		}
+	}
+
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/groups/from_viewport?user="+s.IssueUserToken("u1"), strings.NewReader(body))
+		s.auth(authUser, s.handleCreateGroupFromViewport)(rec, req)
+		return rec
+	}
+	ids := func(rec *httptest.ResponseRecorder) []string {
+		var group db.Group
+		if err := json.NewDecoder(rec.Body).Decode(&group); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		var ids []string
+		for _, c := range group.Campgrounds {
+			ids = append(ids, c.CampgroundID)
+		}
+		return ids
+	}
+	bounds := `"north":46,"south":44,"east":-119,"west":-121,"zoom":8`
+
+	rec := post(`{"name":"everything",` + bounds + `}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+	}
+	got := ids(rec)
+	if len(got) != db.MaxGroupCampgrounds || got[0] != "cg00" || got[len(got)-1] != fmt.Sprintf("cg%02d", db.MaxGroupCampgrounds-1) {
+		t.Errorf("group = %v, want the %d nearest the centre", got, db.MaxGroupCampgrounds)
+	}
+
+	rec = post(`{"name":"cheap",` + bounds + `,"max_price":50}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := ids(rec); len(got) != 5 || got[0] != fmt.Sprintf("cg%02d", db.MaxGroupCampgrounds) {
+		t.Errorf("filtered group = %v, want the 5 cheap campgrounds", got)
+	}
+
+	if rec := post(`{"name":"nothing",` + bounds + `,"max_price":5}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("no matches: got %d, want 400", rec.Code)
+	}
+	if rec := post(`{` + bounds + `}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("missing name: got %d, want 400", rec.Code)
+	}
+
+	groups, err := store.GetUserGroups(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetUserGroups: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Errorf("got %d groups, want 2", len(groups))
+	}
+}
+
+func TestNearestCampgroundRefsDedupes(t *testing.T) {
+	camps := []CampgroundMapData{
+		{Provider: "p", ID: "far", Lat: 46, Lon: -120},
+		{Provider: "p", ID: "near", Lat: 45, Lon: -120},
+		{Provider: "p", ID: "near", Lat: 45, Lon: -120},
+		{Provider: "q", ID: "near", Lat: 45.5, Lon: -120},
+	}
+	got := nearestCampgroundRefs(camps, 45, -120, 2)
+	want := []db.CampgroundRef{{Provider: "p", CampgroundID: "near"}, {Provider: "q", CampgroundID: "near"}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
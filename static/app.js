@@ -8,7 +8,12 @@ let currentFilters = {
     amenities: [],
     campsiteTypes: [],
     equipment: [],
+    tags: [],
     minRating: 0,
+    includeUnrated: false,
+    includeDayUse: false,
+    reservableOnly: false,
+    specialty: false,
     minPrice: 0,
     maxPrice: 500
 };
@@ -96,11 +101,11 @@ function createClusterIcon(count) {
 }
 
 // Load campgrounds for current viewport
-async function loadViewportData() {
+function currentViewportRequest() {
     const bounds = map.getBounds();
     const zoom = map.getZoom();
     
-    const viewport = {
+    return {
         north: bounds.getNorth(),
         south: bounds.getSouth(),
         east: bounds.getEast(),
@@ -110,10 +115,19 @@ async function loadViewportData() {
         amenities: currentFilters.amenities,
         campsite_types: currentFilters.campsiteTypes,
         equipment: currentFilters.equipment,
+        tags: currentFilters.tags,
         min_rating: currentFilters.minRating,
+        include_unrated: currentFilters.includeUnrated,
+        include_day_use: currentFilters.includeDayUse,
+        reservable_only: currentFilters.reservableOnly,
+        specialty: currentFilters.specialty,
         min_price: currentFilters.minPrice,
         max_price: currentFilters.maxPrice
     };
+}
+
+async function loadViewportData() {
+    const viewport = currentViewportRequest();
     
     try {
         const response = await fetch('/api/viewport', {
@@ -235,7 +249,7 @@ function renderMarkersFromViewport(result) {
                 const providerEmoji = campground.provider === 'recreation_gov' ? '🏞️' : '🌲';
                 
                 // Format rating display with outlined stars and bordered style
-                const ratingDisplay = campground.rating > 0 
+                const ratingDisplay = campground.has_rating 
                     ? `<div class="popup-rating">⭐ ${campground.rating.toFixed(1)}/5.0</div>`
                     : '';
                 
@@ -371,10 +385,10 @@ function updateSaveGroupButton() {
     
     // Since backend now sends individual campgrounds when ≤100, we can check directly
     if (currentData.type === 'clusters') {
-        // If we still have clusters, it means >100 campgrounds
+        // If we still have clusters, it means >100 campgrounds, so offer the nearest 10 instead
         const totalCount = currentData.data ? currentData.data.reduce((sum, cluster) => sum + cluster.count, 0) : 0;
-        saveGroupBtn.disabled = true;
-        saveGroupBtn.textContent = `🔍 Too many schniffgrounds (${totalCount})`;
+        saveGroupBtn.disabled = totalCount === 0;
+        saveGroupBtn.textContent = `🐽 Schniff nearest 10 (${totalCount})`;
     } else {
         // We have individual campgrounds (≤100)
         const campgroundCount = currentData.data ? currentData.data.length : 0;
@@ -393,8 +407,9 @@ function openSaveGroupModal() {
         return;
     }
     
-    // Only allow if we have individual campgrounds (backend now handles this automatically)
+    // Too many to pick from, so let the backend choose the ones nearest the middle of the map
     if (currentData.type === 'clusters') {
+        saveViewportGroup();
         return;
     }
     
@@ -410,8 +425,8 @@ function openSaveGroupModal() {
         item.className = 'campground-item';
         
         // Format rating display for modal
-        const ratingDisplay = campground.rating > 0 
-            ? `<span class="campground-rating">⭐ ${campground.rating.toFixed(2)}</span>`
+        const ratingDisplay = campground.has_rating 
+            ? `<span class="campground-rating">⭐ ${campground.rating.toFixed(1)}</span>`
             : '';
             
         // Format price display for modal
@@ -536,6 +551,34 @@ async function saveGroup() {
     }
 }
 
+async function saveViewportGroup() {
+    const groupName = (prompt('Name your schniffgroup') || '').trim();
+    if (!groupName) {
+        return;
+    }
+    
+    try {
+        const response = await fetch(`/api/groups/from_viewport?user=${encodeURIComponent(userToken)}`, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            body: JSON.stringify({ name: groupName, ...currentViewportRequest() })
+        });
+        
+        if (!response.ok) {
+            const error = await response.text();
+            throw new Error(error);
+        }
+        
+        const group = await response.json();
+        showSuccessModal(group.name, group.campgrounds.length);
+    } catch (error) {
+        console.error('Failed to save group:', error);
+        showErrorModal('Failed to save group: ' + error.message);
+    }
+}
+
 function showSuccessModal(groupName, campgroundCount) {
     const modal = document.getElementById('success-modal');
     const messageEl = document.getElementById('success-message');
@@ -719,6 +762,16 @@ function populateFilterOptions() {
             equipmentContainer.appendChild(item);
         });
     }
+    
+    // Populate tags
+    const tagsContainer = document.getElementById('tags-container');
+    tagsContainer.innerHTML = '';
+    if (filterOptions.tags) {
+        filterOptions.tags.forEach(tag => {
+            const item = createFilterCheckbox('tag', tag, tag);
+            tagsContainer.appendChild(item);
+        });
+    }
 }
 
 function createFilterCheckbox(type, value, label) {
@@ -753,6 +806,10 @@ function updateFiltersFromCheckboxes() {
     // Update equipment
     const equipmentCheckboxes = document.querySelectorAll('#equipment-container input[type="checkbox"]:checked');
     currentFilters.equipment = Array.from(equipmentCheckboxes).map(cb => cb.value);
+    
+    // Update tags
+    const tagCheckboxes = document.querySelectorAll('#tags-container input[type="checkbox"]:checked');
+    currentFilters.tags = Array.from(tagCheckboxes).map(cb => cb.value);
 }
 
 function updateRatingValue(value) {
@@ -760,6 +817,22 @@ function updateRatingValue(value) {
     document.getElementById('rating-value').textContent = parseFloat(value).toFixed(1);
 }
 
+function updateIncludeUnrated(checked) {
+    currentFilters.includeUnrated = checked;
+}
+
+function updateIncludeDayUse(checked) {
+    currentFilters.includeDayUse = checked;
+}
+
+function updateReservableOnly(checked) {
+    currentFilters.reservableOnly = checked;
+}
+
+function updateSpecialty(checked) {
+    currentFilters.specialty = checked;
+}
+
 function updatePriceMinValue(value) {
     currentFilters.minPrice = parseInt(value);
     document.getElementById('price-min-value').textContent = value;
@@ -800,11 +873,16 @@ function clearAllFilters() {
     document.querySelectorAll('#amenities-container input[type="checkbox"]').forEach(cb => cb.checked = false);
     document.querySelectorAll('#campsite-types-container input[type="checkbox"]').forEach(cb => cb.checked = false);
     document.querySelectorAll('#equipment-container input[type="checkbox"]').forEach(cb => cb.checked = false);
+    document.querySelectorAll('#tags-container input[type="checkbox"]').forEach(cb => cb.checked = false);
     
     // Reset sliders
     const ratingSlider = document.getElementById('rating-slider');
     ratingSlider.value = filterOptions?.rating_range?.min || 0;
     updateRatingValue(ratingSlider.value);
+    document.getElementById('include-unrated').checked = false;
+    document.getElementById('include-day-use').checked = false;
+    document.getElementById('reservable-only').checked = false;
+    document.getElementById('specialty-only').checked = false;
     
     const priceMinSlider = document.getElementById('price-min-slider');
     const priceMaxSlider = document.getElementById('price-max-slider');
@@ -818,7 +896,12 @@ function clearAllFilters() {
         amenities: [],
         campsiteTypes: [],
         equipment: [],
+        tags: [],
         minRating: filterOptions?.rating_range?.min || 0,
+        includeUnrated: false,
+        includeDayUse: false,
+        reservableOnly: false,
+        specialty: false,
         minPrice: filterOptions?.price_range?.min || 0,
         maxPrice: filterOptions?.price_range?.max || 500
     };
@@ -837,9 +920,15 @@ function applyFilters() {
 }
 
 function matchesFilters(campground) {
-    // Check rating filter
-    if (campground.rating < currentFilters.minRating) {
-        return false;
+    // Check rating filter - unrated campgrounds only pass if explicitly included
+    if (currentFilters.minRating > 0) {
+        if (!campground.has_rating) {
+            if (!currentFilters.includeUnrated) {
+                return false;
+            }
+        } else if (campground.rating < currentFilters.minRating) {
+            return false;
+        }
     }
     
     // Check price filter